@@ -0,0 +1,201 @@
+package main
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "hash/crc32"
+    "log"
+    "math"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   实验性：音频备份（FSK）
+// -------------------------
+//
+// Bell 202 风格 FSK：1200 Hz 表示 1，2200 Hz 表示 0，300 波特。
+// 帧格式：前导（64 个交替位）| 同步字节 0x7E | 长度 | 熵 | CRC-32
+//
+
+const (
+    fskSampleRate = 8000
+    fskBaud       = 300
+    fskMark       = 1200.0
+    fskSpace      = 2200.0
+    fskSync       = 0x7E
+    fskPreamble   = 64
+)
+
+func fskFrame(payload []byte) []byte {
+    frame := []byte{fskSync, byte(len(payload))}
+    frame = append(frame, payload...)
+    return binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(payload))
+}
+
+func fskModulate(frame []byte) []int16 {
+    bits := make([]bool, 0, fskPreamble+len(frame)*8)
+    for i := 0; i < fskPreamble; i++ {
+        bits = append(bits, i%2 == 0)
+    }
+    bits = append(bits, bip39.BytesToBits(frame)...)
+
+    samplesPerBit := float64(fskSampleRate) / fskBaud
+    samples := make([]int16, 0, int(float64(len(bits))*samplesPerBit)+1)
+    phase := 0.0
+    for n, b := range bits {
+        freq := fskSpace
+        if b {
+            freq = fskMark
+        }
+        // 相位连续，避免频率切换时的爆音
+        end := int(math.Round(float64(n+1) * samplesPerBit))
+        for len(samples) < end {
+            samples = append(samples, int16(math.Sin(phase)*0.8*math.MaxInt16))
+            phase += 2 * math.Pi * freq / fskSampleRate
+        }
+    }
+    return samples
+}
+
+// Goertzel 算法：求一段采样在指定频率上的能量
+func goertzel(samples []float64, freq float64, rate int) float64 {
+    coeff := 2 * math.Cos(2*math.Pi*freq/float64(rate))
+    var s1, s2 float64
+    for _, x := range samples {
+        s0 := x + coeff*s1 - s2
+        s2, s1 = s1, s0
+    }
+    return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+func fskDemodulate(samples []float64, rate int, offset float64) []bool {
+    samplesPerBit := float64(rate) / fskBaud
+    var bits []bool
+    for n := 0; ; n++ {
+        start := int(math.Round(offset + float64(n)*samplesPerBit))
+        end := int(math.Round(offset + float64(n+1)*samplesPerBit))
+        if end > len(samples) {
+            return bits
+        }
+        win := samples[start:end]
+        bits = append(bits, goertzel(win, fskMark, rate) > goertzel(win, fskSpace, rate))
+    }
+}
+
+// 在位流中寻找同步字节，并校验长度与 CRC
+func fskFindPayload(bits []bool) ([]byte, bool) {
+    for start := 0; start+16 <= len(bits); start++ {
+        if bip39.BitsToInt(bits[start:start+8]) != fskSync {
+            continue
+        }
+        n := bip39.BitsToInt(bits[start+8 : start+16])
+        end := start + 16 + (n+4)*8
+        if n == 0 || end > len(bits) {
+            continue
+        }
+        body := bip39.BitsToBytes(bits[start+16 : end])
+        payload, sum := body[:n], binary.BigEndian.Uint32(body[n:])
+        if crc32.ChecksumIEEE(payload) == sum {
+            return payload, true
+        }
+    }
+    return nil, false
+}
+
+func writeWAV(filename string, samples []int16, rate int) error {
+    var buf bytes.Buffer
+    dataLen := uint32(len(samples) * 2)
+    buf.WriteString("RIFF")
+    binary.Write(&buf, binary.LittleEndian, 36+dataLen)
+    buf.WriteString("WAVEfmt ")
+    binary.Write(&buf, binary.LittleEndian, uint32(16))
+    binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+    binary.Write(&buf, binary.LittleEndian, uint16(1)) // mono
+    binary.Write(&buf, binary.LittleEndian, uint32(rate))
+    binary.Write(&buf, binary.LittleEndian, uint32(rate*2))
+    binary.Write(&buf, binary.LittleEndian, uint16(2))
+    binary.Write(&buf, binary.LittleEndian, uint16(16))
+    buf.WriteString("data")
+    binary.Write(&buf, binary.LittleEndian, dataLen)
+    binary.Write(&buf, binary.LittleEndian, samples)
+    return writeSecretFile(filename, buf.Bytes())
+}
+
+// 读取 16 位 PCM WAV，多声道时只取第一个声道
+func readWAV(filename string) ([]float64, int, error) {
+    data, err := readFileLimited(filename, maxWAVSize)
+    if err != nil {
+        return nil, 0, err
+    }
+    if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+        return nil, 0, fmt.Errorf("not a WAV file")
+    }
+
+    var channels, bitsPerSample uint16
+    var rate uint32
+    pos := 12
+    for pos+8 <= len(data) {
+        id := string(data[pos : pos+4])
+        size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+        body := data[pos+8 : min(pos+8+size, len(data))]
+        switch id {
+        case "fmt ":
+            if len(body) < 16 || binary.LittleEndian.Uint16(body[0:2]) != 1 {
+                return nil, 0, fmt.Errorf("only PCM WAV files are supported")
+            }
+            channels = binary.LittleEndian.Uint16(body[2:4])
+            rate = binary.LittleEndian.Uint32(body[4:8])
+            bitsPerSample = binary.LittleEndian.Uint16(body[14:16])
+        case "data":
+            if bitsPerSample != 16 || channels == 0 {
+                return nil, 0, fmt.Errorf("only 16-bit PCM WAV files are supported")
+            }
+            frame := int(channels) * 2
+            samples := make([]float64, 0, len(body)/frame)
+            for i := 0; i+frame <= len(body); i += frame {
+                samples = append(samples, float64(int16(binary.LittleEndian.Uint16(body[i:i+2]))))
+            }
+            return samples, int(rate), nil
+        }
+        pos += 8 + size + size%2
+    }
+    return nil, 0, fmt.Errorf("no audio data found")
+}
+
+func exportAudioBackup(filename string) {
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    samples := fskModulate(fskFrame(entropy))
+    if err := writeWAV(filename, samples, fskSampleRate); err != nil {
+        log.Fatalf("Error writing %s: %v", filename, err)
+    }
+    fmt.Printf("%s written (%.1f s of FSK audio).\n", filename, float64(len(samples))/fskSampleRate)
+}
+
+func decodeAudioBackup(filename string, wordList []string) {
+    samples, rate, err := readWAV(filename)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+    if rate < 2*int(fskSpace) {
+        log.Fatalf("Error: sample rate %d Hz too low for FSK decoding", rate)
+    }
+
+    // 录音时位边界未知：在一个位周期内尝试多个起点
+    samplesPerBit := float64(rate) / fskBaud
+    for offset := 0.0; offset < samplesPerBit; offset += samplesPerBit / 8 {
+        payload, ok := fskFindPayload(fskDemodulate(samples, rate, offset))
+        if !ok {
+            continue
+        }
+        if !bip39.ValidEntropySize(len(payload)) {
+            continue
+        }
+        fmt.Println("Passphrase:")
+        printPhrase(mnemonicFromEntropy(payload, wordList))
+        return
+    }
+    log.Fatalf("Error: no valid backup found in %s", filename)
+}
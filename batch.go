@@ -0,0 +1,102 @@
+package main
+
+import (
+    "crypto/rand"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+//
+// -------------------------
+//   -n 批量生成
+// -------------------------
+//
+// 一次生成 COUNT 组互相独立的熵与助记词（为多台设备或多签的各个签名方准备）：
+//   -b -n 3        写入 binary-1.txt、binary-2.txt、binary-3.txt（名字取自 -f），
+//                  任何一个已存在时什么都不写；
+//   -no-file -n 3  只打印，不写文件；
+//   加 -json       每组一行 JSON（JSON Lines），结构同 -schema mnemonic。
+// 每组都单独从系统随机数生成器取熵。-dice、-cards 等外部熵只够一组使用，
+// 不能与 -n 同用。birthday.txt 只对应 binary.txt，批量生成时不记录生日。
+//
+
+const maxBatchCount = 1000
+
+// binary.txt → binary-01.txt（序号按总数补零）
+func batchFileName(path, suffix string) string {
+    ext := filepath.Ext(path)
+    return strings.TrimSuffix(path, ext) + "-" + suffix + ext
+}
+
+func runBatch(count, size int, noFile bool, wordList []string) {
+    var files []string
+    if !noFile {
+        width := len(fmt.Sprint(count))
+        for i := 1; i <= count; i++ {
+            name := batchFileName(binaryPath, fmt.Sprintf("%0*d", width, i))
+            if _, err := os.Stat(name); err == nil {
+                log.Fatalf("Error: %s already exists; nothing was written.", name)
+            }
+            files = append(files, name)
+        }
+        // -encrypt 时所有文件共用一个口令
+        binaryPath = batchFileName(binaryPath, "N")
+    }
+
+    // 先全部生成并检验，任何一组不通过都不写文件
+    entropies := make([][]byte, count)
+    for i := range entropies {
+        entropies[i] = make([]byte, size)
+        if _, err := rand.Read(entropies[i]); err != nil {
+            log.Fatalf("Error generating entropy: %v", err)
+        }
+        if statChecks {
+            checkEntropyStats(entropies[i], false)
+        }
+    }
+
+    for i := 1; i <= count; i++ {
+        entropy := entropies[i-1]
+        mnemonic := mnemonicFromEntropy(entropy, wordList)
+        fp := masterFingerprint(mnemonic)
+
+        if noFile {
+            if jsonOutput {
+                printJSONLine(entropyReport("no-file", entropy, true, wordList))
+            } else {
+                fmt.Printf("#%d  fingerprint %s\n", i, fp)
+                printPhrase(mnemonic)
+                fmt.Println()
+            }
+            warnWeakMnemonic(entropy, wordList)
+            transcript.record("generate in memory", "ok", fp, fmt.Sprintf("%d of %d", i, count))
+            continue
+        }
+
+        name := files[i-1]
+        if err := writeBinaryFile(name, entropy); err != nil {
+            log.Fatalf("Error writing %s: %v", name, err)
+        }
+        if jsonOutput {
+            r := entropyReport("b", entropy, false, wordList)
+            r.File = name
+            printJSONLine(r)
+        } else {
+            fmt.Printf("%s  fingerprint %s\n", name, fp)
+        }
+        transcript.record("generate "+name, "ok", fp, fmt.Sprintf("%d of %d", i, count))
+    }
+
+    if jsonOutput {
+        return
+    }
+    fmt.Printf("%d independent passphrases (%d bits, %d words each).\n", count, size*8, size*3/4)
+    if noFile {
+        fmt.Println("Write the words down now: they are not stored anywhere and cannot be shown again.")
+    } else {
+        fmt.Printf("No birthday was recorded: %s belongs to binary.txt only.\n", birthdayFile)
+    }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "bufio"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "os"
+    "os/exec"
+    "strings"
+)
+
+//
+// -------------------------
+//   -out 大批量输出
+// -------------------------
+//
+// 十万行以上的输出（地址 CSV 等）边生成边写入文件，不在内存中累积：
+// 内存占用只有一个写缓冲，与行数无关。按扩展名压缩：.gz 用 gzip，
+// .zst 通过外部 zstd 命令（管道，同样是流式）。文件以 0600 新建，
+// 不覆盖已有文件。
+//
+
+// 写文件时 -count 的上限
+const maxBatchRows = 10_000_000
+
+type batchOutput struct {
+    *bufio.Writer
+    closers []func() error // 按顺序关闭
+}
+
+func createBatchOutput(path string) (*batchOutput, error) {
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        return nil, err
+    }
+    out := &batchOutput{}
+    var w io.Writer = f
+    switch {
+    case strings.HasSuffix(path, ".gz"):
+        gz := gzip.NewWriter(f)
+        w = gz
+        out.closers = append(out.closers, gz.Close)
+    case strings.HasSuffix(path, ".zst"):
+        cmd := exec.Command("zstd", "-q", "-c")
+        cmd.Stdout = f
+        cmd.Stderr = os.Stderr
+        stdin, err := cmd.StdinPipe()
+        if err != nil {
+            f.Close()
+            return nil, err
+        }
+        if err := cmd.Start(); err != nil {
+            f.Close()
+            os.Remove(path)
+            return nil, fmt.Errorf("zstd not found (install zstd or use .gz): %v", err)
+        }
+        w = stdin
+        out.closers = append(out.closers, stdin.Close, cmd.Wait)
+    }
+    out.closers = append(out.closers, f.Close)
+    out.Writer = bufio.NewWriterSize(w, 1<<16)
+    return out, nil
+}
+
+// 先刷新缓冲，再依次关闭压缩层与文件；返回第一个错误
+func (o *batchOutput) Close() error {
+    err := o.Flush()
+    for _, c := range o.closers {
+        if cerr := c(); err == nil {
+            err = cerr
+        }
+    }
+    return err
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+    "crypto/pbkdf2"
+    "crypto/sha512"
+    "fmt"
+    "log"
+    "runtime"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/fastpbkdf2"
+    "github.com/li-han-zhang/Go_passphrase/pkg/seedkdf"
+)
+
+//
+// -------------------------
+//   -bench 口令恢复速度
+// -------------------------
+//
+
+// 每项测量的时长
+const benchDuration = 2 * time.Second
+
+const benchMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func runBenchmark() {
+    // 两种实现的结果必须一致
+    salt := []byte("mnemonicTREZOR")
+    want, err := pbkdf2.Key(sha512.New, benchMnemonic, salt, 2048, 64)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if string(fastpbkdf2.Key([]byte(benchMnemonic), salt, 2048)) != string(want) {
+        log.Fatalf("Error: fastpbkdf2 does not match crypto/pbkdf2")
+    }
+
+    fmt.Printf("PBKDF2-HMAC-SHA512, 2048 rounds (%s/%s):\n", runtime.GOOS, runtime.GOARCH)
+    std := benchRate(1, func(int) {
+        pbkdf2.Key(sha512.New, benchMnemonic, salt, 2048, 64)
+    })
+    fmt.Printf("  crypto/pbkdf2   1 core   %8.0f/s\n", std)
+    fast := benchRate(1, func(int) {
+        fastpbkdf2.Key([]byte(benchMnemonic), salt, 2048)
+    })
+    fmt.Printf("  fastpbkdf2      1 core   %8.0f/s  (%.2fx)\n", fast, fast/std)
+    if lanes := fastpbkdf2.Lanes; lanes > 1 {
+        passwords, salts := make([][]byte, lanes), make([][]byte, lanes)
+        for i := range passwords {
+            passwords[i], salts[i] = []byte(benchMnemonic), salt
+        }
+        for _, key := range fastpbkdf2.Keys(passwords, salts, 2048) {
+            if string(key) != string(want) {
+                log.Fatalf("Error: vectorized fastpbkdf2 does not match crypto/pbkdf2")
+            }
+        }
+        vec := float64(lanes) * benchRate(1, func(int) {
+            fastpbkdf2.Keys(passwords, salts, 2048)
+        })
+        fmt.Printf("  fastpbkdf2 x%d   1 core   %8.0f/s  (%.2fx)\n", lanes, vec, vec/std)
+    } else {
+        fmt.Println("  (no vectorized path: build with GOEXPERIMENT=simd on amd64 and run on an AVX-512 CPU)")
+    }
+
+    // 与 -recover-passphrase 相同：种子 + 主指纹比较
+    target := &recoveryTarget{fingerprint: []byte{0, 0, 0, 0}}
+    workers := workerCount(seedkdf.Memory(seedKDF))
+    batch := seedkdf.BatchSize(seedKDF)
+    rate := float64(batch) * benchRate(workers, func(i int) {
+        candidates := make([]string, batch)
+        for j := range candidates {
+            candidates[j] = fmt.Sprint(i*batch + j)
+        }
+        for _, seed := range mnemonicToSeeds(benchMnemonic, candidates) {
+            target.matches(seed)
+        }
+    })
+    fmt.Println()
+    fmt.Printf("Recovery candidates (seed + fingerprint), %d workers: %.0f/s\n", workers, rate)
+}
+
+// 用 workers 个 goroutine 反复执行 f，返回每秒完成次数
+func benchRate(workers int, f func(i int)) float64 {
+    var (
+        count atomic.Int64
+        stop  atomic.Bool
+        wg    sync.WaitGroup
+    )
+    began := time.Now()
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for !stop.Load() {
+                f(int(count.Add(1)))
+            }
+        }()
+    }
+    time.Sleep(benchDuration)
+    stop.Store(true)
+    wg.Wait()
+    return float64(count.Load()) / time.Since(began).Seconds()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   binary.txt 加密
+// -------------------------
+//
+// binary.txt 可以用口令加密保存（scrypt + AES-GCM，格式与 -export-sealed
+// 相同，以 "PBE1" 开头）。读取时按文件头自动识别，加密的文件先询问口令，
+// 同一次运行中只问一次；-p、-q 等所有读 binary.txt 的功能因此都不用改。
+//
+//   -encrypt            就地加密已有的 binary.txt
+//   -decrypt            就地解密回明文
+//   -b -encrypt         生成时直接写加密文件（各种导入同样适用）
+//   -encrypt -dpapi     Windows：改用 DPAPI 绑定当前用户账户，不用口令
+//
+// DPAPI 文件以 "PBD1" 开头，只有同一台电脑上的同一个 Windows 用户能打开；
+// 重装系统或换电脑后就读不出来，纸质备份因此更不能少。
+//
+
+var (
+    encryptBinary    bool   // 写 binary.txt 时加密
+    binaryPassphrase string // 本次运行已输入的口令
+    useDPAPI         bool   // -dpapi，或读到的文件是 DPAPI 加密的
+)
+
+var (
+    dpapiMagic   = []byte("PBD1")
+    dpapiEntropy = []byte("passphrase_bitcoin binary.txt")
+)
+
+func isSealedBinary(data []byte) bool {
+    return bytes.HasPrefix(data, sealMagic) || bytes.HasPrefix(data, dpapiMagic)
+}
+
+// 加密的 binary.txt → 明文的位串文本
+func openBinaryText(data []byte) ([]byte, error) {
+    if !isSealedBinary(data) {
+        return data, nil
+    }
+    if bytes.HasPrefix(data, dpapiMagic) {
+        useDPAPI = true
+        return dpapiUnprotect(data[len(dpapiMagic):])
+    }
+    if binaryPassphrase == "" {
+        p, err := readSecret(fmt.Sprintf("Passphrase for %s: ", binaryPath))
+        if err != nil {
+            return nil, err
+        }
+        binaryPassphrase = p
+    }
+    plain, err := openWithPassphrase(data, binaryPassphrase)
+    if err != nil {
+        binaryPassphrase = ""
+        return nil, err
+    }
+    return plain, nil
+}
+
+func sealBinaryText(text []byte) ([]byte, error) {
+    if useDPAPI {
+        sealed, err := dpapiProtect(text)
+        if err != nil {
+            return nil, err
+        }
+        return append(append([]byte{}, dpapiMagic...), sealed...), nil
+    }
+    if binaryPassphrase == "" {
+        p, err := readNewSecret(fmt.Sprintf("New passphrase for %s: ", binaryPath))
+        if err != nil {
+            return nil, err
+        }
+        binaryPassphrase = p
+    }
+    return sealWithPassphrase(text, binaryPassphrase)
+}
+
+// -encrypt / -decrypt：就地转换，先写临时文件再改名
+func convertBinaryFile(encrypt bool) {
+    data, err := readFileLimited(binaryPath, maxBinaryFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", binaryPath, err)
+    }
+    if isSealedBinary(data) == encrypt {
+        if encrypt {
+            log.Fatalf("Error: %s is already encrypted.", binaryPath)
+        }
+        log.Fatalf("Error: %s is not encrypted.", binaryPath)
+    }
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+
+    // 解密时清空口令，写出明文；加密时重新询问新口令
+    binaryPassphrase = ""
+    encryptBinary = encrypt
+    tmp := binaryPath + ".tmp"
+    if err := writeBinaryFile(tmp, entropy); err != nil {
+        os.Remove(tmp)
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    if err := os.Rename(tmp, binaryPath); err != nil {
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    if encrypt && useDPAPI {
+        fmt.Printf("%s encrypted for this Windows user account (DPAPI); no passphrase is needed to read it.\n", binaryPath)
+        fmt.Println("Only this user on this computer can open it, not after reinstalling Windows; keep the paper backup.")
+    } else if encrypt {
+        fmt.Printf("%s encrypted. -p, -q and the other options will ask for the passphrase.\n", binaryPath)
+        fmt.Println("A forgotten passphrase cannot be recovered; keep the paper backup.")
+    } else {
+        fmt.Printf("%s decrypted; it now holds the entropy in plain text.\n", binaryPath)
+    }
+}
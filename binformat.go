@@ -0,0 +1,265 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   binary.txt 文件格式与 -migrate
+// -------------------------
+//
+// 早期的 binary.txt 只有 0/1 位串（11 位一组，每行 6 组），没有文件头，
+// 读取时忽略其他字符，抄错或截断的文件也能“读出”熵。现在的格式（v2）
+// 在位串前加两行注释：
+//
+//   # passphrase_bitcoin binary v2
+//   # bits 256 check 9f86d081 layout 11x6
+//
+// check 是位串（"0101…"）SHA-256 的前 4 字节，读取时核对位数与 check，
+// 位串行里出现 0/1/空格以外的字符也报错。加密文件（PBE1）里面存的是同样的文本。
+//
+// 旧格式照常读取，只在标准错误提示一次；-migrate 把它就地转换：原文件先
+// 原样复制到 binary.txt.bak，新文件写到临时文件，读回核对熵逐位相同后再改名。
+// 加密的旧文件转换后仍然加密（同一口令），加上 -encrypt 则同时加密。
+// 注意：旧版本的程序读不了 v2 文件，需要时用 .bak 或 -decrypt 后的旧程序。
+//
+// layout 记录位串的排版：每组位数 × 每行组数（-group-bits、-groups-per-line，
+// 默认 11×6，即每组一个单词）。排版只影响显示，读取时忽略空白；
+// 没有给这两个选项时，重写文件（-encrypt、-decrypt、-migrate）沿用文件原有的排版。
+//
+
+const (
+    binaryFormatVersion = 2
+    binaryHeaderPrefix  = "# passphrase_bitcoin binary v"
+)
+
+// 本次运行是否已提示过旧格式
+var legacyBinaryNoted bool
+
+type binaryLayout struct {
+    groupBits, groupsPerLine int
+}
+
+func (l binaryLayout) String() string {
+    return fmt.Sprintf("%dx%d", l.groupBits, l.groupsPerLine)
+}
+
+// 写 binary.txt 用的排版；bitLayoutGiven 为 false 时读文件会换成文件中记录的排版
+var (
+    bitLayout      = binaryLayout{groupBits: 11, groupsPerLine: 6}
+    bitLayoutGiven bool
+)
+
+// -group-bits、-groups-per-line
+// 每组位数、每行组数的上限；文件头里的排版同样受限
+const maxLayout = 64
+
+func setLayout(groupBits, groupsPerLine int) error {
+    if groupBits < 1 || groupBits > maxLayout || groupsPerLine < 1 || groupsPerLine > maxLayout {
+        return fmt.Errorf("-group-bits and -groups-per-line must be 1–%d", maxLayout)
+    }
+    bitLayout = binaryLayout{groupBits, groupsPerLine}
+    return nil
+}
+
+func parseLayout(s string) (binaryLayout, error) {
+    var l binaryLayout
+    a, b, ok := strings.Cut(s, "x")
+    var err1, err2 error
+    l.groupBits, err1 = strconv.Atoi(a)
+    l.groupsPerLine, err2 = strconv.Atoi(b)
+    if !ok || err1 != nil || err2 != nil || l.groupBits < 1 || l.groupBits > maxLayout || l.groupsPerLine < 1 || l.groupsPerLine > maxLayout {
+        return l, fmt.Errorf("bad layout %q", s)
+    }
+    return l, nil
+}
+
+// 按排版把位串分组、分行
+func layoutBits(bits []bool, l binaryLayout) string {
+    var b strings.Builder
+    for i := 0; i < len(bits); i += l.groupBits {
+        b.WriteString(bitString(bits[i:min(i+l.groupBits, len(bits))]))
+        if (i/l.groupBits+1)%l.groupsPerLine == 0 || i+l.groupBits >= len(bits) {
+            b.WriteByte('\n')
+        } else {
+            b.WriteByte(' ')
+        }
+    }
+    return b.String()
+}
+
+// 位串的校验值：SHA-256 的前 4 字节
+func binaryCheck(bits []bool) string {
+    sum := sha256.Sum256([]byte(bitString(bits)))
+    return hex.EncodeToString(sum[:4])
+}
+
+func binaryHeader(bits []bool) string {
+    return fmt.Sprintf("%s%d\n# bits %d check %s layout %s\n", binaryHeaderPrefix, binaryFormatVersion, len(bits), binaryCheck(bits), bitLayout)
+}
+
+// 明文的 binary.txt → 位串与格式版本（旧格式为 1）
+func parseBinaryText(data []byte) ([]bool, int, error) {
+    first, _, _ := strings.Cut(strings.TrimLeft(string(data), " \t\r\n"), "\n")
+    if !strings.HasPrefix(first, "#") {
+        // 旧格式：只取 0 和 1
+        var bits []bool
+        for _, c := range string(data) {
+            if c == '0' {
+                bits = append(bits, false)
+            } else if c == '1' {
+                bits = append(bits, true)
+            }
+        }
+        return bits, 1, nil
+    }
+
+    v, ok := strings.CutPrefix(strings.TrimSpace(first), binaryHeaderPrefix)
+    version, err := strconv.Atoi(v)
+    if !ok || err != nil {
+        return nil, 0, fmt.Errorf("unrecognised header %q", strings.TrimSpace(first))
+    }
+    if version > binaryFormatVersion {
+        return nil, 0, fmt.Errorf("format v%d was written by a newer version of this program", version)
+    }
+
+    var bits []bool
+    count, check := -1, ""
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for n := 1; scanner.Scan(); n++ {
+        line := strings.TrimSpace(scanner.Text())
+        if comment, ok := strings.CutPrefix(line, "#"); ok {
+            fields := strings.Fields(comment)
+            for i := 0; i+1 < len(fields); i += 2 {
+                switch fields[i] {
+                case "bits":
+                    if count, err = strconv.Atoi(fields[i+1]); err != nil {
+                        return nil, 0, fmt.Errorf("line %d: bad bit count %q", n, fields[i+1])
+                    }
+                case "check":
+                    check = fields[i+1]
+                case "layout":
+                    l, err := parseLayout(fields[i+1])
+                    if err != nil {
+                        return nil, 0, fmt.Errorf("line %d: %v", n, err)
+                    }
+                    if !bitLayoutGiven {
+                        bitLayout = l
+                    }
+                }
+            }
+            continue
+        }
+        for _, c := range line {
+            switch c {
+            case '0':
+                bits = append(bits, false)
+            case '1':
+                bits = append(bits, true)
+            case ' ', '\t':
+            default:
+                return nil, 0, fmt.Errorf("line %d: unexpected character %q", n, c)
+            }
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, 0, err
+    }
+    if count < 0 || check == "" {
+        return nil, 0, errors.New("header has no bit count or check")
+    }
+    if len(bits) != count {
+        return nil, 0, fmt.Errorf("%d bits found, header says %d (truncated or edited file)", len(bits), count)
+    }
+    if !secretcmp.EqualString(binaryCheck(bits), check) {
+        return nil, 0, errors.New("check value does not match the bits (corrupted or edited file)")
+    }
+    return bits, version, nil
+}
+
+func noteLegacyBinary(filename string) {
+    if legacyBinaryNoted {
+        return
+    }
+    legacyBinaryNoted = true
+    fmt.Fprintf(os.Stderr, "Note: %s is in the old format without a header; run -migrate to convert it (a backup is kept).\n", filename)
+}
+
+// -migrate：旧格式就地转换为 v2，原文件备份为 .bak
+func migrateBinaryFile() {
+    data, err := readFileLimited(binaryPath, maxBinaryFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", binaryPath, err)
+    }
+    sealed := isSealedBinary(data)
+    plain, err := openBinaryText(data)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", binaryPath, err)
+    }
+    bits, version, err := parseBinaryText(plain)
+    if err != nil {
+        log.Fatalf("Error: %s: %v", binaryPath, err)
+    }
+    if version == binaryFormatVersion && (sealed || !encryptBinary) && !bitLayoutGiven {
+        fmt.Printf("%s is already in format v%d; nothing to do.\n", binaryPath, binaryFormatVersion)
+        return
+    }
+    if _, err := wordCountForEntropyBits(len(bits)); err != nil {
+        log.Fatalf("Error: %s: %v; fix the file by hand (compare with your paper backup) before migrating.", binaryPath, err)
+    }
+
+    // 先原样备份；已有备份时不覆盖
+    backup := binaryPath + ".bak"
+    f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error: backup %s: %v (move the old backup away first)", backup, err)
+    }
+    if _, err := f.Write(data); err != nil {
+        f.Close()
+        log.Fatalf("Error writing %s: %v", backup, err)
+    }
+    if err := f.Close(); err != nil {
+        log.Fatalf("Error writing %s: %v", backup, err)
+    }
+
+    // 加密的文件保持加密（口令不变）
+    encryptBinary = encryptBinary || sealed
+    tmp := binaryPath + ".tmp"
+    if err := writeBinaryFile(tmp, bip39.BitsToBytes(bits)); err != nil {
+        os.Remove(tmp)
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    back, err := readBinaryFile(tmp)
+    if err != nil || !secretcmp.EqualBits(back, bits) {
+        os.Remove(tmp)
+        log.Fatalf("Error: the converted file does not read back the same entropy; %s is unchanged.", binaryPath)
+    }
+    if err := os.Rename(tmp, binaryPath); err != nil {
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+
+    state := "plain text"
+    if encryptBinary {
+        state = "encrypted"
+    }
+    done := fmt.Sprintf("migrated from format v%d to v%d", version, binaryFormatVersion)
+    if version == binaryFormatVersion {
+        done = "rewritten"
+    }
+    fmt.Printf("%s %s (%s, %d bits, check %s, layout %s).\n", binaryPath, done, state, len(bits), binaryCheck(bits), bitLayout)
+    fmt.Printf("The original file is kept as %s; delete it securely once -p shows the same words.\n", backup)
+    transcript.record("migrate binary.txt", "ok", "", fmt.Sprintf("v%d -> v%d", version, binaryFormatVersion))
+}
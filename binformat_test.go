@@ -0,0 +1,53 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestParseLayout(t *testing.T) {
+    for _, s := range []string{"11x6", "1x1", "64x64", "8x4"} {
+        if _, err := parseLayout(s); err != nil {
+            t.Errorf("parseLayout(%q): %v", s, err)
+        }
+    }
+    for _, s := range []string{"", "11", "0x6", "11x0", "65x6", "11x65", "99999999x1", "-1x6", "axb"} {
+        if l, err := parseLayout(s); err == nil {
+            t.Errorf("parseLayout(%q) = %v, want an error", s, l)
+        }
+    }
+}
+
+// 文件头写出的排版必须能读回
+func TestBinaryHeaderLayout(t *testing.T) {
+    saved := bitLayout
+    defer func() { bitLayout = saved }()
+    if err := setLayout(8, 4); err != nil {
+        t.Fatal(err)
+    }
+    bits := make([]bool, 128)
+    text := binaryHeader(bits) + layoutBits(bits, bitLayout)
+    got, version, err := parseBinaryText([]byte(text))
+    if err != nil || version != binaryFormatVersion || len(got) != len(bits) {
+        t.Fatalf("parseBinaryText = %d bits, v%d, %v", len(got), version, err)
+    }
+}
+
+// 已存在的文件（例如 0644）被覆盖后也必须是 0600
+func TestWriteBinaryFileMode(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "binary.txt")
+    if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := writeBinaryFile(path, make([]byte, 16)); err != nil {
+        t.Fatal(err)
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if mode := info.Mode().Perm(); mode != 0600 {
+        t.Errorf("mode = %o, want 600", mode)
+    }
+}
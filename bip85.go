@@ -0,0 +1,192 @@
+package main
+
+import (
+    "crypto/hmac"
+    "crypto/sha512"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+)
+
+//
+// -------------------------
+//   -device BIP85 设备子种子
+// -------------------------
+//
+// 每台硬件钱包用 binary.txt 派生出的独立 BIP85 子助记词：
+// m/83696968'/39'/{语言}'/{单词数}'/{序号}'，语言代号取自 -lang
+// （0' = 英文），与编码单词所用的单词表一致。
+// 一台设备泄露只影响它自己；子助记词随时可以由主助记词重新算出。
+// devices.txt 只记录 设备名 → 序号 的对应关系，不含秘密。
+//
+
+const devicesFile = "devices.txt"
+
+type deviceEntry struct {
+    name        string
+    index       int
+    words       int
+    fingerprint string
+}
+
+// BIP85 规定的 BIP39 语言代号
+var bip85Languages = map[string]int{
+    "english":             0,
+    "japanese":            1,
+    "korean":              2,
+    "spanish":             3,
+    "chinese_simplified":  4,
+    "chinese_traditional": 5,
+    "french":              6,
+    "italian":             7,
+    "czech":               8,
+    "portuguese":          9,
+}
+
+func bip85Path(lang string, words, index int) (string, error) {
+    code, ok := bip85Languages[lang]
+    if !ok {
+        return "", fmt.Errorf("BIP85 has no language code for %q", lang)
+    }
+    return fmt.Sprintf("m/83696968'/39'/%d'/%d'/%d'", code, words, index), nil
+}
+
+// BIP85：k = 派生路径上的私钥，熵 = HMAC-SHA512("bip-entropy-from-k", k) 的前缀
+func bip85Mnemonic(master *bip32.Key, lang string, words, index int, wordList []string) (string, error) {
+    var nBytes int
+    switch words {
+    case 12:
+        nBytes = 16
+    case 18:
+        nBytes = 24
+    case 24:
+        nBytes = 32
+    default:
+        return "", fmt.Errorf("BIP85 supports 12, 18 or 24 words, not %d", words)
+    }
+    path, err := bip85Path(lang, words, index)
+    if err != nil {
+        return "", err
+    }
+    child, err := master.Derive(path)
+    if err != nil {
+        return "", err
+    }
+    mac := hmac.New(sha512.New, []byte("bip-entropy-from-k"))
+    mac.Write(child.Key)
+    return mnemonicFromEntropy(mac.Sum(nil)[:nBytes], wordList), nil
+}
+
+func showDeviceSeed(name string, words int, wordList []string) {
+    name = strings.TrimSpace(name)
+    if name == "" || strings.ContainsAny(name, "\t\n") {
+        log.Fatalf("Error: invalid device name %q", name)
+    }
+    if customWordlist != "" {
+        log.Fatalf("Error: -device needs an official BIP39 list (-lang); BIP85 has no code for -wordlist")
+    }
+    devices, err := readDevices()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", devicesFile, err)
+    }
+
+    // 已登记的设备沿用原序号与单词数
+    entry, known := deviceEntry{}, false
+    next := 0
+    for _, d := range devices {
+        if d.name == name {
+            entry, known = d, true
+        }
+        next = max(next, d.index+1)
+    }
+    if !known {
+        entry = deviceEntry{name: name, index: next, words: words}
+    }
+
+    master := bip39Master(generatePassphraseFromBinary(wordList))
+    mnemonic, err := bip85Mnemonic(master, phraseLanguage, entry.words, entry.index, wordList)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    path, _ := bip85Path(phraseLanguage, entry.words, entry.index)
+    fp := masterFingerprint(mnemonic)
+
+    if known && fp != entry.fingerprint {
+        log.Fatalf("Error: %s was recorded with fingerprint %s but now derives %s; is binary.txt the right one, and -lang the same?", name, entry.fingerprint, fp)
+    }
+    if !known && !demoMode {
+        entry.fingerprint = fp
+        if err := appendDevice(entry); err != nil {
+            log.Fatalf("Error writing %s: %v", devicesFile, err)
+        }
+        fmt.Printf("New device %q recorded in %s.\n", name, devicesFile)
+    }
+
+    fmt.Printf("Device:      %s\n", name)
+    fmt.Printf("Path:        %s\n", path)
+    fmt.Printf("Fingerprint: %s\n", fp)
+    fmt.Println("Passphrase:")
+    printPhrase(mnemonic)
+    p := newDerivationParams(nil, path)
+    p.Extra = fmt.Sprintf(`BIP85: HMAC-SHA512 with key "bip-entropy-from-k" over the child private key, first %d bytes as entropy`, entry.words*4/3)
+    p.print()
+}
+
+func printDevices() {
+    devices, err := readDevices()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", devicesFile, err)
+    }
+    if len(devices) == 0 {
+        fmt.Printf("%s is empty or missing.\n", devicesFile)
+        return
+    }
+    fmt.Println("Index  Words  Fingerprint  Device")
+    for _, d := range devices {
+        fmt.Printf("%5d  %5d  %-11s  %s\n", d.index, d.words, d.fingerprint, d.name)
+    }
+}
+
+// 每行：序号、单词数、子种子主指纹、设备名（制表符分隔）
+func readDevices() ([]deviceEntry, error) {
+    data, err := readFileLimited(devicesFile, maxTextFileSize)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var devices []deviceEntry
+    for n, line := range strings.Split(string(data), "\n") {
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+        fields := strings.Split(line, "\t")
+        if len(fields) != 4 {
+            return nil, fmt.Errorf("line %d: expected 4 tab-separated fields", n+1)
+        }
+        index, err1 := strconv.Atoi(fields[0])
+        words, err2 := strconv.Atoi(fields[1])
+        if err1 != nil || err2 != nil {
+            return nil, fmt.Errorf("line %d: bad number", n+1)
+        }
+        devices = append(devices, deviceEntry{name: fields[3], index: index, words: words, fingerprint: fields[2]})
+    }
+    return devices, nil
+}
+
+func appendDevice(d deviceEntry) error {
+    f, err := os.OpenFile(devicesFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+    if err != nil {
+        return err
+    }
+    if _, err := fmt.Fprintf(f, "%d\t%d\t%s\t%s\n", d.index, d.words, d.fingerprint, d.name); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+// BIP85 规范中 BIP39 应用的测试向量（英文，序号 0）
+func TestBIP85Vectors(t *testing.T) {
+    master, _, err := bip32.Parse("xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb")
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        words int
+        want  string
+    }{
+        {12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+        {18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+        {24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+    }
+    for _, tt := range tests {
+        got, err := bip85Mnemonic(master, "english", tt.words, 0, bip39.English())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got != tt.want {
+            t.Errorf("%d words: %q, want %q", tt.words, got, tt.want)
+        }
+    }
+}
+
+// 路径中的语言代号跟随 -lang
+func TestBIP85Path(t *testing.T) {
+    tests := []struct {
+        lang string
+        want string
+    }{
+        {"english", "m/83696968'/39'/0'/12'/0'"},
+        {"japanese", "m/83696968'/39'/1'/12'/0'"},
+        {"chinese_traditional", "m/83696968'/39'/5'/12'/0'"},
+        {"czech", "m/83696968'/39'/8'/12'/0'"},
+    }
+    for _, tt := range tests {
+        got, err := bip85Path(tt.lang, 12, 0)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got != tt.want {
+            t.Errorf("bip85Path(%s) = %s, want %s", tt.lang, got, tt.want)
+        }
+    }
+    if _, err := bip85Path("klingon", 12, 0); err == nil {
+        t.Error("bip85Path accepted an unknown language")
+    }
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+)
+
+//
+// -------------------------
+//   钱包生日
+// -------------------------
+//
+// -b 生成 binary.txt 时把创建时间写进 birthday.txt（主指纹、UTC 时间、估计区块高度），
+// 不含秘密。钱包软件重新扫描时从生日开始，不必从创世区块扫起。
+// 导入的助记词不知道生日，可以用 -set-birthday YYYY-MM-DD 补记第一次使用的日期。
+// 主指纹与 binary.txt 不符（换了助记词）时生日作废。
+//
+// 区块高度是占位的估计值：从最近的减半区块按每 10 分钟一块推算，再减去两周
+// （2016 块）的余量。实际出块略快于 10 分钟，所以估计值只会偏低，从那里扫不会漏交易。
+//
+
+const birthdayFile = "birthday.txt"
+
+// 减半区块的高度与时间戳，用作推算高度的锚点
+var heightAnchors = []struct {
+    height int
+    time   int64
+}{
+    {0, 1231006505},
+    {210000, 1354116278},
+    {420000, 1468082773},
+    {630000, 1589225023},
+    {840000, 1713571767},
+}
+
+type walletBirthday struct {
+    Fingerprint string
+    Time        time.Time
+    Height      int
+}
+
+// 不晚于 t 的区块高度下限
+func estimateHeight(t time.Time) int {
+    a := heightAnchors[0]
+    for _, anchor := range heightAnchors {
+        if t.Unix() >= anchor.time {
+            a = anchor
+        }
+    }
+    return max(a.height+int((t.Unix()-a.time)/600)-2016, 0)
+}
+
+func recordBirthday(fingerprint string, t time.Time) error {
+    t = t.UTC().Truncate(time.Second)
+    content := fmt.Sprintf("# Wallet birthday for binary.txt (no secrets)\nfingerprint %s\ntime %s\nheight %d\n",
+        fingerprint, t.Format(time.RFC3339), estimateHeight(t))
+    return os.WriteFile(birthdayFile, []byte(content), 0644)
+}
+
+// 没有记录或属于别的助记词时返回 nil
+func loadBirthday(fingerprint string) (*walletBirthday, error) {
+    data, err := readFileLimited(birthdayFile, maxTextFileSize)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    b := &walletBirthday{}
+    for i, line := range strings.Split(string(data), "\n") {
+        key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+        switch key {
+        case "", "#":
+        case "fingerprint":
+            b.Fingerprint = value
+        case "time":
+            if b.Time, err = time.Parse(time.RFC3339, value); err != nil {
+                return nil, fmt.Errorf("%s line %d: %v", birthdayFile, i+1, err)
+            }
+        case "height":
+            if b.Height, err = strconv.Atoi(value); err != nil {
+                return nil, fmt.Errorf("%s line %d: %v", birthdayFile, i+1, err)
+            }
+        default:
+            return nil, fmt.Errorf("%s line %d: unknown field '%s'", birthdayFile, i+1, key)
+        }
+    }
+    if b.Fingerprint != fingerprint {
+        return nil, nil
+    }
+    return b, nil
+}
+
+func (b *walletBirthday) String() string {
+    return fmt.Sprintf("%s (block height ≥ %d, estimated)", b.Time.Format("2006-01-02 15:04 MST"), b.Height)
+}
+
+func birthdayOf(mnemonic string) *walletBirthday {
+    b, err := loadBirthday(masterFingerprint(mnemonic))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    return b
+}
+
+func printBirthday(wordList []string) {
+    b := birthdayOf(generatePassphraseFromBinary(wordList))
+    if b == nil {
+        fmt.Printf("No birthday recorded for binary.txt in %s.\n", birthdayFile)
+        fmt.Println("Record the date of first use with -set-birthday YYYY-MM-DD; until then wallets must rescan from the genesis block.")
+        return
+    }
+    fmt.Println("Wallet birthday:", b)
+}
+
+func setBirthday(date string, wordList []string) {
+    t, err := time.Parse("2006-01-02", date)
+    if err != nil {
+        log.Fatalf("Error: -set-birthday: expected YYYY-MM-DD, got '%s'", date)
+    }
+    if t.After(time.Now()) {
+        log.Fatalf("Error: -set-birthday: %s is in the future", date)
+    }
+    fp := masterFingerprint(generatePassphraseFromBinary(wordList))
+    if err := recordBirthday(fp, t); err != nil {
+        log.Fatalf("Error writing %s: %v", birthdayFile, err)
+    }
+    printBirthday(wordList)
+}
+
+//
+// -------------------------
+//   -descriptors 描述符导出
+// -------------------------
+//
+// 以 Bitcoin Core importdescriptors 的 JSON 格式输出账户的观察钱包描述符
+// （BIP44 pkh、49 sh(wpkh)、84 wpkh、86 tr，收款与找零各一条），
+// "timestamp" 为钱包生日，未记录时为 0（从创世区块扫描）。只含公钥。
+//
+
+type coreDescriptor struct {
+    Desc      string `json:"desc"`
+    Timestamp int64  `json:"timestamp"`
+    Active    bool   `json:"active"`
+    Internal  bool   `json:"internal"`
+    Range     [2]int `json:"range"`
+}
+
+var descriptorFamilies = []struct {
+    purpose     int
+    open, close string
+}{
+    {44, "pkh(", ")"},
+    {49, "sh(wpkh(", "))"},
+    {84, "wpkh(", ")"},
+    {86, "tr(", ")"},
+}
+
+func exportDescriptors(account int, mnemonicIn string, wordList []string) {
+    if account < 0 || int64(account) >= 1<<31 {
+        log.Fatalf("Error: -account must be 0–%d", 1<<31-1)
+    }
+    mnemonic := selectedMnemonic(mnemonicIn, wordList)
+    birthday := birthdayOf(mnemonic)
+    master, err := bip32.NewMaster(seedWithPrompt(mnemonic))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fp := master.Fingerprint()
+
+    var timestamp int64
+    if birthday != nil {
+        timestamp = birthday.Time.Unix()
+        fmt.Fprintln(os.Stderr, "Wallet birthday:", birthday)
+    } else {
+        fmt.Fprintf(os.Stderr, "No birthday recorded in %s: \"timestamp\" is 0, wallets will rescan from the genesis block.\n", birthdayFile)
+    }
+
+    var descs []coreDescriptor
+    for _, f := range descriptorFamilies {
+        acct, err := master.Derive(fmt.Sprintf("m/%d'/0'/%d'", f.purpose, account))
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        xpub := acct.Neuter().Serialize(bip32.VersionXPub)
+        for branch := 0; branch <= 1; branch++ {
+            body := fmt.Sprintf("%s[%x/%dh/0h/%dh]%s/%d/*%s", f.open, fp, f.purpose, account, xpub, branch, f.close)
+            sum, err := descriptorChecksum(body)
+            if err != nil {
+                log.Fatalf("Error: %v", err)
+            }
+            descs = append(descs, coreDescriptor{
+                Desc:      body + "#" + sum,
+                Timestamp: timestamp,
+                Active:    true,
+                Internal:  branch == 1,
+                Range:     [2]int{0, 999},
+            })
+        }
+    }
+    data, err := json.MarshalIndent(descs, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fmt.Println(string(data))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   两台机器的备份核对
+// -------------------------
+//
+// 机器 A 生成 binary.txt 后，用 -bundle-export FILE 写出一份核对包：
+// 单词数、主指纹、binary.txt 的校验值（与文件头中的相同）以及
+// 44/49/84/86 各自的前 3 个收款地址。核对包不含单词、熵和 xpub。
+// 把它（例如用 U 盘或二维码）带到机器 B，在 B 上只凭纸上抄下的单词运行
+// -bundle-verify FILE：B 独立计算同样的内容并逐项比对。全部一致说明
+// 纸上的单词就是 A 生成的那一组，而且两台机器的实现得出相同的地址。
+// 地址只对应不带 BIP39 口令的钱包；核对包仍会暴露这几个地址，请妥善保管。
+//
+
+const bundleAddressCount = 3
+
+type verifyBundle struct {
+    Version     int              `json:"version"`
+    Created     string           `json:"created"`
+    WordCount   int              `json:"word_count"`
+    Fingerprint string           `json:"fingerprint"`
+    Check       string           `json:"check"`
+    Accounts    []bundleAccount  `json:"accounts"`
+    Params      derivationParams `json:"params"`
+}
+
+type bundleAccount struct {
+    Type    string   `json:"type"`
+    Path    string   `json:"path"`
+    Receive []string `json:"receive"`
+}
+
+var bundleTypes = []btcaddr.Type{btcaddr.P2PKH, btcaddr.P2SHP2WPKH, btcaddr.P2WPKH, btcaddr.P2TR}
+
+// 助记词 → 核对包中可公开的部分
+func makeBundle(mnemonic string, entropy []byte) (*verifyBundle, error) {
+    b := &verifyBundle{
+        Version:     1,
+        Created:     time.Now().UTC().Format(time.RFC3339),
+        WordCount:   len(entropy) * 3 / 4,
+        Fingerprint: masterFingerprint(mnemonic),
+        Check:       binaryCheck(bip39.BytesToBits(entropy)),
+        Params:      newDerivationParams(nil, "m/44'/0'/0'/0/i", "m/49'/0'/0'/0/i", "m/84'/0'/0'/0/i", "m/86'/0'/0'/0/i"),
+    }
+    master := bip39Master(mnemonic)
+    for _, t := range bundleTypes {
+        a, err := rotationAccountFor(master, t, bundleAddressCount, false)
+        if err != nil {
+            return nil, err
+        }
+        b.Accounts = append(b.Accounts, bundleAccount{Type: a.Type, Path: a.Path, Receive: a.Receive})
+    }
+    return b, nil
+}
+
+// 机器 A：从 binary.txt 写出核对包
+func exportBundle(path string, wordList []string) {
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    b, err := makeBundle(mnemonic, entropy)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    data, err := json.MarshalIndent(b, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error creating %s: %v", path, err)
+    }
+    _, err = f.Write(append(data, '\n'))
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    if err != nil {
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+
+    fmt.Printf("Verification bundle written to %s (%d words, fingerprint %s, check %s).\n", path, b.WordCount, b.Fingerprint, b.Check)
+    fmt.Println("It holds no words, entropy or xpubs. Take it to a second machine and run:")
+    fmt.Printf("  passphrase_bitcoin -bundle-verify %s\n", path)
+    fmt.Println("there, typing the words from your paper backup, not from this screen.")
+    transcript.record("bundle export", "ok", b.Fingerprint, path)
+}
+
+// 机器 B：只凭抄下的单词核对
+func verifyBundleFile(path, mnemonicIn string, wordList []string) {
+    data, err := readFileLimited(path, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", path, err)
+    }
+    var want verifyBundle
+    if err := json.Unmarshal(data, &want); err != nil {
+        log.Fatalf("Error: %s: %v", path, err)
+    }
+    if want.Version != 1 {
+        log.Fatalf("Error: %s: unsupported version %d", path, want.Version)
+    }
+
+    if mnemonicIn == "" {
+        fmt.Fprintln(os.Stderr, "Type the words from the paper backup (hidden).")
+        if mnemonicIn, err = readSecret("Mnemonic: "); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+    }
+    entropy, err := entropyFromPhrase(mnemonicIn, wordList)
+    if err != nil {
+        log.Fatalf("Error: mnemonic: %v", err)
+    }
+    got, err := makeBundle(mnemonicFromEntropy(entropy, wordList), entropy)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    var results []selftestResult
+    check := func(name string, ok bool, detail string) {
+        results = append(results, selftestResult{name: name, detail: detail, ok: ok})
+    }
+    check("words", got.WordCount == want.WordCount, fmt.Sprintf("%d typed, %d in bundle", got.WordCount, want.WordCount))
+    check("fingerprint", secretcmp.EqualString(got.Fingerprint, want.Fingerprint), want.Fingerprint)
+    check("check", secretcmp.EqualString(got.Check, want.Check), want.Check)
+    for i, a := range got.Accounts {
+        ok := i < len(want.Accounts) && want.Accounts[i].Path == a.Path &&
+            secretcmp.EqualString(strings.Join(a.Receive, " "), strings.Join(want.Accounts[i].Receive, " "))
+        check(a.Type, ok, fmt.Sprintf("%s/0/0–%d", a.Path, len(a.Receive)-1))
+    }
+    if len(want.Accounts) != len(got.Accounts) {
+        check("accounts", false, fmt.Sprintf("%d in bundle, expected %d", len(want.Accounts), len(got.Accounts)))
+    }
+
+    passed := true
+    for _, r := range results {
+        passed = passed && r.ok
+        fmt.Printf("[%s] %-12s %s\n", passFail(r.ok), r.name, r.detail)
+    }
+    fmt.Println()
+    fmt.Println("Bundle check", passFail(passed))
+    if passed {
+        fmt.Println("The written-down words are the ones generated on the first machine.")
+    } else {
+        fmt.Println("The written-down words do not match the bundle: check the paper backup word by word against the first machine.")
+    }
+
+    transcript.record("bundle verify", passFail(passed), got.Fingerprint, path)
+    if !passed {
+        transcript.finish()
+        os.Exit(1)
+    }
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+    "crypto/sha256"
+    "fmt"
+    "math"
+    "strings"
+)
+
+//
+// -------------------------
+//   -b -cards 扑克牌洗牌熵
+// -------------------------
+//
+// 输入洗好的牌的顺序，如 "AS 7H KD ..."。牌面 A 2–9 T(10) J Q K，花色 S H D C
+// （也接受 ♠♥♦♣，大小写不限）。每 52 张为一副，一副之内不能重复；最后一副
+// 可以不完整。熵按实际排列数计算：一副中已翻开 k 张时为 log2(52!/(52−k)!)，
+// 一整副约 225.6 位，所以 24 个词（256 位）需要两副牌的前 58 张左右。
+// 不够所需位数时拒绝。规范化后的牌序经 SHA-256 取前 size 字节作为熵。
+//
+
+const deckSize = 52
+
+var cardRanks = "A23456789TJQK"
+var cardSuits = map[rune]byte{'S': 'S', 'H': 'H', 'D': 'D', 'C': 'C', '♠': 'S', '♥': 'H', '♦': 'D', '♣': 'C'}
+
+// 规范写法如 "AS"、"TH"
+func parseCard(s string) (string, error) {
+    s = strings.ToUpper(s)
+    s = strings.Replace(s, "10", "T", 1)
+    r := []rune(s)
+    if len(r) != 2 || !strings.ContainsRune(cardRanks, r[0]) {
+        return "", fmt.Errorf("'%s' is not a card (rank A 2-9 T J Q K, suit S H D C)", s)
+    }
+    suit, ok := cardSuits[r[1]]
+    if !ok {
+        return "", fmt.Errorf("'%s' is not a card (rank A 2-9 T J Q K, suit S H D C)", s)
+    }
+    return string([]byte{byte(r[0]), suit}), nil
+}
+
+// 返回规范化的牌序与其熵（位）
+func parseCardSequence(s string) ([]string, float64, error) {
+    fields := strings.FieldsFunc(s, func(r rune) bool {
+        return r == ' ' || r == ',' || r == '\n' || r == '\t' || r == '\r'
+    })
+    cards := make([]string, 0, len(fields))
+    bits := 0.0
+    var seen map[string]bool
+    for i, f := range fields {
+        c, err := parseCard(f)
+        if err != nil {
+            return nil, 0, fmt.Errorf("card %d: %v", i+1, err)
+        }
+        pos := i % deckSize
+        if pos == 0 {
+            seen = map[string]bool{}
+        }
+        if seen[c] {
+            return nil, 0, fmt.Errorf("card %d: %s appears twice in deck %d", i+1, c, i/deckSize+1)
+        }
+        seen[c] = true
+        cards = append(cards, c)
+        bits += math.Log2(float64(deckSize - pos))
+    }
+    return cards, bits, nil
+}
+
+func cardEntropy(s string, size int) ([]byte, error) {
+    cards, bits, err := parseCardSequence(s)
+    if err != nil {
+        return nil, err
+    }
+    if bits < float64(size*8) {
+        return nil, fmt.Errorf("%d cards give %.1f bits of entropy, %d are needed (%d more cards)",
+            len(cards), bits, size*8, cardsNeeded(len(cards), float64(size*8)-bits))
+    }
+    sum := sha256.Sum256([]byte(strings.Join(cards, " ")))
+    fmt.Printf("Cards: %d (%.1f bits of entropy, %d used)\n", len(cards), bits, size*8)
+    return sum[:size], nil
+}
+
+// 在已有 have 张牌之后，再翻几张才能多得 missing 位
+func cardsNeeded(have int, missing float64) int {
+    n := 0
+    for missing > 0 {
+        missing -= math.Log2(float64(deckSize - (have+n)%deckSize))
+        n++
+    }
+    return n
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "maps"
+    "os"
+)
+
+//
+// -------------------------
+//   长时间任务的加密断点
+// -------------------------
+//
+// 断点内容（任务参数、进度）为 JSON，用口令加密（scrypt + AES-GCM）后写盘，
+// 重启后以 -resume 继续。
+//
+
+type jobCheckpoint struct {
+    Kind   string            `json:"kind"`
+    Params map[string]string `json:"params"`
+    Next   uint64            `json:"next"`
+    Total  uint64            `json:"total"`
+}
+
+type checkpointFile struct {
+    path       string
+    passphrase string
+    job        jobCheckpoint
+}
+
+// 新任务：断点文件不能已存在；-resume：解密并确认是同一个任务
+func openCheckpoint(path string, resume bool, job jobCheckpoint) (*checkpointFile, uint64, error) {
+    c := &checkpointFile{path: path, job: job}
+
+    data, err := readFileLimited(path, maxTextFileSize)
+    switch {
+    case errors.Is(err, os.ErrNotExist):
+        if resume {
+            return nil, 0, fmt.Errorf("checkpoint %s not found, nothing to resume", path)
+        }
+        if c.passphrase, err = readNewSecret("Checkpoint passphrase: "); err != nil {
+            return nil, 0, err
+        }
+        return c, 0, nil
+    case err != nil:
+        return nil, 0, err
+    case !resume:
+        return nil, 0, fmt.Errorf("checkpoint %s exists, pass -resume to continue it", path)
+    }
+
+    if c.passphrase, err = readSecret("Checkpoint passphrase: "); err != nil {
+        return nil, 0, err
+    }
+    plain, err := openWithPassphrase(data, c.passphrase)
+    if err != nil {
+        return nil, 0, fmt.Errorf("checkpoint %s: %v", path, err)
+    }
+    var saved jobCheckpoint
+    if err := json.Unmarshal(plain, &saved); err != nil {
+        return nil, 0, fmt.Errorf("checkpoint %s: %v", path, err)
+    }
+    if saved.Kind != job.Kind || !maps.Equal(saved.Params, job.Params) || saved.Total != job.Total {
+        return nil, 0, fmt.Errorf("checkpoint %s belongs to a different search", path)
+    }
+    return c, saved.Next, nil
+}
+
+func (c *checkpointFile) save(next uint64) error {
+    c.job.Next = next
+    plain, err := json.Marshal(c.job)
+    if err != nil {
+        return err
+    }
+    sealed, err := sealWithPassphrase(plain, c.passphrase)
+    if err != nil {
+        return err
+    }
+    tmp := c.path + ".tmp"
+    if err := writeSecretFile(tmp, sealed); err != nil {
+        return err
+    }
+    return os.Rename(tmp, c.path)
+}
+
+func (c *checkpointFile) remove() {
+    os.Remove(c.path)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "os/exec"
+    "os/signal"
+    "strings"
+    "syscall"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   -clip 剪贴板输出与定时清除
+// -------------------------
+//
+// -p -clip 与 -seed -clip 把助记词或种子复制到系统剪贴板而不打印，
+// 等待 -clip-timeout（默认 30 秒）后清空；Ctrl-C 会提前清空。
+// 清空前先读回剪贴板，内容已被换成别的时不动它。文本经标准输入交给
+// wl-copy、xclip、xsel、pbcopy 或 PowerShell，不出现在命令行参数里。
+// 剪贴板管理器（KDE Klipper、Windows 剪贴板历史等）可能另存一份，
+// 这里清不到，所以每次都提醒。
+//
+
+const maxClipTimeout = time.Hour
+
+var (
+    clipOutput  bool
+    clipTimeout time.Duration
+)
+
+type clipboardTool struct {
+    copy  []string
+    clear []string // 空：复制一个空字符串
+}
+
+// 顺序与 readClipboard 相同，读回时用的是同一个剪贴板
+var clipboardTools = []clipboardTool{
+    {copy: []string{"wl-copy"}, clear: []string{"wl-copy", "--clear"}},
+    {copy: []string{"xclip", "-i", "-selection", "clipboard"}},
+    {copy: []string{"xsel", "--clipboard", "--input"}, clear: []string{"xsel", "--clipboard", "--clear"}},
+    {copy: []string{"pbcopy"}},
+    {copy: []string{"powershell", "-NoProfile", "-NonInteractive", "-Command",
+        "[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"}},
+}
+
+func findClipboardTool() (clipboardTool, error) {
+    for _, t := range clipboardTools {
+        if _, err := exec.LookPath(t.copy[0]); err == nil {
+            return t, nil
+        }
+    }
+    return clipboardTool{}, fmt.Errorf("no wl-copy, xclip, xsel, pbcopy or powershell found")
+}
+
+func runClipboardTool(args []string, input string) error {
+    cmd := exec.Command(args[0], args[1:]...)
+    cmd.Stdin = strings.NewReader(input)
+    if out, err := cmd.CombinedOutput(); err != nil {
+        if msg := strings.TrimSpace(string(out)); msg != "" {
+            return fmt.Errorf("%s: %v (%s)", args[0], err, msg)
+        }
+        return fmt.Errorf("%s: %v", args[0], err)
+    }
+    return nil
+}
+
+// 复制 secret，等到超时或 Ctrl-C 后清除；what 用于提示，如 "Passphrase"
+func clipSecret(what, secret string) {
+    tool, err := findClipboardTool()
+    if err != nil {
+        log.Fatalf("Error: -clip: %v", err)
+    }
+    if err := runClipboardTool(tool.copy, secret); err != nil {
+        log.Fatalf("Error: -clip: %v", err)
+    }
+    fmt.Printf("%s copied to the clipboard; it will be cleared in %s (Ctrl-C clears it now).\n", what, clipTimeout)
+    fmt.Println("Clipboard managers and clipboard history may keep their own copy; clear those too.")
+
+    c := make(chan os.Signal, 1)
+    signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+    select {
+    case <-time.After(clipTimeout):
+    case <-c:
+    }
+    signal.Stop(c)
+    clearClipboard(tool, secret)
+}
+
+func clearClipboard(tool clipboardTool, secret string) {
+    if r, err := readClipboard(); err == nil {
+        data, _ := io.ReadAll(io.LimitReader(r, int64(len(secret))+2))
+        r.Close()
+        if !secretcmp.EqualString(strings.TrimRight(string(data), "\r\n"), secret) {
+            fmt.Println("The clipboard was changed in the meantime; left as it is.")
+            return
+        }
+    }
+    args := tool.clear
+    if args == nil {
+        args = tool.copy
+    }
+    if err := runClipboardTool(args, ""); err != nil {
+        log.Fatalf("Error: could not clear the clipboard, clear it by hand: %v", err)
+    }
+    fmt.Println("Clipboard cleared.")
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+    "crypto/rand"
+    "flag"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   子命令的选项与执行
+// -------------------------
+//
+// 每个子命令在自己的 FlagSet 上定义自己的选项，值只属于这一次调用。
+// 执行时把它们设置到程序状态（binaryPath、jsonOutput、demoMode 等）上，
+// 和 main 处理全局选项的方式相同。生成、显示助记词与二维码、派生地址的
+// 命令在只读构建中直接返回，编译器删掉其余代码。
+//
+
+type command interface {
+    define(fs *flag.FlagSet)
+    run(fs *flag.FlagSet, args []string, wordList []string)
+}
+
+// -------------------------
+//   generate
+// -------------------------
+
+type generateCommand struct {
+    words, bits, n, groupBits, groupsPerLine     int
+    noFile, encrypt, dpapi, pick, typed, game    bool
+    statCheck, lint, quiz, explain, json, qr     bool
+    reveal, romanize                             bool
+    file, cards, dice, coins, debias, entropyHex string
+    qrEC                                         string
+}
+
+func (o *generateCommand) define(fs *flag.FlagSet) {
+    fs.IntVar(&o.words, "words", 24, "Words in the passphrase: 12, 15, 18, 21 or 24")
+    fs.IntVar(&o.bits, "bits", 0, "Entropy bits: 128, 160, 192, 224 or 256 (instead of -words)")
+    fs.IntVar(&o.n, "n", 1, "Generate COUNT independent passphrases (one file each)")
+    fs.BoolVar(&o.noFile, "no-file", false, "Only print the passphrase, without writing binary.txt")
+    fs.StringVar(&o.file, "f", "binary.txt", "Entropy file to write instead of binary.txt")
+    fs.BoolVar(&o.encrypt, "encrypt", false, "Write binary.txt encrypted with a passphrase")
+    fs.BoolVar(&o.dpapi, "dpapi", false, "With -encrypt (Windows): protect binary.txt with the current user account (DPAPI) instead of a passphrase")
+    fs.IntVar(&o.groupBits, "group-bits", 11, "Bits per group when writing binary.txt")
+    fs.IntVar(&o.groupsPerLine, "groups-per-line", 6, "Groups per line when writing binary.txt")
+    fs.BoolVar(&o.pick, "pick", false, "Choose words for some positions (asked for, hidden); the rest is random")
+    fs.StringVar(&o.cards, "cards", "", "Use a shuffled deck order (\"AS 7H KD ...\") instead of the system random generator")
+    fs.StringVar(&o.dice, "dice", "", "Mix six-sided die rolls (digits 1–6) into the system randomness")
+    fs.StringVar(&o.coins, "coins", "", "Mix coin flips (H/T or 1/0) into the system randomness")
+    fs.StringVar(&o.debias, "debias", "", "With -dice or -coins: debias the raw input first: vn (Von Neumann) or hash (count measured min-entropy)")
+    fs.BoolVar(&o.typed, "typed", false, "Mix a typed random string (asked for, hidden) into the system randomness")
+    fs.BoolVar(&o.game, "game", false, "Mix in keyboard randomness, generating only after a measured entropy estimate")
+    fs.StringVar(&o.entropyHex, "entropy-hex", "", "Use entropy given as 32–64 hex digits")
+    fs.BoolVar(&o.statCheck, "stat-check", false, "Run statistical tests on the random generator and the entropy first; refuse on failure")
+    fs.BoolVar(&o.lint, "lint", false, "Flag confusable word pairs and offer to regenerate")
+    fs.BoolVar(&o.quiz, "quiz", false, "With -no-file: after showing the words, clear the screen and ask for some of them back")
+    fs.BoolVar(&o.explain, "explain", false, "With -no-file: also print every intermediate value (entropy, SHA-256, checksum, 11-bit pieces)")
+    fs.BoolVar(&o.json, "json", false, "Print the result as JSON")
+    fs.BoolVar(&o.qr, "qr", false, "With -no-file: also show the passphrase as a QR code")
+    fs.StringVar(&o.qrEC, "qr-ec", "L", "With -qr: error-correction level L, M, Q or H")
+    fs.BoolVar(&o.reveal, "reveal", false, "Show the words on the terminal without asking (they are masked by default)")
+    fs.BoolVar(&o.romanize, "romanize", false, "With -lang chinese_*, japanese or korean: show pinyin, romaji or Korean romanization next to the words")
+}
+
+func (o *generateCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if buildReadOnly {
+        return
+    }
+    useBinaryFile(o.file)
+    useLayout(fs, o.groupBits, o.groupsPerLine)
+    revealWords = o.reveal
+    if o.romanize {
+        useRomanize()
+    }
+    if o.explain {
+        if !o.noFile || o.json {
+            log.Fatalf("Error: -explain works with -no-file, and not with -json.")
+        }
+        explainSteps = true
+    }
+    if o.quiz && (!o.noFile || o.json) {
+        log.Fatalf("Error: -quiz works with -no-file, and not with -json.")
+    }
+    if o.json {
+        enforceJSONFlags(fs)
+        jsonOutput = true
+    }
+    if o.noFile && o.encrypt {
+        log.Fatalf("Error: -no-file writes nothing; it cannot be combined with -encrypt.")
+    }
+    if o.qr && !o.noFile {
+        log.Fatalf("Error: -qr needs -no-file; show the QR code of binary.txt with the qr command.")
+    }
+    statChecks = o.statCheck
+    useEncryption(o.encrypt, o.dpapi)
+
+    size, err := entropySize(o.words, o.bits)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    // -stat-check → 先检验系统随机数生成器
+    if statChecks && o.cards == "" && o.entropyHex == "" {
+        checkGenerator()
+    }
+    // -n → 多组独立的熵，只用系统随机数
+    if o.n != 1 {
+        if o.n < 1 || o.n > maxBatchCount {
+            log.Fatalf("Error: -n must be between 1 and %d", maxBatchCount)
+        }
+        if o.pick || o.cards != "" || o.entropyHex != "" || o.dice != "" || o.coins != "" || o.typed || o.game || o.lint || o.quiz || o.explain || o.qr {
+            log.Fatalf("Error: -n uses the system random generator only; it cannot be combined with -pick, -cards, -entropy-hex, -dice, -coins, -typed, -game, -lint, -quiz, -explain or -qr.")
+        }
+        runBatch(o.n, size, o.noFile, wordList)
+        return
+    }
+    var sources []entropySource
+    var fixed []byte
+    mixed := o.game || o.dice != "" || o.coins != "" || o.typed
+    switch {
+    case o.cards != "" && o.entropyHex != "", (o.cards != "" || o.entropyHex != "") && mixed:
+        log.Fatalf("Error: -cards and -entropy-hex are used alone; -dice, -coins, -typed and -game are mixed with the system random generator.")
+    case o.pick && (o.cards != "" || o.entropyHex != "" || mixed):
+        log.Fatalf("Error: -pick fills the other words from the system random generator only.")
+    case o.pick:
+        fixed = pickedEntropy(size, wordList)
+    case o.entropyHex != "":
+        if fixed, err = parseEntropyHex(o.entropyHex); err != nil {
+            log.Fatalf("Error: -entropy-hex: %v", err)
+        }
+        // 长度由十六进制决定；明确给了 -words/-bits 时才核对
+        for _, name := range []string{"words", "bits"} {
+            if flagGiven(fs, name) && len(fixed) != size {
+                log.Fatalf("Error: -entropy-hex has %d bits, but -%s asks for %d", len(fixed)*8, name, size*8)
+            }
+        }
+        size = len(fixed)
+    case o.cards != "":
+        // 外部熵：完全由输入决定，不使用 crypto/rand
+        if fixed, err = cardEntropy(o.cards, size); err != nil {
+            log.Fatalf("Error: -cards: %v", err)
+        }
+    }
+    if o.debias != "" && o.dice == "" && o.coins == "" {
+        log.Fatalf("Error: -debias applies to -dice and -coins.")
+    }
+    if o.dice != "" {
+        s, err := diceSource(o.dice)
+        if err == nil {
+            s, err = debiasSource(s, o.debias, "roll")
+        }
+        if err != nil {
+            log.Fatalf("Error: -dice: %v", err)
+        }
+        sources = append(sources, s)
+    }
+    if o.coins != "" {
+        s, err := coinSource(o.coins)
+        if err == nil {
+            s, err = debiasSource(s, o.debias, "flip")
+        }
+        if err != nil {
+            log.Fatalf("Error: -coins: %v", err)
+        }
+        sources = append(sources, s)
+    }
+    if o.typed {
+        s, err := typedSource()
+        if err != nil {
+            log.Fatalf("Error: -typed: %v", err)
+        }
+        sources = append(sources, s)
+    }
+    if o.game {
+        data, err := runEntropyGame(size * 8)
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        sources = append(sources, entropySource{name: "keyboard game", detail: "estimated", bits: float64(size * 8), data: data})
+    }
+    entropy := make([]byte, size)
+    for {
+        if fixed != nil {
+            copy(entropy, fixed)
+            break
+        }
+        _, err := rand.Read(entropy)
+        if err != nil {
+            log.Fatalf("Error generating entropy: %v", err)
+        }
+        if mixed {
+            mixSources(entropy, sources)
+        }
+        if !o.lint || acceptAfterLint(entropy, wordList) {
+            break
+        }
+    }
+    if statChecks {
+        checkEntropyStats(entropy, o.cards != "" || o.entropyHex != "" || o.pick)
+    }
+    if o.noFile {
+        if mixed {
+            printEntropySources(size, sources)
+        }
+        level, err := parseQRLevel(o.qrEC)
+        if err != nil {
+            log.Fatalf("Error: -qr-ec: %v", err)
+        }
+        printMemoryOnly(entropy, o.qr, level, o.lint, o.quiz, wordList)
+        return
+    }
+    err = writeBinaryFile(binaryPath, entropy)
+    if err != nil {
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    if jsonOutput {
+        r := entropyReport("b", entropy, false, wordList)
+        r.File = binaryPath
+        printJSON(r)
+    } else {
+        fmt.Printf("%s generated successfully (%d bits, %d words).\n", binaryPath, size*8, size*3/4)
+    }
+    if mixed {
+        printEntropySources(size, sources)
+    }
+    transcript.recordBinary("generate binary.txt", "ok", wordList)
+    if err := recordBirthday(masterFingerprint(generatePassphraseFromBinary(wordList)), time.Now()); err != nil {
+        log.Fatalf("Error writing %s: %v", birthdayFile, err)
+    }
+    if o.entropyHex != "" {
+        warnWeakMnemonic(entropy, wordList)
+    }
+    // 外部熵无法重新生成，只报告
+    if o.lint && fixed != nil {
+        lintBinary(wordList)
+    }
+}
+
+// -------------------------
+//   mnemonic
+// -------------------------
+
+type mnemonicCommand struct {
+    file                                  string
+    json, reveal, romanize, quiz, explain bool
+    clip, demo                            bool
+    clipAfter                             time.Duration
+}
+
+func (o *mnemonicCommand) define(fs *flag.FlagSet) {
+    fs.StringVar(&o.file, "f", "binary.txt", "Entropy file to read instead of binary.txt")
+    fs.BoolVar(&o.json, "json", false, "Print the result as JSON")
+    fs.BoolVar(&o.reveal, "reveal", false, "Show the words on the terminal without asking (they are masked by default)")
+    fs.BoolVar(&o.romanize, "romanize", false, "With -lang chinese_*, japanese or korean: show pinyin, romaji or Korean romanization next to the words")
+    fs.BoolVar(&o.quiz, "quiz", false, "After showing the words, clear the screen and ask for some of them back")
+    fs.BoolVar(&o.explain, "explain", false, "Also print every intermediate value (entropy, SHA-256, checksum, 11-bit pieces)")
+    fs.BoolVar(&o.clip, "clip", false, "Copy the passphrase to the clipboard instead of printing it, and clear it after -clip-timeout")
+    fs.DurationVar(&o.clipAfter, "clip-timeout", 30*time.Second, "With -clip: clear the clipboard after DURATION")
+    fs.BoolVar(&o.demo, "demo", false, "Use fixed, public demo entropy instead of binary.txt and watermark all output")
+}
+
+func (o *mnemonicCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if buildReadOnly {
+        return
+    }
+    useBinaryFile(o.file)
+    revealWords = o.reveal
+    if o.romanize {
+        useRomanize()
+    }
+    if o.explain {
+        if o.json || o.clip {
+            log.Fatalf("Error: -explain does not work with -json or -clip.")
+        }
+        explainSteps = true
+    }
+    if o.quiz && (o.json || o.clip) {
+        log.Fatalf("Error: -quiz does not work with -json or -clip.")
+    }
+    useClip(fs, o.clip, o.clipAfter)
+    if o.json {
+        enforceJSONFlags(fs)
+        jsonOutput = true
+    }
+    if o.demo {
+        useDemo()
+        defer printDemoWatermark()
+    }
+
+    if jsonOutput {
+        r := entropyReport("p", bip39.BitsToBytes(loadEntropyBits()), true, wordList)
+        if !demoMode {
+            r.File = binaryPath
+        }
+        printJSON(r)
+    } else {
+        passphrase := generatePassphraseFromBinary(wordList)
+        if clipOutput {
+            clipSecret("Passphrase", formatPhrase(passphrase))
+        } else {
+            fmt.Println("Passphrase:")
+            printPhrase(passphrase)
+            if explainSteps {
+                entropy, err := entropyFromPhrase(passphrase, wordList)
+                if err != nil {
+                    log.Fatalf("Error: %v", err)
+                }
+                printExplanation(entropy, wordList)
+            }
+            if o.quiz {
+                runQuiz(passphrase, wordList)
+            }
+        }
+    }
+    if !demoMode {
+        transcript.recordBinary("output from binary.txt", "ok", wordList)
+    }
+}
+
+// -------------------------
+//   qr
+// -------------------------
+
+type qrCommand struct {
+    file, out, ec string
+    scale, border int
+    reveal, demo  bool
+}
+
+func (o *qrCommand) define(fs *flag.FlagSet) {
+    fs.StringVar(&o.file, "f", "binary.txt", "Entropy file to read instead of binary.txt")
+    fs.StringVar(&o.out, "qr-out", "", "Write the QR code to FILE (.png or .svg) instead of the terminal")
+    fs.IntVar(&o.scale, "qr-scale", 8, "With -qr-out: pixels per QR module")
+    fs.IntVar(&o.border, "qr-border", 4, "With -qr-out: quiet zone in modules")
+    fs.StringVar(&o.ec, "qr-ec", "L", "Error-correction level L, M, Q or H")
+    fs.BoolVar(&o.reveal, "reveal", false, "Draw the QR code on the terminal without asking")
+    fs.BoolVar(&o.demo, "demo", false, "Use fixed, public demo entropy instead of binary.txt and watermark all output")
+}
+
+func (o *qrCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if buildReadOnly {
+        return
+    }
+    useBinaryFile(o.file)
+    revealWords = o.reveal
+    if o.demo {
+        useDemo()
+        defer printDemoWatermark()
+    }
+
+    passphrase := generatePassphraseFromBinary(wordList)
+    level, err := parseQRLevel(o.ec)
+    if err != nil {
+        log.Fatalf("Error: -qr-ec: %v", err)
+    }
+    if o.out != "" {
+        if err := writeQRFile(o.out, qrPayload(passphrase), level, o.scale, o.border); err != nil {
+            log.Fatalf("Error: -qr-out: %v", err)
+        }
+        fmt.Printf("Passphrase QR code written to %s (mode 0600). Delete it when done.\n", o.out)
+    } else {
+        fmt.Println("Passphrase QR Code:")
+        printSecretQR(qrPayload(passphrase), level)
+    }
+    if !demoMode {
+        transcript.recordBinary("output from binary.txt", "ok", wordList)
+    }
+}
+
+// -------------------------
+//   inspect
+// -------------------------
+
+type inspectCommand struct {
+    romanize bool
+}
+
+func (o *inspectCommand) define(fs *flag.FlagSet) {
+    fs.BoolVar(&o.romanize, "romanize", false, "With -lang chinese_*, japanese or korean: show and accept pinyin, romaji or Korean romanization")
+}
+
+func (o *inspectCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if o.romanize {
+        useRomanize()
+    }
+    showWordInfo(args[0], wordList)
+}
+
+// -------------------------
+//   validate
+// -------------------------
+
+type validateCommand struct {
+    json bool
+}
+
+func (o *validateCommand) define(fs *flag.FlagSet) {
+    fs.BoolVar(&o.json, "json", false, "Print the result as JSON")
+}
+
+func (o *validateCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if o.json {
+        enforceJSONFlags(fs)
+        jsonOutput = true
+    }
+    // 省略单词时为 -：不回显地询问
+    phrase := strings.Join(args, " ")
+    if phrase == "" {
+        phrase = "-"
+    }
+    validateMnemonic(phrase, wordList)
+}
+
+// -------------------------
+//   derive
+// -------------------------
+
+type deriveCommand struct {
+    file, mnemonic, out         string
+    account, start, count, jobs int
+    change, demo                bool
+}
+
+func (o *deriveCommand) define(fs *flag.FlagSet) {
+    fs.StringVar(&o.file, "f", "binary.txt", "Entropy file to read instead of binary.txt")
+    fs.StringVar(&o.mnemonic, "mnemonic", "", "Mnemonic to use instead of binary.txt")
+    fs.IntVar(&o.account, "account", 0, "Account number")
+    fs.BoolVar(&o.change, "change", false, "Change addresses (.../1/i) instead of receive addresses")
+    fs.IntVar(&o.start, "start", 0, "First address index")
+    fs.IntVar(&o.count, "count", 10, "Number of addresses")
+    fs.StringVar(&o.out, "out", "", "Stream addresses as CSV to FILE (.gz or .zst to compress)")
+    fs.IntVar(&o.jobs, "jobs", 0, "Parallel workers (0: one per CPU)")
+    fs.BoolVar(&o.demo, "demo", false, "Use fixed, public demo entropy instead of binary.txt and watermark all output")
+}
+
+func (o *deriveCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    if buildReadOnly {
+        return
+    }
+    useBinaryFile(o.file)
+    setJobs(o.jobs)
+    if o.demo {
+        useDemo()
+        defer printDemoWatermark()
+    }
+    printDerivedAddresses(args[0], o.account, o.start, o.count, o.change, o.out, o.mnemonic, wordList)
+}
+
+// -------------------------
+//   selftest
+// -------------------------
+
+type selftestCommand struct {
+    canonical bool
+    metrics   string
+}
+
+func (o *selftestCommand) define(fs *flag.FlagSet) {
+    fs.BoolVar(&o.canonical, "canonical", false, "Byte-exact output for comparing builds")
+    fs.StringVar(&o.metrics, "metrics", "", "Write a JSON summary to FILE")
+}
+
+func (o *selftestCommand) run(fs *flag.FlagSet, args []string, wordList []string) {
+    metricsFile = o.metrics
+    printSelftest(bip39.English(), o.canonical)
+}
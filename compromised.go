@@ -0,0 +1,50 @@
+package main
+
+import (
+    _ "embed"
+    "fmt"
+    "log"
+    "os"
+    "sync"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bloom"
+)
+
+//
+// -------------------------
+//   已公开助记词检查
+// -------------------------
+//
+// 测试向量、书里的例子、"abandon ... about" 之类的助记词真的有人往里打钱，
+// 而扫描这些地址的机器人几秒内就会把钱转走。embed/compromised.bloom 是这些
+// 助记词的熵的布隆过滤器（来源见 embed/compromised.txt，go generate 重新生成），
+// 导入、-mnemonic 和 -v 命中时发出警告。误报率 10⁻⁹。
+//
+
+//go:generate go run ./internal/mkcompromised
+
+//go:embed embed/compromised.bloom
+var compromisedBloom []byte
+
+var compromisedFilter = sync.OnceValue(func() *bloom.Filter {
+    f, err := bloom.Unmarshal(compromisedBloom)
+    if err != nil {
+        log.Fatalf("Error: embedded list of known mnemonics: %v", err)
+    }
+    return f
+})
+
+func isKnownMnemonic(entropy []byte) bool {
+    return compromisedFilter().Has(entropy)
+}
+
+// 命中时打印警告并返回 true
+func warnKnownMnemonic(entropy []byte) bool {
+    if !isKnownMnemonic(entropy) {
+        return false
+    }
+    fmt.Fprintln(os.Stderr, "Warning: this is a publicly known mnemonic (test vector, published example or trivial pattern).")
+    fmt.Fprintln(os.Stderr, "Warning: bots sweep funds sent to it within seconds. Never use it for real funds.")
+    transcript.record("known mnemonic check", "match", "", "")
+    return true
+}
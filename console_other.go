@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// 其他系统的终端本来就是 UTF-8 并支持 ANSI 转义序列
+func setupConsole() (restore func()) {
+    return func() {}
+}
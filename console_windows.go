@@ -0,0 +1,44 @@
+//go:build windows
+
+package main
+
+import (
+    "os"
+
+    "golang.org/x/sys/windows"
+)
+
+// Windows 控制台默认使用本地代码页（437、936 等）：二维码的半块字符和中日韩
+// 单词表会显示成乱码，-tui、-masked、-game 的 ANSI 转义序列也不起作用。
+// 运行期间改用 UTF-8（65001）并打开虚拟终端处理，正常退出时恢复原设置。
+// 隐藏输入由 x/term 通过控制台 API（关闭 ENABLE_ECHO_INPUT）实现。
+const codePageUTF8 = 65001
+
+func setupConsole() (restore func()) {
+    var undo []func()
+    if cp, err := windows.GetConsoleCP(); err == nil && cp != codePageUTF8 {
+        if windows.SetConsoleCP(codePageUTF8) == nil {
+            undo = append(undo, func() { windows.SetConsoleCP(cp) })
+        }
+    }
+    if cp, err := windows.GetConsoleOutputCP(); err == nil && cp != codePageUTF8 {
+        if windows.SetConsoleOutputCP(codePageUTF8) == nil {
+            undo = append(undo, func() { windows.SetConsoleOutputCP(cp) })
+        }
+    }
+    for _, f := range []*os.File{os.Stdout, os.Stderr} {
+        h := windows.Handle(f.Fd())
+        var mode uint32
+        if windows.GetConsoleMode(h, &mode) != nil || mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+            continue
+        }
+        if windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil {
+            undo = append(undo, func() { windows.SetConsoleMode(h, mode) })
+        }
+    }
+    return func() {
+        for i := len(undo) - 1; i >= 0; i-- {
+            undo[i]()
+        }
+    }
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+    "bytes"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "errors"
+    "fmt"
+    "os"
+
+    "golang.org/x/crypto/scrypt"
+    "golang.org/x/term"
+)
+
+//
+// -------------------------
+//   口令加密（scrypt + AES-GCM）
+// -------------------------
+//
+// 格式：magic "PBE1" | salt (16) | nonce (12) | ciphertext+tag
+//
+
+var sealMagic = []byte("PBE1")
+
+const (
+    scryptN = 1 << 15
+    scryptR = 8
+    scryptP = 1
+)
+
+func sealWithPassphrase(plaintext []byte, passphrase string) ([]byte, error) {
+    salt := make([]byte, 16)
+    if _, err := rand.Read(salt); err != nil {
+        return nil, err
+    }
+    gcm, err := passphraseAEAD(passphrase, salt)
+    if err != nil {
+        return nil, err
+    }
+    nonce := make([]byte, gcm.NonceSize())
+    if _, err := rand.Read(nonce); err != nil {
+        return nil, err
+    }
+
+    out := append([]byte{}, sealMagic...)
+    out = append(out, salt...)
+    out = append(out, nonce...)
+    return gcm.Seal(out, nonce, plaintext, sealMagic), nil
+}
+
+func openWithPassphrase(sealed []byte, passphrase string) ([]byte, error) {
+    if !bytes.HasPrefix(sealed, sealMagic) || len(sealed) < len(sealMagic)+16+12 {
+        return nil, errors.New("not an encrypted blob")
+    }
+    rest := sealed[len(sealMagic):]
+    salt, rest := rest[:16], rest[16:]
+    gcm, err := passphraseAEAD(passphrase, salt)
+    if err != nil {
+        return nil, err
+    }
+    nonce, ct := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+    plaintext, err := gcm.Open(nil, nonce, ct, sealMagic)
+    if err != nil {
+        return nil, errors.New("wrong passphrase or corrupted data")
+    }
+    return plaintext, nil
+}
+
+func passphraseAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+    key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+    if err != nil {
+        return nil, err
+    }
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    return cipher.NewGCM(block)
+}
+
+// 终端下不回显；管道输入时按行读取
+func readSecret(prompt string) (string, error) {
+    fmt.Fprint(os.Stderr, prompt)
+    fd := int(os.Stdin.Fd())
+    if term.IsTerminal(fd) {
+        b, err := term.ReadPassword(fd)
+        fmt.Fprintln(os.Stderr)
+        return string(b), err
+    }
+    return readLine()
+}
+
+func readNewSecret(prompt string) (string, error) {
+    p1, err := readSecret(prompt)
+    if err != nil {
+        return "", err
+    }
+    if p1 == "" {
+        return "", errors.New("empty passphrase")
+    }
+    p2, err := readSecret("Repeat: ")
+    if err != nil {
+        return "", err
+    }
+    if p1 != p2 {
+        return "", errors.New("passphrases do not match")
+    }
+    return p1, nil
+}
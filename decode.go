@@ -0,0 +1,70 @@
+package main
+
+import (
+    "encoding/hex"
+    "fmt"
+    "log"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -decode 助记词 → 熵
+// -------------------------
+//
+// -p 的逆操作：校验助记词的校验位，去掉后输出熵的十六进制与按单词分组的
+// 二进制；加 -write 时写入 binary.txt，把已有的助记词迁移到本工具。
+// 参数为 - 时不回显地询问助记词，不进入 shell 历史。
+//
+
+func decodeMnemonic(phrase string, write bool, wordList []string) {
+    if phrase == "-" {
+        var err error
+        if phrase, err = readSecret("Mnemonic (hidden): "); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+    }
+    words := splitWords(phrase)
+    entropy, err := entropyFromPhrase(phrase, wordList)
+    if err != nil {
+        log.Fatalf("Error: -decode: %v", err)
+    }
+
+    if jsonOutput {
+        r := entropyReport("decode", entropy, true, wordList)
+        if write {
+            r.File = binaryPath
+        }
+        printJSON(r)
+    } else {
+        printDecoded(words, entropy)
+        if explainSteps {
+            printExplanation(entropy, wordList)
+        }
+    }
+
+    if write {
+        importEntropy(entropy)
+        return
+    }
+    warnWeakMnemonic(entropy, wordList)
+}
+
+func printDecoded(words []string, entropy []byte) {
+    bits := bip39.BytesToBits(entropy)
+    checksum := bip39.ChecksumBits(bits)
+    fmt.Printf("Mnemonic: %d words, checksum OK\n", len(words))
+    fmt.Printf("Entropy (%d bits, hex): %s\n", len(bits), hex.EncodeToString(entropy))
+    fmt.Println("Entropy (binary, 11 bits per word; the last word ends with the checksum):")
+    all := append(bits, checksum...)
+    for i := 0; i < len(words); i++ {
+        group := bitString(all[i*11 : (i+1)*11])
+        if i == len(words)-1 {
+            cut := 11 - len(checksum)
+            group = group[:cut] + " + checksum " + group[cut:]
+        }
+        fmt.Printf("  %2d. %-9s %s\n", i+1, words[i], group)
+    }
+}
+
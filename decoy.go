@@ -0,0 +1,151 @@
+package main
+
+import (
+    "crypto/rand"
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    "golang.org/x/crypto/scrypt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -decoy 诱饵备份纸
+// -------------------------
+//
+// 真实助记词混在 N 个随机生成的诱饵助记词中，位置由 PIN 与纸上的 id
+// 经 scrypt 决定。这只是低成本的混淆：PIN 可以被穷举，诱饵数量也有限，
+// 不能代替真正的加密。
+//
+
+const decoyHeader = "Decoy sheet"
+
+func decoyPosition(pin string, id []byte, count int) (int, error) {
+    key, err := scrypt.Key([]byte(pin), id, scryptN, scryptR, scryptP, 8)
+    if err != nil {
+        return 0, err
+    }
+    return int(binary.BigEndian.Uint64(key) % uint64(count)), nil
+}
+
+func printDecoySheet(decoys int, wordList []string) {
+    if decoys < 1 || decoys > 99 {
+        log.Fatalf("Error: decoy count must be 1–99")
+    }
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+
+    pin, err := readNewSecret("PIN: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    id := make([]byte, 8)
+    if _, err := rand.Read(id); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    count := decoys + 1
+    real, err := decoyPosition(pin, id, count)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    phrases := make([]string, count)
+    fake := make([]byte, len(entropy))
+    for i := range phrases {
+        if i == real {
+            phrases[i] = mnemonicFromEntropy(entropy, wordList)
+            continue
+        }
+        if _, err := rand.Read(fake); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        phrases[i] = mnemonicFromEntropy(fake, wordList)
+    }
+    printDecoyPhrases(id, phrases)
+}
+
+// 真实助记词也在其中，未确认时同样遮住
+func printDecoyPhrases(id []byte, phrases []string) {
+    reveal := shouldReveal()
+    fmt.Printf("%s  id: %s  phrases: %d\n", decoyHeader, hex.EncodeToString(id), len(phrases))
+    for i, phrase := range phrases {
+        if !reveal {
+            phrase = maskPhrase(phrase)
+        }
+        fmt.Printf("#%02d %s\n", i+1, phrase)
+    }
+}
+
+func recoverDecoySheet(filename string, wordList []string) {
+    data, err := readFileLimited(filename, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+
+    var (
+        id      []byte
+        count   int
+        phrases = map[int]string{}
+    )
+    for _, line := range strings.Split(string(data), "\n") {
+        line = strings.TrimSpace(line)
+        if rest, ok := strings.CutPrefix(line, decoyHeader); ok {
+            fields := strings.Fields(rest)
+            for i := 0; i+1 < len(fields); i++ {
+                switch fields[i] {
+                case "id:":
+                    id, _ = hex.DecodeString(fields[i+1])
+                case "phrases:":
+                    count, _ = strconv.Atoi(fields[i+1])
+                }
+            }
+            continue
+        }
+        num, words, ok := strings.Cut(line, " ")
+        if !ok || !strings.HasPrefix(num, "#") {
+            continue
+        }
+        n, err := strconv.Atoi(num[1:])
+        if err != nil {
+            continue
+        }
+        phrases[n-1] = words
+    }
+    if len(id) != 8 {
+        log.Fatalf("Error: %s: no '%s  id: ...' line found", filename, decoyHeader)
+    }
+    // 位置取决于表头记录的总数，少抄或多抄一行都会得出错误的位置
+    if count < 2 || count > 100 {
+        log.Fatalf("Error: %s: no valid 'phrases: N' in the '%s' line", filename, decoyHeader)
+    }
+    for i := 0; i < count; i++ {
+        if _, ok := phrases[i]; !ok {
+            log.Fatalf("Error: %s: phrase #%02d is missing; the sheet lists %d phrases", filename, i+1, count)
+        }
+    }
+    if len(phrases) != count {
+        log.Fatalf("Error: %s: %d phrases found, but the sheet lists %d", filename, len(phrases), count)
+    }
+
+    pin, err := readSecret("PIN: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    pos, err := decoyPosition(pin, id, count)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    phrase := phrases[pos]
+    // 诱饵同样通过校验，PIN 错误时无法察觉，只能由钱包指纹确认
+    if _, err := entropyFromPhrase(phrase, wordList); err != nil {
+        log.Fatalf("Error: phrase #%02d: %v", pos+1, err)
+    }
+    mnemonic := strings.Join(splitWords(phrase), " ")
+    fmt.Printf("Phrase #%02d, fingerprint %s:\n", pos+1, masterFingerprint(mnemonic))
+    printPhrase(mnemonic)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "io"
+    "os"
+    "strings"
+    "testing"
+)
+
+// 运行 f 并返回其标准输出
+func captureStdout(t *testing.T, f func()) string {
+    t.Helper()
+    r, w, err := os.Pipe()
+    if err != nil {
+        t.Fatal(err)
+    }
+    stdout := os.Stdout
+    os.Stdout = w
+    defer func() { os.Stdout = stdout }()
+    done := make(chan string)
+    go func() {
+        b, _ := io.ReadAll(r)
+        done <- string(b)
+    }()
+    f()
+    w.Close()
+    return <-done
+}
+
+// 假装输出是终端、标准输入不是：不加 -reveal 时不询问，直接遮住
+func fakeTerminal(t *testing.T, reveal bool) {
+    t.Helper()
+    saved, savedReveal, savedAsked := isTerminal, revealWords, revealAsked
+    t.Cleanup(func() { isTerminal, revealWords, revealAsked = saved, savedReveal, savedAsked })
+    isTerminal = func(f *os.File) bool { return f != os.Stdin }
+    revealWords, revealAsked = reveal, false
+}
+
+func TestDecoySheetMasked(t *testing.T) {
+    phrases := []string{
+        "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+        "legal winner thank year wave sausage worth useful legal winner thank yellow",
+    }
+    id := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+    fakeTerminal(t, false)
+    out := captureStdout(t, func() { printDecoyPhrases(id, phrases) })
+    for _, w := range []string{"abandon", "about", "legal", "winner", "sausage"} {
+        if strings.Contains(out, w) {
+            t.Errorf("masked sheet shows %q:\n%s", w, out)
+        }
+    }
+    if !strings.Contains(out, "#01 a*** a***") || !strings.Contains(out, "#02 l*** w***") {
+        t.Errorf("rows not masked:\n%s", out)
+    }
+    if !strings.Contains(out, decoyHeader+"  id: 0102030405060708  phrases: 2") {
+        t.Errorf("header missing:\n%s", out)
+    }
+
+    fakeTerminal(t, true)
+    out = captureStdout(t, func() { printDecoyPhrases(id, phrases) })
+    if !strings.Contains(out, "#02 "+phrases[1]) {
+        t.Errorf("-reveal sheet does not show the words:\n%s", out)
+    }
+}
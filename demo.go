@@ -0,0 +1,39 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "os"
+)
+
+//
+// -------------------------
+//   -demo 演示模式
+// -------------------------
+//
+// 使用公开的 BIP39 测试向量熵（32 个 0x7f 字节），所有输出都带有水印，
+// 用于截图与教程。该助记词是公开的，永远不要往里面存钱。
+//
+
+const demoWatermark = "DEMO – DO NOT USE"
+
+var demoMode bool
+
+var demoEntropy = bytes.Repeat([]byte{0x7f}, 32)
+
+func printDemoWatermark() {
+    // -json 时标准输出只留 JSON
+    if jsonOutput {
+        fmt.Fprintln(os.Stderr, "******** "+demoWatermark+" ********")
+        return
+    }
+    fmt.Println("******** " + demoWatermark + " ********")
+}
+
+// 演示模式下二维码内容同样带水印，扫码得到的不是有效助记词
+func qrPayload(passphrase string) string {
+    if demoMode {
+        return demoWatermark + ": " + passphrase
+    }
+    return passphrase
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/hex"
+    "fmt"
+    "io"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "unicode"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -doctor 残留扫描
+// -------------------------
+//
+// 会话结束后检查助记词是否留在了 shell 历史、剪贴板、tmux 回滚缓冲、编辑器
+// 交换文件和临时目录里。只报告位置，不打印匹配到的内容。
+// 判定为残留：连续 doctorRun 个助记词单词、熵的十六进制、或 binary.txt 的
+// 连续三组 11 位二进制。
+//
+
+const (
+    doctorRun         = 4
+    doctorMaxFileSize = 64 << 20
+)
+
+type residueScanner struct {
+    words    []string
+    position map[string][]int
+    needles  []string // 小写的十六进制熵、二进制分组
+}
+
+type residueSource struct {
+    name string
+    read func() (io.ReadCloser, error)
+}
+
+func runDoctor(wordList []string) {
+    mnemonic := generatePassphraseFromBinary(wordList)
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    s := newResidueScanner(strings.Fields(mnemonic), entropy)
+
+    fmt.Printf("Residue scan for the passphrase in binary.txt (fingerprint %s):\n", masterFingerprint(mnemonic))
+    found := 0
+    for _, src := range residueSources() {
+        r, err := src.read()
+        if err != nil {
+            fmt.Printf("[skip]  %s: %v\n", src.name, err)
+            continue
+        }
+        lines, err := s.scan(r)
+        r.Close()
+        switch {
+        case err != nil:
+            fmt.Printf("[skip]  %s: %v\n", src.name, err)
+        case len(lines) > 0:
+            found++
+            fmt.Printf("[FOUND] %s: line %s\n", src.name, joinInts(lines))
+        default:
+            fmt.Printf("[ok]    %s\n", src.name)
+        }
+    }
+
+    fmt.Println()
+    if found == 0 {
+        fmt.Println("No residues found.")
+        transcript.record("doctor", "clean", "", "")
+        return
+    }
+    fmt.Printf("%d place(s) hold parts of the passphrase. Remove those lines or files,\n", found)
+    fmt.Println("clear the clipboard and tmux history (tmux clear-history), then run -doctor again.")
+    transcript.record("doctor", "residues", "", fmt.Sprintf("%d places", found))
+    transcript.finish()
+    os.Exit(1)
+}
+
+func newResidueScanner(words []string, entropy []byte) *residueScanner {
+    s := &residueScanner{words: words, position: map[string][]int{}}
+    for i, w := range words {
+        s.position[w] = append(s.position[w], i)
+    }
+    s.needles = append(s.needles, hex.EncodeToString(entropy))
+
+    // binary.txt 的前三组，与文件中的写法一致（以空格分隔）
+    bits := bip39.BytesToBits(entropy)
+    groups := make([]string, 0, 3)
+    for i := 0; i < 3 && (i+1)*11 <= len(bits); i++ {
+        groups = append(groups, bitString(bits[i*11:(i+1)*11]))
+    }
+    s.needles = append(s.needles, strings.Join(groups, " "))
+    return s
+}
+
+// 返回含残留的行号（从 1 开始）
+func (s *residueScanner) scan(r io.Reader) ([]int, error) {
+    var lines []int
+    sc := bufio.NewScanner(r)
+    sc.Buffer(make([]byte, 64*1024), doctorMaxFileSize)
+    for n := 1; sc.Scan(); n++ {
+        if s.matchLine(sc.Bytes()) {
+            lines = append(lines, n)
+        }
+    }
+    return lines, sc.Err()
+}
+
+func (s *residueScanner) matchLine(line []byte) bool {
+    lower := strings.ToLower(string(line))
+    for _, needle := range s.needles {
+        if strings.Contains(lower, needle) {
+            return true
+        }
+    }
+
+    tokens := strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+    run := min(doctorRun, len(s.words))
+    for i := 0; i+run <= len(tokens); i++ {
+        for _, start := range s.position[tokens[i]] {
+            if start+run > len(s.words) {
+                continue
+            }
+            match := true
+            for k := 1; k < run; k++ {
+                if tokens[i+k] != s.words[start+k] {
+                    match = false
+                    break
+                }
+            }
+            if match {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func residueSources() []residueSource {
+    var sources []residueSource
+    home, _ := os.UserHomeDir()
+
+    // shell 与 REPL 历史
+    for _, name := range []string{
+        ".bash_history", ".zsh_history", ".histfile", ".sh_history",
+        ".local/share/fish/fish_history", ".python_history", ".node_repl_history",
+        ".lesshst", ".viminfo", ".psql_history", ".mysql_history",
+        ".local/share/powershell/PSReadLine/ConsoleHost_history.txt",
+    } {
+        if path := filepath.Join(home, name); home != "" && fileExists(path) {
+            sources = append(sources, fileSource(path))
+        }
+    }
+    // Windows PowerShell 的历史在 %APPDATA% 下
+    if appData, err := os.UserConfigDir(); err == nil {
+        if path := filepath.Join(appData, "Microsoft", "Windows", "PowerShell", "PSReadLine", "ConsoleHost_history.txt"); fileExists(path) {
+            sources = append(sources, fileSource(path))
+        }
+    }
+
+    // 编辑器交换文件与自动保存：当前目录和常见的集中存放目录
+    var swaps []string
+    for _, pattern := range []string{".*.sw?", "*~", "#*#", ".#*"} {
+        m, _ := filepath.Glob(pattern)
+        swaps = append(swaps, m...)
+    }
+    if home != "" {
+        for _, dir := range []string{".vim/swap", ".vim/backup", ".cache/vim/swap", ".local/state/nvim/swap", ".local/share/nvim/swap", ".emacs.d/auto-save-list"} {
+            m, _ := filepath.Glob(filepath.Join(home, dir, "*"))
+            swaps = append(swaps, m...)
+        }
+    }
+    for _, path := range swaps {
+        if fileExists(path) {
+            sources = append(sources, fileSource(path))
+        }
+    }
+
+    // 临时目录第一层中能读取的文件
+    tmp := os.TempDir()
+    entries, _ := os.ReadDir(tmp)
+    for _, e := range entries {
+        info, err := e.Info()
+        if err != nil || !info.Mode().IsRegular() || info.Size() > doctorMaxFileSize {
+            continue
+        }
+        if f, err := os.Open(filepath.Join(tmp, e.Name())); err == nil {
+            f.Close()
+            sources = append(sources, fileSource(filepath.Join(tmp, e.Name())))
+        }
+    }
+
+    sources = append(sources, residueSource{"clipboard", readClipboard})
+    sources = append(sources, tmuxSources()...)
+    return sources
+}
+
+func fileExists(path string) bool {
+    info, err := os.Stat(path)
+    return err == nil && info.Mode().IsRegular()
+}
+
+func fileSource(path string) residueSource {
+    return residueSource{path, func() (io.ReadCloser, error) { return os.Open(path) }}
+}
+
+func commandOutput(name string, args ...string) (io.ReadCloser, error) {
+    out, err := exec.Command(name, args...).Output()
+    if err != nil {
+        return nil, err
+    }
+    return io.NopCloser(bytes.NewReader(out)), nil
+}
+
+// 依次尝试 Wayland、X11、macOS、Windows 的剪贴板工具
+func readClipboard() (io.ReadCloser, error) {
+    tools := [][]string{
+        {"wl-paste", "--no-newline"},
+        {"xclip", "-o", "-selection", "clipboard"},
+        {"xsel", "--clipboard", "--output"},
+        {"pbpaste"},
+        {"powershell", "-NoProfile", "-NonInteractive", "-Command", "Get-Clipboard -Raw"},
+    }
+    for _, t := range tools {
+        if _, err := exec.LookPath(t[0]); err == nil {
+            return commandOutput(t[0], t[1:]...)
+        }
+    }
+    return nil, fmt.Errorf("no wl-paste, xclip, xsel, pbpaste or powershell found")
+}
+
+// 每个 tmux 窗格的完整回滚缓冲
+func tmuxSources() []residueSource {
+    if _, err := exec.LookPath("tmux"); err != nil {
+        return []residueSource{{"tmux scrollback", func() (io.ReadCloser, error) {
+            return nil, fmt.Errorf("tmux not found")
+        }}}
+    }
+    out, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{pane_id}").Output()
+    if err != nil {
+        return []residueSource{{"tmux scrollback", func() (io.ReadCloser, error) {
+            return nil, fmt.Errorf("no tmux server running")
+        }}}
+    }
+    var sources []residueSource
+    for _, pane := range strings.Fields(string(out)) {
+        sources = append(sources, residueSource{"tmux pane " + pane, func() (io.ReadCloser, error) {
+            return commandOutput("tmux", "capture-pane", "-p", "-J", "-S", "-", "-t", pane)
+        }})
+    }
+    return sources
+}
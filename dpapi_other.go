@@ -0,0 +1,17 @@
+//go:build !windows
+
+package main
+
+import "errors"
+
+const dpapiAvailable = false
+
+var errNoDPAPI = errors.New("DPAPI-protected files can only be opened on Windows, by the user account that wrote them")
+
+func dpapiProtect(plain []byte) ([]byte, error) {
+    return nil, errNoDPAPI
+}
+
+func dpapiUnprotect(sealed []byte) ([]byte, error) {
+    return nil, errNoDPAPI
+}
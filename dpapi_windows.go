@@ -0,0 +1,42 @@
+//go:build windows
+
+package main
+
+import (
+    "unsafe"
+
+    "golang.org/x/sys/windows"
+)
+
+const dpapiAvailable = true
+
+func dpapiBlob(b []byte) *windows.DataBlob {
+    if len(b) == 0 {
+        return &windows.DataBlob{}
+    }
+    return &windows.DataBlob{Size: uint32(len(b)), Data: &b[0]}
+}
+
+// 复制出结果并释放系统分配的内存
+func dpapiResult(out *windows.DataBlob) []byte {
+    defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+    return append([]byte{}, unsafe.Slice(out.Data, out.Size)...)
+}
+
+func dpapiProtect(plain []byte) ([]byte, error) {
+    var out windows.DataBlob
+    err := windows.CryptProtectData(dpapiBlob(plain), nil, dpapiBlob(dpapiEntropy), 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+    if err != nil {
+        return nil, err
+    }
+    return dpapiResult(&out), nil
+}
+
+func dpapiUnprotect(sealed []byte) ([]byte, error) {
+    var out windows.DataBlob
+    err := windows.CryptUnprotectData(dpapiBlob(sealed), nil, dpapiBlob(dpapiEntropy), 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+    if err != nil {
+        return nil, err
+    }
+    return dpapiResult(&out), nil
+}
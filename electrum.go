@@ -0,0 +1,201 @@
+package main
+
+import (
+    "bufio"
+    "crypto/sha256"
+    "crypto/tls"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "net"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+)
+
+//
+// -------------------------
+//   -balance-check Electrum 查询（联网，需显式开启）
+// -------------------------
+//
+// 只向用户指定的 Electrum 服务器发送地址的 scripthash，
+// 从不发送助记词、种子或私钥。服务器能把这些地址关联到同一个人。
+// SERVER：ssl://host:port（默认，TLS）或 tcp://host:port；.onion 地址经 Tor 可直接使用。
+//
+
+const electrumTimeout = 30 * time.Second
+
+type electrumClient struct {
+    conn net.Conn
+    r    *bufio.Reader
+    id   int
+}
+
+func dialElectrum(server string, insecure bool) (*electrumClient, error) {
+    scheme, addr, ok := strings.Cut(server, "://")
+    if !ok {
+        scheme, addr = "ssl", server
+    }
+    if _, _, err := net.SplitHostPort(addr); err != nil {
+        return nil, fmt.Errorf("server must be host:port: %v", err)
+    }
+
+    conn, err := dialNetwork(addr, electrumTimeout)
+    if err != nil {
+        return nil, err
+    }
+    switch scheme {
+    case "ssl":
+        host, _, _ := net.SplitHostPort(addr)
+        tc := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: insecure})
+        if err := tc.Handshake(); err != nil {
+            conn.Close()
+            return nil, err
+        }
+        conn = tc
+    case "tcp":
+    default:
+        conn.Close()
+        return nil, fmt.Errorf("unknown scheme '%s' (ssl, tcp)", scheme)
+    }
+
+    c := &electrumClient{conn: conn, r: bufio.NewReaderSize(conn, 1<<20)}
+    if _, err := c.call("server.version", "passphrase_bitcoin", "1.4"); err != nil {
+        conn.Close()
+        return nil, err
+    }
+    return c, nil
+}
+
+// JSON-RPC，每行一条消息
+func (c *electrumClient) call(method string, params ...any) (json.RawMessage, error) {
+    c.id++
+    req, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params})
+    if err != nil {
+        return nil, err
+    }
+    c.conn.SetDeadline(time.Now().Add(electrumTimeout))
+    if _, err := c.conn.Write(append(req, '\n')); err != nil {
+        return nil, err
+    }
+
+    for {
+        line, err := readLimitedLine(c.r)
+        if err != nil {
+            return nil, err
+        }
+        var resp struct {
+            ID     *int            `json:"id"`
+            Result json.RawMessage `json:"result"`
+            Error  json.RawMessage `json:"error"`
+        }
+        if err := json.Unmarshal([]byte(line), &resp); err != nil {
+            return nil, err
+        }
+        // 跳过订阅通知
+        if resp.ID == nil || *resp.ID != c.id {
+            continue
+        }
+        if len(resp.Error) > 0 && string(resp.Error) != "null" {
+            return nil, errors.New(string(resp.Error))
+        }
+        return resp.Result, nil
+    }
+}
+
+func (c *electrumClient) Close() error {
+    return c.conn.Close()
+}
+
+// Electrum 的 scripthash：scriptPubKey 的 SHA-256，字节倒序
+func electrumScriptHash(script []byte) string {
+    sum := sha256.Sum256(script)
+    slices.Reverse(sum[:])
+    return hex.EncodeToString(sum[:])
+}
+
+func checkBalances(server string, insecure bool, mnemonic string, gap int) {
+    via := "via " + networkProxy
+    if clearnet {
+        via = "directly (clearnet)"
+    }
+    fmt.Printf("Connecting to %s %s (only address hashes are sent).\n", server, via)
+    c, err := dialElectrum(server, insecure)
+    if err != nil {
+        log.Fatalf("Error: %s: %v", server, err)
+    }
+    defer c.Close()
+
+    master := bip39Master(mnemonic)
+    var total int64
+    used := 0
+    fmt.Println("Type         Path              Address                                                          Txs  Balance (sat)")
+    for _, t := range []btcaddr.Type{btcaddr.P2PKH, btcaddr.P2SHP2WPKH, btcaddr.P2WPKH, btcaddr.P2TR} {
+        for branch := 0; branch < 2; branch++ {
+            chain, err := master.Derive(fmt.Sprintf("m/%d'/0'/0'/%d", t.Purpose(), branch))
+            if err != nil {
+                log.Fatalf("Error: %v", err)
+            }
+            for i := 0; i < gap; i++ {
+                child, err := chain.Child(uint32(i))
+                if err != nil {
+                    continue
+                }
+                addr, _ := btcaddr.Encode(t, child.PublicKey())
+                script, err := btcaddr.ScriptPubKey(t, child.PublicKey())
+                if err != nil {
+                    log.Fatalf("Error: %v", err)
+                }
+                txs, balance, err := c.scriptHashStatus(electrumScriptHash(script))
+                if err != nil {
+                    log.Fatalf("Error: %s: %v", server, err)
+                }
+                if txs == 0 {
+                    continue
+                }
+                used++
+                total += balance
+                path := fmt.Sprintf("m/%d'/0'/0'/%d/%d", t.Purpose(), branch, i)
+                fmt.Printf("%-12s %-17s %-64s %4d  %d\n", t, path, addr, txs, balance)
+            }
+        }
+    }
+
+    fmt.Println()
+    if used == 0 {
+        fmt.Printf("No history found in the first %d receive and change addresses of each type.\n", gap)
+        return
+    }
+    fmt.Printf("%d address(es) with history, total balance %d sat (%.8f BTC).\n", used, total, float64(total)/1e8)
+}
+
+func (c *electrumClient) scriptHashStatus(scriptHash string) (int, int64, error) {
+    raw, err := c.call("blockchain.scripthash.get_history", scriptHash)
+    if err != nil {
+        return 0, 0, err
+    }
+    var history []json.RawMessage
+    if err := json.Unmarshal(raw, &history); err != nil {
+        return 0, 0, err
+    }
+    if len(history) == 0 {
+        return 0, 0, nil
+    }
+
+    raw, err = c.call("blockchain.scripthash.get_balance", scriptHash)
+    if err != nil {
+        return 0, 0, err
+    }
+    var bal struct {
+        Confirmed   int64 `json:"confirmed"`
+        Unconfirmed int64 `json:"unconfirmed"`
+    }
+    if err := json.Unmarshal(raw, &bal); err != nil {
+        return 0, 0, err
+    }
+    return len(history), bal.Confirmed + bal.Unconfirmed, nil
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+//
+// -------------------------
+//   单词分隔与宽松解析
+// -------------------------
+//
+
+// 输出时单词之间的分隔符（-separator）
+var wordSeparator = " "
+
+func parseSeparator(name string) (string, error) {
+    switch name {
+    case "space":
+        return " ", nil
+    case "newline":
+        return "\n", nil
+    case "comma":
+        return ",", nil
+    }
+    return "", fmt.Errorf("unknown separator '%s' (space, newline, comma)", name)
+}
+
+// -lang 选择的词表
+var phraseLanguage = "english"
+
+// 日文助记词按惯例用全角空格（U+3000）分隔；读入时 NFKD 会把它还原成普通空格，
+// 种子不受影响
+func formatPhrase(phrase string) string {
+    sep := wordSeparator
+    if sep == " " && phraseLanguage == "japanese" {
+        sep = "\u3000"
+    }
+    return strings.Join(strings.Fields(phrase), sep)
+}
+
+// 序号前缀："1."、"12)"、"3:"、"#4"
+var numberingRe = regexp.MustCompile(`^#?[0-9]+[.):]?`)
+
+// 宽松拆分：任意空白、换行、逗号、分号分隔，去掉序号（"1. abandon"、"1.abandon"），
+// 统一小写与 NFKD
+func splitWords(s string) []string {
+    fields := strings.FieldsFunc(s, func(r rune) bool {
+        return unicode.IsSpace(r) || r == ',' || r == ';'
+    })
+    words := make([]string, 0, len(fields))
+    for _, f := range fields {
+        if m := numberingRe.FindString(f); m != "" && (m == f || strings.ContainsAny(m, "#.):")) {
+            f = f[len(m):]
+        }
+        if f = textnorm.Word(f); f != "" {
+            words = append(words, f)
+        }
+    }
+    return words
+}
+
+//
+// -------------------------
+//   备份编码：十进制索引
+// -------------------------
+//
+
+func passphraseIndicesFromBinary() []int {
+    bits := loadMnemonicBits()
+    indices := make([]int, 0, len(bits)/11)
+    for i := 0; i+11 <= len(bits); i += 11 {
+        indices = append(indices, bip39.BitsToInt(bits[i:i+11]))
+    }
+    return indices
+}
+
+// binary.txt 中的熵（演示模式下为公开的演示熵）
+func loadEntropyBits() []bool {
+    if demoMode {
+        return bip39.BytesToBits(demoEntropy)
+    }
+    if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
+        log.Fatalf("Error: %s not found. Use -b first.", binaryPath)
+    }
+    bits, err := readBinaryFile(binaryPath)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", binaryPath, err)
+    }
+    if _, err := wordCountForEntropyBits(len(bits)); err != nil {
+        log.Fatalf("Error: %s: %v", binaryPath, err)
+    }
+    return bits
+}
+
+// 根据熵的位数识别 BIP39 规格（128/160/192/224/256 位 → 12–24 个单词）
+func wordCountForEntropyBits(n int) (int, error) {
+    if n%8 == 0 && bip39.ValidEntropySize(n/8) {
+        return (n + n/32) / 11, nil
+    }
+    return 0, fmt.Errorf("entropy is %d bits, expected 128, 160, 192, 224 or 256", n)
+}
+
+// -entropy-hex：其他地方（硬件随机数发生器、另一台机器）生成的熵，允许空格和 0x 前缀
+func parseEntropyHex(s string) ([]byte, error) {
+    s = strings.TrimPrefix(strings.Join(strings.Fields(s), ""), "0x")
+    entropy, err := hex.DecodeString(s)
+    if err != nil {
+        return nil, fmt.Errorf("not hex: %v", err)
+    }
+    if _, err := wordCountForEntropyBits(len(entropy) * 8); err != nil {
+        return nil, err
+    }
+    return entropy, nil
+}
+
+func printEntropyPhrase(entropy []byte, wordList []string) {
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    fmt.Printf("Passphrase (%d bits, %d words):\n", len(entropy)*8, len(strings.Fields(mnemonic)))
+    printPhrase(mnemonic)
+    fmt.Println("Fingerprint:", masterFingerprint(mnemonic))
+    if explainSteps {
+        printExplanation(entropy, wordList)
+    }
+    warnWeakMnemonic(entropy, wordList)
+}
+
+// -words / -bits → 熵的字节数；-bits 优先，两者都给出时必须一致
+func entropySize(words, bits int) (int, error) {
+    if bits != 0 {
+        n, err := wordCountForEntropyBits(bits)
+        if err != nil {
+            return 0, err
+        }
+        if words != 24 && words != n {
+            return 0, fmt.Errorf("-bits %d makes %d words, not %d", bits, n, words)
+        }
+        return bits / 8, nil
+    }
+    switch words {
+    case 12, 15, 18, 21, 24:
+        return words * 4 / 3, nil
+    }
+    return 0, fmt.Errorf("-words must be 12, 15, 18, 21 or 24, got %d", words)
+}
+
+// 熵加上校验位
+func loadMnemonicBits() []bool {
+    bits := loadEntropyBits()
+    return append(bits, bip39.ChecksumBits(bits)...)
+}
+
+func printDecimalIndices() {
+    indices := passphraseIndicesFromBinary()
+    if !shouldReveal() {
+        fmt.Println("(-d skipped: the indices are the passphrase; answer y or use -reveal)")
+        return
+    }
+    fmt.Println("Passphrase indices:")
+    for i, idx := range indices {
+        fmt.Printf("%04d", idx)
+        if (i+1)%6 == 0 || i == len(indices)-1 {
+            fmt.Println()
+        } else {
+            fmt.Print(" ")
+        }
+    }
+}
+
+func parseDecimalIndices(s string) ([]int, error) {
+    fields := strings.FieldsFunc(s, func(r rune) bool {
+        return r == ' ' || r == ',' || r == '\n' || r == '\t' || r == '\r'
+    })
+    indices := make([]int, 0, len(fields))
+    for _, f := range fields {
+        n, err := strconv.Atoi(f)
+        if err != nil {
+            return nil, fmt.Errorf("'%s' is not a decimal index", f)
+        }
+        if n < 0 || n >= 2048 {
+            return nil, fmt.Errorf("index %d out of range (0–2047)", n)
+        }
+        indices = append(indices, n)
+    }
+    return indices, nil
+}
+
+func importDecimalIndices(s string) {
+    indices, err := parseDecimalIndices(s)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    importIndices(indices)
+}
+
+func importIndices(indices []int) {
+    entropy, err := bip39.EntropyFromIndices(indices)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    importEntropy(entropy)
+}
+
+func importEntropy(entropy []byte) {
+    if _, err := os.Stat(binaryPath); err == nil {
+        log.Fatalf("Error: %s already exists, move it away first.", binaryPath)
+    }
+    if err := writeBinaryFile(binaryPath, entropy); err != nil {
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    if !jsonOutput {
+        fmt.Printf("%s imported successfully.\n", binaryPath)
+        fmt.Println("Its wallet birthday is unknown; record the date of first use with -set-birthday YYYY-MM-DD.")
+    }
+    warnWeakMnemonic(entropy, loadWordList())
+    transcript.recordBinary("import binary.txt", "ok", loadWordList())
+}
+
+//
+// -------------------------
+//   备份编码：打孔网格
+// -------------------------
+//
+
+// 打孔 = 1，不打孔 = 0
+const (
+    gridPunched = "●"
+    gridBlank   = "·"
+)
+
+func printPunchGrid() {
+    indices := passphraseIndicesFromBinary()
+    if !shouldReveal() {
+        fmt.Println("(-g skipped: the grid is the passphrase; answer y or use -reveal)")
+        return
+    }
+    fmt.Println("Punch card grid (● = punch, · = leave blank):")
+    fmt.Println()
+    fmt.Print("Row ")
+    for i := 10; i >= 0; i-- {
+        fmt.Printf(" %4d", 1<<i)
+    }
+    fmt.Println()
+    for row, idx := range indices {
+        fmt.Printf("%02d  ", row+1)
+        for i := 10; i >= 0; i-- {
+            cell := gridBlank
+            if (idx>>i)&1 == 1 {
+                cell = gridPunched
+            }
+            fmt.Printf("    %s", cell)
+        }
+        fmt.Println()
+    }
+}
+
+// 单元格：1/0、●/·/○、x/.
+func gridCell(r rune) (bool, bool) {
+    switch r {
+    case '1', 'x', 'X', '●':
+        return true, true
+    case '0', '.', '·', '○':
+        return false, true
+    }
+    return false, false
+}
+
+func isGridLabel(line string) bool {
+    r := []rune(line)[0]
+    return unicode.IsLetter(r) && r != 'x' && r != 'X'
+}
+
+// 每行 11 格
+const gridWidth = 11
+
+func parseGridLine(line string) ([]bool, error) {
+    fields := strings.Fields(line)
+    // 去掉行号：带 . : ) 的（"1."、"12:"）一定是行号；纯数字的（"01"）
+    // 只在去掉后恰好剩 11 格时才是，"1 0 1 1 0 0 1 1 0 1 0" 整行都是格子
+    if len(fields) > 1 {
+        first := fields[0]
+        if n := strings.TrimRight(first, ".:)"); n != first {
+            if _, err := strconv.Atoi(n); err == nil {
+                fields = fields[1:]
+            }
+        } else if _, err := strconv.Atoi(first); err == nil && countGridCells(fields[1:]) == gridWidth {
+            fields = fields[1:]
+        }
+    }
+
+    var cells []bool
+    for _, f := range fields {
+        for _, r := range f {
+            v, ok := gridCell(r)
+            if !ok {
+                return nil, fmt.Errorf("unexpected character %q", r)
+            }
+            cells = append(cells, v)
+        }
+    }
+    return cells, nil
+}
+
+func countGridCells(fields []string) int {
+    n := 0
+    for _, f := range fields {
+        n += utf8.RuneCountInString(f)
+    }
+    return n
+}
+
+func parsePunchGrid(text string) ([]int, error) {
+    var indices []int
+    for n, line := range strings.Split(text, "\n") {
+        line = strings.TrimSpace(line)
+        // 空行、说明与表头
+        if line == "" || isGridLabel(line) {
+            continue
+        }
+        cells, err := parseGridLine(line)
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", n+1, err)
+        }
+        if len(cells) != gridWidth {
+            return nil, fmt.Errorf("line %d: got %d cells, expected %d", n+1, len(cells), gridWidth)
+        }
+        indices = append(indices, bip39.BitsToInt(cells))
+    }
+    return indices, nil
+}
+
+func importPunchGrid(filename string) {
+    data, err := readFileLimited(filename, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+    indices, err := parsePunchGrid(string(data))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    importIndices(indices)
+}
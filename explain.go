@@ -0,0 +1,65 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -explain 逐步列出中间值
+// -------------------------
+//
+// 供审计时手工核对 BIP39 的每一步：熵的十六进制、熵的完整 SHA-256、
+// 从摘要开头取出的校验位，以及熵加校验位切成的 11 位片段与对应的
+// 序号和单词。与 -p、-no-file、-decode、-entropy-hex 一起使用，
+// 在助记词之后输出。内容与助记词同样是秘密，终端上遮住单词时不输出。
+//
+
+var explainSteps bool
+
+func printExplanation(entropy []byte, wordList []string) {
+    if !shouldReveal() {
+        fmt.Println("(-explain skipped: the words are masked; answer y or use -reveal)")
+        return
+    }
+    bits := bip39.BytesToBits(entropy)
+    digest := sha256.Sum256(entropy)
+    checksum := bip39.ChecksumBits(bits)
+    all := append(append([]bool{}, bits...), checksum...)
+    n := len(all) / 11
+    cut := 11 - len(checksum)
+
+    fmt.Println()
+    fmt.Println("How the words are made (BIP39):")
+    fmt.Printf("1. Entropy, %d bits (%d bytes):\n", len(bits), len(entropy))
+    fmt.Printf("     %s\n", hex.EncodeToString(entropy))
+    fmt.Println("2. SHA-256 of the entropy bytes:")
+    fmt.Printf("     %s\n", hex.EncodeToString(digest[:]))
+    fmt.Printf("3. Checksum: the first %d bits (entropy bits / 32) of the digest.\n", len(checksum))
+    fmt.Printf("     digest byte 0 = 0x%02x = %08b  →  checksum %s\n", digest[0], digest[0], bitString(checksum))
+    fmt.Printf("4. Entropy followed by the checksum is %d bits, cut into %d pieces of 11 bits;\n", len(all), n)
+    fmt.Println("   each piece, read as a binary number, is the word's index (from 0) in the list.")
+    fmt.Println("      #  11 bits       index  word")
+    for i := 0; i < n; i++ {
+        piece := all[i*11 : (i+1)*11]
+        group := bitString(piece)
+        if i == n-1 {
+            group = group[:cut] + " " + group[cut:]
+        }
+        idx := bip39.BitsToInt(piece)
+        fmt.Printf("     %2d  %-12s  %5d  %s\n", i+1, group, idx, wordList[idx])
+    }
+    fmt.Printf("   The last piece is %d entropy bits and the %d checksum bits.\n", cut, len(checksum))
+    fmt.Printf("5. Word list: %s; index i is the word on line i+1.\n", wordListName())
+}
+
+func wordListName() string {
+    if customWordlist != "" {
+        return customWordlist + " (non-standard)"
+    }
+    return phraseLanguage
+}
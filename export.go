@@ -0,0 +1,179 @@
+package main
+
+import (
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/base58"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+)
+
+//
+// -------------------------
+//   -export 其他工具的格式
+// -------------------------
+//
+// ian-coleman-json：Ian Coleman BIP39 页面上显示的全部内容（熵、助记词、
+// 口令、种子、根密钥、账户与 BIP32 扩展密钥、派生地址表），便于在两个
+// 工具之间迁移。文件含明文私钥，只以 0600 新建，用完请销毁。
+//
+
+type ianColemanState struct {
+    Entropy        string          `json:"entropy"`
+    Mnemonic       string          `json:"mnemonic"`
+    Passphrase     string          `json:"passphrase"`
+    Seed           string          `json:"seed"`
+    RootKey        string          `json:"bip32RootKey"`
+    Purpose        uint32          `json:"purpose"`
+    AccountXprv    string          `json:"accountExtendedPrivateKey"`
+    AccountXpub    string          `json:"accountExtendedPublicKey"`
+    DerivationPath string          `json:"derivationPath"`
+    Xprv           string          `json:"bip32ExtendedPrivateKey"`
+    Xpub           string          `json:"bip32ExtendedPublicKey"`
+    Rows           []ianColemanRow `json:"derivedAddresses"`
+    Params         *derivationParams `json:"params,omitempty"`
+}
+
+type ianColemanRow struct {
+    Path       string `json:"path"`
+    Address    string `json:"address"`
+    PublicKey  string `json:"publicKey"`
+    PrivateKey string `json:"privateKey"`
+}
+
+func exportTo(format, path, addrType string, count int, wordList []string) {
+    if format != "ian-coleman-json" {
+        log.Fatalf("Error: unknown export format '%s' (ian-coleman-json)", format)
+    }
+    t := btcaddr.P2PKH // 与 Ian Coleman 页面默认的 BIP44 标签一致
+    if addrType != "" {
+        var err error
+        if t, err = btcaddr.ParseType(addrType); err != nil {
+            log.Fatalf("Error: -addr-type: %v", err)
+        }
+    }
+    if count < 1 || count > maxSheetAddresses {
+        log.Fatalf("Error: -count must be 1–%d", maxSheetAddresses)
+    }
+
+    passphrase, err := readSecret("BIP39 passphrase (Enter for none): ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    state, err := ianColemanExport(entropy, passphrase, t, count, wordList)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    data, err := json.MarshalIndent(state, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error creating %s: %v", path, err)
+    }
+    if _, err := f.Write(append(data, '\n')); err != nil {
+        f.Close()
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+    if err := f.Close(); err != nil {
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+    fmt.Printf("%s written (%s, %s, %d addresses).\n", path, state.DerivationPath, t, count)
+    state.Params.print()
+    fmt.Println("Warning: the file holds the mnemonic and private keys in plain text.")
+}
+
+func ianColemanExport(entropy []byte, passphrase string, t btcaddr.Type, count int, wordList []string) (*ianColemanState, error) {
+    mnemonic, err := bip39.NewMnemonic(entropy, wordList)
+    if err != nil {
+        return nil, err
+    }
+    seed := mnemonic.Seed(passphrase)
+    master, err := bip32.NewMaster(seed)
+    if err != nil {
+        return nil, err
+    }
+
+    accountPath := fmt.Sprintf("m/%d'/0'/0'", t.Purpose())
+    account, err := master.Derive(accountPath)
+    if err != nil {
+        return nil, err
+    }
+    chain, err := account.Child(0)
+    if err != nil {
+        return nil, err
+    }
+
+    s := &ianColemanState{
+        Entropy:        hex.EncodeToString(entropy),
+        Mnemonic:       string(mnemonic),
+        Passphrase:     passphrase,
+        Seed:           hex.EncodeToString(seed),
+        RootKey:        master.Serialize(bip32.VersionXPrv),
+        Purpose:        t.Purpose(),
+        AccountXprv:    account.Serialize(bip32.VersionXPrv),
+        AccountXpub:    account.Neuter().Serialize(bip32.VersionXPub),
+        DerivationPath: accountPath + "/0",
+        Xprv:           chain.Serialize(bip32.VersionXPrv),
+        Xpub:           chain.Neuter().Serialize(bip32.VersionXPub),
+    }
+    params := newDerivationParams(nil, s.DerivationPath+"/i")
+    params.Extra = fmt.Sprintf("%s addresses", t)
+    s.Params = &params
+    for i := 0; i < count; i++ {
+        child, err := chain.Child(uint32(i))
+        if err != nil {
+            return nil, err
+        }
+        address, err := btcaddr.Encode(t, child.PublicKey())
+        if err != nil {
+            return nil, err
+        }
+        s.Rows = append(s.Rows, ianColemanRow{
+            Path:       fmt.Sprintf("%s/%d", s.DerivationPath, i),
+            Address:    address,
+            PublicKey:  hex.EncodeToString(child.PublicKey()),
+            PrivateKey: wif(child.Key),
+        })
+    }
+    return s, nil
+}
+
+// 主网压缩公钥的 WIF：0x80 || 私钥 || 0x01
+func wif(key []byte) string {
+    return base58.CheckEncode(append(append([]byte{0x80}, key...), 0x01))
+}
+
+// 导入时核对文件中的派生地址表：路径与地址必须由同一助记词和口令得到
+func checkIanColemanRows(mnemonic, passphrase string, rows []ianColemanRow) error {
+    master, err := bip32.NewMaster(bip39.Mnemonic(mnemonic).Seed(passphrase))
+    if err != nil {
+        return err
+    }
+    for _, r := range rows {
+        target, err := parseRecoveryTarget(r.Address, 0)
+        if err != nil || target.fingerprint != nil {
+            return fmt.Errorf("row %s: invalid address %q", r.Path, r.Address)
+        }
+        child, err := master.Derive(r.Path)
+        if err != nil {
+            return fmt.Errorf("row %s: %v", r.Path, err)
+        }
+        address, err := btcaddr.Encode(target.addrType, child.PublicKey())
+        if err != nil {
+            return err
+        }
+        if address != target.address {
+            return fmt.Errorf("row %s: file has %s, mnemonic gives %s", r.Path, r.Address, address)
+        }
+    }
+    return nil
+}
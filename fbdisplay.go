@@ -0,0 +1,141 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/skip2/go-qrcode"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/framebuffer"
+)
+
+//
+// -------------------------
+//   -fb 直接显示到帧缓冲
+// -------------------------
+//
+// 树莓派等无头签名机常接小屏或墨水屏（/dev/fb0、/dev/fb1）。-fb 把 binary.txt
+// 的二维码和编号单词表直接画到帧缓冲上，不经过终端模拟器（也就不进入回滚缓冲、
+// tmux 或终端日志）。按回车后清屏。
+// 内置字体只有小写拉丁字母与数字，所以只支持纯 ASCII 的词表。
+//
+
+// 单词表行距（字体像素）
+const fbLineHeight = framebuffer.GlyphHeight + 2
+
+func showOnFramebuffer(path string, wordList []string) {
+    passphrase := generatePassphraseFromBinary(wordList)
+    words := strings.Fields(passphrase)
+    header := "fingerprint " + masterFingerprint(passphrase)
+
+    labels := make([]string, len(words))
+    for i, w := range words {
+        labels[i] = fmt.Sprintf("%2d. %s", i+1, w)
+        if !framebuffer.CanDraw(labels[i]) {
+            log.Fatalf("Error: -fb: the built-in font has only a-z and digits; use an ASCII wordlist (-lang english, italian or czech)")
+        }
+    }
+
+    qr, err := qrcode.New(qrPayload(passphrase), qrcode.Low)
+    if err != nil {
+        log.Fatalf("Error generating QR code: %v", err)
+    }
+    bm := qr.Bitmap()
+
+    dev, err := framebuffer.Open(path)
+    if err != nil {
+        log.Fatalf("Error: -fb: %v", err)
+    }
+    defer dev.Close()
+
+    pages, err := layoutFramebuffer(dev.Width, dev.Height, bm, header, labels)
+    if err != nil {
+        log.Fatalf("Error: -fb %s (%dx%d): %v", path, dev.Width, dev.Height, err)
+    }
+    for i, page := range pages {
+        if err := dev.Draw(page); err != nil {
+            log.Fatalf("Error: -fb: %v", err)
+        }
+        next := "clear the display"
+        if i < len(pages)-1 {
+            next = "show the word table"
+        }
+        fmt.Printf("Passphrase shown on %s (%dx%d, page %d/%d). Press Enter to %s.\n", path, dev.Width, dev.Height, i+1, len(pages), next)
+        readLine()
+    }
+    if err := dev.Clear(); err != nil {
+        log.Fatalf("Error: -fb: clearing %s: %v", path, err)
+    }
+    fmt.Println("Display cleared.")
+}
+
+// 优先把二维码和单词表放在同一屏：横屏时二维码在左，竖屏时在上。
+// 屏幕太小（如 250×122 的墨水屏）时分成两页，先二维码，回车后单词表。
+func layoutFramebuffer(w, h int, bm [][]bool, header string, labels []string) ([]*framebuffer.Canvas, error) {
+    modules := len(bm)
+    if min(w, h) < modules {
+        return nil, fmt.Errorf("too small for a %d-module QR code", modules)
+    }
+
+    c := framebuffer.NewCanvas(w, h)
+    if w >= h {
+        side := min(h, w/2) / modules * modules
+        if side > 0 && drawWordTable(c, side, 0, w-side, h, header, labels) == nil {
+            c.Bitmap(0, (h-side)/2, side/modules, bm)
+            return []*framebuffer.Canvas{c}, nil
+        }
+    } else {
+        side := min(w, h/2) / modules * modules
+        if side > 0 && drawWordTable(c, 0, side, w, h-side, header, labels) == nil {
+            c.Bitmap((w-side)/2, 0, side/modules, bm)
+            return []*framebuffer.Canvas{c}, nil
+        }
+    }
+
+    qrPage := framebuffer.NewCanvas(w, h)
+    scale := min(w, h) / modules
+    qrPage.Bitmap((w-modules*scale)/2, (h-modules*scale)/2, scale, bm)
+    wordPage := framebuffer.NewCanvas(w, h)
+    if err := drawWordTable(wordPage, 0, 0, w, h, header, labels); err != nil {
+        return nil, err
+    }
+    return []*framebuffer.Canvas{qrPage, wordPage}, nil
+}
+
+// 在 (x, y, w, h) 区域内画标题和按列排列的单词表，列数（1–4）与字号取使文字最大的组合
+func drawWordTable(c *framebuffer.Canvas, x, y, w, h int, header string, labels []string) error {
+    colChars := 0
+    for _, l := range labels {
+        colChars = max(colChars, len(l))
+    }
+    charWidth := framebuffer.TextWidth(" ", 1)
+
+    bestScale, bestCols := 0, 0
+    for cols := 1; cols <= 4; cols++ {
+        rows := (len(labels) + cols - 1) / cols
+        // 列间空 2 个字符，上方为标题加一空行，四周留一个字体像素
+        lineChars := max(cols*(colChars+2)-2, len(header))
+        scale := min((w-2)/(lineChars*charWidth), (h-2)/((rows+2)*fbLineHeight))
+        if scale > bestScale {
+            bestScale, bestCols = scale, cols
+        }
+    }
+    if bestScale < 1 {
+        return fmt.Errorf("too small for %d words", len(labels))
+    }
+
+    scale, rows := bestScale, (len(labels)+bestCols-1)/bestCols
+    x += scale
+    y += scale
+    if err := c.Text(x, y, scale, header); err != nil {
+        return err
+    }
+    colWidth := (colChars + 2) * charWidth * scale
+    for i, l := range labels {
+        if err := c.Text(x+(i/rows)*colWidth, y+(2+i%rows)*fbLineHeight*scale, scale, l); err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+    "encoding/hex"
+    "log"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/seedkdf"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+//
+// -------------------------
+//   主密钥与主指纹
+// -------------------------
+//
+// 各功能用主指纹（BIP32 主公钥 hash160 的前 4 字节，与硬件钱包显示的相同）
+// 标识一份助记词：文件、分享、日志、统计里只记指纹，不记单词。
+// 指纹取决于单词本身，同一份熵换一种语言的词表，指纹也不同。
+//
+
+// BIP32 主密钥（BIP39 种子，空口令），不受 -kdf 影响
+func bip39Master(mnemonic string) *bip32.Key {
+    seed, err := seedkdf.BIP39{}.Seed(textnorm.Mnemonic(mnemonic), "")
+    if err != nil {
+        log.Fatalf("Error deriving seed: %v", err)
+    }
+    master, err := bip32.NewMaster(seed)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    return master
+}
+
+func masterFingerprint(mnemonic string) string {
+    fp := bip39Master(mnemonic).Fingerprint()
+    return hex.EncodeToString(fp[:])
+}
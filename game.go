@@ -0,0 +1,119 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "math"
+    "os"
+    "strings"
+    "time"
+
+    "golang.org/x/term"
+)
+
+//
+// -------------------------
+//   -b -game 用户随机性
+// -------------------------
+//
+// 让用户乱敲键盘，记录每次按键的键值与时间，实时显示保守的熵估计；估计值
+// 达到熵的位数后才允许生成。收集到的数据经 SHA-256 与操作系统随机数混合，
+// 所以结果绝不会比单独用 crypto/rand 更弱——游戏让“用户随机性”可以度量，
+// 而不是想当然。
+//
+// 估计规则（每次按键）：
+//   时间：与前两次按键间隔之差 d（毫秒），计 log2(d) 位，最多 2 位
+//   键值：此前 n 次按键中该键出现 c 次，计 log2((n+1)/(c+1)) 位，最多 1 位
+// 一直按同一个键或匀速敲击，估计几乎不增长。
+//
+
+const (
+    gameMaxTimingBits = 2.0
+    gameMaxChoiceBits = 1.0
+    gameBarWidth      = 32
+)
+
+var errGameAborted = errors.New("entropy game aborted")
+
+type entropyGame struct {
+    pool     []byte // 键值与纳秒时间戳，最后整体哈希
+    counts   map[byte]int
+    presses  int
+    last     time.Time
+    interval time.Duration
+    estimate float64
+}
+
+// 返回收集到的数据的 SHA-256；need 为要求的估计位数
+func runEntropyGame(need int) ([]byte, error) {
+    fd := int(os.Stdin.Fd())
+    if !term.IsTerminal(fd) {
+        return nil, errors.New("-game needs an interactive terminal")
+    }
+    state, err := term.MakeRaw(fd)
+    if err != nil {
+        return nil, err
+    }
+    defer term.Restore(fd, state)
+
+    fmt.Fprint(os.Stderr, "Mash the keyboard: vary the keys and the rhythm. Ctrl-C aborts.\r\n")
+    fmt.Fprint(os.Stderr, "Enter finishes once the estimate is reached.\r\n")
+
+    g := &entropyGame{counts: map[byte]int{}}
+    buf := make([]byte, 1)
+    for {
+        g.printProgress(need)
+        if _, err := os.Stdin.Read(buf); err != nil {
+            return nil, err
+        }
+        now := time.Now()
+        switch c := buf[0]; {
+        case c == 3 || c == 4: // Ctrl-C / Ctrl-D
+            fmt.Fprint(os.Stderr, "\r\n")
+            return nil, errGameAborted
+        case (c == '\r' || c == '\n') && g.estimate >= float64(need):
+            fmt.Fprint(os.Stderr, "\r\n")
+            sum := sha256.Sum256(g.pool)
+            return sum[:], nil
+        default:
+            g.press(c, now)
+        }
+    }
+}
+
+func (g *entropyGame) press(c byte, now time.Time) {
+    g.pool = append(g.pool, c)
+    g.pool = binary.BigEndian.AppendUint64(g.pool, uint64(now.UnixNano()))
+
+    // 键值：越少见的键计得越多
+    choice := math.Log2(float64(g.presses+1) / float64(g.counts[c]+1))
+    g.estimate += math.Min(choice, gameMaxChoiceBits)
+
+    // 时间：节奏的变化，而不是间隔本身
+    if !g.last.IsZero() {
+        interval := now.Sub(g.last)
+        if g.interval != 0 {
+            d := (interval - g.interval).Abs().Milliseconds()
+            if d > 1 {
+                g.estimate += math.Min(math.Log2(float64(d)), gameMaxTimingBits)
+            }
+        }
+        g.interval = interval
+    }
+    g.last = now
+    g.counts[c]++
+    g.presses++
+}
+
+func (g *entropyGame) printProgress(need int) {
+    done := min(int(g.estimate), need)
+    filled := done * gameBarWidth / need
+    bar := strings.Repeat("#", filled) + strings.Repeat(".", gameBarWidth-filled)
+    status := "keep going"
+    if done >= need {
+        status = "press Enter"
+    }
+    fmt.Fprintf(os.Stderr, "\r[%s] %3d/%d bits, %d keys  %-11s", bar, done, need, g.presses, status)
+}
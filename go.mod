@@ -1,7 +1,15 @@
-module passphrase_bitcoin
+module github.com/li-han-zhang/Go_passphrase
 
 go 1.25.4
 
 replace github.com/skip2/go-qrcode => ./go-qrcode
 
-require github.com/skip2/go-qrcode v0.0.0-00010101000000-000000000000
+require github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.1
+	golang.org/x/crypto v0.45.0
+	golang.org/x/sys v0.38.0
+	golang.org/x/term v0.37.0
+	golang.org/x/text v0.31.0
+)
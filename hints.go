@@ -0,0 +1,161 @@
+package main
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "golang.org/x/crypto/scrypt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+//
+// -------------------------
+//   -hint-* 口令提示
+// -------------------------
+//
+// hints.txt 每行：标签、盐、scrypt 哈希、提示文字（制表符分隔）。
+// 只保存口令的加盐慢哈希，不派生任何密钥；以后可以用 -hint-check
+// 确认“是不是这个口令”。哈希仍可被离线穷举，弱口令不要登记。
+//
+
+const hintsFile = "hints.txt"
+
+// 比加密文件用的参数更慢：每次约 0.5 秒
+const hintScryptN = 1 << 17
+
+type hintEntry struct {
+    label string
+    salt  []byte
+    hash  []byte
+    hint  string
+}
+
+func hintHash(passphrase string, salt []byte) ([]byte, error) {
+    return scrypt.Key([]byte(textnorm.Passphrase(passphrase)), salt, hintScryptN, scryptR, scryptP, 32)
+}
+
+func addHint(label string) {
+    label = strings.TrimSpace(label)
+    if label == "" || strings.ContainsAny(label, "\t\n") {
+        log.Fatalf("Error: invalid label %q", label)
+    }
+    hints, err := readHints()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", hintsFile, err)
+    }
+    for _, h := range hints {
+        if h.label == label {
+            log.Fatalf("Error: %s already has a hint labelled %q", hintsFile, label)
+        }
+    }
+
+    fmt.Fprint(os.Stderr, "Hint (stored in plain text): ")
+    hint, err := readLine()
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    passphrase, err := readNewSecret("BIP39 passphrase: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    salt := make([]byte, 16)
+    if _, err := rand.Read(salt); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    hash, err := hintHash(passphrase, salt)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    f, err := os.OpenFile(hintsFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+    if err != nil {
+        log.Fatalf("Error writing %s: %v", hintsFile, err)
+    }
+    _, err = fmt.Fprintf(f, "%s\t%x\t%x\t%s\n", label, salt, hash, strings.ReplaceAll(hint, "\t", " "))
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    if err != nil {
+        log.Fatalf("Error writing %s: %v", hintsFile, err)
+    }
+    fmt.Printf("Hint %q recorded in %s.\n", label, hintsFile)
+}
+
+func checkHint(label string) {
+    hints, err := readHints()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", hintsFile, err)
+    }
+    for _, h := range hints {
+        if h.label != label {
+            continue
+        }
+        fmt.Printf("Hint: %s\n", h.hint)
+        passphrase, err := readSecret("BIP39 passphrase to test: ")
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        hash, err := hintHash(passphrase, h.salt)
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if secretcmp.Equal(hash, h.hash) {
+            fmt.Println("Yes, that is the recorded passphrase.")
+            transcript.record("hint-check "+label, "match", "", "")
+            return
+        }
+        fmt.Println("No, that is not the recorded passphrase.")
+        transcript.record("hint-check "+label, "no match", "", "")
+        transcript.finish()
+        os.Exit(1)
+    }
+    log.Fatalf("Error: no hint labelled %q in %s", label, hintsFile)
+}
+
+func printHints() {
+    hints, err := readHints()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", hintsFile, err)
+    }
+    if len(hints) == 0 {
+        fmt.Printf("%s is empty or missing.\n", hintsFile)
+        return
+    }
+    for _, h := range hints {
+        fmt.Printf("%-16s %s\n", h.label, h.hint)
+    }
+}
+
+func readHints() ([]hintEntry, error) {
+    data, err := readFileLimited(hintsFile, maxTextFileSize)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var hints []hintEntry
+    for n, line := range strings.Split(string(data), "\n") {
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+        fields := strings.SplitN(line, "\t", 4)
+        if len(fields) != 4 {
+            return nil, fmt.Errorf("line %d: expected 4 tab-separated fields", n+1)
+        }
+        salt, err1 := hex.DecodeString(fields[1])
+        hash, err2 := hex.DecodeString(fields[2])
+        if err1 != nil || err2 != nil {
+            return nil, fmt.Errorf("line %d: bad hex", n+1)
+        }
+        hints = append(hints, hintEntry{label: fields[0], salt: salt, hash: hash, hint: fields[3]})
+    }
+    return hints, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+    "crypto/hmac"
+    "crypto/sha512"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "log"
+    "regexp"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/base58"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -import 其他工具的导出格式
+// -------------------------
+//
+
+func importFrom(format, filename string, wordList []string) {
+    data, err := readFileLimited(filename, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+
+    switch format {
+    case "ian-coleman-json":
+        importIanColemanJSON(data, wordList)
+    case "electrum":
+        importElectrumSeed(string(data), wordList)
+    case "descriptor":
+        inspectDescriptor(strings.TrimSpace(string(data)))
+    default:
+        log.Fatalf("Error: unknown import format '%s' (ian-coleman-json, electrum, descriptor)", format)
+    }
+}
+
+// 描述符只含公开信息，只读模式下也可用
+func inspectDescriptorFile(filename string) {
+    data, err := readFileLimited(filename, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+    inspectDescriptor(strings.TrimSpace(string(data)))
+}
+
+// Ian Coleman BIP39 页面的 JSON：只导入熵与助记词；口令不导入，
+// 但用来核对文件中的派生地址表（-export -to ian-coleman-json 的输出）
+func importIanColemanJSON(data []byte, wordList []string) {
+    var doc map[string]any
+    if err := json.Unmarshal(data, &doc); err != nil {
+        log.Fatalf("Error parsing JSON: %v", err)
+    }
+    field := func(names ...string) string {
+        for k, v := range doc {
+            for _, n := range names {
+                if s, ok := v.(string); ok && strings.EqualFold(k, n) {
+                    return strings.TrimSpace(s)
+                }
+            }
+        }
+        return ""
+    }
+
+    entropyHex := field("entropy", "bip39Entropy", "bip39_entropy")
+    phrase := field("mnemonic", "phrase", "bip39Phrase", "bip39_phrase")
+    passphrase := field("passphrase", "bip39Passphrase", "bip39_passphrase")
+    if passphrase != "" {
+        fmt.Println("Note: the BIP39 passphrase in the file is not imported, keep it separately.")
+    }
+
+    var entropy []byte
+    if phrase != "" {
+        e, err := entropyFromPhrase(phrase, wordList)
+        if err != nil {
+            log.Fatalf("Error: mnemonic in file is invalid: %v", err)
+        }
+        entropy = e
+    }
+    if entropyHex != "" {
+        e, err := hex.DecodeString(entropyHex)
+        if err != nil {
+            log.Fatalf("Error: entropy in file is not hex: %v", err)
+        }
+        if _, err := wordCountForEntropyBits(len(e) * 8); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if entropy != nil && hex.EncodeToString(entropy) != strings.ToLower(entropyHex) {
+            log.Fatalf("Error: entropy and mnemonic in file do not match")
+        }
+        entropy = e
+    }
+    if entropy == nil {
+        log.Fatalf("Error: file has neither entropy nor mnemonic")
+    }
+
+    var state ianColemanState
+    if err := json.Unmarshal(data, &state); err == nil && len(state.Rows) > 0 {
+        if err := checkIanColemanRows(mnemonicFromEntropy(entropy, wordList), passphrase, state.Rows); err != nil {
+            log.Fatalf("Error: derived addresses in file: %v", err)
+        }
+        fmt.Printf("%d derived addresses in the file match.\n", len(state.Rows))
+    }
+    importEntropy(entropy)
+}
+
+func entropyFromPhrase(phrase string, wordList []string) ([]byte, error) {
+    words := splitWords(phrase)
+    indices, err := bip39.Indices(words, wordList)
+    if err != nil {
+        // 找出第一个不在表中的单词，给出拼写建议
+        matcher := wordmatch.New(wordList)
+        for i, w := range words {
+            if c, ok := matcher.Lookup(w); !ok || c.Word != w {
+                return nil, fmt.Errorf("word %d '%s' is not on the list%s", i+1, w, didYouMean(matcher, w))
+            }
+        }
+        return nil, err
+    }
+    return bip39.EntropyFromIndices(indices)
+}
+
+// Electrum 自有格式的种子与 BIP39 不兼容，无法换算成熵，只能识别类型
+func importElectrumSeed(text string, wordList []string) {
+    seed := electrumNormalize(text)
+    if e, err := entropyFromPhrase(seed, wordList); err == nil {
+        fmt.Println("Seed is a valid BIP39 mnemonic.")
+        importEntropy(e)
+        return
+    }
+
+    mac := hmac.New(sha512.New, []byte("Seed version"))
+    mac.Write([]byte(seed))
+    version := hex.EncodeToString(mac.Sum(nil))
+
+    kinds := []struct{ prefix, name string }{
+        {"01", "standard"},
+        {"100", "segwit"},
+        {"101", "2fa"},
+        {"102", "2fa segwit"},
+    }
+    for _, k := range kinds {
+        if strings.HasPrefix(version, k.prefix) {
+            fmt.Printf("Electrum %s seed (%d words).\n", k.name, len(strings.Fields(seed)))
+            fmt.Println("Electrum seeds are not BIP39: they cannot be converted to BIP39 entropy.")
+            fmt.Println("Restore it in Electrum and move the funds to a new BIP39 wallet instead.")
+            return
+        }
+    }
+    log.Fatalf("Error: text is neither a BIP39 mnemonic nor an Electrum seed")
+}
+
+func electrumNormalize(s string) string {
+    s = textnorm.NFKD(strings.ToLower(s))
+    var b strings.Builder
+    for _, r := range s {
+        // 去掉组合附加符号（重音）
+        if r >= 0x300 && r <= 0x36f {
+            continue
+        }
+        b.WriteRune(r)
+    }
+    return strings.Join(strings.Fields(b.String()), " ")
+}
+
+//
+// -------------------------
+//   输出描述符（BIP380）
+// -------------------------
+//
+
+var descriptorKeyRe = regexp.MustCompile(`(?:\[([0-9a-fA-F]{8})((?:/[0-9]+['hH]?)*)\])?([xtyzuv]pub[1-9A-HJ-NP-Za-km-z]+)((?:/[0-9*]+['hH]?)*)`)
+
+// 描述符只含公钥，没有熵：校验格式与 checksum 并展示其中的内容
+func inspectDescriptor(desc string) {
+    body, sum, hasSum := strings.Cut(desc, "#")
+    if hasSum {
+        want, err := descriptorChecksum(body)
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if want != sum {
+            log.Fatalf("Error: descriptor checksum %s does not match, expected %s", sum, want)
+        }
+        fmt.Println("Checksum: OK")
+    } else {
+        fmt.Println("Checksum: missing")
+    }
+
+    if i := strings.Index(body, "("); i > 0 {
+        fmt.Println("Script:", body[:i])
+    }
+
+    keys := descriptorKeyRe.FindAllStringSubmatch(body, -1)
+    if len(keys) == 0 {
+        fmt.Println("No extended public keys found.")
+    }
+    for i, k := range keys {
+        fmt.Printf("Key %d:\n", i+1)
+        if k[1] != "" {
+            fmt.Println("  Fingerprint:", strings.ToLower(k[1]))
+            fmt.Println("  Origin:      m" + k[2])
+        }
+        status := "valid"
+        if payload, err := base58.CheckDecode(k[3]); err != nil {
+            status = err.Error()
+        } else if len(payload) != 78 {
+            status = "wrong length"
+        }
+        fmt.Printf("  Key:         %s (%s)\n", k[3], status)
+        if k[4] != "" {
+            fmt.Println("  Derivation: ", k[4])
+        }
+    }
+    fmt.Println("Descriptors hold public keys only: nothing to import into binary.txt.")
+}
+
+const (
+    descInputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+    descChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+func descriptorPolymod(c uint64, val int) uint64 {
+    gen := []uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+    c0 := c >> 35
+    c = (c&0x7ffffffff)<<5 ^ uint64(val)
+    for i, g := range gen {
+        if (c0>>i)&1 == 1 {
+            c ^= g
+        }
+    }
+    return c
+}
+
+func descriptorChecksum(desc string) (string, error) {
+    c := uint64(1)
+    cls, clsCount := 0, 0
+    for _, ch := range desc {
+        pos := strings.IndexRune(descInputCharset, ch)
+        if pos < 0 {
+            return "", fmt.Errorf("invalid character %q in descriptor", ch)
+        }
+        c = descriptorPolymod(c, pos&31)
+        cls = cls*3 + pos>>5
+        clsCount++
+        if clsCount == 3 {
+            c = descriptorPolymod(c, cls)
+            cls, clsCount = 0, 0
+        }
+    }
+    if clsCount > 0 {
+        c = descriptorPolymod(c, cls)
+    }
+    for i := 0; i < 8; i++ {
+        c = descriptorPolymod(c, 0)
+    }
+    c ^= 1
+
+    out := make([]byte, 8)
+    for i := range out {
+        out[i] = descChecksumCharset[(c>>(5*(7-i)))&31]
+    }
+    return string(out), nil
+}
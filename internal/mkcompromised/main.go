@@ -0,0 +1,78 @@
+// Command mkcompromised builds embed/compromised.bloom, the Bloom filter of
+// publicly known mnemonics, from embed/compromised.txt plus generated
+// families of trivially structured phrases. Run it with go generate from
+// the repository root.
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bloom"
+)
+
+// False-positive rate: a random phrase is wrongly flagged once in 10⁹.
+const falsePositiveRate = 1e-9
+
+func main() {
+    wordList := bip39.English()
+    seen := map[string]bool{}
+    var entries [][]byte
+    add := func(e bip39.Entropy) {
+        if !seen[string(e)] {
+            seen[string(e)] = true
+            entries = append(entries, e)
+        }
+    }
+
+    f, err := os.Open("embed/compromised.txt")
+    if err != nil {
+        log.Fatal(err)
+    }
+    sc := bufio.NewScanner(f)
+    for n := 1; sc.Scan(); n++ {
+        line := strings.TrimSpace(sc.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        e, err := bip39.Mnemonic(line).Entropy(wordList)
+        if err != nil {
+            log.Fatalf("embed/compromised.txt:%d: %v", n, err)
+        }
+        add(e)
+    }
+    f.Close()
+    listed := len(entries)
+
+    for _, words := range []int{12, 15, 18, 21, 24} {
+        // One word repeated, where the checksum happens to be valid
+        for _, w := range wordList {
+            if e, err := bip39.Mnemonic(strings.TrimSpace(strings.Repeat(w+" ", words))).Entropy(wordList); err == nil {
+                add(e)
+            }
+        }
+        // abandon/zoo repeated with any last word: near-all-zero or all-one entropy
+        for _, w := range []string{"abandon", "zoo"} {
+            prefix := strings.Repeat(w+" ", words-1)
+            for _, last := range wordList {
+                if e, err := bip39.Mnemonic(prefix + last).Entropy(wordList); err == nil {
+                    add(e)
+                }
+            }
+        }
+    }
+
+    filter := bloom.New(len(entries), falsePositiveRate)
+    for _, e := range entries {
+        filter.Add(e)
+    }
+    if err := os.WriteFile("embed/compromised.bloom", filter.Marshal(), 0644); err != nil {
+        log.Fatal(err)
+    }
+    fmt.Printf("embed/compromised.bloom: %d listed + %d generated phrases, %d bytes\n",
+        listed, len(entries)-listed, len(filter.Marshal()))
+}
@@ -0,0 +1,134 @@
+package main
+
+import "strings"
+
+// 国语罗马字（2000 年文化观光部告示）按发音转写：收音中和为 k/t/p 等，
+// 连音、鼻音化、流音化、ㅎ 的送气与腭化都反映在拼写中（가격 → gagyeok，
+// 백로 → baengno，신라 → silla，같이 → gachi）。名词中 ㄱ、ㄷ、ㅂ 之后的 ㅎ
+// 照规定不合并（축하 → chukha）。紧音化不反映。
+
+const (
+    hangulBase  = 0xac00
+    hangulCount = 11172
+)
+
+var (
+    // 初声、中声、终声（0 = 无终声），用兼容字母表示
+    krInitials = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+    krFinals   = []rune("\x00ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+    krVowels   = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+
+    krInitialRoman = map[rune]string{
+        'ㄱ': "g", 'ㄲ': "kk", 'ㄴ': "n", 'ㄷ': "d", 'ㄸ': "tt", 'ㄹ': "r", 'ㅁ': "m", 'ㅂ': "b", 'ㅃ': "pp",
+        'ㅅ': "s", 'ㅆ': "ss", 'ㅇ': "", 'ㅈ': "j", 'ㅉ': "jj", 'ㅊ': "ch", 'ㅋ': "k", 'ㅌ': "t", 'ㅍ': "p", 'ㅎ': "h",
+    }
+
+    // 收音的七个代表音
+    krFinalSound = map[rune]rune{
+        'ㄱ': 'ㄱ', 'ㄲ': 'ㄱ', 'ㅋ': 'ㄱ', 'ㄳ': 'ㄱ', 'ㄺ': 'ㄱ',
+        'ㄴ': 'ㄴ', 'ㄵ': 'ㄴ', 'ㄶ': 'ㄴ',
+        'ㄷ': 'ㄷ', 'ㅅ': 'ㄷ', 'ㅆ': 'ㄷ', 'ㅈ': 'ㄷ', 'ㅊ': 'ㄷ', 'ㅌ': 'ㄷ', 'ㅎ': 'ㄷ',
+        'ㄹ': 'ㄹ', 'ㄼ': 'ㄹ', 'ㄽ': 'ㄹ', 'ㄾ': 'ㄹ', 'ㅀ': 'ㄹ',
+        'ㅁ': 'ㅁ', 'ㄻ': 'ㅁ',
+        'ㅂ': 'ㅂ', 'ㅍ': 'ㅂ', 'ㅄ': 'ㅂ', 'ㄿ': 'ㅂ',
+        'ㅇ': 'ㅇ',
+    }
+    krFinalRoman = map[rune]string{'ㄱ': "k", 'ㄴ': "n", 'ㄷ': "t", 'ㄹ': "l", 'ㅁ': "m", 'ㅂ': "p", 'ㅇ': "ng"}
+
+    // 连音：后一音节以 ㅇ 开头时，终声（双收音的第二个）移到后面；0 = 不发音
+    krLiaison = map[rune][2]rune{
+        'ㄳ': {'ㄱ', 'ㅅ'}, 'ㄵ': {'ㄴ', 'ㅈ'}, 'ㄶ': {0, 'ㄴ'}, 'ㄺ': {'ㄹ', 'ㄱ'}, 'ㄻ': {'ㄹ', 'ㅁ'},
+        'ㄼ': {'ㄹ', 'ㅂ'}, 'ㄽ': {'ㄹ', 'ㅅ'}, 'ㄾ': {'ㄹ', 'ㅌ'}, 'ㄿ': {'ㄹ', 'ㅍ'}, 'ㅀ': {0, 'ㄹ'},
+        'ㅄ': {'ㅂ', 'ㅅ'}, 'ㅎ': {0, 'ㅇ'},
+    }
+    krAspirated = map[rune]rune{'ㄱ': 'ㅋ', 'ㄷ': 'ㅌ', 'ㅈ': 'ㅊ', 'ㅅ': 'ㅆ'}
+)
+
+type krSyllable struct {
+    initial rune
+    vowel   int
+    final   rune // 0 = 无终声；处理后为代表音
+}
+
+// revisedRomanization 转写一个由韩文音节组成的单词；含其他字符时返回空串
+func revisedRomanization(word string) string {
+    var syl []krSyllable
+    for _, r := range word {
+        n := int(r) - hangulBase
+        if n < 0 || n >= hangulCount {
+            return ""
+        }
+        syl = append(syl, krSyllable{krInitials[n/588], n % 588 / 28, krFinals[n%28]})
+    }
+
+    for i := 0; i+1 < len(syl); i++ {
+        cur, next := &syl[i], &syl[i+1]
+        f := cur.final
+        if f == 0 {
+            continue
+        }
+        switch {
+        // 连音，ㄷ/ㅌ 遇 이 腭化为 ㅈ/ㅊ
+        case next.initial == 'ㅇ' && f != 'ㅇ':
+            keep, move := rune(0), f
+            if l, ok := krLiaison[f]; ok {
+                keep, move = l[0], l[1]
+            }
+            if next.vowel == 20 && move == 'ㄷ' {
+                move = 'ㅈ'
+            } else if next.vowel == 20 && move == 'ㅌ' {
+                move = 'ㅊ'
+            }
+            cur.final, next.initial = keep, move
+        // 终声 ㅎ 使后面的 ㄱ、ㄷ、ㅈ 送气（좋고 → joko），ㅅ 变为 ㅆ，ㄴ 前读 ㄴ
+        case f == 'ㅎ' || f == 'ㄶ' || f == 'ㅀ':
+            rest := map[rune]rune{'ㅎ': 0, 'ㄶ': 'ㄴ', 'ㅀ': 'ㄹ'}[f]
+            if a, ok := krAspirated[next.initial]; ok {
+                cur.final, next.initial = rest, a
+            } else if next.initial == 'ㄴ' && f == 'ㅎ' {
+                cur.final = 'ㄴ'
+            }
+        }
+    }
+
+    for i := range syl {
+        if syl[i].final != 0 {
+            syl[i].final = krFinalSound[syl[i].final]
+        }
+    }
+
+    // 流音化与鼻音化：ㄹ 前的 ㄴ 与 ㄴ 前的 ㄹ 读 ㄹㄹ；
+    // 其他收音后的 ㄹ 读 ㄴ；ㄴ、ㅁ 前的 ㄱ、ㄷ、ㅂ 读 ㅇ、ㄴ、ㅁ
+    lateral := make([]bool, len(syl))
+    for i := 0; i+1 < len(syl); i++ {
+        cur, next := &syl[i], &syl[i+1]
+        switch {
+        case cur.final == 0:
+        case next.initial == 'ㄹ' && (cur.final == 'ㄹ' || cur.final == 'ㄴ'):
+            cur.final, lateral[i+1] = 'ㄹ', true
+        case next.initial == 'ㄴ' && cur.final == 'ㄹ':
+            lateral[i+1] = true
+        case next.initial == 'ㄹ':
+            next.initial = 'ㄴ'
+        }
+        if next.initial == 'ㄴ' || next.initial == 'ㅁ' {
+            if nasal, ok := map[rune]rune{'ㄱ': 'ㅇ', 'ㄷ': 'ㄴ', 'ㅂ': 'ㅁ'}[cur.final]; ok {
+                cur.final = nasal
+            }
+        }
+    }
+
+    var b strings.Builder
+    for i, s := range syl {
+        if lateral[i] {
+            b.WriteString("l")
+        } else {
+            b.WriteString(krInitialRoman[s.initial])
+        }
+        b.WriteString(krVowels[s.vowel])
+        if s.final != 0 {
+            b.WriteString(krFinalRoman[s.final])
+        }
+    }
+    return b.String()
+}
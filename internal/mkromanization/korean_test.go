@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestRevisedRomanization(t *testing.T) {
+    tests := []struct {
+        word, want string
+    }{
+        {"가격", "gagyeok"},   // 收音中和
+        {"학교", "hakgyo"},    // 紧音化不反映
+        {"닭", "dak"},        // 双收音
+        {"국민", "gungmin"},   // 鼻音化
+        {"입문", "immun"},
+        {"왕십리", "wangsimni"}, // ㄹ 读 ㄴ，再鼻音化
+        {"종로", "jongno"},
+        {"신라", "silla"},     // 流音化
+        {"설날", "seollal"},
+        {"한라산", "hallasan"},
+        {"발음", "bareum"},    // 连音
+        {"앉아", "anja"},
+        {"많이", "mani"},
+        {"싫어", "sireo"},
+        {"없어", "eopseo"},
+        {"같이", "gachi"},     // 腭化
+        {"굳이", "guji"},
+        {"좋고", "joko"},      // 送气
+        {"놓다", "nota"},
+        {"축하", "chukha"},    // 名词中不合并
+        {"의사", "uisa"},
+        {"word", ""},
+    }
+    for _, tt := range tests {
+        if got := revisedRomanization(tt.word); got != tt.want {
+            t.Errorf("revisedRomanization(%s) = %q, want %q", tt.word, got, tt.want)
+        }
+    }
+}
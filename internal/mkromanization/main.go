@@ -0,0 +1,95 @@
+// Command mkromanization builds embed/romanization/LANG.txt, one
+// romanization per line in wordlist order, for the Chinese, Japanese and
+// Korean wordlists. It runs with go generate from the repository root;
+// arguments limit it to the named languages.
+//
+// Chinese uses ICU's Han-Latin (pinyin with tone marks, the most common
+// reading) and Japanese Hiragana-Latin (romaji as typed on a Japanese
+// keyboard, ou for a long o); both need uconv from ICU (package
+// icu-devtools). Korean uses the Revised Romanization as pronounced, with
+// final consonant neutralization and assimilation (가격 is gagyeok, 국민 is
+// gungmin), which ICU does not implement; see korean.go.
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "log"
+    "os"
+    "os/exec"
+    "sort"
+    "strings"
+
+    "golang.org/x/text/unicode/norm"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+var transforms = map[string]string{
+    "chinese_simplified":  "Han-Latin",
+    "chinese_traditional": "Han-Latin",
+    "japanese":            "Hiragana-Latin",
+    "korean":              "",
+}
+
+func main() {
+    langs := os.Args[1:]
+    if len(langs) == 0 {
+        for lang := range transforms {
+            langs = append(langs, lang)
+        }
+        sort.Strings(langs)
+    }
+    for _, lang := range langs {
+        transform, ok := transforms[lang]
+        if !ok {
+            log.Fatalf("no romanization for %q", lang)
+        }
+        words, err := bip39.Wordlist(lang)
+        if err != nil {
+            log.Fatal(err)
+        }
+        // 词表是 NFKD；ICU 和韩文规则都需要合成后的字符（韩文音节、带浊点的假名）
+        for i, w := range words {
+            words[i] = norm.NFC.String(w)
+        }
+        var lines []string
+        if transform == "" {
+            for _, w := range words {
+                lines = append(lines, revisedRomanization(w))
+            }
+        } else if lines, err = uconv(transform, words); err != nil {
+            log.Fatalf("uconv -x %s: %v", transform, err)
+        }
+        if len(lines) != len(words) {
+            log.Fatalf("%s: %d romanizations for %d words", lang, len(lines), len(words))
+        }
+        for i, l := range lines {
+            l = strings.ReplaceAll(strings.TrimSpace(l), " ", "")
+            if l == "" || strings.IndexFunc(l, func(r rune) bool { return r > 0x24f && r != '\'' }) >= 0 {
+                log.Fatalf("%s: word %d %q: no Latin romanization (%q)", lang, i+1, words[i], l)
+            }
+            lines[i] = l
+        }
+        path := "embed/romanization/" + lang + ".txt"
+        if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+            log.Fatal(err)
+        }
+        fmt.Printf("%s: %d words\n", path, len(lines))
+    }
+}
+
+func uconv(transform string, words []string) ([]string, error) {
+    var in bytes.Buffer
+    for _, w := range words {
+        in.WriteString(w + "\n")
+    }
+    cmd := exec.Command("uconv", "-x", transform)
+    cmd.Stdin = &in
+    cmd.Stderr = os.Stderr
+    out, err := cmd.Output()
+    if err != nil {
+        return nil, err
+    }
+    return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"), nil
+}
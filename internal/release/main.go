@@ -0,0 +1,139 @@
+// Command release cross-compiles the static release binaries and prints
+// their SHA-256 hashes. Run it from the repository root:
+//
+//	go run ./internal/release build [-version v1.2.0] [-out dist] [-readonly]
+//
+// Every binary is built with CGO disabled, -trimpath and an empty build ID,
+// so anyone with the same Go version and source revision gets byte-identical
+// files and can check them against the published SHA256SUMS before copying
+// one to an air-gapped machine.
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "flag"
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strings"
+)
+
+type target struct {
+    goos, goarch, goarm string
+}
+
+func (t target) String() string {
+    if t.goarm != "" {
+        return fmt.Sprintf("%s/%sv%s", t.goos, t.goarch, t.goarm)
+    }
+    return t.goos + "/" + t.goarch
+}
+
+var targets = []target{
+    {"linux", "amd64", ""},
+    {"linux", "arm64", ""},
+    {"linux", "arm", "6"}, // Raspberry Pi Zero and 1
+    {"darwin", "amd64", ""},
+    {"darwin", "arm64", ""},
+    {"windows", "amd64", ""},
+    {"windows", "arm64", ""},
+}
+
+func main() {
+    log.SetFlags(0)
+    if len(os.Args) < 2 || os.Args[1] != "build" {
+        fmt.Fprintln(os.Stderr, "usage: go run ./internal/release build [-version V] [-out DIR] [-readonly]")
+        os.Exit(2)
+    }
+    fs := flag.NewFlagSet("build", flag.ExitOnError)
+    version := fs.String("version", "", "version to embed (default: git describe)")
+    out := fs.String("out", "dist", "output directory")
+    readOnly := fs.Bool("readonly", false, "also build read-only variants (-tags readonly)")
+    fs.Parse(os.Args[2:])
+
+    if *version == "" {
+        *version = gitDescribe()
+    }
+    if err := os.MkdirAll(*out, 0755); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    variants := []string{""}
+    if *readOnly {
+        variants = append(variants, "readonly")
+    }
+    var sums strings.Builder
+    fmt.Printf("Building passphrase_bitcoin %s with %s\n\n", *version, runtime.Version())
+    for _, t := range targets {
+        for _, tags := range variants {
+            name := fmt.Sprintf("passphrase_bitcoin-%s-%s-%s", *version, t.goos, t.goarch)
+            if t.goarm != "" {
+                name += "v" + t.goarm
+            }
+            if tags != "" {
+                name += "-" + tags
+            }
+            if t.goos == "windows" {
+                name += ".exe"
+            }
+            path := filepath.Join(*out, name)
+            if err := build(t, tags, *version, path); err != nil {
+                log.Fatalf("Error: building %s: %v", t, err)
+            }
+            sum, err := fileSHA256(path)
+            if err != nil {
+                log.Fatalf("Error: %v", err)
+            }
+            fmt.Printf("%s  %s\n", sum, name)
+            fmt.Fprintf(&sums, "%s  %s\n", sum, name)
+        }
+    }
+
+    sumsPath := filepath.Join(*out, "SHA256SUMS")
+    if err := os.WriteFile(sumsPath, []byte(sums.String()), 0644); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fmt.Printf("\nWrote %s. Sign it, and publish the Go version and source revision with it.\n", sumsPath)
+}
+
+func build(t target, tags, version, path string) error {
+    args := []string{"build", "-trimpath",
+        "-ldflags", "-s -w -buildid= -X main.version=" + version,
+        "-o", path}
+    if tags != "" {
+        args = append(args, "-tags", tags)
+    }
+    args = append(args, ".")
+    cmd := exec.Command("go", args...)
+    cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOOS="+t.goos, "GOARCH="+t.goarch, "GOARM="+t.goarm)
+    cmd.Stdout = os.Stdout
+    cmd.Stderr = os.Stderr
+    return cmd.Run()
+}
+
+func fileSHA256(path string) (string, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return "", err
+    }
+    defer f.Close()
+    h := sha256.New()
+    if _, err := io.Copy(h, f); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Version from the nearest tag, e.g. v1.2.0-3-gabc1234-dirty.
+func gitDescribe() string {
+    out, err := exec.Command("git", "describe", "--tags", "--always", "--dirty").Output()
+    if err != nil {
+        return "dev"
+    }
+    return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,166 @@
+// Package walletsim is a small wallet and chain simulator for end-to-end
+// checks. A Chain records payments to addresses; a watch-only Wallet made
+// from an exported account xpub hands out receive addresses the way a
+// wallet does; Restore rebuilds the wallet from a BIP39 seed and finds the
+// funds again by scanning both branches with a gap limit, like a real
+// wallet restoring from a backup. Nothing here touches the network.
+package walletsim
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+    "github.com/li-han-zhang/Go_passphrase/pkg/watchonly"
+)
+
+// DefaultGap is the gap limit of BIP44 wallets: scanning stops after this
+// many consecutive unused addresses.
+const DefaultGap = 20
+
+// Chain is the simulated ledger: amounts in satoshis received per address.
+type Chain struct {
+    received map[string]int64
+    total    int64
+}
+
+func NewChain() *Chain {
+    return &Chain{received: map[string]int64{}}
+}
+
+// Pay records a payment of sats to addr.
+func (c *Chain) Pay(addr string, sats int64) {
+    c.received[addr] += sats
+    c.total += sats
+}
+
+// Received returns the amount paid to addr so far.
+func (c *Chain) Received(addr string) int64 {
+    return c.received[addr]
+}
+
+// Total returns the sum of all payments.
+func (c *Chain) Total() int64 {
+    return c.total
+}
+
+// Export returns the account-level extended public key for
+// m/purpose'/0'/account' in the form wallets import for t: ypub for
+// P2SH-P2WPKH, zpub for P2WPKH, xpub otherwise.
+func Export(seed []byte, t btcaddr.Type, account uint32) (string, error) {
+    acct, err := accountKey(seed, t, account)
+    if err != nil {
+        return "", err
+    }
+    version := bip32.VersionXPub
+    switch t {
+    case btcaddr.P2SHP2WPKH:
+        version = watchonly.VersionYPub
+    case btcaddr.P2WPKH:
+        version = watchonly.VersionZPub
+    }
+    return acct.Neuter().Serialize(version), nil
+}
+
+func accountKey(seed []byte, t btcaddr.Type, account uint32) (*bip32.Key, error) {
+    if account >= bip32.HardenedOffset {
+        return nil, errors.New("walletsim: account out of range")
+    }
+    master, err := bip32.NewMaster(seed)
+    if err != nil {
+        return nil, err
+    }
+    return master.Derive(fmt.Sprintf("m/%d'/0'/%d'", t.Purpose(), account))
+}
+
+// Wallet is a watch-only wallet: it can give out and recognise addresses
+// but holds no private key.
+type Wallet struct {
+    account *watchonly.Account
+    next    [2]uint32
+}
+
+// NewWatchOnly imports an account xpub, ypub or zpub; def is the address
+// type for a plain xpub.
+func NewWatchOnly(xpub string, def btcaddr.Type) (*Wallet, error) {
+    a, err := watchonly.Parse(xpub, def)
+    if err != nil {
+        return nil, err
+    }
+    return &Wallet{account: a}, nil
+}
+
+// Restore derives the account from seed and scans chain for its funds.
+func Restore(seed []byte, t btcaddr.Type, account uint32, chain *Chain, gap int) (*Wallet, *Scan, error) {
+    acct, err := accountKey(seed, t, account)
+    if err != nil {
+        return nil, nil, err
+    }
+    w := &Wallet{account: &watchonly.Account{Key: acct.Neuter(), Type: t}}
+    s, err := w.Scan(chain, gap)
+    if err != nil {
+        return nil, nil, err
+    }
+    return w, s, nil
+}
+
+// Address returns the address at branch/index (0 receive, 1 change).
+func (w *Wallet) Address(branch, index uint32) (string, error) {
+    return w.account.Address(branch, index)
+}
+
+// Next returns the next unused address on branch and advances past it.
+// Skipping ahead (as when a payer never pays an invoice) leaves gaps that
+// Restore must bridge.
+func (w *Wallet) Next(branch uint32, skip uint32) (uint32, string, error) {
+    w.next[branch] += skip
+    i := w.next[branch]
+    addr, err := w.Address(branch, i)
+    if err != nil {
+        return 0, "", err
+    }
+    w.next[branch]++
+    return i, addr, nil
+}
+
+// Found is a used address discovered by a scan.
+type Found struct {
+    Branch, Index uint32
+    Address       string
+    Amount        int64
+}
+
+// Scan is the result of scanning a chain.
+type Scan struct {
+    Used    []Found
+    Balance int64
+    Checked int // addresses derived
+}
+
+// Scan walks both branches from index 0 until gap consecutive addresses
+// have received nothing.
+func (w *Wallet) Scan(chain *Chain, gap int) (*Scan, error) {
+    if gap < 1 {
+        return nil, errors.New("walletsim: gap limit must be positive")
+    }
+    s := &Scan{}
+    for branch := uint32(0); branch <= 1; branch++ {
+        unused := 0
+        for i := uint32(0); unused < gap; i++ {
+            addr, err := w.Address(branch, i)
+            if err != nil {
+                return nil, err
+            }
+            s.Checked++
+            if amount := chain.Received(addr); amount > 0 {
+                s.Used = append(s.Used, Found{Branch: branch, Index: i, Address: addr, Amount: amount})
+                s.Balance += amount
+                unused = 0
+            } else {
+                unused++
+            }
+        }
+    }
+    return s, nil
+}
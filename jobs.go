@@ -0,0 +1,107 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "log"
+    "os"
+    "runtime"
+    "strconv"
+    "strings"
+)
+
+//
+// -------------------------
+//   -jobs 并行度
+// -------------------------
+//
+// CPU 密集的部分（-recover-passphrase、-bench、-derive 批量派生）都从
+// workerCount 取 goroutine 数：默认 runtime.NumCPU()，-jobs N 可指定。
+// 显式给出较小的 N 时同时设置 GOMAXPROCS，连 Argon2 的内部线程也受限，
+// 便于在 Pi Zero 这类单核、发热严重的设备上保持可用。
+// 内存密集的 KDF（scrypt、Argon2id）按 /proc/meminfo 的可用内存减少
+// worker 数，至少保留 1 个，避免小内存设备开始交换或被 OOM 杀掉。
+//
+
+const maxJobs = 256
+
+var jobs = 0 // 0 表示自动
+
+func setJobs(n int) {
+    if n < 0 || n > maxJobs {
+        log.Fatalf("Error: -jobs must be 1–%d (0 for one per CPU)", maxJobs)
+    }
+    jobs = n
+    if n > 0 && n < runtime.NumCPU() {
+        runtime.GOMAXPROCS(n)
+    }
+}
+
+// 每个 worker 需要 perWorker 字节内存时的 worker 数
+func workerCount(perWorker uint64) int {
+    n := jobs
+    if n == 0 {
+        n = runtime.NumCPU()
+    }
+    if perWorker > 0 {
+        // 只用可用内存的一半，留给系统和其它进程
+        if avail := availableMemory(); avail > 0 {
+            if fit := int(avail / 2 / perWorker); fit < n {
+                n = max(fit, 1)
+                fmt.Fprintf(os.Stderr, "Note: limited to %d workers by available memory (%d MiB each).\n", n, perWorker>>20)
+            }
+        }
+    }
+    return n
+}
+
+// Linux 的 MemAvailable（字节）；读不到时返回 0，不做限制
+func availableMemory() uint64 {
+    f, err := os.Open("/proc/meminfo")
+    if err != nil {
+        return 0
+    }
+    defer f.Close()
+    sc := bufio.NewScanner(f)
+    for sc.Scan() {
+        fields := strings.Fields(sc.Text())
+        if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+            kb, err := strconv.ParseUint(fields[1], 10, 64)
+            if err != nil {
+                return 0
+            }
+            return kb << 10
+        }
+    }
+    return 0
+}
+
+// 把 [0, total) 分块交给 workers 个 goroutine 并行计算，再按块的顺序
+// 交给 emit。在途的块最多 2×workers 个，内存占用与 total 无关。
+func orderedParallel[T any](total, chunk, workers int, work func(lo, hi int) T, emit func(T)) {
+    type job struct {
+        lo, hi int
+        result chan T
+    }
+    jobCh := make(chan job)
+    pending := make(chan chan T, 2*workers)
+    for w := 0; w < workers; w++ {
+        go func() {
+            for j := range jobCh {
+                j.result <- work(j.lo, j.hi)
+            }
+        }()
+    }
+    go func() {
+        for lo := 0; lo < total; lo += chunk {
+            j := job{lo, min(lo+chunk, total), make(chan T, 1)}
+            pending <- j.result
+            jobCh <- j
+        }
+        close(jobCh)
+        close(pending)
+    }()
+    for result := range pending {
+        emit(<-result)
+    }
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+    "encoding/hex"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -json 机器可读输出
+// -------------------------
+//
+// -b、-no-file、-p、-v、-decode、-check-bits 加 -json 时，标准输出只有一个
+// JSON 对象（熵的十六进制、带索引和位的单词、校验位、是否有效、指纹），
+// 提示、警告和演示水印都写到标准错误，便于脚本和装机流程直接解析。
+// 结构见 -schema mnemonic。-b 与文字输出一样不给出单词和熵。
+// 会往标准输出打印其他内容的选项（-dice、-pick、-lint 等）不能与 -json 同用。
+// 无效时仍然输出 JSON，退出码为 1。
+// 加 -n COUNT 批量生成时改为每行一个对象（JSON Lines）。
+//
+
+var jsonOutput bool
+
+// 可以与 -json 同用的选项
+var jsonFlags = map[string]bool{
+    "json":            true,
+    "b":               true,
+    "no-file":         true,
+    "p":               true,
+    "v":               true,
+    "decode":          true,
+    "write":           true,
+    "check-bits":      true,
+    "words":           true,
+    "bits":            true,
+    "n":               true,
+    "stat-check":      true,
+    "wordlist":        true,
+    "f":               true,
+    "group-bits":      true,
+    "groups-per-line": true,
+    "entropy-hex":     true,
+    "cards":           true,
+    "encrypt":         true,
+    "dpapi":           true,
+    "demo":            true,
+    "read-only":       true,
+    "transcript":      true,
+    "stats":           true,
+    "jobs":            true,
+}
+
+func enforceJSONFlags(fs *flag.FlagSet) {
+    fs.Visit(func(f *flag.Flag) {
+        if !jsonFlags[f.Name] {
+            log.Fatalf("Error: -json is not available with -%s; use it with -b, -no-file, -p, -v, -decode or -check-bits.", f.Name)
+        }
+    })
+}
+
+type reportWord struct {
+    Position int    `json:"position"`
+    Word     string `json:"word"`
+    Index    int    `json:"index"` // 不在单词表上为 -1
+    Bits     string `json:"bits,omitempty"`
+}
+
+type mnemonicReport struct {
+    Command          string       `json:"command"`
+    Valid            bool         `json:"valid"`
+    Demo             bool         `json:"demo,omitempty"`
+    File             string       `json:"file,omitempty"`
+    WordCount        int          `json:"word_count"`
+    Words            []reportWord `json:"words,omitempty"`
+    EntropyBits      int          `json:"entropy_bits"`
+    EntropyHex       string       `json:"entropy_hex,omitempty"`
+    Checksum         string       `json:"checksum,omitempty"`
+    ExpectedChecksum string       `json:"expected_checksum,omitempty"`
+    Corrected        string       `json:"corrected,omitempty"`
+    Fingerprint      string       `json:"fingerprint,omitempty"`
+    Errors           []string     `json:"errors,omitempty"`
+}
+
+func printJSON(v any) {
+    data, err := json.MarshalIndent(v, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fmt.Println(string(data))
+}
+
+// -n 批量输出：每个对象占一行（JSON Lines）
+func printJSONLine(v any) {
+    data, err := json.Marshal(v)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fmt.Println(string(data))
+}
+
+// 有效的熵 → 完整报告；secret 为 false 时不含单词与熵（-b）
+func entropyReport(command string, entropy []byte, secret bool, wordList []string) *mnemonicReport {
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    bits := bip39.BytesToBits(entropy)
+    checksum := bip39.ChecksumBits(bits)
+    r := &mnemonicReport{
+        Command:     command,
+        Valid:       true,
+        Demo:        demoMode,
+        WordCount:   len(bits) * 3 / 32,
+        EntropyBits: len(bits),
+        Fingerprint: masterFingerprint(mnemonic),
+    }
+    if !secret {
+        return r
+    }
+    r.EntropyHex = hex.EncodeToString(entropy)
+    r.Checksum = bitString(checksum)
+    all := append(bits, checksum...)
+    for i, w := range splitWords(mnemonic) {
+        idx := 0
+        for _, b := range all[i*11 : (i+1)*11] {
+            idx <<= 1
+            if b {
+                idx |= 1
+            }
+        }
+        r.Words = append(r.Words, reportWord{Position: i + 1, Word: w, Index: idx, Bits: bitString(all[i*11 : (i+1)*11])})
+    }
+    return r
+}
+
+// -v -json：逐词查表与校验，结果写进报告而不是逐行打印
+func validateMnemonicJSON(phrase string, wordList []string) {
+    words := splitWords(phrase)
+    r := &mnemonicReport{Command: "v", Demo: demoMode, WordCount: len(words)}
+    valid := true
+    switch len(words) {
+    case 12, 15, 18, 21, 24:
+        r.EntropyBits = len(words) * 11 * 32 / 33
+    default:
+        r.Errors = append(r.Errors, fmt.Sprintf("%d words, expected 12, 15, 18, 21 or 24", len(words)))
+        valid = false
+    }
+
+    matcher := wordmatch.New(wordList)
+    indices := make([]int, len(words))
+    for i, w := range words {
+        c, ok := matcher.Lookup(w)
+        if !ok {
+            r.Errors = append(r.Errors, fmt.Sprintf("word %d '%s' is not on the list%s", i+1, w, didYouMean(matcher, w)))
+            r.Words = append(r.Words, reportWord{Position: i + 1, Word: w, Index: -1})
+            valid = false
+            continue
+        }
+        indices[i] = c.Index
+        r.Words = append(r.Words, reportWord{Position: i + 1, Word: c.Word, Index: c.Index, Bits: fmt.Sprintf("%011b", c.Index)})
+    }
+
+    if valid {
+        var all []bool
+        for _, idx := range indices {
+            for b := 10; b >= 0; b-- {
+                all = append(all, (idx>>b)&1 == 1)
+            }
+        }
+        ent := all[:r.EntropyBits]
+        r.Checksum = bitString(all[r.EntropyBits:])
+        r.EntropyHex = hex.EncodeToString(bip39.BitsToBytes(ent))
+        if e, err := bip39.EntropyFromIndices(indices); err != nil {
+            r.ExpectedChecksum = bitString(bip39.ChecksumBits(ent))
+            r.Errors = append(r.Errors, "checksum mismatch: any word may be wrong or out of order")
+            valid = false
+        } else {
+            r.Fingerprint = masterFingerprint(wordsFromIndices(indices, wordList))
+            warnWeakMnemonic(e, wordList)
+        }
+    }
+
+    r.Valid = valid
+    printJSON(r)
+    if !valid {
+        transcript.record("validate mnemonic", "invalid", "", "")
+        transcript.finish()
+        os.Exit(1)
+    }
+    transcript.record("validate mnemonic", "valid", r.Fingerprint, "")
+}
+
+// -check-bits -json
+func checkBitsReport(c bip39.ChecksumReport) *mnemonicReport {
+    r := &mnemonicReport{
+        Command:          "check-bits",
+        Valid:            c.Valid,
+        Demo:             demoMode,
+        WordCount:        len(c.Corrected) / 11,
+        EntropyBits:      len(c.Entropy),
+        EntropyHex:       hex.EncodeToString(bip39.BitsToBytes(c.Entropy)),
+        Checksum:         bitString(c.Checksum),
+        ExpectedChecksum: bitString(c.Expected),
+    }
+    if !c.Valid {
+        r.Corrected = bitString(c.Corrected)
+        if len(c.Checksum) == 0 {
+            r.Errors = append(r.Errors, "checksum missing")
+        } else {
+            r.Errors = append(r.Errors, "checksum mismatch")
+        }
+    }
+    return r
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+    "crypto/rand"
+    "fmt"
+    "log"
+    "math/big"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -last-word 手工生成的最后一个单词
+// -------------------------
+//
+// 完全手工生成助记词时，先用骰子或硬币选出 11、14、17、20 或 23 个单词，
+// 最后一个单词的前几位同样随机，剩下的是校验位，要靠计算补上。
+// -last-word WORDS 列出所有能让校验位成立的最后一个单词（24 词时 8 个，
+// 12 词时 128 个），让用户按编号选一个或随机选一个，然后显示完整的助记词；
+// 加 -write 时写入 binary.txt。参数为 - 时不回显地询问单词。
+// 前面的单词必须真正随机，由人挑选的单词熵会大大减少。
+//
+
+func lastWordCalculator(phrase string, write bool, wordList []string) {
+    if phrase == "-" {
+        var err error
+        if phrase, err = readSecret("Words so far (hidden): "); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+    }
+    matcher := wordmatch.New(wordList)
+    words := splitWords(phrase)
+    indices := make([]int, len(words))
+    for i, w := range words {
+        c, ok := matcher.Lookup(w)
+        if !ok {
+            log.Fatalf("Error: -last-word: word %d '%s' is not on the list%s", i+1, w, didYouMean(matcher, w))
+        }
+        words[i], indices[i] = c.Word, c.Index
+    }
+    last, err := bip39.LastWords(indices)
+    if err != nil {
+        log.Fatalf("Error: -last-word: %v", err)
+    }
+
+    n := len(words) + 1
+    free := 11 - n/3
+    fmt.Printf("%d words given; %d final words complete a valid %d-word mnemonic.\n", len(words), len(last), n)
+    fmt.Printf("The final word holds %d more random bits and %d checksum bits.\n", free, n/3)
+    fmt.Println()
+    for i, idx := range last {
+        b := fmt.Sprintf("%011b", idx)
+        fmt.Printf("  %3d. %-9s %4d  %s + checksum %s\n", i+1, wordList[idx], idx, b[:free], b[free:])
+    }
+    fmt.Println()
+
+    var choice int
+    for choice == 0 {
+        fmt.Printf("Choose 1–%d to match your dice, r for a random one, or Enter to quit: ", len(last))
+        line, err := readLine()
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        line = strings.TrimSpace(line)
+        switch {
+        case line == "":
+            return
+        case strings.EqualFold(line, "r"):
+            k, err := rand.Int(rand.Reader, big.NewInt(int64(len(last))))
+            if err != nil {
+                log.Fatalf("Error generating entropy: %v", err)
+            }
+            choice = int(k.Int64()) + 1
+        default:
+            if k, err := strconv.Atoi(line); err == nil && k >= 1 && k <= len(last) {
+                choice = k
+            }
+        }
+    }
+
+    entropy, err := bip39.EntropyFromIndices(append(indices, last[choice-1]))
+    if err != nil {
+        log.Fatalf("Error: -last-word: %v", err)
+    }
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    fmt.Println()
+    fmt.Printf("Final word %d: %s\n", n, wordList[last[choice-1]])
+    fmt.Println("Passphrase:")
+    printPhrase(mnemonic)
+    fmt.Println("Fingerprint:", masterFingerprint(mnemonic))
+
+    // importEntropy 会给出弱助记词警告
+    if write {
+        importEntropy(entropy)
+        return
+    }
+    warnWeakMnemonic(entropy, wordList)
+    transcript.record("last word", "ok", masterFingerprint(mnemonic), fmt.Sprintf("%d words", n))
+}
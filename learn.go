@@ -0,0 +1,144 @@
+package main
+
+import (
+    "encoding/hex"
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -learn 交互式入门
+// -------------------------
+//
+// 用 -demo 的公开熵一步步演示 BIP39：熵 → 校验和 → 11 位分组 → 单词 →
+// 种子。每一步都调用工具里真正的代码（mnemonicFromEntropy、showWordInfo、
+// entropyFromPhrase、mnemonicToSeed），而不是打印写死的文字。
+//
+
+func runTutorial(wordList []string) {
+    demoMode = true
+    printDemoWatermark()
+    defer printDemoWatermark()
+
+    entropy := demoEntropy
+    bits := bip39.BytesToBits(entropy)
+    checksum := bip39.ChecksumBits(bits)
+    all := append(append([]bool{}, bits...), checksum...)
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    words := strings.Fields(mnemonic)
+
+    learnStep(1, "Entropy")
+    fmt.Println("A passphrase starts as 256 random bits. This tutorial uses the public demo entropy:")
+    fmt.Println("  " + hex.EncodeToString(entropy))
+    fmt.Println("-b draws these bits from the operating system's random generator instead.")
+
+    learnStep(2, "Checksum")
+    fmt.Println("BIP39 appends the first 256/32 = 8 bits of SHA-256(entropy) as a checksum:")
+    fmt.Println("  " + bitString(checksum))
+    fmt.Println("That makes 264 bits, which split evenly into 24 groups of 11 bits.")
+
+    learnStep(3, "Words")
+    fmt.Println("Each 11-bit group is a number from 0 to 2047, an index into the 2048-word list:")
+    for i := 0; i < 3; i++ {
+        group := all[i*11 : (i+1)*11]
+        fmt.Printf("  %s = %4d → %s\n", bitString(group), bip39.BitsToInt(group), words[i])
+    }
+    fmt.Println("  ...")
+    last := all[23*11:]
+    fmt.Printf("  %s = %4d → %s   (the last 8 bits are the checksum)\n", bitString(last), bip39.BitsToInt(last), words[23])
+    fmt.Println()
+    fmt.Println("The whole passphrase:")
+    fmt.Println(formatPhrase(mnemonic))
+
+    learnStep(4, "Exercise: inspect a word")
+    fmt.Println("Type any word from the passphrase above (or its first four letters) to look it up;")
+    fmt.Println("this runs the same code as -i WORD.")
+    if input, ok := learnPrompt("Word: "); ok && input != "" {
+        showWordInfo(input, wordList)
+    }
+
+    learnStep(5, "Exercise: flip a bit")
+    fmt.Println("Pick a word position (1–24) and a bit (1–11) to flip, as a misread word would.")
+    pos := learnNumber("Position: ", 1, 24)
+    bit := learnNumber("Bit: ", 1, 11)
+    i := (pos-1)*11 + bit - 1
+    flipped := append([]bool{}, all...)
+    flipped[i] = !flipped[i]
+    changed := string(bip39.MnemonicFromBits(flipped, wordList))
+    fmt.Printf("Word %d changes from %q to %q.\n", pos, words[pos-1], strings.Fields(changed)[pos-1])
+    if _, err := entropyFromPhrase(changed, wordList); err != nil {
+        fmt.Printf("Checking the changed passphrase: %v.\n", err)
+        fmt.Println("The checksum catches the mistake, so a wallet refuses to restore it.")
+    } else {
+        fmt.Println("The changed passphrase still passes: an 8-bit checksum misses 1 error in 256.")
+        fmt.Println("That is why -s prints per-row checkwords and -rs adds Reed-Solomon parity words.")
+    }
+
+    learnStep(6, "Seed and BIP39 passphrase")
+    fmt.Println("Wallets stretch the words with PBKDF2-HMAC-SHA512 (2048 rounds) into a 512-bit seed.")
+    fmt.Println("An optional BIP39 passphrase is mixed in, and every passphrase gives a different wallet.")
+    fmt.Printf("Master fingerprint without a passphrase: %s\n", learnFingerprint(mnemonic, ""))
+    if p, ok := learnPrompt("Try a BIP39 passphrase (Enter to skip): "); ok && p != "" {
+        fmt.Printf("Master fingerprint with %q: %s\n", p, learnFingerprint(mnemonic, p))
+        fmt.Println("Nothing tells you a passphrase is wrong: you simply get an empty wallet.")
+    }
+
+    fmt.Println()
+    fmt.Println("Done. Never send funds to the demo passphrase; -b creates a real one.")
+}
+
+func learnStep(n int, title string) {
+    fmt.Println()
+    fmt.Printf("── Step %d: %s ──\n", n, title)
+}
+
+func learnPrompt(prompt string) (string, bool) {
+    fmt.Print(prompt)
+    line, err := readLine()
+    if err != nil {
+        fmt.Println()
+        return "", false
+    }
+    return strings.TrimSpace(line), true
+}
+
+// 输入无效时重新提问，读到 EOF 时退出
+func learnNumber(prompt string, min, max int) int {
+    for {
+        s, ok := learnPrompt(prompt)
+        if !ok {
+            log.Fatalf("Error: tutorial aborted")
+        }
+        if n, err := strconv.Atoi(s); err == nil && n >= min && n <= max {
+            return n
+        }
+        fmt.Printf("Please enter a number from %d to %d.\n", min, max)
+    }
+}
+
+func learnFingerprint(mnemonic, passphrase string) string {
+    master, err := bip32.NewMaster(mnemonicToSeed(mnemonic, passphrase))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fp := master.Fingerprint()
+    return hex.EncodeToString(fp[:])
+}
+
+func bitString(bits []bool) string {
+    var b strings.Builder
+    for _, bit := range bits {
+        if bit {
+            b.WriteByte('1')
+        } else {
+            b.WriteByte('0')
+        }
+    }
+    return b.String()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+)
+
+//
+// -------------------------
+//   备份纸序列号台账
+// -------------------------
+//
+// 每张 -s 备份纸带一个随机序列号，并在 sheets.ledger 中记一行
+// （序列号 → 主指纹），台账不含秘密。
+// 每行最后一列是“前一行链值 + 本行内容”的 SHA-256 前缀，
+// 删改任何一行都会让链值对不上（截掉末尾几行无法发现）。
+//
+
+const ledgerFile = "sheets.ledger"
+
+// 去掉易混淆的 0/O、1/I
+const serialAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+func newSheetSerial() (string, error) {
+    b := make([]byte, 8)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    s := make([]byte, len(b))
+    for i, v := range b {
+        s[i] = serialAlphabet[int(v)%len(serialAlphabet)]
+    }
+    return fmt.Sprintf("PB-%s-%s", s[:4], s[4:]), nil
+}
+
+func ledgerChain(prev, record string) string {
+    sum := sha256.Sum256([]byte(prev + "\n" + record))
+    return hex.EncodeToString(sum[:4])
+}
+
+// 字段：序列号、主指纹、单词数、日期
+func recordSheet(serial, fingerprint string, words int) error {
+    lines, err := readLedger()
+    if err != nil {
+        return err
+    }
+    prev := ""
+    if len(lines) > 0 {
+        fields := strings.Split(lines[len(lines)-1], "\t")
+        prev = fields[len(fields)-1]
+    }
+
+    record := strings.Join([]string{serial, fingerprint, fmt.Sprint(words), time.Now().UTC().Format("2006-01-02")}, "\t")
+    f, err := os.OpenFile(ledgerFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+    if err != nil {
+        return err
+    }
+    if _, err := fmt.Fprintf(f, "%s\t%s\n", record, ledgerChain(prev, record)); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+func readLedger() ([]string, error) {
+    data, err := readFileLimited(ledgerFile, maxTextFileSize)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var lines []string
+    for _, line := range strings.Split(string(data), "\n") {
+        if strings.TrimSpace(line) != "" {
+            lines = append(lines, line)
+        }
+    }
+    return lines, nil
+}
+
+func printLedger() {
+    lines, err := readLedger()
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", ledgerFile, err)
+    }
+    if len(lines) == 0 {
+        fmt.Printf("%s is empty or missing.\n", ledgerFile)
+        return
+    }
+
+    fmt.Println("Serial        Fingerprint  Words  Date        Chain")
+    prev, broken := "", 0
+    for n, line := range lines {
+        fields := strings.Split(line, "\t")
+        if len(fields) != 5 {
+            fmt.Printf("line %d: malformed\n", n+1)
+            broken++
+            continue
+        }
+        record := strings.Join(fields[:4], "\t")
+        status := "ok"
+        if ledgerChain(prev, record) != fields[4] {
+            status = "BROKEN"
+            broken++
+        }
+        fmt.Printf("%-12s  %-11s  %5s  %-10s  %s\n", fields[0], fields[1], fields[2], fields[3], status)
+        prev = fields[4]
+    }
+
+    fmt.Println()
+    if broken > 0 {
+        fmt.Printf("%d line(s) fail the chain check: the ledger was edited.\n", broken)
+        return
+    }
+    fmt.Printf("%d sheet(s), chain intact.\n", len(lines))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "log"
+    "os"
+)
+
+//
+// -------------------------
+//   输入大小上限
+// -------------------------
+//
+// 所有外部输入都有明确上限，并以流的方式读取，超过上限立即报错，
+// 畸形或超大的文件不会耗尽内存。
+//
+
+const (
+    maxBinaryFileSize = 64 << 10  // binary.txt
+    maxTextFileSize   = 1 << 20   // 备份纸、网格、导入文件、分享、台账等文本
+    maxWordlistSize   = 4 << 20   // 第三方词表
+    maxWAVSize        = 64 << 20  // 约 60 分钟 16 位单声道 8 kHz
+    maxImagePixels    = 50 << 20  // PNG 解码前按宽 × 高检查
+    maxStegoPayload   = 4 << 10   // 隐写数据（加密后的熵）
+    maxArgLen         = 4 << 10   // 命令行字符串参数（助记词、索引、模式等）
+    maxLineLen        = 4 << 10   // 交互输入的一行
+)
+
+var errTooLarge = errors.New("input too large")
+
+// 最多读取 limit 字节，超出则报错
+func readFileLimited(path string, limit int64) ([]byte, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return readAllLimited(f, limit)
+}
+
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+    data, err := io.ReadAll(io.LimitReader(r, limit+1))
+    if err != nil {
+        return nil, err
+    }
+    if int64(len(data)) > limit {
+        return nil, fmt.Errorf("%w (limit %d bytes)", errTooLarge, limit)
+    }
+    return data, nil
+}
+
+// 命令行字符串参数的长度检查
+func checkArgLengths(fs *flag.FlagSet) {
+    fs.Visit(func(f *flag.Flag) {
+        if len(f.Value.String()) > maxArgLen {
+            log.Fatalf("Error: -%s: argument longer than %d bytes", f.Name, maxArgLen)
+        }
+    })
+}
+
+// 限制单行长度：超过 bufio 缓冲区即报错，不会无限读取
+func readLimitedLine(r *bufio.Reader) (string, error) {
+    line, err := r.ReadSlice('\n')
+    if errors.Is(err, bufio.ErrBufferFull) {
+        return "", fmt.Errorf("%w: line longer than %d bytes", errTooLarge, r.Size())
+    }
+    if err != nil && len(line) == 0 {
+        return "", err
+    }
+    return string(line), nil
+}
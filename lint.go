@@ -0,0 +1,79 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -lint 易混淆单词检查
+// -------------------------
+//
+
+type confusablePair struct {
+    a, b int // 单词位置（从 0 开始）
+}
+
+// 两个单词抄写/听写时容易混淆：
+// 编辑距离为 1，或前 3 个字母相同且编辑距离 ≤ 2 或互为前缀（quick/quit、hold/holiday）
+func confusable(a, b string) bool {
+    if a == b {
+        return false
+    }
+    d := wordmatch.Distance(a, b)
+    if d <= 1 {
+        return true
+    }
+    if len(a) < 3 || len(b) < 3 || a[:3] != b[:3] {
+        return false
+    }
+    return d <= 2 || strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func lintPhrase(words []string) []confusablePair {
+    var pairs []confusablePair
+    for i := range words {
+        for j := i + 1; j < len(words); j++ {
+            if confusable(words[i], words[j]) {
+                pairs = append(pairs, confusablePair{i, j})
+            }
+        }
+    }
+    return pairs
+}
+
+func printLintReport(words []string, pairs []confusablePair) {
+    for _, p := range pairs {
+        fmt.Printf("Confusable: #%d '%s' and #%d '%s'\n", p.a+1, words[p.a], p.b+1, words[p.b])
+    }
+}
+
+func lintBinary(wordList []string) {
+    lintWords(strings.Fields(generatePassphraseFromBinary(wordList)))
+}
+
+func lintWords(words []string) {
+    pairs := lintPhrase(words)
+    if len(pairs) == 0 {
+        fmt.Println("No confusable word pairs found.")
+        return
+    }
+    printLintReport(words, pairs)
+}
+
+// -b -lint：发现易混淆单词时询问是否重新生成
+func acceptAfterLint(entropy []byte, wordList []string) bool {
+    words := strings.Fields(mnemonicFromEntropy(entropy, wordList))
+    pairs := lintPhrase(words)
+    if len(pairs) == 0 {
+        return true
+    }
+    printLintReport(words, pairs)
+    fmt.Print("Regenerate? [Y/n] ")
+    answer, _ := readLine()
+    answer = strings.TrimSpace(strings.ToLower(answer))
+    return answer == "n" || answer == "no"
+}
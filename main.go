@@ -2,31 +2,187 @@ package main
 
 import (
     "bufio"
-    "crypto/rand"
-    "crypto/sha256"
-    _ "embed"
+    "bytes"
     "flag"
     "fmt"
     "log"
     "os"
+    "slices"
     "strings"
+    "time"
 
-    qrcode "github.com/skip2/go-qrcode"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/seedkdf"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
 )
 
-//go:embed embed/english.txt
-var wordListText string
-
 func main() {
+    // -b、-no-file、-p、-q、-i、-v、-derive、-selftest 与只属于它们的选项（没有变量的那些）
+    // 由 legacyCommands 改写成子命令；子命令有自己的选项，见 commands.go
     genBinary := flag.Bool("b", false, "Generate binary.txt only")
+    binaryFile := flag.String("f", "binary.txt", "Entropy file to use instead of binary.txt")
+    wordCount := flag.Int("words", 24, "Words in the passphrase made by -b: 12, 15, 18, 21 or 24")
+    batchCount := flag.Int("n", 1, "With -b or -no-file: generate COUNT independent passphrases (-b: one file each)")
+    entropyBits := flag.Int("bits", 0, "Entropy bits for -b: 128, 160, 192, 224 or 256 (instead of -words)")
     useBinary := flag.Bool("p", false, "Generate passphrase from binary.txt")
     showHelp := flag.Bool("h", false, "Show help message")
     showQRCode := flag.Bool("q", false, "Generate QR code of passphrase from binary.txt")
+    schema := flag.String("schema", "", "Print the JSON Schema of a machine-readable output NAME (list: show all)")
+    noFile := flag.Bool("no-file", false, "Generate and print a passphrase in memory only, without writing binary.txt")
+    checkBits := flag.String("check-bits", "", "Verify the checksum of an entropy+checksum bit string BITS (or typed with -)")
+    flag.Bool("pick", false, "With -b: choose words for some positions (asked for, hidden); the rest is random")
+    encrypt := flag.Bool("encrypt", false, "Encrypt binary.txt with a passphrase (with -b or an import: write it encrypted)")
+    decrypt := flag.Bool("decrypt", false, "Decrypt an encrypted binary.txt back to plain text")
+    dpapi := flag.Bool("dpapi", false, "With -encrypt (Windows): protect binary.txt with the current user account (DPAPI) instead of a passphrase")
+    tmpDir := flag.String("tmpdir", "", "Directory for the session's temporary files (default: a RAM-backed tmpfs)")
+    groupBits := flag.Int("group-bits", 11, "Bits per group when writing binary.txt and in -check-bits output")
+    groupsPerLine := flag.Int("groups-per-line", 6, "Groups per line when writing binary.txt and in -check-bits output")
+    guided := flag.Bool("tui", false, "Full-screen guided setup: entropy source, words one at a time, quiz, QR, save; or compare a backup with binary.txt")
+    e2e := flag.Bool("e2e", false, "Run an in-memory end-to-end check: generate, export watch-only, receive, restore")
+    jsonOut := flag.Bool("json", false, "Print the result of -b, -no-file, -p, -v, -decode or -check-bits as JSON")
+    migrate := flag.Bool("migrate", false, "Convert an old-format binary.txt to the current format in place (backup in binary.txt.bak)")
+    statsFile := flag.String("stats", "", "Count generation and verification events per wallet in encrypted FILE")
+    statsShow := flag.Bool("stats-show", false, "With -stats: list the recorded wallets, least recently verified first")
+    qrDecode := flag.String("qr-decode", "", "Read a backup QR code from IMG and validate the mnemonic (needs zbarimg)")
+    flag.String("qr-out", "", "With -q: write the QR code to FILE (.png or .svg) instead of the terminal")
+    flag.Int("qr-scale", 8, "With -qr-out: pixels per QR module")
+    flag.Int("qr-border", 4, "With -qr-out: quiet zone in modules")
+    flag.String("qr-ec", "L", "With -q: error-correction level L, M, Q or H")
     inspectWord := flag.String("i", "", "Inspect a word or 11-bit binary")
+    ocrImage := flag.String("ocr", "", "Verify a photo/scan of a paper backup against binary.txt")
+    showDecimal := flag.Bool("d", false, "Show passphrase from binary.txt as 4-digit word indices")
+    importDecimal := flag.String("import-dec", "", "Import 4-digit word indices into binary.txt")
+    showOffset := flag.Bool("offset", false, "Show binary.txt with every word index shifted by a PIN (obfuscation, not encryption)")
+    importOffset := flag.String("import-offset", "", "Import a phrase written with -offset into binary.txt (asks for the PIN)")
+    showGrid := flag.Bool("g", false, "Show passphrase from binary.txt as a punch card grid (11 columns per word)")
+    importGrid := flag.String("import-grid", "", "Import a typed-back punch card grid file into binary.txt")
+    showSheet := flag.Bool("s", false, "Print a backup sheet (QR code and words with per-row checkwords)")
+    decoy := flag.Int("decoy", 0, "Print the passphrase hidden among N decoy passphrases, placed by a PIN")
+    decoyRecover := flag.String("decoy-recover", "", "Pick the real passphrase out of a typed-back decoy sheet FILE using the PIN")
+    ledger := flag.Bool("ledger", false, "List backup sheet serials recorded in sheets.ledger and check the chain")
+    importSheet := flag.String("import-sheet", "", "Import a typed-back backup sheet into binary.txt, checking each row")
+    rsParityWords := flag.Int("rs", 0, "Show passphrase from binary.txt plus N Reed-Solomon parity words")
+    recoverRS := flag.String("recover-rs", "", "Recover a passphrase from words plus parity words, '?' for illegible ones; wrong words are corrected too")
+    audioExport := flag.String("audio-export", "", "Experimental: write the entropy from binary.txt as an FSK WAV file")
+    audioDecode := flag.String("audio-decode", "", "Experimental: decode an FSK WAV backup back into a passphrase")
+    stegoIn := flag.String("stego-embed", "", "Encrypt the entropy from binary.txt and hide it in a PNG image")
+    stegoOut := flag.String("stego-extract", "", "Extract and decrypt a passphrase hidden in a PNG image")
+    sealedOut := flag.String("export-sealed", "", "Write binary.txt as a passphrase-sealed FILE safe for cloud storage")
+    sealedIn := flag.String("import-sealed", "", "Import a FILE written by -export-sealed into binary.txt")
+    threshold := flag.Int("threshold", 0, "Split binary.txt into shares, any K of which recover it (see -recipients)")
+    recipients := flag.String("recipients", "", "Comma-separated age recipients or GPG user IDs, one share each")
+    thresholdCombine := flag.String("threshold-combine", "", "Combine decrypted share FILEs (comma-separated; - for standard input)")
+    slip39Files := flag.String("slip39-combine", "", "Combine SLIP-39 shares from FILEs (comma-separated, one share per line) or typed (-) into a BIP39 phrase")
+    slip39Split := flag.String("slip39-split", "", "Split binary.txt into SLIP-39 shares: SPEC 2of3, or 2:2of3,1of1,3of5 for groups")
+    slip39TokenWrite := flag.String("slip39-token-write", "", "Like -slip39-split, but write each share to its own YubiKey (PIV data object) with ykman")
+    slip39TokenRead := flag.Bool("slip39-token-read", false, "Read SLIP-39 shares from YubiKeys written by -slip39-token-write and combine them")
+    slip39Audit := flag.String("slip39-audit", "", "Check the checksums and set metadata of SLIP-39 shares in FILEs (or typed, -) without combining them")
+    device := flag.String("device", "", "Derive (and record) the BIP85 child passphrase for hardware wallet NAME")
+    deviceWords := flag.Int("device-words", 24, "Words in a new -device passphrase: 12, 18 or 24")
+    devices := flag.Bool("devices", false, "List devices recorded in devices.txt")
+    importFile := flag.String("import", "", "Import another tool's export FILE (see -from)")
+    exportFile := flag.String("export", "", "Write binary.txt to FILE in another tool's format (see -to)")
+    exportFormat := flag.String("to", "ian-coleman-json", "Format for -export: ian-coleman-json")
+    importFormat := flag.String("from", "", "Format for -import: ian-coleman-json, electrum or descriptor")
+    wordlistCheck := flag.String("wordlist-check", "", "Validate a third-party wordlist FILE")
+    wordlistSort := flag.String("wordlist-sort", "", "Print wordlist FILE in canonical form (NFKD, sorted)")
+    balanceCheck := flag.Bool("balance-check", false, "ONLINE: ask -electrum SERVER which derived addresses have history and balance")
+    electrumServer := flag.String("electrum", "", "Electrum server for -balance-check: ssl://host:port or tcp://host:port")
+    electrumInsecure := flag.Bool("electrum-insecure", false, "Do not verify the Electrum server's TLS certificate")
+    proxy := flag.String("proxy", defaultProxy, "SOCKS5 proxy for online features (Tor by default)")
+    clearnetFlag := flag.Bool("clearnet", false, "Allow online features to connect without the proxy")
+    bundleExport := flag.String("bundle-export", "", "Write a verification bundle of binary.txt (fingerprint, check value, first addresses; no words) to FILE")
+    bundleVerify := flag.String("bundle-verify", "", "On a second machine: check typed-in words (or -mnemonic) against a bundle FILE from -bundle-export")
+    rotatePlan := flag.String("rotate-plan", "", "Write a JSON plan for moving funds from an old mnemonic (-mnemonic or prompt) to binary.txt")
+    hintAdd := flag.String("hint-add", "", "Record a salted hash of a BIP39 passphrase and a hint under LABEL in hints.txt")
+    hintCheck := flag.String("hint-check", "", "Test whether a passphrase is the one recorded under LABEL")
+    hintList := flag.Bool("hints", false, "List the hints recorded in hints.txt")
+    recoverPass := flag.Bool("recover-passphrase", false, "Brute-force a half-remembered BIP39 passphrase (see -target, -pattern)")
+    mnemonicIn := flag.String("mnemonic", "", "Mnemonic to use instead of binary.txt")
+    target := flag.String("target", "", "Master fingerprint (8 hex) or address the passphrase must produce")
+    pattern := flag.String("pattern", "", "Passphrase pattern, e.g. 'Summer?d?d{!|?|}'")
+    checkpoint := flag.String("checkpoint", "", "Save encrypted progress of long searches to FILE")
+    resume := flag.Bool("resume", false, "Continue the search saved in -checkpoint FILE")
+    kdfName := flag.String("kdf", seedkdf.Default, "Seed KDF for -recover-passphrase: "+strings.Join(seedkdf.Names(), ", ")+" (only bip39 is standard)")
+    gap := flag.Int("gap", 20, "Receive addresses to check per candidate when -target is an address")
+    statCheck := flag.Bool("stat-check", false, "With -b or -no-file: run statistical tests on the random generator and the entropy first; refuse on failure")
+    lint := flag.Bool("lint", false, "Flag confusable word pairs (with -b: offer to regenerate)")
+    selftest := flag.Bool("selftest", false, "Run the official BIP39 test vectors (entropy, mnemonic, seed) and wordlist checks")
+    bench := flag.Bool("bench", false, "Measure PBKDF2 and passphrase recovery speed")
+    flag.Bool("canonical", false, "With -selftest: byte-exact output for comparing builds")
+    masked := flag.String("masked", "", "Type a passphrase with masked display: verify (against binary.txt) or import")
+    separator := flag.String("separator", "space", "Word separator in printed passphrases: space, newline or comma")
+    metrics := flag.String("metrics", "", "Write a JSON summary of -selftest or -recover-passphrase to FILE")
+    readOnly := flag.Bool("read-only", false, "Only allow inspecting and verifying; refuse anything that generates or shows secrets")
+    demo := flag.Bool("demo", false, "Use fixed, public demo entropy instead of binary.txt and watermark all output")
+    qrOnly := flag.Bool("qr-only", false, "Air-gap mode: input only from scanned QR codes, output only as QR codes, no files")
+    uri := flag.String("uri", "", "Show a BIP21 payment request QR for ADDRESS, or for receive address N of binary.txt")
+    amount := flag.String("amount", "", "With -uri: amount in BTC")
+    label := flag.String("label", "", "With -uri: label for the payment request")
+    message := flag.String("message", "", "With -uri: message for the payment request")
+    doctor := flag.Bool("doctor", false, "Scan shell history, clipboard, tmux scrollback, editor swap and temp files for the passphrase")
+    validate := flag.String("v", "", "Validate an existing mnemonic: check each word and the checksum (or typed with -)")
+    xpubSheet := flag.String("xpub-sheet", "", "Print QR codes of receive addresses derived from account XPUB (no secrets needed)")
+    addrType := flag.String("addr-type", "", "With -xpub-sheet or -export: p2pkh, p2sh-p2wpkh, p2wpkh or p2tr")
+    sheetStart := flag.Int("start", 0, "With -xpub-sheet or -derive: first address index")
+    sheetCount := flag.Int("count", 10, "With -xpub-sheet, -derive or -export: number of addresses")
+    learn := flag.Bool("learn", false, "Interactive BIP39 tutorial with exercises on the demo entropy")
+    transcriptFile := flag.String("transcript", "", "Record commands, options, fingerprints and verification results (no secrets) as JSON in FILE")
+    qrIn := flag.String("qr-in", "", "With -qr-only: scan a mnemonic QR code from IMG, or from the camera with 'cam'")
+    fbDevice := flag.String("fb", "", "Draw the passphrase QR and word table on framebuffer DEVICE, e.g. /dev/fb0")
+    entropyHex := flag.String("entropy-hex", "", "Show the passphrase for entropy given as 32–64 hex digits (with -b: write it to binary.txt)")
+    flag.String("cards", "", "With -b: use a shuffled deck order (\"AS 7H KD ...\") instead of the system random generator")
+    flag.String("dice", "", "With -b: mix six-sided die rolls (digits 1–6) into the system randomness")
+    flag.String("coins", "", "With -b: mix coin flips (H/T or 1/0) into the system randomness")
+    flag.String("debias", "", "With -dice or -coins: debias the raw input first: vn (Von Neumann) or hash (count measured min-entropy)")
+    flag.Bool("typed", false, "With -b: mix a typed random string (asked for, hidden) into the system randomness")
+    flag.Bool("game", false, "With -b: mix in keyboard randomness, generating only after a measured entropy estimate")
+    derive := flag.String("derive", "", "Print addresses of binary.txt or -mnemonic for path family 44, 49, 84 or 86")
+    account := flag.Int("account", 0, "With -derive or -descriptors: account number")
+    flag.Bool("change", false, "With -derive: change addresses (.../1/i) instead of receive addresses")
+    flag.String("out", "", "With -derive: stream addresses as CSV to FILE (.gz or .zst to compress)")
+    showRoot := flag.Bool("root", false, "Show the BIP32 root xprv, xpub and chain code of binary.txt or -mnemonic")
+    showSeed := flag.Bool("seed", false, "Show the BIP39 seed (hex) of binary.txt or -mnemonic, with an optional passphrase")
+    romanize := flag.Bool("romanize", false, "With -lang chinese_*, japanese or korean: show pinyin, romaji or Korean romanization next to the words")
+    reveal := flag.Bool("reveal", false, "Show the words of a passphrase on the terminal without asking (they are masked by default)")
+    explain := flag.Bool("explain", false, "With -p, -no-file, -decode or -entropy-hex: also print every intermediate value (entropy, SHA-256, checksum, 11-bit pieces)")
+    quiz := flag.Bool("quiz", false, "With -no-file or -p: after showing the words, clear the screen and ask for some of them back")
+    clip := flag.Bool("clip", false, "With -p or -seed: copy to the clipboard instead of printing, and clear it after -clip-timeout")
+    clipAfter := flag.Duration("clip-timeout", 30*time.Second, "With -clip: clear the clipboard after DURATION")
+    showBirthday := flag.Bool("birthday", false, "Show the recorded wallet birthday of binary.txt")
+    setBirthdayDate := flag.String("set-birthday", "", "Record YYYY-MM-DD as the wallet birthday of binary.txt (for imported phrases)")
+    descriptors := flag.Bool("descriptors", false, "Print watch-only descriptors with the wallet birthday as Bitcoin Core importdescriptors JSON")
+    rngTest := flag.String("rngtest", "", "Soak-test the random source for DURATION (e.g. 10m) with continuous health tests")
+    rngDevice := flag.String("rng-device", "", "With -rngtest: read this device or file (e.g. /dev/hwrng) instead of crypto/rand")
+    minEntropy := flag.Float64("min-entropy", 8, "With -rngtest: claimed min-entropy of the source in bits per byte")
+    showVersion := flag.Bool("version", false, "Show the version and build information")
+    jobsN := flag.Int("jobs", 0, "Parallel workers for -recover-passphrase, -bench and -derive (0: one per CPU)")
+    decode := flag.String("decode", "", "Show the entropy (hex and binary) of mnemonic WORDS, or of a typed one with -")
+    lastWord := flag.String("last-word", "", "List the final words that complete 11–23 dice-chosen WORDS (or typed with -) and pick one")
+    writeBinary := flag.Bool("write", false, "With -decode or -last-word: also write the entropy to binary.txt")
+    wordlistFile := flag.String("wordlist", "", "Use the 2048-word list in FILE instead of the built-in one (non-standard; checked first)")
+    lang := flag.String("lang", "english", "Wordlist for -p, -q and -i: "+strings.Join(bip39.Languages(), ", "))
 
-    flag.Parse()
+    sub := parseCommandLine()
+    defer setupConsole()()
+    if sub != nil {
+        runCommands(sub.fs, []*invocation{sub})
+        return
+    }
 
-    if !*genBinary && !*useBinary && !*showQRCode && !*showHelp && *inspectWord == "" {
+    if !*genBinary && !*noFile && !*useBinary && !*showQRCode && !*showHelp && *inspectWord == "" && *ocrImage == "" &&
+        *entropyHex == "" && !*showDecimal && *importDecimal == "" && !*showOffset && *importOffset == "" && !*showGrid && *importGrid == "" &&
+        !*showSheet && *importSheet == "" && !*ledger && *decoy == 0 && *decoyRecover == "" && *rsParityWords == 0 && *recoverRS == "" &&
+        !*lint && *audioExport == "" && *audioDecode == "" &&
+        *stegoIn == "" && *stegoOut == "" && !*selftest && !*bench &&
+        *sealedOut == "" && *sealedIn == "" && *threshold == 0 && *thresholdCombine == "" && *slip39Files == "" && *slip39Split == "" && *slip39Audit == "" && *slip39TokenWrite == "" && !*slip39TokenRead &&
+        *device == "" && !*devices && *masked == "" &&
+        *hintAdd == "" && *hintCheck == "" && !*hintList && *rotatePlan == "" && *bundleExport == "" && *bundleVerify == "" && !*balanceCheck &&
+        *importFile == "" && *wordlistCheck == "" && *wordlistSort == "" && !*recoverPass &&
+        !*qrOnly && *qrIn == "" && !*learn && *uri == "" && *xpubSheet == "" && *validate == "" && *exportFile == "" && !*doctor &&
+        !*showSeed && !*showRoot && *derive == "" && *fbDevice == "" &&
+        !*showBirthday && *setBirthdayDate == "" && !*descriptors && *rngTest == "" && *decode == "" && *lastWord == "" && !*showVersion && *qrDecode == "" && !*statsShow && !*encrypt && !*decrypt && !*migrate && *checkBits == "" && *schema == "" && !*e2e && !*guided {
         printHelp()
         return
     }
@@ -35,51 +191,591 @@ func main() {
         printHelp()
         return
     }
+    if *showVersion {
+        printVersion()
+        return
+    }
+    if *schema != "" {
+        printSchema(*schema)
+        return
+    }
+
+    // 旧的动作选项交给对应的子命令
+    if invs := legacyCommands(); invs != nil {
+        runCommands(flag.CommandLine, invs)
+        return
+    }
+
+    checkArgLengths(flag.CommandLine)
+    setJobs(*jobsN)
+    useTmpDir(*tmpDir)
+    sweepStaleSandboxes()
+    defer wipeSandbox()
+    useBinaryFile(*binaryFile)
+    if *gap < 1 || *gap > maxGap {
+        log.Fatalf("Error: -gap must be between 1 and %d", maxGap)
+    }
+    useLayout(flag.CommandLine, *groupBits, *groupsPerLine)
+    metricsFile = *metrics
+    networkProxy, clearnet = *proxy, *clearnetFlag
+    useSeparator(*separator)
+    useWordList(flag.CommandLine, *lang, *wordlistFile)
+
+    revealWords = *reveal
+    if *romanize {
+        useRomanize()
+    }
+    if *explain {
+        if *decode == "" && *entropyHex == "" || *jsonOut || *clip {
+            log.Fatalf("Error: -explain works with -p, -no-file, -decode or -entropy-hex, and not with -json or -clip.")
+        }
+        explainSteps = true
+    }
+    if *quiz {
+        log.Fatalf("Error: -quiz works with -no-file or -p, and not with -json or -clip.")
+    }
+    if *clip && !*showSeed {
+        log.Fatalf("Error: -clip works with -p or -seed.")
+    }
+    useClip(flag.CommandLine, *clip, *clipAfter)
+    if buildReadOnly || *readOnly {
+        enforceReadOnly(flag.CommandLine, *importFormat)
+    }
+    if *jsonOut {
+        enforceJSONFlags(flag.CommandLine)
+        jsonOutput = true
+    }
+
+    // -qr-only → 只经二维码进出，不碰任何文件
+    if !buildReadOnly && (*qrOnly || *qrIn != "") {
+        enforceQROnly()
+        runQROnly(*genBinary, *qrIn, loadWordList())
+        return
+    }
+
+    // -qr-only 不写文件，所以会话记录在检查选项之后才开始
+    if *transcriptFile != "" {
+        startTranscript(*transcriptFile, flag.CommandLine)
+        defer transcript.finish()
+    }
+
+    // -stats FILE → 本地使用统计（加密）
+    if *statsShow && *statsFile == "" {
+        log.Fatalf("Error: -stats-show needs -stats FILE")
+    }
+    if *statsFile != "" {
+        openStats(*statsFile)
+        if *statsShow {
+            usage.print()
+            return
+        }
+    }
+
+    // 台账不含秘密，不需要 binary.txt
+    if *ledger {
+        printLedger()
+        return
+    }
+    if *devices {
+        printDevices()
+        return
+    }
+
+    // -xpub-sheet 只用公开的 xpub
+    if *xpubSheet != "" {
+        printAddressSheet(*xpubSheet, *addrType, *sheetStart, *sheetCount)
+        return
+    }
+
+    // 口令提示只用到口令本身，不需要词表与 binary.txt
+    if *hintList {
+        printHints()
+        return
+    }
+    if !buildReadOnly && *hintAdd != "" {
+        addHint(*hintAdd)
+        return
+    }
+    if *hintCheck != "" {
+        checkHint(*hintCheck)
+        return
+    }
+
+    // 词表维护不依赖内置词表
+    if *wordlistCheck != "" {
+        printWordlistCheck(*wordlistCheck)
+        return
+    }
+    if *wordlistSort != "" {
+        printSortedWordlist(*wordlistSort)
+        return
+    }
+
+    // -bench 不需要词表与 binary.txt
+    if *bench {
+        runBenchmark()
+        return
+    }
+
+    // -rngtest 同样不需要
+    if *rngTest != "" {
+        runRNGTest(*rngTest, *rngDevice, *minEntropy)
+        return
+    }
+
+    if err := useSeedKDF(*kdfName); err != nil {
+        log.Fatalf("Error: -kdf: %v", err)
+    }
+
+    if *demo {
+        if *setBirthdayDate != "" || *writeBinary || *encrypt || *decrypt || *migrate || *importDecimal != "" || *importOffset != "" || *importGrid != "" || *importSheet != "" || *importFile != "" || *sealedIn != "" || *masked == "import" {
+            log.Fatalf("Error: writing binary.txt or %s is disabled in demo mode.", birthdayFile)
+        }
+        useDemo()
+        defer printDemoWatermark()
+    }
+
+    if *batchCount != 1 {
+        log.Fatalf("Error: -n needs -b or -no-file.")
+    }
+    if *statCheck {
+        log.Fatalf("Error: -stat-check needs -b or -no-file.")
+    }
+
+    // -encrypt / -decrypt → 就地转换 binary.txt；与 -b 或导入同用时直接写加密文件
+    if *encrypt && *decrypt {
+        log.Fatalf("Error: use either -encrypt or -decrypt, not both.")
+    }
+    useEncryption(*encrypt, *dpapi)
+    // -migrate → 旧格式 binary.txt 就地转换（可同时 -encrypt）
+    if !buildReadOnly && *migrate {
+        if *decrypt {
+            log.Fatalf("Error: -migrate keeps the file encrypted or plain; run -decrypt separately.")
+        }
+        migrateBinaryFile()
+        return
+    }
+    writesBinary := *writeBinary || *importDecimal != "" || *importOffset != "" || *importGrid != "" ||
+        *importSheet != "" || *importFile != "" || *sealedIn != "" || *masked == "import"
+    if !buildReadOnly && (*decrypt || *encrypt && !writesBinary) {
+        convertBinaryFile(*encrypt)
+        return
+    }
 
     wordList := loadWordList()
     if len(wordList) != 2048 {
         log.Fatalf("Error: word list length %d, expected 2048", len(wordList))
     }
 
-    // -i WORD / -i BINARY
-    if *inspectWord != "" {
-        showWordInfo(*inspectWord, wordList)
+    // -learn → 交互式入门（只用演示熵）
+    if *learn {
+        runTutorial(wordList)
         return
     }
 
-    // -b → generate binary
-    if *genBinary {
-        entropy := make([]byte, 32)
-        _, err := rand.Read(entropy)
+    // -entropy-hex（不带 -b）→ 只显示，不写 binary.txt
+    if !buildReadOnly && *entropyHex != "" {
+        entropy, err := parseEntropyHex(*entropyHex)
         if err != nil {
-            log.Fatalf("Error generating entropy: %v", err)
+            log.Fatalf("Error: -entropy-hex: %v", err)
         }
-        err = writeBinaryFile("binary.txt", entropy)
-        if err != nil {
-            log.Fatalf("Error writing binary.txt: %v", err)
+        printEntropyPhrase(entropy, wordList)
+        return
+    }
+
+    // -recover-passphrase → 暴力恢复口令
+    if !buildReadOnly && *recoverPass {
+        mnemonic := selectedMnemonic(*mnemonicIn, wordList)
+        recoverPassphrase(mnemonic, *target, *pattern, *checkpoint, *resume, *gap)
+        return
+    }
+
+    // -seed → 512 位种子
+    if !buildReadOnly && *showSeed {
+        printSeed(*mnemonicIn, wordList)
+        return
+    }
+
+    // -root → BIP32 主密钥
+    if !buildReadOnly && *showRoot {
+        printRootKey(*mnemonicIn, wordList)
+        return
+    }
+
+    // -descriptors → importdescriptors JSON（带生日）
+    if !buildReadOnly && *descriptors {
+        exportDescriptors(*account, *mnemonicIn, wordList)
+        return
+    }
+
+    // -set-birthday / -birthday → 钱包生日
+    if !buildReadOnly && *setBirthdayDate != "" {
+        setBirthday(*setBirthdayDate, wordList)
+        return
+    }
+    if !buildReadOnly && *showBirthday {
+        printBirthday(wordList)
+        return
+    }
+
+    // -balance-check → 联网查询（只发送地址哈希）
+    if !buildReadOnly && *balanceCheck {
+        if *electrumServer == "" {
+            log.Fatalf("Error: -balance-check needs -electrum SERVER")
         }
-        fmt.Println("binary.txt generated successfully.")
+        checkBalances(*electrumServer, *electrumInsecure, selectedMnemonic(*mnemonicIn, wordList), *gap)
+        return
     }
 
-    // -p → passphrase
-    if *useBinary {
-        passphrase := generatePassphraseFromBinary(wordList)
-        fmt.Println("Passphrase:")
-        fmt.Println(passphrase)
+    // -rotate-plan FILE → 旧助记词迁移到 binary.txt 的计划
+    if !buildReadOnly && *rotatePlan != "" {
+        writeRotationPlan(*rotatePlan, *mnemonicIn, *gap, wordList)
+        return
     }
 
-    // -q → QR Code
-    if *showQRCode {
-        passphrase := generatePassphraseFromBinary(wordList)
-        fmt.Println("Passphrase QR Code:")
-        qr, err := qrcode.New(passphrase, qrcode.Low)
+    // -bundle-export / -bundle-verify → 两台机器核对备份
+    if !buildReadOnly && *bundleExport != "" {
+        exportBundle(*bundleExport, wordList)
+        return
+    }
+    if *bundleVerify != "" {
+        verifyBundleFile(*bundleVerify, *mnemonicIn, wordList)
+        return
+    }
+
+    // -masked verify|import → 遮挡输入
+    if !buildReadOnly && *masked != "" {
+        maskedEntry(*masked, wordList)
+        return
+    }
+
+    // -doctor → 残留扫描
+    if !buildReadOnly && *doctor {
+        runDoctor(wordList)
+        return
+    }
+
+    // -tui → 全屏引导
+    if !buildReadOnly && *guided {
+        runTUI(wordList)
+        return
+    }
+
+    // -e2e → 内存中的端到端演练
+    if !buildReadOnly && *e2e {
+        size, err := entropySize(*wordCount, *entropyBits)
         if err != nil {
-            log.Fatalf("Error generating QR code: %v", err)
+            log.Fatalf("Error: %v", err)
+        }
+        runE2E(size, wordList)
+        return
+    }
+
+    // -check-bits "0101..." → 校验任意位串
+    if *checkBits != "" {
+        checkBitString(*checkBits)
+        return
+    }
+
+    // -qr-decode IMG → 读取二维码并校验
+    if *qrDecode != "" {
+        decodeQRImage(*qrDecode, wordList)
+        return
+    }
+
+    // -uri ADDR|N → BIP21 收款二维码
+    if *uri != "" {
+        printPaymentRequest(*uri, *amount, *label, *message, !*readOnly, wordList)
+        return
+    }
+
+    // -ocr IMAGE → 校验纸质备份
+    if !buildReadOnly && *ocrImage != "" {
+        verifyPaperBackup(*ocrImage, wordList)
+        return
+    }
+
+    // -audio-decode FILE.wav → 还原助记词
+    if !buildReadOnly && *audioDecode != "" {
+        decodeAudioBackup(*audioDecode, wordList)
+        return
+    }
+
+    // -stego-extract IMG.png → 解密还原助记词
+    if !buildReadOnly && *stegoOut != "" {
+        stegoExtract(*stegoOut, wordList)
+        return
+    }
+
+    // -decode → 熵（-write 时写入 binary.txt）
+    if !buildReadOnly && *decode != "" {
+        decodeMnemonic(*decode, *writeBinary, wordList)
+        return
+    }
+
+    // -last-word WORDS → 手工生成时补上最后一个单词
+    if !buildReadOnly && *lastWord != "" {
+        lastWordCalculator(*lastWord, *writeBinary, wordList)
+        return
+    }
+
+    // -slip39-audit 不合并分享，只读模式下也可用
+    if *slip39Audit != "" {
+        auditSLIP39(*slip39Audit)
+        return
+    }
+
+    // -slip39-combine → SLIP-39 分享还原为 BIP39 助记词
+    if !buildReadOnly && *slip39Files != "" {
+        combineSLIP39(*slip39Files, wordList)
+        return
+    }
+
+    if !buildReadOnly && *slip39TokenRead {
+        readSLIP39Tokens(wordList)
+        return
+    }
+
+    if !buildReadOnly && *thresholdCombine != "" {
+        combineThreshold(*thresholdCombine, wordList)
+        return
+    }
+
+    if !buildReadOnly && *sealedIn != "" {
+        importSealed(*sealedIn, wordList)
+        return
+    }
+
+    // -import FILE -from FORMAT → 其他工具的导出
+    if *importFile != "" && *importFormat == "descriptor" {
+        inspectDescriptorFile(*importFile)
+        return
+    }
+    if !buildReadOnly && *importFile != "" {
+        importFrom(*importFormat, *importFile, wordList)
+        return
+    }
+
+    // -import-offset WORDS → 减去 PIN 偏移后写入 binary.txt
+    if !buildReadOnly && *importOffset != "" {
+        importOffsetPhrase(*importOffset, wordList)
+        return
+    }
+
+    // -import-dec "0001 0002 ..." → 写入 binary.txt
+    if !buildReadOnly && *importDecimal != "" {
+        importDecimalIndices(*importDecimal)
+        return
+    }
+
+    // -import-grid FILE → 写入 binary.txt
+    if !buildReadOnly && *importGrid != "" {
+        importPunchGrid(*importGrid)
+        return
+    }
+
+    // -import-sheet FILE → 逐行校验后写入 binary.txt
+    if !buildReadOnly && *importSheet != "" {
+        importBackupSheet(*importSheet, wordList)
+        return
+    }
+
+    // -decoy-recover FILE → 用 PIN 找出真实助记词
+    if !buildReadOnly && *decoyRecover != "" {
+        recoverDecoySheet(*decoyRecover, wordList)
+        return
+    }
+
+    // -recover-rs "WORDS ... PARITY" → 还原 -words 个单词
+    if !buildReadOnly && *recoverRS != "" {
+        // 单词数由输入推断；明确给了 -words 时才按它拆分
+        n := 0
+        flag.Visit(func(f *flag.Flag) {
+            if f.Name == "words" {
+                n = *wordCount
+            }
+        })
+        recoverRSBackup(*recoverRS, n, wordList)
+        return
+    }
+
+    // -lint → 检查 binary.txt 的助记词（generate -lint 在生成阶段检查）
+    if !buildReadOnly && *lint {
+        lintBinary(wordList)
+    }
+
+    // -fb DEVICE → 帧缓冲 / 墨水屏
+    if !buildReadOnly && *fbDevice != "" {
+        showOnFramebuffer(*fbDevice, wordList)
+    }
+
+    // -d → 十进制索引
+    if !buildReadOnly && *showDecimal {
+        printDecimalIndices()
+    }
+
+    // -offset → PIN 偏移混淆
+    if !buildReadOnly && *showOffset {
+        printOffsetPhrase(wordList)
+    }
+
+    // -g → 打孔网格
+    if !buildReadOnly && *showGrid {
+        printPunchGrid()
+    }
+
+    // -s → 备份纸
+    if !buildReadOnly && *showSheet {
+        printBackupSheet(wordList)
+    }
+
+    // -decoy N → 诱饵备份纸
+    if !buildReadOnly && *decoy != 0 {
+        printDecoySheet(*decoy, wordList)
+    }
+
+    // -rs N → Reed–Solomon 校验词
+    if !buildReadOnly && *rsParityWords != 0 {
+        printRSBackup(*rsParityWords, wordList)
+    }
+
+    // -audio-export FILE.wav → FSK 音频备份
+    if !buildReadOnly && *audioExport != "" {
+        exportAudioBackup(*audioExport)
+    }
+
+    // -stego-embed IMG.png → 加密后写入图片
+    if !buildReadOnly && *stegoIn != "" {
+        stegoEmbed(*stegoIn)
+    }
+
+    if !buildReadOnly && *sealedOut != "" {
+        exportSealed(*sealedOut, wordList)
+    }
+
+    if !buildReadOnly && *threshold != 0 {
+        splitThreshold(*threshold, *recipients, wordList)
+    }
+
+    if !buildReadOnly && *slip39Split != "" {
+        splitSLIP39(*slip39Split, wordList)
+    }
+
+    if !buildReadOnly && *slip39TokenWrite != "" {
+        writeSLIP39Tokens(*slip39TokenWrite, wordList)
+    }
+
+    if !buildReadOnly && *device != "" {
+        showDeviceSeed(*device, *deviceWords, wordList)
+    }
+
+    // -export FILE -to FORMAT → 其他工具的格式
+    if !buildReadOnly && *exportFile != "" {
+        exportTo(*exportFormat, *exportFile, *addrType, *sheetCount, wordList)
+    }
+
+    // 记录输出所用 binary.txt 的指纹（demo 模式没有用到 binary.txt）
+    shown := *fbDevice != "" || *showDecimal || *showOffset || *showGrid || *showSheet || *decoy != 0 ||
+        *rsParityWords != 0 || *audioExport != "" || *stegoIn != "" || *sealedOut != "" ||
+        *threshold != 0 || *device != "" || *exportFile != ""
+    if !buildReadOnly && shown && !demoMode {
+        transcript.recordBinary("output from binary.txt", "ok", wordList)
+    }
+}
+
+//
+// -------------------------
+//   会话设置
+// -------------------------
+//
+// main 与子命令共用：把选项的值设置到程序状态上。fs 用来判断某个选项
+// 是否明确给出
+//
+
+func useTmpDir(dir string) {
+    if dir == "" {
+        return
+    }
+    if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+        log.Fatalf("Error: -tmpdir %s is not a directory", dir)
+    }
+    sandboxBase = dir
+}
+
+func useBinaryFile(path string) {
+    if path == "" {
+        log.Fatalf("Error: -f needs a file path")
+    }
+    binaryPath = path
+}
+
+func useLayout(fs *flag.FlagSet, groupBits, groupsPerLine int) {
+    if flagGiven(fs, "group-bits") || flagGiven(fs, "groups-per-line") {
+        bitLayoutGiven = true
+    }
+    if err := setLayout(groupBits, groupsPerLine); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+}
+
+func useSeparator(name string) {
+    sep, err := parseSeparator(name)
+    if err != nil {
+        log.Fatalf("Error: -separator: %v", err)
+    }
+    wordSeparator = sep
+}
+
+func useWordList(fs *flag.FlagSet, lang, file string) {
+    phraseLanguage = lang
+    if file != "" {
+        if flagGiven(fs, "lang") {
+            log.Fatalf("Error: use either -wordlist or -lang, not both.")
+        }
+        customWordlist = file
+    }
+}
+
+// 在 useWordList 之后调用
+func useRomanize() {
+    if customWordlist != "" || !slices.Contains(romanizationLanguages(), phraseLanguage) {
+        log.Fatalf("Error: -romanize works with -lang %s.", strings.Join(romanizationLanguages(), ", "))
+    }
+    romanizeWords = true
+}
+
+func useClip(fs *flag.FlagSet, clip bool, after time.Duration) {
+    if !clip {
+        if flagGiven(fs, "clip-timeout") {
+            log.Fatalf("Error: -clip-timeout needs -clip")
+        }
+        return
+    }
+    if after < time.Second || after > maxClipTimeout {
+        log.Fatalf("Error: -clip-timeout must be between 1s and %s", maxClipTimeout)
+    }
+    clipOutput, clipTimeout = true, after
+}
+
+func useEncryption(encrypt, dpapi bool) {
+    encryptBinary = encrypt
+    if dpapi {
+        if !encrypt {
+            log.Fatalf("Error: -dpapi is used with -encrypt.")
+        }
+        if !dpapiAvailable {
+            log.Fatalf("Error: -dpapi needs Windows; use -encrypt alone for a passphrase.")
         }
-        fmt.Println(qr.ToSmallString(false))
+        useDPAPI = true
     }
 }
 
+// 调用者在结束时再打印一次水印
+func useDemo() {
+    demoMode = true
+    printDemoWatermark()
+}
+
 //
 // -------------------------
 //   -i 处理逻辑（双模式）
@@ -87,7 +783,7 @@ func main() {
 //
 
 func showWordInfo(input string, wordList []string) {
-    input = strings.TrimSpace(strings.ToLower(input))
+    input = textnorm.NFKD(strings.TrimSpace(strings.ToLower(input)))
 
     // 1. 输入是二进制？
     if isBinary(input) {
@@ -109,20 +805,44 @@ func showWordInfo(input string, wordList []string) {
         fmt.Println("Binary:", input)
         fmt.Println("Index:", idx)
         fmt.Println("Word:", wordList[idx])
+        printWordRomanization(wordList[idx])
+        return
+    }
+
+    // 2. 否则输入是单词（或唯一前缀）
+    matcher := wordmatch.New(wordList)
+    if c, ok := matcher.Lookup(input); ok {
+        fmt.Println("Word:", c.Word)
+        printWordRomanization(c.Word)
+        fmt.Println("Index:", c.Index)
+        fmt.Printf("Binary: %011b\n", c.Index)
         return
     }
 
-    // 2. 否则输入是单词
-    for i, w := range wordList {
-        if w == input {
-            fmt.Println("Word:", input)
-            fmt.Println("Index:", i)
-            fmt.Printf("Binary: %011b\n", i)
+    // 拉丁转写（-romanize）：拼音可能对应多个汉字
+    if romanizeWords {
+        if found := wordsForRomanization(input, wordList); len(found) > 0 {
+            fmt.Printf("%d word(s) read '%s':\n", len(found), input)
+            fmt.Println("Index  Binary       Word")
+            table := romanizationTable()
+            for _, idx := range found {
+                fmt.Printf("%5d  %011b  %s %s\n", idx, idx, wordList[idx], table[wordList[idx]])
+            }
             return
         }
     }
 
-    fmt.Printf("Error: '%s' is neither a valid word nor a valid binary.\n", input)
+    // 3. 不唯一的前缀：列出所有以它开头的单词（前 4 个字母总能确定一个单词）
+    if matches := matcher.Prefix(input); len(matches) > 0 {
+        fmt.Printf("%d words start with '%s':\n", len(matches), input)
+        fmt.Println("Index  Binary       Word")
+        for _, c := range matches {
+            fmt.Printf("%5d  %011b  %s\n", c.Index, c.Index, c.Word)
+        }
+        return
+    }
+
+    fmt.Printf("Error: '%s' is neither a valid word nor a valid binary%s.\n", input, didYouMean(matcher, input))
 }
 
 func isBinary(s string) bool {
@@ -153,66 +873,274 @@ func binaryToInt(bin string) int {
 
 func printHelp() {
     fmt.Println("passphrase_bitcoin - A 256-bit entropy & BIP39 passphrase generator")
+    if buildReadOnly {
+        fmt.Println("Read-only build: only -i, -selftest, -bench, -wordlist-*, -ledger, -devices")
+        fmt.Println("and -import -from descriptor are available.")
+    }
     fmt.Println()
     fmt.Println("Usage:")
+    fmt.Println("  passphrase_bitcoin COMMAND [options] [arguments]")
     fmt.Println("  passphrase_bitcoin [options]")
     fmt.Println()
+    fmt.Println("Commands (each takes only its own options; see help COMMAND or COMMAND -h):")
+    fmt.Println("  generate  Generate binary.txt (-b); -no-file only prints the passphrase")
+    fmt.Println("  mnemonic  Print the passphrase of binary.txt (-p)")
+    fmt.Println("  qr        Show the passphrase of binary.txt as a QR code (-q)")
+    fmt.Println("  inspect WORD|PREFIX|BITS  Look up a word, a prefix or 11 bits (-i)")
+    fmt.Println("  validate [WORDS...]  Check a mnemonic (-v); without WORDS it asks, hidden")
+    fmt.Println("  derive 44|49|84|86  Print addresses of binary.txt or -mnemonic (-derive)")
+    fmt.Println("  selftest  Check this binary against the official BIP39 test vectors (-selftest)")
+    fmt.Println("  help [COMMAND]  Show this help, or the options of COMMAND")
+    fmt.Println()
     fmt.Println("Options:")
     fmt.Println("  -b        Generate binary.txt only")
+    fmt.Println("  -b -words N  Generate a 12, 15, 18, 21 or 24-word (default) passphrase")
+    fmt.Println("            (or -bits 128|160|192|224|256; the checksum is bits/32 long)")
+    fmt.Println("  -f PATH   Use the entropy file PATH instead of ./binary.txt for every option")
+    fmt.Println("            that reads or writes it (e.g. -b -f /mnt/secure/wallet2.txt, then -p -f ...)")
+    fmt.Println("  -group-bits N -groups-per-line M  Lay out the bits of binary.txt (and -check-bits)")
+    fmt.Println("            in groups of N bits, M groups per line (11 and 6); kept in the file header")
     fmt.Println("  -p        Generate passphrase from binary.txt")
+    fmt.Println("  -reveal   Show passphrase words on the terminal without asking; by default they are")
+    fmt.Println("            masked (l*** w*** ...) until you confirm; files and pipes get them in full")
+    fmt.Println("  -quiz     With -no-file or -p: once the words are on paper, clear the screen and ask")
+    fmt.Println("            for 3 random positions (hidden input); exit code 1 if you give up")
+    fmt.Println("  -explain  With -p, -no-file, -decode or -entropy-hex: also print every intermediate step")
+    fmt.Println("            (entropy hex, SHA-256, checksum bits, 11-bit pieces beside the words)")
+    fmt.Println("  -clip     With -p or -seed: copy the passphrase or seed to the clipboard instead of")
+    fmt.Println("            printing it, and clear the clipboard after -clip-timeout DURATION (30s)")
+    fmt.Println("  -encrypt  Encrypt binary.txt with a passphrase (scrypt + AES-GCM); with -b or an")
+    fmt.Println("            import, write it encrypted. -p, -q etc. then ask for the passphrase")
+    fmt.Println("  -encrypt -dpapi  On Windows: protect binary.txt with the current user account")
+    fmt.Println("            (DPAPI) instead of a passphrase; only that user on that computer can read it")
+    fmt.Println("  -decrypt  Turn an encrypted binary.txt back into plain text")
+    fmt.Println("  -json     With -b, -no-file, -p, -v, -decode or -check-bits: print one JSON object")
+    fmt.Println("            (entropy hex, words with indices and bits, checksum, validity, fingerprint)")
+    fmt.Println("  -migrate  Convert an old binary.txt (no header) to the current checked format in")
+    fmt.Println("            place; the original is kept as binary.txt.bak. Add -encrypt to encrypt it")
     fmt.Println("  -q        Generate QR code of passphrase from binary.txt")
+    fmt.Println("  -q -qr-out FILE  Write the QR code to FILE.png or FILE.svg instead (mode 0600);")
+    fmt.Println("            -qr-scale N pixels per module (8), -qr-border N modules (4),")
+    fmt.Println("            -qr-ec L|M|Q|H error correction (L, also for the terminal QR code)")
+    fmt.Println("  -qr-decode IMG  Read a printed backup QR code from a photo or scan, check every word")
+    fmt.Println("            and the checksum, and compare it with binary.txt (needs zbarimg)")
+    fmt.Println("  -fb DEV   Draw the passphrase QR code and word table on a framebuffer or e-ink")
+    fmt.Println("            display (e.g. /dev/fb0), bypassing the terminal; Enter clears it")
     fmt.Println("  -i WORD   Show WORD's index and 11-bit binary")
+    fmt.Println("  -i PREFIX List every word starting with PREFIX with its index and binary")
     fmt.Println("  -i BIN    Show BIN's index and corresponding word")
+    fmt.Println("  -v WORDS|-  Validate a 12–24 word mnemonic: each word and the checksum (- asks for it)")
+    fmt.Println("  -check-bits BITS|-  Verify an entropy+checksum bit string (e.g. from another tool's")
+    fmt.Println("            debug output): report the expected checksum and the corrected bits")
+    fmt.Println("  -d        Show passphrase from binary.txt as 4-digit word indices")
+    fmt.Println("  -decode WORDS|-  Reverse of -p: verify the checksum and show the entropy in hex and")
+    fmt.Println("            binary; -write also writes it to binary.txt (- asks for the words)")
+    fmt.Println("  -last-word WORDS|-  For a seed made with dice or coins: list every final word that")
+    fmt.Println("            gives the 11–23 words a valid checksum (8 for 24 words) and pick one or r for")
+    fmt.Println("            random; -write also writes it to binary.txt (- asks for the words)")
+    fmt.Println("  -import-dec IDX  Import 4-digit word indices into binary.txt")
+    fmt.Println("  -offset   Show passphrase from binary.txt with each word index shifted by a PIN")
+    fmt.Println("            (obfuscation only: 2047 possible offsets)")
+    fmt.Println("  -import-offset WORDS  Undo -offset with the PIN and import into binary.txt")
+    fmt.Println("  -g        Show passphrase from binary.txt as a punch card grid (11 columns per word)")
+    fmt.Println("  -import-grid FILE  Import a typed-back punch card grid into binary.txt")
+    fmt.Println("  -s        Print a backup sheet (QR code and words with per-row checkwords)")
+    fmt.Println("            Each sheet gets a serial, recorded in sheets.ledger")
+    fmt.Println("  -ledger   List sheet serials and fingerprints, check the ledger for edits")
+    fmt.Println("  -decoy N  Print the passphrase hidden among N decoys at a PIN-derived position")
+    fmt.Println("  -decoy-recover FILE  Pick the real passphrase out of a decoy sheet with the PIN")
+    fmt.Println("  -import-sheet FILE  Import a typed-back backup sheet, checking each row")
+    fmt.Println("  -rs N     Show passphrase from binary.txt plus N Reed-Solomon parity words")
+    fmt.Println("  -recover-rs WORDS  Recover a passphrase from words + parity words ('?' = illegible);")
+    fmt.Println("            also corrects wrong words while 2 x wrong + illegible <= N; the word count")
+    fmt.Println("            is read from the input unless -words is given")
+    fmt.Println("  -ocr IMG  Verify a photo/scan of a paper backup (needs tesseract); exit code 1 on a")
+    fmt.Println("            mismatch, 2 if some words only need a manual review")
+    fmt.Println("  -audio-export WAV  Experimental: write binary.txt as an FSK audio backup")
+    fmt.Println("  -audio-decode WAV  Experimental: decode an FSK audio backup")
+    fmt.Println("  -stego-embed PNG    Encrypt binary.txt and hide it in a copy of PNG")
+    fmt.Println("  -stego-extract PNG  Extract and decrypt a passphrase hidden in PNG")
+    fmt.Println("  -export-sealed FILE  Encrypt binary.txt into FILE for cloud storage (Argon2id + XChaCha20-Poly1305)")
+    fmt.Println("  -import-sealed FILE  Decrypt a sealed FILE into binary.txt")
+    fmt.Println("  -threshold K -recipients R1,R2,...  Split binary.txt into shares encrypted to")
+    fmt.Println("            age/GPG recipients; any K of them recover the passphrase")
+    fmt.Println("  -threshold-combine F1,F2,...  Combine decrypted shares; - reads them from standard input,")
+    fmt.Println("            e.g. age -d share-1.age | passphrase_bitcoin -threshold-combine -,s2.txt")
+    fmt.Println("  -entropy-hex HEX  Show the passphrase for 128–256 bits of entropy made elsewhere")
+    fmt.Println("            (hardware RNG, another machine); with -b write it to binary.txt")
+    fmt.Println("  -slip39-combine F1,F2,...|-  Combine SLIP-39 shares (files with one share per line,")
+    fmt.Println("            or - to type them) and show the master secret as a BIP39 phrase")
+    fmt.Println("  -slip39-split SPEC  Split binary.txt into SLIP-39 shares: 2of3 for one group,")
+    fmt.Println("            2:2of3,1of1,3of5 for any 2 of 3 groups (asks for an optional passphrase)")
+    fmt.Println("  -slip39-token-write SPEC  Like -slip39-split, but write each share to its own YubiKey")
+    fmt.Println("            (PIV data object, via ykman) and read it back; -slip39-token-read combines them")
+    fmt.Println("  -slip39-audit F1,F2,...|-  Check each SLIP-39 share's checksum and that the shares")
+    fmt.Println("            belong to one set, without combining them; exit code 1 on any problem")
+    fmt.Println("  -b -cards \"AS 7H KD ...\"  Use a shuffled deck order as the entropy (52 cards per deck,")
+    fmt.Println("            about 225 bits each; refused if there are too few cards)")
+    fmt.Println("  -b -game  Mash the keyboard first: key choice and timing are measured and mixed")
+    fmt.Println("            into the system randomness; generation waits for the estimate")
+    fmt.Println("  -b -dice 16325...  Mix die rolls into the system randomness (2.58 bits per roll)")
+    fmt.Println("  -b -coins HTTH...  Mix coin flips (H/T or 1/0) into the system randomness (1 bit per flip)")
+    fmt.Println("  -b -debias vn|hash  Debias -dice and -coins first: vn keeps one unbiased bit per unequal")
+    fmt.Println("            pair (Von Neumann); hash counts only the measured min-entropy")
+    fmt.Println("  -b -typed  Also mix in a typed random string (hidden; its entropy is not counted)")
+    fmt.Println("  -b -pick  Choose the words for some positions (asked for, hidden) and fill the rest")
+    fmt.Println("            randomly with a valid checksum; each chosen word costs 11 bits")
+    fmt.Println("  -b -stat-check  Before writing, run the FIPS 140-2 monobit, poker, runs and long-run")
+    fmt.Println("            tests on a 20000-bit sample of the random generator and a sanity check on the")
+    fmt.Println("            entropy; refuse if the generator looks broken (also with -no-file and -n)")
+    fmt.Println("  -no-file  Like -b, but only print the new passphrase (and its QR code with -q);")
+    fmt.Println("            nothing is written to disk. Works with the -b entropy options")
+    fmt.Println("            -dice, -coins, -typed and -game can be combined; the sources used are listed")
+    fmt.Println("  -n COUNT  With -b or -no-file: generate COUNT independent passphrases; -b writes")
+    fmt.Println("            binary-1.txt ... binary-COUNT.txt (named after -f), -json prints one line each")
+    fmt.Println("  -seed     Show the 512-bit BIP39 seed in hex for binary.txt (or -mnemonic WORDS),")
+    fmt.Println("            asking for an optional BIP39 passphrase")
+    fmt.Println("  -root     Show the BIP32 root key (xprv, xpub, chain code, master fingerprint)")
+    fmt.Println("  -derive 44|49|84|86  Print addresses m/purpose'/0'/account'/0/i of binary.txt")
+    fmt.Println("            (or -mnemonic) to compare with a wallet (-account N, -start I, -count N,")
+    fmt.Println("            -change for .../1/i, -out FILE[.gz|.zst] to stream up to 10 million rows as CSV)")
+    fmt.Println("  -birthday  Show the wallet birthday recorded by -b in birthday.txt")
+    fmt.Println("  -set-birthday YYYY-MM-DD  Record the date of first use for an imported phrase")
+    fmt.Println("  -descriptors  Print BIP44/49/84/86 watch-only descriptors (-account N) as Bitcoin")
+    fmt.Println("            Core importdescriptors JSON, with the birthday as the rescan timestamp")
+    fmt.Println("  -rngtest DURATION  Soak-test the random source (e.g. 10m): repetition, adaptive")
+    fmt.Println("            proportion and chi-square tests; -rng-device /dev/hwrng to test a hardware")
+    fmt.Println("            RNG, -min-entropy BITS for a raw noise source (default 8 per byte)")
+    fmt.Println("  -device NAME  Show the BIP85 child passphrase for hardware wallet NAME")
+    fmt.Println("            (new names are recorded in devices.txt; -device-words 12|18|24)")
+    fmt.Println("  -devices  List devices recorded in devices.txt")
+    fmt.Println("  -uri ADDR|N  Show a BIP21 payment request QR for ADDR, or for BIP84 receive address N")
+    fmt.Println("            of binary.txt (-amount BTC, -label TEXT, -message TEXT)")
+    fmt.Println("  -xpub-sheet XPUB  Print numbered QR codes of receive addresses from an account")
+    fmt.Println("            xpub/ypub/zpub, no secrets needed (-start I, -count N, -addr-type T)")
+    fmt.Println("  -masked verify|import  Type the passphrase word by word, shown only as ****")
+    fmt.Println("            with a per-word ✓/✗; compare with or import into binary.txt")
+    fmt.Println("  -import FILE -from FMT  Import FILE exported by another tool")
+    fmt.Println("            (FMT: ian-coleman-json, electrum, descriptor)")
+    fmt.Println("  -export FILE -to ian-coleman-json  Write binary.txt as an Ian Coleman BIP39 page state")
+    fmt.Println("            (seed, keys, -count N addresses of -addr-type T); holds private keys")
+    fmt.Println("  -wordlist-check FILE  Validate a third-party wordlist")
+    fmt.Println("  -wordlist-sort FILE   Print a wordlist in canonical form (NFKD, sorted)")
+    fmt.Println("  -balance-check -electrum SERVER  ONLINE: show which derived addresses have history")
+    fmt.Println("            and balance (only address hashes are sent; -electrum-insecure, -gap N)")
+    fmt.Println("            Goes through Tor (-proxy socks5://127.0.0.1:9050) unless -clearnet")
+    fmt.Println("  -rotate-plan FILE  Write a JSON plan and checklist for moving funds from an old")
+    fmt.Println("            mnemonic (-mnemonic or prompt) to binary.txt (-gap N addresses)")
+    fmt.Println("  -bundle-export FILE  Write a verification bundle of binary.txt: word count, fingerprint,")
+    fmt.Println("            check value and the first 3 addresses of 44/49/84/86 (no words or xpubs)")
+    fmt.Println("  -bundle-verify FILE  On a second machine: type the words from paper (or -mnemonic)")
+    fmt.Println("            and compare everything with the bundle; exit status 1 on a mismatch")
+    fmt.Println("  -hint-add LABEL    Record a hint and a salted hash of a BIP39 passphrase in hints.txt")
+    fmt.Println("  -hint-check LABEL  Test whether a passphrase is the recorded one")
+    fmt.Println("  -hints    List recorded hints")
+    fmt.Println("  -recover-passphrase -target FP|ADDR -pattern MASK [-mnemonic WORDS]")
+    fmt.Println("            Brute-force a half-remembered BIP39 passphrase")
+    fmt.Println("            (-checkpoint FILE [-resume] to save/continue, -gap N addresses)")
+    fmt.Println("            (-kdf scrypt|argon2id: experimental, NON-STANDARD seeds)")
+    fmt.Println("  -bench    Measure PBKDF2 and passphrase recovery speed (candidates/second)")
+    fmt.Println("  -jobs N   Parallel workers for -recover-passphrase, -bench and -derive (default: one")
+    fmt.Println("            per CPU; fewer when a memory-hard -kdf would not fit in free memory)")
+    fmt.Println("  -lint     Flag confusable word pairs (with -b: offer to regenerate)")
+    fmt.Println("  -doctor   After a session: look for the passphrase in shell history, clipboard,")
+    fmt.Println("            tmux scrollback, editor swap files and the temp directory")
+    fmt.Println("  -selftest Run the 24 official BIP39 test vectors (entropy, mnemonic, seed) and the")
+    fmt.Println("            wordlist and schema checks; exit code 1 on any failure")
+    fmt.Println("  -selftest -canonical  Byte-exact selftest output for comparing builds")
+    fmt.Println("  -e2e      End-to-end check in memory: generate a wallet, export watch-only keys,")
+    fmt.Println("            receive on a simulated chain, restore from the words and compare (-words N)")
+    fmt.Println("  -lang LANG  Wordlist for -p, -q and -i: english (default), japanese, korean, spanish,")
+    fmt.Println("            chinese_simplified, chinese_traditional, french, italian, czech")
+    fmt.Println("  -romanize  With -lang chinese_*, japanese or korean: show pinyin, romaji or Korean")
+    fmt.Println("            romanization next to the words; -i also looks words up by it")
+    fmt.Println("  -wordlist FILE  Use the 2048 words in FILE instead of the built-in list (NOT standard")
+    fmt.Println("            BIP39: other wallets cannot restore the result; checked like -wordlist-check)")
+    fmt.Println("  -separator SEP  Separate printed words with space (default), newline or comma;")
+    fmt.Println("            input may use any of these, plus numbering such as \"1. abandon\"")
+    fmt.Println("  -metrics FILE  With -selftest or -recover-passphrase: write counts, failures and")
+    fmt.Println("            throughput as JSON to FILE (local only, no secrets)")
+    fmt.Println("  -read-only  Only inspect and verify; refuse to generate, import or show secrets")
+    fmt.Println("            (go build -tags readonly builds a binary without those code paths)")
+    fmt.Println("  -transcript FILE  Record options, fingerprints and verification results as JSON")
+    fmt.Println("            (secret option values are redacted; nothing secret is written)")
+    fmt.Println("  -stats FILE  Count generations, imports and verifications per wallet fingerprint")
+    fmt.Println("            in passphrase-encrypted FILE (local only); -stats-show lists them")
+    fmt.Println("  -demo     Use fixed, public demo entropy and watermark all output")
+    fmt.Println("  -tui      Full-screen guided setup for beginners: entropy source, words one at a")
+    fmt.Println("            time, a short quiz, optional QR code, then save (nothing left in scrollback);")
+    fmt.Println("            with an existing binary.txt it can compare a written backup word by word")
+    fmt.Println("  -learn    Interactive BIP39 tutorial: entropy, checksum, words and seed, with")
+    fmt.Println("            exercises on the demo entropy (inspect a word, flip a bit)")
+    fmt.Println("  -qr-only -b  Air-gap mode: show a new passphrase only as a QR code, write no files")
+    fmt.Println("  -qr-only -qr-in IMG|cam  Scan a passphrase QR code (needs zbar) and show the")
+    fmt.Println("            BIP84 watch-only descriptor as a QR code; no other input or output")
+    fmt.Println("  -schema NAME  Print the JSON Schema of a JSON output: transcript, metrics,")
+    fmt.Println("            rotate-plan, descriptors, export (-schema list shows all)")
+    fmt.Println("  -version  Show the version, Go version, platform and source revision")
+    fmt.Println("  -tmpdir DIR  Run external tools (zbar, tesseract, age, gpg) in a per-session")
+    fmt.Println("            directory under DIR, wiped on exit (default: a RAM-backed tmpfs)")
     fmt.Println("  -h        Show this help message")
 }
 
-func loadWordList() []string {
-    lines := strings.Split(wordListText, "\n")
-    words := make([]string, 0, 2048)
-    for _, w := range lines {
-        w = strings.TrimSpace(w)
-        w = strings.TrimPrefix(w, "\ufeff")
-        if w != "" {
-            words = append(words, w)
-        }
+// 所有交互提示共用一个 stdin 缓冲，避免管道输入被提前读走
+var stdinReader = bufio.NewReaderSize(os.Stdin, maxLineLen)
+
+func readLine() (string, error) {
+    line, err := readLimitedLine(stdinReader)
+    if err != nil {
+        return "", err
     }
-    return words
+    return strings.TrimRight(line, "\r\n"), nil
 }
 
-func writeBinaryFile(filename string, entropy []byte) error {
-    bits := bytesToBits(entropy)
-    f, err := os.Create(filename)
+func loadWordList() []string {
+    if customWordlist != "" {
+        return loadCustomWordlist()
+    }
+    wordList, err := bip39.Wordlist(phraseLanguage)
     if err != nil {
-        return err
+        log.Fatalf("Error: -lang: %v", err)
     }
-    defer f.Close()
+    return wordList
+}
 
-    writer := bufio.NewWriter(f)
-    groupCount := 0
+// 熵文件的路径，-f 可改为其他文件（多个熵文件、加密卷、内存盘）
+var binaryPath = "binary.txt"
 
-    for i, b := range bits {
-        if b {
-            writer.WriteByte('1')
-        } else {
-            writer.WriteByte('0')
-        }
-
-        if (i+1)%11 == 0 {
-            writer.WriteByte(' ')
-            groupCount++
-        }
+func writeBinaryFile(filename string, entropy []byte) error {
+    bits := bip39.BytesToBits(entropy)
+    var buf bytes.Buffer
+    buf.WriteString(binaryHeader(bits))
+    buf.WriteString(layoutBits(bits, bitLayout))
 
-        if groupCount == 6 {
-            writer.WriteByte('\n')
-            groupCount = 0
+    // -encrypt：加密后写入
+    if encryptBinary {
+        sealed, err := sealBinaryText(buf.Bytes())
+        if err != nil {
+            return err
         }
+        return writeSecretFile(filename, sealed)
     }
+    return writeSecretFile(filename, buf.Bytes())
+}
 
-    if groupCount != 0 {
-        writer.WriteByte('\n')
+// 含秘密的输出文件以 0600 创建；文件已存在时 OpenFile 不改权限，
+// 所以写入前再 Chmod 一次，截断之前别人也读不到新内容
+func writeSecretFile(filename string, data []byte) error {
+    f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+    if err != nil {
+        return err
     }
-
-    return writer.Flush()
+    if err := f.Chmod(0600); err != nil {
+        f.Close()
+        return err
+    }
+    if _, err := f.Write(data); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
 }
 
 func readBinaryFile(filename string) ([]bool, error) {
@@ -222,81 +1150,72 @@ func readBinaryFile(filename string) ([]bool, error) {
     }
     defer f.Close()
 
-    scanner := bufio.NewScanner(f)
-    var bits []bool
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        for _, c := range line {
-            if c == '0' {
-                bits = append(bits, false)
-            } else if c == '1' {
-                bits = append(bits, true)
-            }
-        }
+    data, err := readAllLimited(f, maxBinaryFileSize)
+    if err != nil {
+        return nil, err
     }
-
-    return bits, scanner.Err()
-}
-
-func generatePassphraseFromBinary(wordList []string) string {
-    if _, err := os.Stat("binary.txt"); os.IsNotExist(err) {
-        log.Fatalf("Error: binary.txt not found. Use -b first.")
+    if data, err = openBinaryText(data); err != nil {
+        return nil, err
     }
-    bits, err := readBinaryFile("binary.txt")
+    bits, version, err := parseBinaryText(data)
     if err != nil {
-        log.Fatalf("Error reading binary.txt: %v", err)
+        return nil, err
+    }
+    if version < binaryFormatVersion {
+        noteLegacyBinary(filename)
     }
-    csBits := checksumBits(bits)
-    allBits := append(bits, csBits...)
-    return generateMnemonic(allBits, wordList)
+    return bits, nil
 }
 
-func generateMnemonic(bits []bool, wordList []string) string {
-    wordCount := len(bits) / 11
-    words := make([]string, 0, wordCount)
-    for i := 0; i < wordCount; i++ {
-        index := bitsToInt(bits[i*11 : (i+1)*11])
-        words = append(words, wordList[index])
-    }
-    return strings.Join(words, " ")
+func generatePassphraseFromBinary(wordList []string) string {
+    return string(bip39.MnemonicFromBits(loadMnemonicBits(), wordList))
 }
 
-func checksumBits(entropyBits []bool) []bool {
-    entropy := bitsToBytes(entropyBits)
-    hash := sha256.Sum256(entropy)
-    csLen := len(entropyBits) / 32
-    return bytesToBits(hash[:])[:csLen]
+// 种子：默认 BIP39（PBKDF2-HMAC-SHA512，2048 轮，盐为 "mnemonic"+口令），均做 NFKD；
+// -kdf 可换成实验性的非标准 KDF
+var (
+    seedKDFName             = seedkdf.Default
+    seedKDF     seedkdf.KDF = seedkdf.BIP39{}
+)
+
+func useSeedKDF(name string) error {
+    kdf, err := seedkdf.Lookup(name)
+    if err != nil {
+        return err
+    }
+    if !kdf.Standard() {
+        fmt.Fprintf(os.Stderr, "Warning: -kdf %s is %s.\n", name, kdf.Describe())
+        fmt.Fprintln(os.Stderr, "Warning: its seeds are not BIP39 seeds and will not match any Bitcoin wallet.")
+    }
+    seedKDFName, seedKDF = name, kdf
+    return nil
 }
 
-func bytesToBits(b []byte) []bool {
-    bits := make([]bool, 0, len(b)*8)
-    for _, by := range b {
-        for i := 7; i >= 0; i-- {
-            bits = append(bits, ((by>>i)&1) == 1)
-        }
+func mnemonicToSeed(mnemonic, passphrase string) []byte {
+    seed, err := seedKDF.Seed(textnorm.Mnemonic(mnemonic), textnorm.Passphrase(passphrase))
+    if err != nil {
+        log.Fatalf("Error deriving seed: %v", err)
     }
-    return bits
+    return seed
 }
 
-func bitsToBytes(bits []bool) []byte {
-    n := (len(bits) + 7) / 8
-    out := make([]byte, n)
-    for i, b := range bits {
-        if b {
-            out[i/8] |= 1 << (7 - uint(i%8))
-        }
+// 同一助记词、多个口令的种子；KDF 支持时成批计算
+func mnemonicToSeeds(mnemonic string, passphrases []string) [][]byte {
+    normalized := make([]string, len(passphrases))
+    for i, p := range passphrases {
+        normalized[i] = textnorm.Passphrase(p)
+    }
+    seeds, err := seedkdf.Seeds(seedKDF, textnorm.Mnemonic(mnemonic), normalized)
+    if err != nil {
+        log.Fatalf("Error deriving seed: %v", err)
     }
-    return out
+    return seeds
 }
 
-func bitsToInt(bits []bool) int {
-    n := 0
-    for _, b := range bits {
-        n <<= 1
-        if b {
-            n |= 1
-        }
+func mnemonicFromEntropy(entropy []byte, wordList []string) string {
+    m, err := bip39.NewMnemonic(entropy, wordList)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
     }
-    return n
+    return string(m)
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "strings"
+    "unicode"
+
+    "golang.org/x/term"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -masked 遮挡输入
+// -------------------------
+//
+// 逐个输入单词，屏幕上只显示 ****，每个单词后面只显示 ✓（在词表中）或 ✗。
+// 空格结束一个单词，在空单词处回车结束输入；也接受唯一前缀。不显示候选
+// 单词（-tui 的 ↑/↓ 选择）：候选会暴露正在输入的单词。
+// verify：与 binary.txt 比对，只报告位置；import：写入 binary.txt。
+//
+
+const maskedMaxWords = 24
+
+var errMaskedAborted = errors.New("aborted")
+
+func maskedEntry(mode string, wordList []string) {
+    if mode != "verify" && mode != "import" {
+        log.Fatalf("Error: -masked must be verify or import")
+    }
+    matcher := wordmatch.New(wordList)
+    fmt.Fprintln(os.Stderr, "Type the words separated by spaces; press Enter on an empty word to finish.")
+    words, err := readMaskedWords(matcher)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    indices := make([]int, len(words))
+    var unknown []int
+    for i, w := range words {
+        c, ok := matcher.Lookup(w)
+        if !ok {
+            unknown = append(unknown, i+1)
+        }
+        indices[i] = c.Index
+    }
+    if len(unknown) > 0 {
+        log.Fatalf("Error: word(s) at position %s are not on the list", joinInts(unknown))
+    }
+
+    if mode == "import" {
+        importIndices(indices)
+        return
+    }
+
+    expected := passphraseIndicesFromBinary()
+    if secretcmp.EqualInts(expected, indices) {
+        fmt.Println("Entered passphrase matches binary.txt.")
+        transcript.recordBinary("masked verify", "match", wordList)
+        return
+    }
+    // 不一致时才逐个找出位置
+    var wrong []int
+    for i := 0; i < max(len(expected), len(indices)); i++ {
+        if i >= len(expected) || i >= len(indices) || expected[i] != indices[i] {
+            wrong = append(wrong, i+1)
+        }
+    }
+    fmt.Printf("Entered passphrase does not match binary.txt at position %s.\n", joinInts(wrong))
+    transcript.recordBinary("masked verify", "mismatch", wordList)
+    transcript.finish()
+    os.Exit(1)
+}
+
+// 终端：原始模式逐字符读取并自行回显 *；管道输入：按行读取
+func readMaskedWords(matcher *wordmatch.Matcher) ([]string, error) {
+    fd := int(os.Stdin.Fd())
+    if !term.IsTerminal(fd) {
+        line, err := readLine()
+        if err != nil {
+            return nil, err
+        }
+        return splitWords(line), nil
+    }
+
+    state, err := term.MakeRaw(fd)
+    if err != nil {
+        return nil, err
+    }
+    defer term.Restore(fd, state)
+    return readMaskedKeys(stdinReader, os.Stderr, matcher)
+}
+
+// 按 UTF-8 字符读取：西班牙语、法语的重音字母，输入法提交的假名、韩文、汉字
+// 都是多字节的。每个字符回显一个 *；单词结束时做 NFKD，与词表的写法一致。
+// 输入法或粘贴的全角空格（U+3000）与普通空格一样分隔单词
+func readMaskedKeys(in *bufio.Reader, out io.Writer, matcher *wordmatch.Matcher) ([]string, error) {
+    var (
+        words   []string
+        current []rune
+    )
+    finishWord := func() {
+        // 擦掉逐字符的 *，换成固定的 **** 与有效性标记
+        fmt.Fprint(out, strings.Repeat("\b \b", len(current)))
+        word := textnorm.Word(string(current))
+        mark := "✗"
+        if _, ok := matcher.Lookup(word); ok {
+            mark = "✓"
+        }
+        fmt.Fprintf(out, "****%s ", mark)
+        words = append(words, word)
+        current = current[:0]
+        if len(words)%6 == 0 {
+            fmt.Fprint(out, "\r\n")
+        }
+    }
+
+    for len(words) < maskedMaxWords {
+        c, _, err := in.ReadRune()
+        if err != nil {
+            return nil, err
+        }
+        switch {
+        case c == 3 || c == 4: // Ctrl-C / Ctrl-D
+            fmt.Fprint(out, "\r\n")
+            return nil, errMaskedAborted
+        case c == '\r' || c == '\n':
+            if len(current) == 0 {
+                fmt.Fprint(out, "\r\n")
+                return words, nil
+            }
+            finishWord()
+        case unicode.IsSpace(c):
+            if len(current) > 0 {
+                finishWord()
+            }
+        case c == 127 || c == 8: // 退格，删掉一个字符
+            if len(current) > 0 {
+                current = current[:len(current)-1]
+                fmt.Fprint(out, "\b \b")
+            }
+        // 分解写法粘贴进来的重音是组合符号（Mn）
+        case unicode.IsLetter(c) || unicode.Is(unicode.Mn, c):
+            if len(current) < maxLineLen {
+                current = append(current, c)
+                fmt.Fprint(out, "*")
+            }
+        }
+    }
+    fmt.Fprint(out, "\r\n")
+    return words, nil
+}
+
+func joinInts(ns []int) string {
+    s := make([]string, len(ns))
+    for i, n := range ns {
+        s[i] = fmt.Sprint(n)
+    }
+    return strings.Join(s, ", ")
+}
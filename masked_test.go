@@ -0,0 +1,61 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "slices"
+    "strings"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+// 原始模式下的多字节输入：合成与分解的重音、全角空格分隔的假名、韩文；
+// 每个字符回显一个 *，单词以 NFKD 返回
+func TestReadMaskedKeysUTF8(t *testing.T) {
+    cases := []struct {
+        lang  string
+        input string
+        want  []string
+        stars int
+    }{
+        {"spanish", "ábaco acción\r\r", []string{"ábaco", "acción"}, 11},
+        {"spanish", "ábaco\r\r", []string{"ábaco"}, 6},
+        {"french", "ÉLÈVE\r\r", []string{"élève"}, 5},
+        {"japanese", "あいこくしん　あおぞら\r\r", []string{"あいこくしん", "あおぞら"}, 10},
+        {"korean", "가격 가난\r\r", []string{"가격", "가난"}, 4},
+        // 退格删掉一个字符而不是一个字节
+        {"spanish", "ábacoó\x7f\r\r", []string{"ábaco"}, 6},
+    }
+    for _, c := range cases {
+        wordList, err := bip39.Wordlist(c.lang)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var out bytes.Buffer
+        words, err := readMaskedKeys(bufio.NewReader(strings.NewReader(c.input)), &out, wordmatch.New(wordList))
+        if err != nil {
+            t.Fatalf("%q: %v", c.input, err)
+        }
+        want := make([]string, len(c.want))
+        for i, w := range c.want {
+            want[i] = textnorm.NFKD(w)
+        }
+        if !slices.Equal(words, want) {
+            t.Errorf("%q: words = %q, want %q", c.input, words, want)
+        }
+        if n := strings.Count(out.String(), "*") - 4*len(want); n != c.stars {
+            t.Errorf("%q: echoed %d *, want %d", c.input, n, c.stars)
+        }
+        if strings.Count(out.String(), "✓") != len(want) {
+            t.Errorf("%q: output %q, want every word marked valid", c.input, out.String())
+        }
+        for _, w := range want {
+            if strings.Contains(out.String(), w) {
+                t.Errorf("%q: output shows the word %q", c.input, w)
+            }
+        }
+    }
+}
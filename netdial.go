@@ -0,0 +1,135 @@
+package main
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/url"
+    "strconv"
+    "time"
+)
+
+//
+// -------------------------
+//   联网功能的出口：SOCKS5 / Tor
+// -------------------------
+//
+// 所有联网功能都经 dialNetwork 连接。默认走本机 Tor（socks5://127.0.0.1:9050），
+// 主机名交给代理解析（本机不做 DNS 查询）；只有加 -clearnet 才会直连。
+//
+
+const defaultProxy = "socks5://127.0.0.1:9050"
+
+var (
+    networkProxy = defaultProxy
+    clearnet     bool
+)
+
+func dialNetwork(addr string, timeout time.Duration) (net.Conn, error) {
+    if clearnet {
+        return net.DialTimeout("tcp", addr, timeout)
+    }
+    if networkProxy == "" {
+        return nil, errors.New("no proxy configured; pass -proxy socks5://HOST:PORT or -clearnet")
+    }
+    u, err := url.Parse(networkProxy)
+    if err != nil || (u.Scheme != "socks5" && u.Scheme != "socks5h") || u.Host == "" {
+        return nil, fmt.Errorf("proxy must be socks5://host:port, got %q", networkProxy)
+    }
+
+    conn, err := net.DialTimeout("tcp", u.Host, timeout)
+    if err != nil {
+        return nil, fmt.Errorf("proxy %s: %v (is Tor running? use -clearnet to connect directly)", u.Host, err)
+    }
+    conn.SetDeadline(time.Now().Add(timeout))
+    if err := socks5Connect(conn, u.User, addr); err != nil {
+        conn.Close()
+        return nil, fmt.Errorf("proxy %s: %v", u.Host, err)
+    }
+    conn.SetDeadline(time.Time{})
+    return conn, nil
+}
+
+// RFC 1928 CONNECT，目标以域名形式发送（DNS 由代理解析）；可选 RFC 1929 用户名/密码
+// （Tor 用它区分不同的线路）
+func socks5Connect(conn net.Conn, user *url.Userinfo, addr string) error {
+    host, portStr, err := net.SplitHostPort(addr)
+    if err != nil {
+        return err
+    }
+    port, err := strconv.Atoi(portStr)
+    if err != nil || port <= 0 || port > 65535 {
+        return fmt.Errorf("bad port %q", portStr)
+    }
+    if len(host) > 255 {
+        return errors.New("host name too long")
+    }
+
+    method := byte(0x00)
+    if user != nil {
+        method = 0x02
+    }
+    if _, err := conn.Write([]byte{5, 1, method}); err != nil {
+        return err
+    }
+    reply := make([]byte, 2)
+    if _, err := io.ReadFull(conn, reply); err != nil {
+        return err
+    }
+    if reply[0] != 5 || reply[1] != method {
+        return errors.New("proxy refused the authentication method")
+    }
+
+    if user != nil {
+        name := user.Username()
+        pass, _ := user.Password()
+        if len(name) > 255 || len(pass) > 255 {
+            return errors.New("proxy credentials too long")
+        }
+        msg := append([]byte{1, byte(len(name))}, name...)
+        msg = append(append(msg, byte(len(pass))), pass...)
+        if _, err := conn.Write(msg); err != nil {
+            return err
+        }
+        if _, err := io.ReadFull(conn, reply); err != nil {
+            return err
+        }
+        if reply[1] != 0 {
+            return errors.New("proxy rejected the credentials")
+        }
+    }
+
+    req := append([]byte{5, 1, 0, 3, byte(len(host))}, host...)
+    req = binary.BigEndian.AppendUint16(req, uint16(port))
+    if _, err := conn.Write(req); err != nil {
+        return err
+    }
+    head := make([]byte, 4)
+    if _, err := io.ReadFull(conn, head); err != nil {
+        return err
+    }
+    if head[1] != 0 {
+        return fmt.Errorf("connect to %s failed (SOCKS reply %d)", addr, head[1])
+    }
+
+    // 跳过代理返回的绑定地址
+    var skip int
+    switch head[3] {
+    case 1:
+        skip = 4
+    case 4:
+        skip = 16
+    case 3:
+        n := make([]byte, 1)
+        if _, err := io.ReadFull(conn, n); err != nil {
+            return err
+        }
+        skip = int(n[0])
+    default:
+        return errors.New("bad SOCKS reply")
+    }
+    _, err = io.ReadFull(conn, make([]byte, skip+2))
+    return err
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    qrcode "github.com/skip2/go-qrcode"
+)
+
+//
+// -------------------------
+//   -no-file 只在内存中生成
+// -------------------------
+//
+// 与 -b 相同的熵来源（含 -dice、-typed、-game、-cards、-pick 等），
+// 但不写 binary.txt，也不记录生日：助记词只在内存中生成并直接打印
+// （加 -q 时同时显示二维码，加 -quiz 时随后抽查），进程结束后即不存在。
+// 不能防止操作系统把内存换出到交换分区；需要时请用加密交换分区或 Live 系统。
+//
+
+func printMemoryOnly(entropy []byte, showQR bool, level qrcode.RecoveryLevel, lint, quiz bool, wordList []string) {
+    if jsonOutput {
+        r := entropyReport("no-file", entropy, true, wordList)
+        printJSON(r)
+        warnWeakMnemonic(entropy, wordList)
+        transcript.record("generate in memory", "ok", r.Fingerprint, "")
+        return
+    }
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    fmt.Printf("Passphrase (%d bits, %d words; nothing was written to disk):\n", len(entropy)*8, len(entropy)*3/4)
+    printPhrase(mnemonic)
+    if showQR {
+        printSecretQR(qrPayload(mnemonic), level)
+    }
+    fp := masterFingerprint(mnemonic)
+    fmt.Println("Fingerprint:", fp)
+    if lint {
+        lintWords(strings.Fields(mnemonic))
+    }
+    if explainSteps {
+        printExplanation(entropy, wordList)
+    }
+    warnWeakMnemonic(entropy, wordList)
+    fmt.Println("Write the words down now: they are not stored anywhere and cannot be shown again.")
+    transcript.record("generate in memory", "ok", fp, "")
+    if quiz {
+        runQuiz(mnemonic, wordList)
+    }
+}
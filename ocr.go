@@ -0,0 +1,209 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "log"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+    "golang.org/x/text/unicode/norm"
+)
+
+//
+// -------------------------
+//   -ocr 纸质备份校验
+// -------------------------
+//
+
+// 低于该置信度（tesseract conf 0–100）的位置需要人工复核
+const ocrMinConfidence = 80
+
+// 退出码：有不一致的单词为 1，只有需要人工复核的位置为 2
+const (
+    ocrExitMismatch = 1
+    ocrExitReview   = 2
+)
+
+type ocrToken struct {
+    text       string
+    confidence float64
+}
+
+type ocrMatch struct {
+    raw        string
+    word       string
+    match      float64
+    confidence float64
+}
+
+func verifyPaperBackup(imagePath string, wordList []string) {
+    tokens, err := runOCR(imagePath, wordList)
+    if err != nil {
+        log.Fatalf("Error running OCR: %v", err)
+    }
+
+    expected := strings.Fields(generatePassphraseFromBinary(wordList))
+
+    matcher := wordmatch.New(wordList)
+    matches := make([]ocrMatch, 0, len(tokens))
+    for _, t := range tokens {
+        c := matcher.Best(t.text)
+        matches = append(matches, ocrMatch{
+            raw:        t.text,
+            word:       c.Word,
+            match:      c.Confidence,
+            confidence: t.confidence,
+        })
+    }
+
+    if len(matches) != len(expected) {
+        fmt.Printf("Warning: recognized %d words, expected %d.\n", len(matches), len(expected))
+    }
+
+    mismatches, reviews := 0, 0
+    fmt.Println("Pos  Recognized       Matched    Conf  Status")
+    for i := 0; i < len(expected) || i < len(matches); i++ {
+        var m ocrMatch
+        if i < len(matches) {
+            m = matches[i]
+        }
+
+        status := "OK"
+        switch {
+        case i >= len(expected):
+            status = "EXTRA"
+            mismatches++
+        case i >= len(matches):
+            status = "MISSING"
+            mismatches++
+        case m.word != expected[i]:
+            status = "MISMATCH"
+            mismatches++
+        case m.match < 1 || m.confidence < ocrMinConfidence:
+            status = "REVIEW"
+            reviews++
+        }
+
+        fmt.Printf("%3d  %-15s  %-9s  %4.0f  %s\n", i+1, m.raw, m.word, m.confidence, status)
+    }
+
+    fmt.Println()
+    fp := ""
+    if transcript != nil || usage != nil {
+        fp = masterFingerprint(strings.Join(expected, " "))
+    }
+    if mismatches == 0 && reviews == 0 {
+        fmt.Println("Paper backup matches binary.txt.")
+        transcript.record("ocr paper backup", "match", fp, "")
+        return
+    }
+    fmt.Printf("%d mismatched, %d to review manually.\n", mismatches, reviews)
+    transcript.record("ocr paper backup", "mismatch", fp, fmt.Sprintf("%d mismatched, %d to review", mismatches, reviews))
+    if mismatches > 0 {
+        os.Exit(ocrExitMismatch)
+    }
+    os.Exit(ocrExitReview)
+}
+
+// 调用 tesseract，读取 TSV 输出中每个单词及其置信度。
+// 识别限定在单词表上：--user-words 给出全部单词并关闭内置词典，
+// 字符白名单只含单词表中的字母以及编号用的数字和标点
+func runOCR(imagePath string, wordList []string) ([]ocrToken, error) {
+    if _, err := exec.LookPath("tesseract"); err != nil {
+        return nil, fmt.Errorf("tesseract not found in PATH")
+    }
+
+    imagePath, err := filepath.Abs(imagePath)
+    if err != nil {
+        return nil, err
+    }
+    dir, err := sandboxDir()
+    if err != nil {
+        return nil, fmt.Errorf("temporary directory: %v", err)
+    }
+    userWords := filepath.Join(dir, "user-words.txt")
+    if err := os.WriteFile(userWords, []byte(strings.Join(wordList, "\n")+"\n"), 0600); err != nil {
+        return nil, err
+    }
+    defer wipeFile(userWords)
+    cmd, err := sandboxCommand("tesseract", imagePath, "stdout",
+        "--user-words", userWords,
+        "-c", "load_system_dawg=0",
+        "-c", "load_freq_dawg=0",
+        "-c", "tessedit_char_whitelist="+ocrWhitelist(wordList),
+        "tsv")
+    if err != nil {
+        return nil, err
+    }
+    out, err := cmd.Output()
+    if err != nil {
+        return nil, err
+    }
+
+    letters := ocrLetters(wordList)
+    var tokens []ocrToken
+    scanner := bufio.NewScanner(strings.NewReader(string(out)))
+    for scanner.Scan() {
+        cols := strings.Split(scanner.Text(), "\t")
+        // level page block par line word left top width height conf text
+        if len(cols) < 12 || cols[0] != "5" {
+            continue
+        }
+        conf, err := strconv.ParseFloat(cols[10], 64)
+        if err != nil {
+            continue
+        }
+        text := cleanOCRWord(cols[11], letters)
+        if text == "" {
+            continue
+        }
+        tokens = append(tokens, ocrToken{text: text, confidence: conf})
+    }
+
+    return tokens, scanner.Err()
+}
+
+// 单词表中出现的字母，加上编号（"1."、"12)"）用的数字与标点。
+// 单词表是 NFKD，tesseract 输出的是合成字符（é、ñ、ぞ），两种写法都要放行
+func ocrWhitelist(wordList []string) string {
+    seen := map[rune]bool{}
+    var b strings.Builder
+    for _, w := range wordList {
+        for _, c := range textnorm.NFKD(w) + norm.NFC.String(w) + "0123456789.)" {
+            if !seen[c] {
+                seen[c] = true
+                b.WriteRune(c)
+            }
+        }
+    }
+    return b.String()
+}
+
+// 单词表（NFKD、小写）中出现的字符
+func ocrLetters(wordList []string) map[rune]bool {
+    letters := map[rune]bool{}
+    for _, w := range wordList {
+        for _, c := range textnorm.Word(w) {
+            letters[c] = true
+        }
+    }
+    return letters
+}
+
+// 与单词表同样做 NFKD 和小写，再去掉编号（"1."、"12)"）、标点等
+// 单词表中没有的字符
+func cleanOCRWord(s string, letters map[rune]bool) string {
+    var b strings.Builder
+    for _, c := range textnorm.Word(s) {
+        if letters[c] {
+            b.WriteRune(c)
+        }
+    }
+    return b.String()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+// tesseract 输出合成字符并带编号；清理后必须与 NFKD 单词表逐字相同
+func TestCleanOCRWordNonEnglish(t *testing.T) {
+    cases := []struct {
+        lang, raw, want string
+    }{
+        {"english", "12)Abandon", "abandon"},
+        {"spanish", "1.ÁBACO", "ábaco"},
+        {"spanish", "7)Acción", "acción"},
+        {"french", "3.élève", "élève"},
+        {"czech", "24.ZVYK", "zvyk"},
+        {"japanese", "2.あおぞら", "あおぞら"},
+        {"korean", "5)가격", "가격"},
+        {"chinese_simplified", "9.的", "的"},
+    }
+    for _, c := range cases {
+        wordList, err := bip39.Wordlist(c.lang)
+        if err != nil {
+            t.Fatal(err)
+        }
+        want := textnorm.NFKD(c.want)
+        got := cleanOCRWord(c.raw, ocrLetters(wordList))
+        if got != want {
+            t.Errorf("%s: cleanOCRWord(%q) = %q, want %q", c.lang, c.raw, got, want)
+            continue
+        }
+        if m := wordmatch.New(wordList).Best(got); m.Word != want || m.Confidence != 1 {
+            t.Errorf("%s: Best(%q) = %q (%.2f), want an exact match", c.lang, got, m.Word, m.Confidence)
+        }
+    }
+}
+
+// 白名单要同时包含单词表的分解写法和 tesseract 输出的合成写法
+func TestOCRWhitelistComposed(t *testing.T) {
+    wordList, err := bip39.Wordlist("spanish")
+    if err != nil {
+        t.Fatal(err)
+    }
+    w := ocrWhitelist(wordList)
+    for _, c := range []rune{'á', 'ñ', 'a', '́', '7', ')'} {
+        if !containsRune(w, c) {
+            t.Errorf("whitelist is missing %q", c)
+        }
+    }
+}
+
+func containsRune(s string, r rune) bool {
+    for _, c := range s {
+        if c == r {
+            return true
+        }
+    }
+    return false
+}
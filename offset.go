@@ -0,0 +1,120 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -offset 索引偏移混淆
+// -------------------------
+//
+// 很多人手工把每个词在词表中的位置加上一个“私人数字”再抄写，常见的错误是
+// 索引从 0 还是 1 算、溢出后不回绕、抄回时减错方向。这里把做法固定下来：
+//
+//   偏移量   = PIN（十进制数）mod 2048，不能为 0
+//   抄写的词 = 词表[(索引 + 偏移量) mod 2048]，索引从 0 开始（与 -d、-i 相同）
+//   还原     = 词表[(索引 − 偏移量) mod 2048]
+//
+// 这不是加密：偏移量只有 2047 种，拿到纸的人一秒内就能全部试完，校验和
+// 还会帮他排除大部分。只能防止别人一眼认出助记词。
+//
+
+const offsetWarning = `WARNING: this is obfuscation, not encryption. There are only 2047 offsets;
+anyone who suspects the scheme tries them all in under a second, and the
+BIP39 checksum rules out most wrong guesses for them. It only stops a casual
+glance. Do not rely on it instead of physical security or -export-sealed.`
+
+func pinOffset(pin string) (int, error) {
+    n, err := strconv.ParseUint(pin, 10, 64)
+    if err != nil || pin == "" {
+        return 0, fmt.Errorf("the PIN must be a decimal number")
+    }
+    offset := int(n % bip39.WordCount)
+    if offset == 0 {
+        return 0, fmt.Errorf("the PIN is a multiple of 2048, which would not change any word")
+    }
+    return offset, nil
+}
+
+func shiftIndices(indices []int, offset int) []int {
+    out := make([]int, len(indices))
+    for i, idx := range indices {
+        out[i] = ((idx+offset)%bip39.WordCount + bip39.WordCount) % bip39.WordCount
+    }
+    return out
+}
+
+// 2047 个偏移量中，还原后校验和仍然有效的个数（攻击者要逐个验证的候选数）
+func offsetCandidates(shifted []int) int {
+    n := 0
+    for offset := 1; offset < bip39.WordCount; offset++ {
+        if _, err := bip39.EntropyFromIndices(shiftIndices(shifted, -offset)); err == nil {
+            n++
+        }
+    }
+    return n
+}
+
+func printOffsetPhrase(wordList []string) {
+    fmt.Fprintln(os.Stderr, offsetWarning)
+    pin, err := readNewSecret("PIN: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    offset, err := pinOffset(pin)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    shifted := shiftIndices(passphraseIndicesFromBinary(), offset)
+
+    fmt.Println("Offset passphrase (each word moved forward by the PIN offset, wrapping at 2048):")
+    printPhrase(wordsFromIndices(shifted, wordList))
+    fmt.Println()
+    if _, err := bip39.EntropyFromIndices(shifted); err == nil {
+        fmt.Println("By chance this phrase also has a valid checksum: a wallet would open a different,")
+        fmt.Println("empty wallet with it. Restore it only with -import-offset and the same PIN.")
+    } else {
+        fmt.Println("Wallets reject this phrase. Restore it with -import-offset and the same PIN.")
+    }
+    fmt.Printf("Fingerprint of the real passphrase: %s (write it next to the words to confirm the PIN later)\n",
+        masterFingerprint(generatePassphraseFromBinary(wordList)))
+    fmt.Printf("Without the PIN, %d of the 2047 offsets give a valid checksum.\n", offsetCandidates(shifted))
+}
+
+func importOffsetPhrase(phrase string, wordList []string) {
+    fmt.Fprintln(os.Stderr, offsetWarning)
+    words := splitWords(phrase)
+    matcher := wordmatch.New(wordList)
+    indices := make([]int, len(words))
+    for i, w := range words {
+        c, ok := matcher.Lookup(w)
+        if !ok {
+            log.Fatalf("Error: word %d '%s' is not on the list", i+1, w)
+        }
+        indices[i] = c.Index
+    }
+
+    pin, err := readSecret("PIN: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    offset, err := pinOffset(pin)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    entropy, err := bip39.EntropyFromIndices(shiftIndices(indices, -offset))
+    if err != nil {
+        log.Fatalf("Error: wrong PIN or a miscopied word (%v)", err)
+    }
+    // 错误的 PIN 也可能碰巧通过校验，只能由指纹确认
+    fmt.Printf("Restored passphrase fingerprint: %s. Compare it with the one written down.\n",
+        masterFingerprint(mnemonicFromEntropy(entropy, wordList)))
+    importEntropy(entropy)
+}
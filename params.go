@@ -0,0 +1,58 @@
+package main
+
+import (
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/seedkdf"
+)
+
+//
+// -------------------------
+//   派生参数回显
+// -------------------------
+//
+// 派生种子或密钥的输出都附带所用的全部参数（规范化、盐、KDF 与轮数、
+// 曲线、路径），JSON 输出中为 "params" 字段，审计者可以用其他工具独立复现。
+//
+
+type derivationParams struct {
+    Normalization string   `json:"normalization"`
+    SeedKDF       string   `json:"seed_kdf"`
+    Salt          string   `json:"salt"`
+    SeedBytes     int      `json:"seed_bytes"`
+    MasterKey     string   `json:"master_key"`
+    Curve         string   `json:"curve"`
+    Paths         []string `json:"paths,omitempty"`
+    Extra         string   `json:"extra,omitempty"`
+}
+
+// kdf 为 nil 时为标准 BIP39（bip39Master 总是用它）
+func newDerivationParams(kdf seedkdf.KDF, paths ...string) derivationParams {
+    if kdf == nil {
+        kdf = seedkdf.BIP39{}
+    }
+    return derivationParams{
+        Normalization: "Unicode NFKD; mnemonic lower-cased, words joined by single spaces; passphrase NFKD only",
+        SeedKDF:       kdf.Describe(),
+        Salt:          `"mnemonic" + passphrase, UTF-8`,
+        SeedBytes:     64,
+        MasterKey:     `BIP32: HMAC-SHA512 with key "Bitcoin seed"`,
+        Curve:         "secp256k1",
+        Paths:         paths,
+    }
+}
+
+func (p derivationParams) print() {
+    fmt.Println("Parameters:")
+    fmt.Println("  Normalization:", p.Normalization)
+    fmt.Printf("  Seed:          %s, %d bytes\n", p.SeedKDF, p.SeedBytes)
+    fmt.Println("  Salt:         ", p.Salt)
+    fmt.Println("  Master key:   ", p.MasterKey)
+    fmt.Println("  Curve:        ", p.Curve)
+    for _, path := range p.Paths {
+        fmt.Println("  Path:         ", path)
+    }
+    if p.Extra != "" {
+        fmt.Println("  Then:         ", p.Extra)
+    }
+}
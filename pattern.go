@@ -0,0 +1,147 @@
+package main
+
+import (
+    "fmt"
+    "math/big"
+    "strings"
+)
+
+//
+// -------------------------
+//   口令模式（掩码）
+// -------------------------
+//
+// 字面字符原样匹配，另支持：
+//   ?l ?u ?d ?s ?a   小写、大写、数字、符号、全部可打印字符
+//   [abc] [a-z0-9]   自定义字符集
+//   {foo|bar|}       备选字符串（可为空）
+//   ?? \x            转义
+//
+
+const (
+    charsLower   = "abcdefghijklmnopqrstuvwxyz"
+    charsUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+    charsDigit   = "0123456789"
+    charsSpecial = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+type passPattern struct {
+    positions [][]string
+}
+
+func parsePassPattern(s string) (*passPattern, error) {
+    p := &passPattern{}
+    r := []rune(s)
+    for i := 0; i < len(r); i++ {
+        switch r[i] {
+        case '\\':
+            if i+1 >= len(r) {
+                return nil, fmt.Errorf("pattern ends with '\\'")
+            }
+            i++
+            p.positions = append(p.positions, []string{string(r[i])})
+        case '?':
+            if i+1 >= len(r) {
+                return nil, fmt.Errorf("pattern ends with '?'")
+            }
+            i++
+            var set string
+            switch r[i] {
+            case 'l':
+                set = charsLower
+            case 'u':
+                set = charsUpper
+            case 'd':
+                set = charsDigit
+            case 's':
+                set = charsSpecial
+            case 'a':
+                set = charsLower + charsUpper + charsDigit + charsSpecial
+            case '?':
+                set = "?"
+            default:
+                return nil, fmt.Errorf("unknown class '?%c'", r[i])
+            }
+            p.positions = append(p.positions, splitChars(set))
+        case '[':
+            end := indexRune(r, ']', i+1)
+            if end < 0 {
+                return nil, fmt.Errorf("unclosed '['")
+            }
+            set, err := expandCharSet(r[i+1 : end])
+            if err != nil {
+                return nil, err
+            }
+            p.positions = append(p.positions, splitChars(set))
+            i = end
+        case '{':
+            end := indexRune(r, '}', i+1)
+            if end < 0 {
+                return nil, fmt.Errorf("unclosed '{'")
+            }
+            p.positions = append(p.positions, strings.Split(string(r[i+1:end]), "|"))
+            i = end
+        default:
+            p.positions = append(p.positions, []string{string(r[i])})
+        }
+    }
+    return p, nil
+}
+
+func indexRune(r []rune, c rune, from int) int {
+    for i := from; i < len(r); i++ {
+        if r[i] == c {
+            return i
+        }
+    }
+    return -1
+}
+
+func expandCharSet(r []rune) (string, error) {
+    var b strings.Builder
+    for i := 0; i < len(r); i++ {
+        if i+2 < len(r) && r[i+1] == '-' {
+            if r[i] > r[i+2] {
+                return "", fmt.Errorf("invalid range %c-%c", r[i], r[i+2])
+            }
+            for c := r[i]; c <= r[i+2]; c++ {
+                b.WriteRune(c)
+            }
+            i += 2
+            continue
+        }
+        b.WriteRune(r[i])
+    }
+    if b.Len() == 0 {
+        return "", fmt.Errorf("empty character set '[]'")
+    }
+    return b.String(), nil
+}
+
+func splitChars(s string) []string {
+    out := make([]string, 0, len(s))
+    for _, c := range s {
+        out = append(out, string(c))
+    }
+    return out
+}
+
+// 候选总数
+func (p *passPattern) count() *big.Int {
+    n := big.NewInt(1)
+    for _, opts := range p.positions {
+        n.Mul(n, big.NewInt(int64(len(opts))))
+    }
+    return n
+}
+
+// 第 idx 个候选（混合进制，最后一个位置变化最快）
+func (p *passPattern) candidate(idx uint64) string {
+    parts := make([]string, len(p.positions))
+    for i := len(p.positions) - 1; i >= 0; i-- {
+        n := uint64(len(p.positions[i]))
+        parts[i] = p.positions[i][idx%n]
+        idx /= n
+    }
+    return strings.Join(parts, "")
+}
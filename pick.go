@@ -0,0 +1,125 @@
+package main
+
+import (
+    "crypto/rand"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -b -pick 自选单词
+// -------------------------
+//
+// 逐个位置询问：输入单词则固定该位置，直接回车由系统随机数填充，
+// 输入 . 则其余位置全部随机。其余位置用 crypto/rand 填充，再使校验位有效：
+// 最后一个单词若是自选的，它只有前 11−CS 位属于熵，后 CS 位由校验决定，
+// 因此反复重抽随机位置，直到校验恰好等于所选单词的末几位（平均 2^CS 次）。
+//
+// 损失的熵是精确的：每个自选单词去掉 11 位——普通位置 11 位熵直接固定；
+// 最后一个位置固定 11−CS 位熵，校验约束再排除随机部分中的 CS 位。
+// 另外，对你“有意义”的单词更容易被熟人猜中，实际安全性可能更低。
+//
+
+func pickedEntropy(size int, wordList []string) []byte {
+    words := size * 3 / 4
+    matcher := wordmatch.New(wordList)
+
+    fmt.Printf("Choose words for a %d-word passphrase. Enter a word to fix its position,\n", words)
+    fmt.Println("Enter alone for a random word, or . to make all remaining words random.")
+    chosen := map[int]int{} // 位置 → 单词索引
+    for i := 0; i < words; i++ {
+        line, err := readSecret(fmt.Sprintf("Word %d: ", i+1))
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        line = strings.TrimSpace(line)
+        if line == "." {
+            break
+        }
+        if line == "" {
+            continue
+        }
+        c, ok := matcher.Lookup(line)
+        if !ok {
+            fmt.Printf("'%s' is not on the list%s\n", line, didYouMean(matcher, line))
+            i--
+            continue
+        }
+        chosen[i] = c.Index
+    }
+
+    random := size*8 - 11*len(chosen)
+    fmt.Println()
+    fmt.Printf("Chosen words: %d; random entropy: %d of %d bits (each chosen word removes 11 bits).\n", len(chosen), max(random, 0), size*8)
+    if len(chosen) > 0 {
+        fmt.Println("Words that mean something to you are easier to guess; the real loss may be larger.")
+    }
+    if random < 128 {
+        fmt.Printf("Warning: below 128 random bits. Continue anyway? [y/N] ")
+        answer, _ := readLine()
+        if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+            log.Fatalf("Error: cancelled.")
+        }
+    }
+
+    entropy, err := fillChosenWords(size, chosen)
+    if err != nil {
+        log.Fatalf("Error: -pick: %v", err)
+    }
+    return entropy
+}
+
+// 自选位置写入单词的位，其余位置随机，直到校验位与最后一个单词相符
+func fillChosenWords(size int, chosen map[int]int) ([]byte, error) {
+    words := size * 3 / 4
+    entBits := size * 8
+    last, lastChosen := chosen[words-1]
+    if len(chosen) == words {
+        // 全部自选：校验要么碰巧有效，要么无解
+        indices := make([]int, words)
+        for i := range indices {
+            indices[i] = chosen[i]
+        }
+        e, err := bip39.EntropyFromIndices(indices)
+        if err != nil {
+            return nil, errors.New("the chosen words have no valid checksum; leave at least one word random")
+        }
+        return e, nil
+    }
+
+    entropy := make([]byte, size)
+    for tries := 0; tries < 1<<16; tries++ {
+        if _, err := rand.Read(entropy); err != nil {
+            return nil, err
+        }
+        bits := bip39.BytesToBits(entropy)
+        for pos, idx := range chosen {
+            for b := 0; b < 11; b++ {
+                if k := pos*11 + b; k < entBits {
+                    bits[k] = idx>>(10-b)&1 == 1
+                }
+            }
+        }
+        if lastChosen {
+            cs := bip39.ChecksumBits(bits)
+            ok := true
+            for b, bit := range cs {
+                if bit != (last>>(len(cs)-1-b)&1 == 1) {
+                    ok = false
+                    break
+                }
+            }
+            if !ok {
+                continue
+            }
+        }
+        return bip39.BitsToBytes(bits), nil
+    }
+    return nil, errors.New("no checksum match found")
+}
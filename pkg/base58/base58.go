@@ -0,0 +1,86 @@
+// Package base58 implements Base58 and Base58Check as used by Bitcoin
+// extended keys and legacy addresses.
+package base58
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "errors"
+    "math/big"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Encode returns the Base58 encoding of b.
+func Encode(b []byte) string {
+    n := new(big.Int).SetBytes(b)
+    radix := big.NewInt(58)
+    mod := new(big.Int)
+    var out []byte
+    for n.Sign() > 0 {
+        n.DivMod(n, radix, mod)
+        out = append(out, alphabet[mod.Int64()])
+    }
+    for _, c := range b {
+        if c != 0 {
+            break
+        }
+        out = append(out, '1')
+    }
+    for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+        out[i], out[j] = out[j], out[i]
+    }
+    return string(out)
+}
+
+// Decode parses a Base58 string.
+func Decode(s string) ([]byte, error) {
+    n := new(big.Int)
+    radix := big.NewInt(58)
+    for _, c := range s {
+        i := bytes.IndexByte([]byte(alphabet), byte(c))
+        if c > 127 || i < 0 {
+            return nil, errors.New("base58: invalid character")
+        }
+        n.Mul(n, radix)
+        n.Add(n, big.NewInt(int64(i)))
+    }
+    out := n.Bytes()
+    for _, c := range s {
+        if c != '1' {
+            break
+        }
+        out = append([]byte{0}, out...)
+    }
+    return out, nil
+}
+
+// CheckEncode appends the 4-byte double-SHA256 checksum and encodes.
+func CheckEncode(payload []byte) string {
+    sum := doubleSHA256(payload)
+    return Encode(append(append([]byte{}, payload...), sum[:4]...))
+}
+
+// CheckDecode decodes s and verifies and strips its checksum.
+func CheckDecode(s string) ([]byte, error) {
+    b, err := Decode(s)
+    if err != nil {
+        return nil, err
+    }
+    if len(b) < 4 {
+        return nil, errors.New("base58: string too short")
+    }
+    payload, sum := b[:len(b)-4], b[len(b)-4:]
+    want := doubleSHA256(payload)
+    if !secretcmp.Equal(sum, want[:4]) {
+        return nil, errors.New("base58: checksum mismatch")
+    }
+    return payload, nil
+}
+
+func doubleSHA256(b []byte) [32]byte {
+    first := sha256.Sum256(b)
+    return sha256.Sum256(first[:])
+}
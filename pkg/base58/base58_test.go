@@ -0,0 +1,81 @@
+package base58
+
+import (
+    "bytes"
+    "encoding/hex"
+    "testing"
+)
+
+// Bitcoin Core base58_encode_decode.json 中的向量
+var vectors = []struct {
+    hex, b58 string
+}{
+    {"", ""},
+    {"61", "2g"},
+    {"626262", "a3gV"},
+    {"636363", "aPEr"},
+    {"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+    {"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+    {"516b6fcd0f", "ABnLTmg"},
+    {"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+    {"572e4794", "3EFU7m"},
+    {"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+    {"10c8511e", "Rt5zm"},
+    {"00000000000000000000", "1111111111"},
+}
+
+func TestEncodeDecode(t *testing.T) {
+    for _, v := range vectors {
+        b, _ := hex.DecodeString(v.hex)
+        if got := Encode(b); got != v.b58 {
+            t.Errorf("Encode(%s) = %q, want %q", v.hex, got, v.b58)
+        }
+        got, err := Decode(v.b58)
+        if err != nil {
+            t.Errorf("Decode(%q): %v", v.b58, err)
+            continue
+        }
+        if !bytes.Equal(got, b) {
+            t.Errorf("Decode(%q) = %x, want %s", v.b58, got, v.hex)
+        }
+    }
+    for _, s := range []string{"0", "O", "I", "l", "3mJr0", "ä"} {
+        if _, err := Decode(s); err == nil {
+            t.Errorf("Decode(%q) accepted an invalid character", s)
+        }
+    }
+}
+
+// Base58Check：P2PKH 地址（版本 0x00 + HASH160）
+var checkVectors = []struct {
+    payload, b58 string
+}{
+    {"00010966776006953d5567439e5e39f86a0d273bee", "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"},
+    {"00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31", "1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs"},
+}
+
+func TestCheckEncodeDecode(t *testing.T) {
+    for _, v := range checkVectors {
+        payload, _ := hex.DecodeString(v.payload)
+        if got := CheckEncode(payload); got != v.b58 {
+            t.Errorf("CheckEncode(%s) = %q, want %q", v.payload, got, v.b58)
+        }
+        got, err := CheckDecode(v.b58)
+        if err != nil {
+            t.Errorf("CheckDecode(%q): %v", v.b58, err)
+            continue
+        }
+        if !bytes.Equal(got, payload) {
+            t.Errorf("CheckDecode(%q) = %x, want %s", v.b58, got, v.payload)
+        }
+    }
+
+    // 改动最后一个字符，校验和必须失败
+    bad := "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"
+    if _, err := CheckDecode(bad); err == nil {
+        t.Errorf("CheckDecode(%q) accepted a bad checksum", bad)
+    }
+    if _, err := CheckDecode("111"); err == nil {
+        t.Error("CheckDecode accepted a string shorter than the checksum")
+    }
+}
@@ -0,0 +1,237 @@
+// Package bip32 implements BIP32 hierarchical deterministic key derivation
+// for Bitcoin: master key generation from a seed, private and public child
+// derivation, and xprv/xpub serialization.
+package bip32
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+
+    "golang.org/x/crypto/ripemd160"
+    "github.com/li-han-zhang/Go_passphrase/pkg/base58"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secp256k1"
+)
+
+// HardenedOffset is added to an index to request hardened derivation.
+const HardenedOffset uint32 = 0x80000000
+
+// Serialization versions for mainnet extended keys.
+var (
+    VersionXPrv = [4]byte{0x04, 0x88, 0xad, 0xe4}
+    VersionXPub = [4]byte{0x04, 0x88, 0xb2, 0x1e}
+)
+
+// Key is an extended private or public key.
+type Key struct {
+    Key       []byte // 32-byte private key or 33-byte compressed public key
+    ChainCode []byte
+    Depth     byte
+    ParentFP  [4]byte
+    Index     uint32
+    Private   bool
+}
+
+// NewMaster derives the master key from a BIP39 (or other) seed.
+func NewMaster(seed []byte) (*Key, error) {
+    mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+    mac.Write(seed)
+    sum := mac.Sum(nil)
+
+    if !secp256k1.ValidScalar(sum[:32]) {
+        return nil, errors.New("bip32: invalid master key, use another seed")
+    }
+    return &Key{Key: sum[:32], ChainCode: sum[32:], Private: true}, nil
+}
+
+// PublicKey returns the 33-byte compressed public key.
+func (k *Key) PublicKey() []byte {
+    if !k.Private {
+        return k.Key
+    }
+    return secp256k1.Compress(secp256k1.ScalarBaseMult(k.Key))
+}
+
+// Fingerprint returns the first 4 bytes of HASH160 of the public key.
+func (k *Key) Fingerprint() [4]byte {
+    var fp [4]byte
+    copy(fp[:], Hash160(k.PublicKey()))
+    return fp
+}
+
+// Neuter returns the public version of k.
+func (k *Key) Neuter() *Key {
+    if !k.Private {
+        return k
+    }
+    return &Key{
+        Key:       k.PublicKey(),
+        ChainCode: k.ChainCode,
+        Depth:     k.Depth,
+        ParentFP:  k.ParentFP,
+        Index:     k.Index,
+    }
+}
+
+// Child derives the child key at index i. Hardened indices (i ≥
+// HardenedOffset) require a private key.
+func (k *Key) Child(i uint32) (*Key, error) {
+    var data []byte
+    if i >= HardenedOffset {
+        if !k.Private {
+            return nil, errors.New("bip32: hardened derivation from a public key")
+        }
+        data = append([]byte{0}, k.Key...)
+    } else {
+        data = append([]byte{}, k.PublicKey()...)
+    }
+    data = binary.BigEndian.AppendUint32(data, i)
+
+    mac := hmac.New(sha512.New, k.ChainCode)
+    mac.Write(data)
+    sum := mac.Sum(nil)
+    il, ir := sum[:32], sum[32:]
+
+    if secp256k1.Overflows(il) {
+        return nil, fmt.Errorf("bip32: invalid child at index %d", i)
+    }
+
+    child := &Key{
+        ChainCode: ir,
+        Depth:     k.Depth + 1,
+        ParentFP:  k.Fingerprint(),
+        Index:     i,
+        Private:   k.Private,
+    }
+
+    if k.Private {
+        child.Key = secp256k1.AddScalars(il, k.Key)
+        if !secp256k1.ValidScalar(child.Key) {
+            return nil, fmt.Errorf("bip32: invalid child at index %d", i)
+        }
+        return child, nil
+    }
+
+    parent, err := secp256k1.Decompress(k.Key)
+    if err != nil {
+        return nil, err
+    }
+    p := secp256k1.Add(secp256k1.ScalarBaseMult(il), parent)
+    if p.IsInfinity() {
+        return nil, fmt.Errorf("bip32: invalid child at index %d", i)
+    }
+    child.Key = secp256k1.Compress(p)
+    return child, nil
+}
+
+// Derive follows a path such as "m/84'/0'/0'/0/5" ("h" also marks hardened
+// indices). A leading "m" is optional.
+func (k *Key) Derive(path string) (*Key, error) {
+    indices, err := ParsePath(path)
+    if err != nil {
+        return nil, err
+    }
+    key := k
+    for _, i := range indices {
+        if key, err = key.Child(i); err != nil {
+            return nil, err
+        }
+    }
+    return key, nil
+}
+
+// ParsePath parses a derivation path into child indices.
+func ParsePath(path string) ([]uint32, error) {
+    path = strings.TrimSpace(path)
+    path = strings.TrimPrefix(strings.TrimPrefix(path, "m"), "/")
+    if path == "" {
+        return nil, nil
+    }
+    var indices []uint32
+    for _, part := range strings.Split(path, "/") {
+        hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+        if hardened {
+            part = part[:len(part)-1]
+        }
+        n, err := strconv.ParseUint(part, 10, 31)
+        if err != nil {
+            return nil, fmt.Errorf("bip32: invalid path element %q", part)
+        }
+        i := uint32(n)
+        if hardened {
+            i += HardenedOffset
+        }
+        indices = append(indices, i)
+    }
+    return indices, nil
+}
+
+// String serializes k as xprv or xpub.
+func (k *Key) String() string {
+    if k.Private {
+        return k.Serialize(VersionXPrv)
+    }
+    return k.Serialize(VersionXPub)
+}
+
+// Serialize encodes k with the given 4-byte version prefix.
+func (k *Key) Serialize(version [4]byte) string {
+    b := make([]byte, 0, 78)
+    b = append(b, version[:]...)
+    b = append(b, k.Depth)
+    b = append(b, k.ParentFP[:]...)
+    b = binary.BigEndian.AppendUint32(b, k.Index)
+    b = append(b, k.ChainCode...)
+    if k.Private {
+        b = append(b, 0)
+    }
+    b = append(b, k.Key...)
+    return base58.CheckEncode(b)
+}
+
+// Parse decodes a serialized extended key and returns it with its version.
+func Parse(s string) (*Key, [4]byte, error) {
+    var version [4]byte
+    b, err := base58.CheckDecode(s)
+    if err != nil {
+        return nil, version, err
+    }
+    if len(b) != 78 {
+        return nil, version, errors.New("bip32: extended key has wrong length")
+    }
+    copy(version[:], b[:4])
+    k := &Key{
+        Depth:     b[4],
+        Index:     binary.BigEndian.Uint32(b[9:13]),
+        ChainCode: b[13:45],
+    }
+    copy(k.ParentFP[:], b[5:9])
+
+    if b[45] == 0 {
+        k.Private = true
+        k.Key = b[46:]
+        if !secp256k1.ValidScalar(k.Key) {
+            return nil, version, errors.New("bip32: invalid private key")
+        }
+    } else {
+        k.Key = b[45:]
+        if _, err := secp256k1.Decompress(k.Key); err != nil {
+            return nil, version, err
+        }
+    }
+    return k, version, nil
+}
+
+// Hash160 returns RIPEMD160(SHA256(b)).
+func Hash160(b []byte) []byte {
+    sha := sha256.Sum256(b)
+    h := ripemd160.New()
+    h.Write(sha[:])
+    return h.Sum(nil)
+}
+
@@ -0,0 +1,150 @@
+package bip32
+
+import (
+    "encoding/hex"
+    "slices"
+    "testing"
+)
+
+type vectorStep struct {
+    path, xpub, xprv string
+}
+
+// BIP32 测试向量 1–3
+var vectors = []struct {
+    seed  string
+    steps []vectorStep
+}{
+    {
+        "000102030405060708090a0b0c0d0e0f",
+        []vectorStep{
+            {"m",
+                "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+                "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+            {"m/0H",
+                "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+                "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+            {"m/0H/1",
+                "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+                "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs"},
+            {"m/0H/1/2H",
+                "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+                "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM"},
+            {"m/0H/1/2H/2",
+                "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+                "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334"},
+            {"m/0H/1/2H/2/1000000000",
+                "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+                "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76"},
+        },
+    },
+    {
+        "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+        []vectorStep{
+            {"m",
+                "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+                "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U"},
+            {"m/0",
+                "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+                "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt"},
+            {"m/0/2147483647H",
+                "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+                "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9"},
+            {"m/0/2147483647H/1",
+                "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+                "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef"},
+            {"m/0/2147483647H/1/2147483646H",
+                "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+                "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc"},
+            {"m/0/2147483647H/1/2147483646H/2",
+                "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+                "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j"},
+        },
+    },
+    {
+        // 私钥带前导零
+        "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+        []vectorStep{
+            {"m",
+                "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+                "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6"},
+            {"m/0H",
+                "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+                "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L"},
+        },
+    },
+}
+
+func TestVectors(t *testing.T) {
+    for i, v := range vectors {
+        seed, _ := hex.DecodeString(v.seed)
+        master, err := NewMaster(seed)
+        if err != nil {
+            t.Fatalf("vector %d: %v", i+1, err)
+        }
+        for _, s := range v.steps {
+            k, err := master.Derive(s.path)
+            if err != nil {
+                t.Fatalf("vector %d %s: %v", i+1, s.path, err)
+            }
+            if got := k.String(); got != s.xprv {
+                t.Errorf("vector %d %s: xprv = %s, want %s", i+1, s.path, got, s.xprv)
+            }
+            if got := k.Neuter().String(); got != s.xpub {
+                t.Errorf("vector %d %s: xpub = %s, want %s", i+1, s.path, got, s.xpub)
+            }
+
+            // 解析后重新序列化应得到原字符串
+            for _, want := range []string{s.xprv, s.xpub} {
+                parsed, _, err := Parse(want)
+                if err != nil {
+                    t.Errorf("Parse(%s): %v", want, err)
+                    continue
+                }
+                if got := parsed.String(); got != want {
+                    t.Errorf("Parse(%s).String() = %s", want, got)
+                }
+            }
+        }
+    }
+}
+
+// 非强化子密钥可以只用 xpub 推导，结果应与由 xprv 推导后再去掉私钥一致
+func TestPublicDerivation(t *testing.T) {
+    seed, _ := hex.DecodeString(vectors[0].seed)
+    master, _ := NewMaster(seed)
+    parent, err := master.Derive("m/0H/1/2H")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want, err := parent.Derive("2/1000000000")
+    if err != nil {
+        t.Fatal(err)
+    }
+    got, err := parent.Neuter().Derive("2/1000000000")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.String() != want.Neuter().String() {
+        t.Errorf("public derivation = %s, want %s", got, want.Neuter())
+    }
+    if _, err := parent.Neuter().Child(HardenedOffset); err == nil {
+        t.Error("hardened child derived from a public key")
+    }
+}
+
+func TestParsePath(t *testing.T) {
+    for _, path := range []string{"m/x", "m/0/", "m/2147483648", "m//1"} {
+        if _, err := ParsePath(path); err == nil {
+            t.Errorf("ParsePath(%q) accepted an invalid path", path)
+        }
+    }
+    got, err := ParsePath("m/84'/0h/0H/1/5")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []uint32{84 + HardenedOffset, HardenedOffset, HardenedOffset, 1, 5}
+    if !slices.Equal(got, want) {
+        t.Errorf("ParsePath = %v, want %v", got, want)
+    }
+}
@@ -0,0 +1,216 @@
+package bip39_test
+
+import (
+    "encoding/hex"
+    "errors"
+    "slices"
+    "strings"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+// v1 API：每个导出名称的签名。改动签名或删除名称会在这里编译失败，
+// 那是 v2 才允许的改动（见包文档的 Compatibility 一节）。
+var (
+    _ int   = bip39.WordCount
+    _ error = bip39.ErrChecksum
+
+    _ func(string) []string                                 = bip39.ParseWordList
+    _ func(int) bool                                        = bip39.ValidEntropySize
+    _ func(int) (bip39.Entropy, error)                      = bip39.NewEntropy
+    _ func(bip39.Entropy, []string) (bip39.Mnemonic, error) = bip39.NewMnemonic
+    _ func([]bool, []string) bip39.Mnemonic                 = bip39.MnemonicFromBits
+    _ func(bip39.Mnemonic) []string                         = bip39.Mnemonic.Words
+    _ func(bip39.Mnemonic, []string) (bip39.Entropy, error) = bip39.Mnemonic.Entropy
+    _ func(bip39.Mnemonic, string) []byte                   = bip39.Mnemonic.Seed
+    _ func([]string, []string) ([]int, error)               = bip39.Indices
+    _ func([]int) (bip39.Entropy, error)                    = bip39.EntropyFromIndices
+    _ func([]int) ([]int, error)                            = bip39.LastWords
+    _ func([]bool) []bool                                   = bip39.ChecksumBits
+    _ func(string) ([]bool, error)                          = bip39.ParseBits
+    _ func([]bool) (bip39.ChecksumReport, error)            = bip39.CheckBits
+    _ func([]byte) []bool                                   = bip39.BytesToBits
+    _ func([]bool) []byte                                   = bip39.BitsToBytes
+    _ func([]bool) int                                      = bip39.BitsToInt
+    _ func() []string                                       = bip39.Languages
+    _ func(string) ([]string, error)                        = bip39.Wordlist
+    _ func() []string                                       = bip39.English
+
+    _ []byte = bip39.Entropy(nil)
+    _        = bip39.ChecksumReport{Entropy: nil, Checksum: nil, Expected: nil, Valid: false, Corrected: nil}
+    _ string = string(bip39.Mnemonic(""))
+)
+
+// 官方英文测试向量（trezor/python-mnemonic vectors.json，口令 TREZOR）
+var v1Vectors = []struct {
+    entropy, mnemonic, seed string
+}{
+    {
+        "00000000000000000000000000000000",
+        "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+        "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+    },
+    {
+        "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+        "legal winner thank year wave sausage worth useful legal winner thank yellow",
+        "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+    },
+    {
+        "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+        "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+        "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+    },
+    {
+        "9e885d952ad362caeb4efe34a8e91bd2",
+        "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+        "274ddc525802f7c828d8ef7ddbcdc5304e87ac3535913611fbbfa986d0c9e5476c91689f9c8a54fd55bd38606aa6a8595ad213d4c9c9f9aca3fb217069a41028",
+    },
+}
+
+func TestV1Vectors(t *testing.T) {
+    english := bip39.English()
+    for _, v := range v1Vectors {
+        entropy, _ := hex.DecodeString(v.entropy)
+        m, err := bip39.NewMnemonic(entropy, english)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if string(m) != v.mnemonic {
+            t.Errorf("NewMnemonic(%s) = %q, want %q", v.entropy, m, v.mnemonic)
+        }
+        back, err := bip39.Mnemonic(v.mnemonic).Entropy(english)
+        if err != nil || hex.EncodeToString(back) != v.entropy {
+            t.Errorf("Entropy(%q) = %x, %v, want %s", v.mnemonic, back, err, v.entropy)
+        }
+        if got := hex.EncodeToString(bip39.Mnemonic(v.mnemonic).Seed("TREZOR")); got != v.seed {
+            t.Errorf("Seed(%q) = %s, want %s", v.mnemonic, got, v.seed)
+        }
+    }
+}
+
+// v1 承诺的错误行为
+func TestV1Errors(t *testing.T) {
+    english := bip39.English()
+    bad := bip39.Mnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+    if _, err := bad.Entropy(english); !errors.Is(err, bip39.ErrChecksum) {
+        t.Errorf("bad checksum: err = %v, want ErrChecksum", err)
+    }
+    if _, err := bip39.NewMnemonic(make([]byte, 15), english); err == nil {
+        t.Error("NewMnemonic accepted 120 bits")
+    }
+    if _, err := bip39.NewMnemonic(make([]byte, 16), english[:100]); err == nil {
+        t.Error("NewMnemonic accepted a 100-word list")
+    }
+    if _, err := bip39.NewEntropy(100); err == nil {
+        t.Error("NewEntropy accepted 100 bits")
+    }
+    if _, err := bip39.Mnemonic("abandon notaword").Entropy(english); err == nil {
+        t.Error("Entropy accepted a word not on the list")
+    }
+    if _, err := bip39.Wordlist("klingon"); err == nil {
+        t.Error("Wordlist accepted an unknown language")
+    }
+}
+
+// 语言名称是 API 的一部分：v1 内只增不减
+func TestV1Languages(t *testing.T) {
+    v1 := []string{"chinese_simplified", "chinese_traditional", "czech", "english", "french", "italian", "japanese", "korean", "spanish"}
+    langs := bip39.Languages()
+    for _, lang := range v1 {
+        if !slices.Contains(langs, lang) {
+            t.Errorf("language %q missing from Languages()", lang)
+            continue
+        }
+        words, err := bip39.Wordlist(lang)
+        if err != nil || len(words) != bip39.WordCount {
+            t.Errorf("Wordlist(%q): %d words, %v", lang, len(words), err)
+        }
+    }
+}
+
+// Wordlist 每次返回新切片，调用者可以修改
+func TestWordlistCopy(t *testing.T) {
+    a := bip39.English()
+    a[0] = "changed"
+    if b := bip39.English(); b[0] != "abandon" {
+        t.Errorf("English()[0] = %q after modifying an earlier result", b[0])
+    }
+}
+
+// CheckBits：有效、校验位错误、缺少校验位、长度错误
+func TestCheckBits(t *testing.T) {
+    bitString := func(bits []bool) string {
+        s := ""
+        for _, b := range bits {
+            if b {
+                s += "1"
+            } else {
+                s += "0"
+            }
+        }
+        return s
+    }
+    zeros := strings.Repeat("00000000000 ", 11) + "0000000"
+    for _, tc := range []struct {
+        name, in  string
+        valid     bool
+        expected  string
+        corrected string
+    }{
+        {"valid", zeros + "0011", true, "0011", strings.Repeat("0", 128) + "0011"},
+        {"wrong checksum", zeros + "|0101", false, "0011", strings.Repeat("0", 128) + "0011"},
+        {"missing checksum", zeros, false, "0011", strings.Repeat("0", 128) + "0011"},
+    } {
+        bits, err := bip39.ParseBits(tc.in)
+        if err != nil {
+            t.Fatalf("%s: ParseBits: %v", tc.name, err)
+        }
+        r, err := bip39.CheckBits(bits)
+        if err != nil {
+            t.Fatalf("%s: CheckBits: %v", tc.name, err)
+        }
+        if r.Valid != tc.valid || bitString(r.Expected) != tc.expected || bitString(r.Corrected) != tc.corrected {
+            t.Errorf("%s: Valid=%v Expected=%s Corrected=%s", tc.name, r.Valid, bitString(r.Expected), bitString(r.Corrected))
+        }
+    }
+    if _, err := bip39.CheckBits(make([]bool, 130)); err == nil {
+        t.Error("CheckBits accepted 130 bits")
+    }
+    if _, err := bip39.ParseBits("0102"); err == nil {
+        t.Error("ParseBits accepted '2'")
+    }
+}
+
+func TestLastWords(t *testing.T) {
+    english := bip39.English()
+    for _, v := range v1Vectors {
+        words := strings.Fields(v.mnemonic)
+        indices, err := bip39.Indices(words, english)
+        if err != nil {
+            t.Fatal(err)
+        }
+        last, err := bip39.LastWords(indices[:len(indices)-1])
+        if err != nil {
+            t.Fatal(err)
+        }
+        // 12 词 128 个、24 词 8 个，且都通过校验
+        if want := 1 << (11 - len(words)/3); len(last) != want {
+            t.Errorf("%s: %d candidates, want %d", v.entropy, len(last), want)
+        }
+        if !slices.Contains(last, indices[len(indices)-1]) {
+            t.Errorf("%s: the vector's last word is missing", v.entropy)
+        }
+        for _, w := range last {
+            if _, err := bip39.EntropyFromIndices(append(indices[:len(indices)-1:len(indices)-1], w)); err != nil {
+                t.Errorf("%s: candidate %s: %v", v.entropy, english[w], err)
+            }
+        }
+    }
+    if _, err := bip39.LastWords(make([]int, 12)); err == nil {
+        t.Error("LastWords accepted 12 indices")
+    }
+    if _, err := bip39.LastWords(append(make([]int, 10), 2048)); err == nil {
+        t.Error("LastWords accepted index 2048")
+    }
+}
@@ -0,0 +1,287 @@
+// Package bip39 implements the BIP39 mnemonic encoding: entropy plus a
+// SHA-256 checksum, split into 11-bit indices into a 2048-word list, and the
+// PBKDF2 seed derived from the words.
+//
+// The official wordlists are embedded (see Wordlist); other lists can be
+// passed to every function that takes a wordList. Wallet-facing
+// normalisation (NFKD, case folding) is done by package textnorm.
+//
+// # Compatibility
+//
+// The exported API of this package is stable at v1 and follows semantic
+// versioning: within v1 no exported name is removed or changes signature,
+// and the output for a given input (words, entropy, seed) never changes.
+// New functions may be added in minor releases. api_test.go pins every
+// exported signature and the official test vectors.
+//
+// A name that is to be replaced is first marked with a "Deprecated:"
+// paragraph naming its replacement. It keeps working for the rest of v1
+// and is removed no earlier than v2.
+package bip39
+
+import (
+    "crypto/rand"
+    "crypto/sha256"
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/fastpbkdf2"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+// WordCount is the number of words in a BIP39 wordlist.
+const WordCount = 2048
+
+// ErrChecksum is returned when the checksum bits of a mnemonic do not match
+// its entropy.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// Entropy is 16, 20, 24, 28 or 32 bytes of randomness.
+type Entropy []byte
+
+// Mnemonic is a list of words separated by single spaces.
+type Mnemonic string
+
+// ParseWordList splits text into one word per non-empty line, ignoring a
+// byte order mark and surrounding whitespace.
+func ParseWordList(text string) []string {
+    words := make([]string, 0, WordCount)
+    for _, w := range strings.Split(text, "\n") {
+        if w = strings.TrimPrefix(strings.TrimSpace(w), "\ufeff"); w != "" {
+            words = append(words, w)
+        }
+    }
+    return words
+}
+
+// ValidEntropySize reports whether n bytes is a BIP39 entropy length
+// (128, 160, 192, 224 or 256 bits).
+func ValidEntropySize(n int) bool {
+    return n >= 16 && n <= 32 && n%4 == 0
+}
+
+// NewEntropy reads bits of entropy from crypto/rand.
+func NewEntropy(bits int) (Entropy, error) {
+    if bits%8 != 0 || !ValidEntropySize(bits/8) {
+        return nil, fmt.Errorf("entropy is %d bits, expected 128, 160, 192, 224 or 256", bits)
+    }
+    e := make(Entropy, bits/8)
+    if _, err := rand.Read(e); err != nil {
+        return nil, err
+    }
+    return e, nil
+}
+
+// NewMnemonic encodes e as words from wordList.
+func NewMnemonic(e Entropy, wordList []string) (Mnemonic, error) {
+    if !ValidEntropySize(len(e)) {
+        return "", fmt.Errorf("entropy is %d bits, expected 128, 160, 192, 224 or 256", len(e)*8)
+    }
+    if len(wordList) != WordCount {
+        return "", fmt.Errorf("word list length %d, expected %d", len(wordList), WordCount)
+    }
+    bits := BytesToBits(e)
+    return MnemonicFromBits(append(bits, ChecksumBits(bits)...), wordList), nil
+}
+
+// MnemonicFromBits encodes entropy-plus-checksum bits 11 at a time. It does
+// not check the checksum; trailing bits that do not fill a word are ignored.
+func MnemonicFromBits(bits []bool, wordList []string) Mnemonic {
+    wordCount := len(bits) / 11
+    words := make([]string, 0, wordCount)
+    for i := 0; i < wordCount; i++ {
+        words = append(words, wordList[BitsToInt(bits[i*11:(i+1)*11])])
+    }
+    return Mnemonic(strings.Join(words, " "))
+}
+
+// Words returns the words of m.
+func (m Mnemonic) Words() []string {
+    return strings.Fields(string(m))
+}
+
+// Entropy decodes m with wordList and verifies its checksum.
+func (m Mnemonic) Entropy(wordList []string) (Entropy, error) {
+    indices, err := Indices(m.Words(), wordList)
+    if err != nil {
+        return nil, err
+    }
+    return EntropyFromIndices(indices)
+}
+
+// Seed returns the 64-byte BIP39 seed (PBKDF2-HMAC-SHA512, 2048 rounds,
+// salt "mnemonic"+passphrase, both NFKD-normalised).
+func (m Mnemonic) Seed(passphrase string) []byte {
+    salt := "mnemonic" + textnorm.Passphrase(passphrase)
+    return fastpbkdf2.Key([]byte(textnorm.Mnemonic(string(m))), []byte(salt), 2048)
+}
+
+// Indices looks up each word in wordList.
+func Indices(words []string, wordList []string) ([]int, error) {
+    pos := make(map[string]int, len(wordList))
+    for i, w := range wordList {
+        pos[w] = i
+    }
+    indices := make([]int, len(words))
+    for i, w := range words {
+        idx, ok := pos[w]
+        if !ok {
+            return nil, fmt.Errorf("word %d '%s' is not on the list", i+1, w)
+        }
+        indices[i] = idx
+    }
+    return indices, nil
+}
+
+// EntropyFromIndices joins 12, 15, 18, 21 or 24 word indices and verifies
+// the checksum.
+func EntropyFromIndices(indices []int) (Entropy, error) {
+    switch len(indices) {
+    case 12, 15, 18, 21, 24:
+    default:
+        return nil, fmt.Errorf("got %d indices, expected 12, 15, 18, 21 or 24", len(indices))
+    }
+
+    bits := make([]bool, 0, len(indices)*11)
+    for _, idx := range indices {
+        for i := 10; i >= 0; i-- {
+            bits = append(bits, (idx>>i)&1 == 1)
+        }
+    }
+
+    entLen := len(bits) * 32 / 33
+    entropy, cs := bits[:entLen], bits[entLen:]
+    expected := ChecksumBits(entropy)
+    if !secretcmp.EqualBits(cs, expected) {
+        return nil, ErrChecksum
+    }
+    return BitsToBytes(entropy), nil
+}
+
+// LastWords returns, in increasing order, the indices of every final word
+// that completes the first 11, 14, 17, 20 or 23 word indices to a mnemonic
+// with a valid checksum. The final word carries 11-cs free entropy bits, so
+// there are 128, 64, 32, 16 or 8 of them.
+func LastWords(indices []int) ([]int, error) {
+    n := len(indices) + 1
+    switch n {
+    case 12, 15, 18, 21, 24:
+    default:
+        return nil, fmt.Errorf("got %d indices, expected 11, 14, 17, 20 or 23", len(indices))
+    }
+    bits := make([]bool, 0, n*11)
+    for i, idx := range indices {
+        if idx < 0 || idx >= WordCount {
+            return nil, fmt.Errorf("index %d at position %d is out of range", idx, i+1)
+        }
+        for b := 10; b >= 0; b-- {
+            bits = append(bits, (idx>>b)&1 == 1)
+        }
+    }
+
+    // Each candidate overwrites the same free bits past len(bits).
+    cs := n / 3
+    free := 11 - cs
+    last := make([]int, 0, 1<<free)
+    for v := 0; v < 1<<free; v++ {
+        entropy := bits
+        for b := free - 1; b >= 0; b-- {
+            entropy = append(entropy, (v>>b)&1 == 1)
+        }
+        last = append(last, v<<cs|BitsToInt(ChecksumBits(entropy)))
+    }
+    return last, nil
+}
+
+// ChecksumBits returns the first len/32 bits of SHA-256 of the entropy.
+func ChecksumBits(entropyBits []bool) []bool {
+    hash := sha256.Sum256(BitsToBytes(entropyBits))
+    return BytesToBits(hash[:])[:len(entropyBits)/32]
+}
+
+// ChecksumReport is the result of CheckBits.
+type ChecksumReport struct {
+    Entropy   []bool // the entropy part of the input
+    Checksum  []bool // the checksum bits as given; empty if they were missing
+    Expected  []bool // the checksum computed from Entropy
+    Valid     bool   // Checksum equals Expected
+    Corrected []bool // Entropy followed by Expected
+}
+
+// ParseBits reads a string of '0' and '1', ignoring whitespace and the
+// separators '|', ',', '_' and '-' often found in debug output.
+func ParseBits(s string) ([]bool, error) {
+    bits := make([]bool, 0, len(s))
+    for i, c := range s {
+        switch {
+        case c == '0' || c == '1':
+            bits = append(bits, c == '1')
+        case c == ' ' || c == '\t' || c == '\n' || c == '\r' || strings.ContainsRune("|,_-", c):
+        default:
+            return nil, fmt.Errorf("character %q at offset %d is not a bit", c, i)
+        }
+    }
+    return bits, nil
+}
+
+// CheckBits verifies a bit string of entropy followed by its checksum
+// (132, 165, 198, 231 or 264 bits). Plain entropy (128, 160, 192, 224 or
+// 256 bits) is accepted too and reported as invalid, with the checksum
+// missing. Only the checksum can be corrected: a flipped entropy bit gives a
+// different, equally valid Corrected string.
+func CheckBits(bits []bool) (ChecksumReport, error) {
+    var entLen int
+    switch n := len(bits); {
+    case n%33 == 0 && n >= 132 && n <= 264:
+        entLen = n * 32 / 33
+    case n%32 == 0 && n >= 128 && n <= 256:
+        entLen = n
+    default:
+        return ChecksumReport{}, fmt.Errorf("got %d bits, expected entropy plus checksum (132, 165, 198, 231 or 264) or entropy alone (128, 160, 192, 224 or 256)", n)
+    }
+    r := ChecksumReport{
+        Entropy:  append([]bool{}, bits[:entLen]...),
+        Checksum: append([]bool{}, bits[entLen:]...),
+    }
+    r.Expected = ChecksumBits(r.Entropy)
+    r.Valid = secretcmp.EqualBits(r.Checksum, r.Expected)
+    r.Corrected = append(append([]bool{}, r.Entropy...), r.Expected...)
+    return r, nil
+}
+
+// BytesToBits expands b most significant bit first.
+func BytesToBits(b []byte) []bool {
+    bits := make([]bool, 0, len(b)*8)
+    for _, by := range b {
+        for i := 7; i >= 0; i-- {
+            bits = append(bits, (by>>i)&1 == 1)
+        }
+    }
+    return bits
+}
+
+// BitsToBytes packs bits most significant bit first, zero-padding the last
+// byte.
+func BitsToBytes(bits []bool) []byte {
+    out := make([]byte, (len(bits)+7)/8)
+    for i, b := range bits {
+        if b {
+            out[i/8] |= 1 << (7 - uint(i%8))
+        }
+    }
+    return out
+}
+
+// BitsToInt reads bits as a big-endian unsigned integer.
+func BitsToInt(bits []bool) int {
+    n := 0
+    for _, b := range bits {
+        n <<= 1
+        if b {
+            n |= 1
+        }
+    }
+    return n
+}
@@ -0,0 +1,73 @@
+package bip39_test
+
+import (
+    "encoding/hex"
+    "errors"
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+func ExampleNewMnemonic() {
+    entropy, _ := hex.DecodeString("00000000000000000000000000000000")
+    m, err := bip39.NewMnemonic(entropy, bip39.English())
+    if err != nil {
+        panic(err)
+    }
+    fmt.Println(m)
+    // Output: abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about
+}
+
+func ExampleNewEntropy() {
+    e, err := bip39.NewEntropy(256)
+    if err != nil {
+        panic(err)
+    }
+    m, err := bip39.NewMnemonic(e, bip39.English())
+    if err != nil {
+        panic(err)
+    }
+    fmt.Println(len(m.Words()), "words")
+    // Output: 24 words
+}
+
+func ExampleMnemonic_Entropy() {
+    m := bip39.Mnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow")
+    e, err := m.Entropy(bip39.English())
+    if err != nil {
+        panic(err)
+    }
+    fmt.Println(hex.EncodeToString(e))
+
+    _, err = bip39.Mnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon").Entropy(bip39.English())
+    fmt.Println(errors.Is(err, bip39.ErrChecksum))
+    // Output:
+    // 7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f
+    // true
+}
+
+func ExampleMnemonic_Seed() {
+    m := bip39.Mnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+    fmt.Println(hex.EncodeToString(m.Seed("TREZOR")))
+    // Output: c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04
+}
+
+func ExampleWordlist() {
+    words, err := bip39.Wordlist("spanish")
+    if err != nil {
+        panic(err)
+    }
+    // Words are stored in NFKD form: á is a followed by a combining accent.
+    fmt.Printf("%d %+q %+q\n", len(words), words[0], words[bip39.WordCount-1])
+    // Output: 2048 "a\u0301baco" "zurdo"
+}
+
+func ExampleEntropyFromIndices() {
+    indices := []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3}
+    e, err := bip39.EntropyFromIndices(indices)
+    if err != nil {
+        panic(err)
+    }
+    fmt.Println(hex.EncodeToString(e))
+    // Output: 00000000000000000000000000000000
+}
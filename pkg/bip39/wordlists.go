@@ -0,0 +1,68 @@
+package bip39
+
+import (
+    "embed"
+    "fmt"
+    "hash/crc32"
+    "sort"
+)
+
+//go:embed wordlists/*.txt
+var wordlistFS embed.FS
+
+// CRC-32 (IEEE) of each official list file as published in the BIP39
+// repository. Wordlist refuses a list that does not match.
+var wordlistCRC = map[string]uint32{
+    "english":             0xc1dbd296,
+    "japanese":            0x0acc1419,
+    "korean":              0x4ef461eb,
+    "spanish":             0x266e4f3d,
+    "chinese_simplified":  0xe3721bbf,
+    "chinese_traditional": 0x3c20b443,
+    "french":              0x3e56b216,
+    "italian":             0x2fc7d07e,
+    "czech":               0xd1b5fda0,
+}
+
+// Languages returns the names accepted by Wordlist, sorted.
+func Languages() []string {
+    names := make([]string, 0, len(wordlistCRC))
+    for name := range wordlistCRC {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// Wordlist returns the official list for lang. The words are already in
+// NFKD form. Each call returns a new slice that the caller may modify.
+func Wordlist(lang string) ([]string, error) {
+    want, ok := wordlistCRC[lang]
+    if !ok && lang == "portuguese" {
+        return nil, fmt.Errorf("the Portuguese list is not embedded in this build")
+    }
+    if !ok {
+        return nil, fmt.Errorf("unknown language %q", lang)
+    }
+    data, err := wordlistFS.ReadFile("wordlists/" + lang + ".txt")
+    if err != nil {
+        return nil, err
+    }
+    if got := crc32.ChecksumIEEE(data); got != want {
+        return nil, fmt.Errorf("%s wordlist is corrupt: crc32 %08x, expected %08x", lang, got, want)
+    }
+    words := ParseWordList(string(data))
+    if len(words) != WordCount {
+        return nil, fmt.Errorf("%s wordlist has %d words", lang, len(words))
+    }
+    return words, nil
+}
+
+// English returns the English wordlist.
+func English() []string {
+    words, err := Wordlist("english")
+    if err != nil {
+        panic(err)
+    }
+    return words
+}
@@ -0,0 +1,50 @@
+package bip39
+
+import (
+    "hash/crc32"
+    "io/fs"
+    "strings"
+    "testing"
+)
+
+// 每个嵌入的词表文件都有登记的 CRC-32 并与之相符；登记的语言都有文件。
+// 新增词表（如葡萄牙语）时，文件与 CRC 必须一起加入
+func TestWordlistCRC(t *testing.T) {
+    files, err := fs.Glob(wordlistFS, "wordlists/*.txt")
+    if err != nil {
+        t.Fatal(err)
+    }
+    seen := map[string]bool{}
+    for _, f := range files {
+        lang := strings.TrimSuffix(strings.TrimPrefix(f, "wordlists/"), ".txt")
+        seen[lang] = true
+        want, ok := wordlistCRC[lang]
+        if !ok {
+            t.Errorf("%s has no published CRC-32 in wordlistCRC", f)
+            continue
+        }
+        data, err := wordlistFS.ReadFile(f)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := crc32.ChecksumIEEE(data); got != want {
+            t.Errorf("%s: crc32 %08x, published %08x", f, got, want)
+        }
+    }
+    for lang := range wordlistCRC {
+        if !seen[lang] {
+            t.Errorf("wordlistCRC lists %s but wordlists/%s.txt is not embedded", lang, lang)
+        }
+    }
+}
+
+// 葡萄牙语词表尚未嵌入：给出说明，而不是“未知语言”
+func TestPortugueseNotEmbedded(t *testing.T) {
+    if _, ok := wordlistCRC["portuguese"]; ok {
+        t.Skip("the Portuguese list is embedded")
+    }
+    _, err := Wordlist("portuguese")
+    if err == nil || !strings.Contains(err.Error(), "not embedded") {
+        t.Errorf("Wordlist(portuguese) = %v", err)
+    }
+}
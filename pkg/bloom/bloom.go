@@ -0,0 +1,104 @@
+// Package bloom implements a small, serialisable Bloom filter: a set that
+// answers "definitely not present" or "probably present" without storing
+// its members. Positions come from SHA-256 with double hashing, so a filter
+// built on one machine gives the same answers everywhere.
+package bloom
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+    "errors"
+    "math"
+)
+
+const magic = "BLM1"
+
+// Filter is a Bloom filter with m bits and k hash functions.
+type Filter struct {
+    m, k  uint32
+    count uint32
+    bits  []byte
+}
+
+// ErrFormat is returned by Unmarshal for data that is not a serialised Filter.
+var ErrFormat = errors.New("bloom: invalid filter data")
+
+// New returns an empty filter sized for n members at false-positive rate p.
+func New(n int, p float64) *Filter {
+    n = max(n, 1)
+    m := uint32(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+    m = (m + 7) &^ 7
+    k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+    return &Filter{m: m, k: max(k, 1), bits: make([]byte, m/8)}
+}
+
+func (f *Filter) positions(data []byte) func(i uint32) uint32 {
+    sum := sha256.Sum256(data)
+    h1 := binary.BigEndian.Uint64(sum[0:8])
+    h2 := binary.BigEndian.Uint64(sum[8:16]) | 1
+    return func(i uint32) uint32 {
+        return uint32((h1 + uint64(i)*h2) % uint64(f.m))
+    }
+}
+
+// Add inserts data into the filter.
+func (f *Filter) Add(data []byte) {
+    pos := f.positions(data)
+    for i := uint32(0); i < f.k; i++ {
+        p := pos(i)
+        f.bits[p/8] |= 1 << (p % 8)
+    }
+    f.count++
+}
+
+// Has reports whether data is probably in the filter. False means it was
+// never added.
+func (f *Filter) Has(data []byte) bool {
+    pos := f.positions(data)
+    for i := uint32(0); i < f.k; i++ {
+        p := pos(i)
+        if f.bits[p/8]&(1<<(p%8)) == 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// Count returns the number of Add calls.
+func (f *Filter) Count() int {
+    return int(f.count)
+}
+
+// FalsePositiveRate estimates the probability that Has reports a value that
+// was never added.
+func (f *Filter) FalsePositiveRate() float64 {
+    return math.Pow(1-math.Exp(-float64(f.k)*float64(f.count)/float64(f.m)), float64(f.k))
+}
+
+// Marshal serialises the filter: "BLM1", m, k and count as big-endian
+// uint32, then the bit array.
+func (f *Filter) Marshal() []byte {
+    out := make([]byte, 0, 16+len(f.bits))
+    out = append(out, magic...)
+    out = binary.BigEndian.AppendUint32(out, f.m)
+    out = binary.BigEndian.AppendUint32(out, f.k)
+    out = binary.BigEndian.AppendUint32(out, f.count)
+    return append(out, f.bits...)
+}
+
+// Unmarshal parses data written by Marshal.
+func Unmarshal(data []byte) (*Filter, error) {
+    if len(data) < 16 || string(data[:4]) != magic {
+        return nil, ErrFormat
+    }
+    f := &Filter{
+        m:     binary.BigEndian.Uint32(data[4:]),
+        k:     binary.BigEndian.Uint32(data[8:]),
+        count: binary.BigEndian.Uint32(data[12:]),
+    }
+    if f.m == 0 || f.m%8 != 0 || f.k == 0 || f.k > 64 || len(data)-16 != int(f.m/8) {
+        return nil, ErrFormat
+    }
+    f.bits = append([]byte(nil), data[16:]...)
+    return f, nil
+}
@@ -0,0 +1,128 @@
+package btcaddr
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+    bech32Const  = 1
+    bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+    gen := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+    chk := uint32(1)
+    for _, v := range values {
+        top := chk >> 25
+        chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+        for i, g := range gen {
+            if (top>>i)&1 == 1 {
+                chk ^= g
+            }
+        }
+    }
+    return chk
+}
+
+func hrpExpand(hrp string) []byte {
+    out := make([]byte, 0, len(hrp)*2+1)
+    for _, c := range hrp {
+        out = append(out, byte(c)>>5)
+    }
+    out = append(out, 0)
+    for _, c := range hrp {
+        out = append(out, byte(c)&31)
+    }
+    return out
+}
+
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+    acc, bits := 0, uint(0)
+    maxv := 1<<to - 1
+    var out []byte
+    for _, b := range data {
+        if int(b)>>from != 0 {
+            return nil, errors.New("btcaddr: invalid data value")
+        }
+        acc = acc<<from | int(b)
+        bits += from
+        for bits >= to {
+            bits -= to
+            out = append(out, byte(acc>>bits&maxv))
+        }
+    }
+    if pad {
+        if bits > 0 {
+            out = append(out, byte(acc<<(to-bits)&maxv))
+        }
+    } else if bits >= from || acc<<(to-bits)&maxv != 0 {
+        return nil, errors.New("btcaddr: invalid padding")
+    }
+    return out, nil
+}
+
+// SegwitEncode encodes a witness program as bech32 (v0) or bech32m (v1+).
+func SegwitEncode(hrp string, version byte, program []byte) (string, error) {
+    data, err := convertBits(program, 8, 5, true)
+    if err != nil {
+        return "", err
+    }
+    values := append([]byte{version}, data...)
+
+    c := uint32(bech32Const)
+    if version > 0 {
+        c = bech32mConst
+    }
+    poly := bech32Polymod(append(append(hrpExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ c
+
+    var b strings.Builder
+    b.WriteString(hrp)
+    b.WriteByte('1')
+    for _, v := range values {
+        b.WriteByte(bech32Charset[v])
+    }
+    for i := 0; i < 6; i++ {
+        b.WriteByte(bech32Charset[(poly>>(5*(5-i)))&31])
+    }
+    return b.String(), nil
+}
+
+// SegwitDecode parses a segwit address and returns its witness version and
+// program.
+func SegwitDecode(hrp, addr string) (byte, []byte, error) {
+    lower := strings.ToLower(addr)
+    if lower != addr && strings.ToUpper(addr) != addr {
+        return 0, nil, errors.New("btcaddr: mixed-case address")
+    }
+    pos := strings.LastIndexByte(lower, '1')
+    if pos < 1 || pos+8 > len(lower) || len(lower) > 90 || lower[:pos] != hrp {
+        return 0, nil, fmt.Errorf("btcaddr: not a %s segwit address", hrp)
+    }
+    var values []byte
+    for _, c := range lower[pos+1:] {
+        i := strings.IndexRune(bech32Charset, c)
+        if i < 0 {
+            return 0, nil, errors.New("btcaddr: invalid bech32 character")
+        }
+        values = append(values, byte(i))
+    }
+
+    poly := bech32Polymod(append(hrpExpand(hrp), values...))
+    version := values[0]
+    if (version == 0 && poly != bech32Const) || (version > 0 && poly != bech32mConst) {
+        return 0, nil, errors.New("btcaddr: checksum mismatch")
+    }
+    program, err := convertBits(values[1:len(values)-6], 5, 8, false)
+    if err != nil {
+        return 0, nil, err
+    }
+    if len(program) < 2 || len(program) > 40 || version > 16 ||
+        (version == 0 && len(program) != 20 && len(program) != 32) {
+        return 0, nil, errors.New("btcaddr: invalid witness program")
+    }
+    return version, program, nil
+}
@@ -0,0 +1,82 @@
+package btcaddr
+
+import (
+    "encoding/hex"
+    "strings"
+    "testing"
+)
+
+// BIP173 / BIP350 中的有效地址与对应的 scriptPubKey
+var validSegwit = []struct {
+    addr, script string
+}{
+    {"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+    {"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+    {"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6"},
+    {"BC1SW50QGDZ25J", "6002751e"},
+    {"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "5210751e76e8199196d454941c45d1b3a323"},
+    {"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+    {"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+    {"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+}
+
+// BIP173 / BIP350 中的无效地址
+var invalidSegwit = []string{
+    "tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut",     // 错误的 hrp
+    "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",     // v1 用了 bech32 校验和
+    "tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf",     // v2 用了 bech32 校验和
+    "BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL",     // v16 用了 bech32 校验和
+    "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",                         // v0 用了 bech32m 校验和
+    "tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47",     // v0 用了 bech32m 校验和
+    "bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4",     // 非法字符
+    "BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R",     // 版本号超过 16
+    "bc1pw5dgrnzv",                                                       // 程序长度 1
+    "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav", // 程序长度 41
+    "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P",                               // v0 程序长度 16
+    "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq",     // 大小写混用
+    "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf",   // 多于 4 位的零填充
+    "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j",     // 非零填充
+    "bc1gmk9yu",                                                          // 空数据
+}
+
+func hrpOf(addr string) string {
+    return strings.ToLower(addr[:strings.LastIndexByte(addr, '1')])
+}
+
+func TestSegwitValid(t *testing.T) {
+    for _, v := range validSegwit {
+        hrp := hrpOf(v.addr)
+        version, program, err := SegwitDecode(hrp, v.addr)
+        if err != nil {
+            t.Errorf("SegwitDecode(%s): %v", v.addr, err)
+            continue
+        }
+        // scriptPubKey：OP_0 或 OP_1..OP_16，然后是程序长度与程序
+        op := version
+        if op > 0 {
+            op += 0x50
+        }
+        script := append([]byte{op, byte(len(program))}, program...)
+        if got := hex.EncodeToString(script); got != v.script {
+            t.Errorf("SegwitDecode(%s) script = %s, want %s", v.addr, got, v.script)
+        }
+        enc, err := SegwitEncode(hrp, version, program)
+        if err != nil {
+            t.Errorf("SegwitEncode(%s): %v", v.addr, err)
+            continue
+        }
+        if enc != strings.ToLower(v.addr) {
+            t.Errorf("SegwitEncode = %s, want %s", enc, strings.ToLower(v.addr))
+        }
+    }
+}
+
+func TestSegwitInvalid(t *testing.T) {
+    for _, addr := range invalidSegwit {
+        for _, hrp := range []string{"bc", "tb"} {
+            if _, _, err := SegwitDecode(hrp, addr); err == nil {
+                t.Errorf("SegwitDecode(%q, %s) accepted an invalid address", hrp, addr)
+            }
+        }
+    }
+}
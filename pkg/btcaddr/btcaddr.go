@@ -0,0 +1,129 @@
+// Package btcaddr encodes Bitcoin mainnet addresses for public keys:
+// legacy P2PKH, nested segwit P2SH-P2WPKH, native segwit P2WPKH and
+// taproot P2TR (BIP86 key-path only).
+package btcaddr
+
+import (
+    "crypto/sha256"
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/base58"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secp256k1"
+)
+
+// Type identifies an address/script type.
+type Type int
+
+const (
+    P2PKH     Type = iota // BIP44, 1…
+    P2SHP2WPKH            // BIP49, 3…
+    P2WPKH                // BIP84, bc1q…
+    P2TR                  // BIP86, bc1p…
+)
+
+// Purpose returns the BIP43 purpose number conventionally used for t.
+func (t Type) Purpose() uint32 {
+    return [...]uint32{44, 49, 84, 86}[t]
+}
+
+func (t Type) String() string {
+    return [...]string{"p2pkh", "p2sh-p2wpkh", "p2wpkh", "p2tr"}[t]
+}
+
+// TypeForPurpose maps 44/49/84/86 to the matching address type.
+func TypeForPurpose(purpose uint32) (Type, error) {
+    switch purpose {
+    case 44:
+        return P2PKH, nil
+    case 49:
+        return P2SHP2WPKH, nil
+    case 84:
+        return P2WPKH, nil
+    case 86:
+        return P2TR, nil
+    }
+    return 0, fmt.Errorf("btcaddr: unsupported purpose %d", purpose)
+}
+
+// ParseType is the inverse of Type.String.
+func ParseType(s string) (Type, error) {
+    for t := P2PKH; t <= P2TR; t++ {
+        if s == t.String() {
+            return t, nil
+        }
+    }
+    return 0, fmt.Errorf("btcaddr: unknown address type %q", s)
+}
+
+// Encode returns the address of type t for a 33-byte compressed public key.
+func Encode(t Type, pubKey []byte) (string, error) {
+    switch t {
+    case P2PKH:
+        return base58.CheckEncode(append([]byte{0x00}, bip32.Hash160(pubKey)...)), nil
+    case P2SHP2WPKH:
+        redeem := append([]byte{0x00, 0x14}, bip32.Hash160(pubKey)...)
+        return base58.CheckEncode(append([]byte{0x05}, bip32.Hash160(redeem)...)), nil
+    case P2WPKH:
+        return SegwitEncode("bc", 0, bip32.Hash160(pubKey))
+    case P2TR:
+        out, err := TaprootOutputKey(pubKey)
+        if err != nil {
+            return "", err
+        }
+        return SegwitEncode("bc", 1, out)
+    }
+    return "", fmt.Errorf("btcaddr: unknown address type %d", t)
+}
+
+// ScriptPubKey returns the output script of type t for a compressed public key.
+func ScriptPubKey(t Type, pubKey []byte) ([]byte, error) {
+    switch t {
+    case P2PKH:
+        s := append([]byte{0x76, 0xa9, 0x14}, bip32.Hash160(pubKey)...)
+        return append(s, 0x88, 0xac), nil
+    case P2SHP2WPKH:
+        redeem := append([]byte{0x00, 0x14}, bip32.Hash160(pubKey)...)
+        s := append([]byte{0xa9, 0x14}, bip32.Hash160(redeem)...)
+        return append(s, 0x87), nil
+    case P2WPKH:
+        return append([]byte{0x00, 0x14}, bip32.Hash160(pubKey)...), nil
+    case P2TR:
+        out, err := TaprootOutputKey(pubKey)
+        if err != nil {
+            return nil, err
+        }
+        return append([]byte{0x51, 0x20}, out...), nil
+    }
+    return nil, fmt.Errorf("btcaddr: unknown address type %d", t)
+}
+
+// TaprootOutputKey applies the BIP86 key-path-only tweak to an internal
+// public key and returns the 32-byte x-only output key.
+func TaprootOutputKey(pubKey []byte) ([]byte, error) {
+    p, err := secp256k1.Decompress(pubKey)
+    if err != nil {
+        return nil, err
+    }
+    internal, err := secp256k1.LiftX(p.X)
+    if err != nil {
+        return nil, err
+    }
+    xOnly := internal.X.FillBytes(make([]byte, 32))
+    t := TaggedHash("TapTweak", xOnly)
+    if secp256k1.Overflows(t) {
+        return nil, fmt.Errorf("btcaddr: invalid taproot tweak")
+    }
+    q := secp256k1.Add(internal, secp256k1.ScalarBaseMult(t))
+    return q.X.FillBytes(make([]byte, 32)), nil
+}
+
+// TaggedHash is the BIP340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || msg).
+func TaggedHash(tag string, msg []byte) []byte {
+    th := sha256.Sum256([]byte(tag))
+    h := sha256.New()
+    h.Write(th[:])
+    h.Write(th[:])
+    h.Write(msg)
+    return h.Sum(nil)
+}
@@ -0,0 +1,116 @@
+package btcaddr
+
+import (
+    "bytes"
+    "encoding/hex"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+// BIP44/49/84/86 常用的测试助记词，空口令
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func testMaster(t *testing.T) *bip32.Key {
+    t.Helper()
+    master, err := bip32.NewMaster(bip39.Mnemonic(testMnemonic).Seed(""))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return master
+}
+
+// BIP84、BIP86 给出的地址，以及常见钱包对 BIP44、BIP49 主网路径给出的地址
+func TestAddressVectors(t *testing.T) {
+    tests := []struct {
+        typ  Type
+        path string
+        addr string
+    }{
+        {P2PKH, "m/44'/0'/0'/0/0", "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+        {P2SHP2WPKH, "m/49'/0'/0'/0/0", "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf"},
+        {P2WPKH, "m/84'/0'/0'/0/0", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+        {P2WPKH, "m/84'/0'/0'/0/1", "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"},
+        {P2WPKH, "m/84'/0'/0'/1/0", "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el"},
+        {P2TR, "m/86'/0'/0'/0/0", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+        {P2TR, "m/86'/0'/0'/0/1", "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh"},
+        {P2TR, "m/86'/0'/0'/1/0", "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+    }
+    master := testMaster(t)
+    for _, tt := range tests {
+        k, err := master.Derive(tt.path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        got, err := Encode(tt.typ, k.PublicKey())
+        if err != nil {
+            t.Errorf("%s %s: %v", tt.typ, tt.path, err)
+            continue
+        }
+        if got != tt.addr {
+            t.Errorf("%s %s = %s, want %s", tt.typ, tt.path, got, tt.addr)
+        }
+    }
+}
+
+// BIP84、BIP86 的账户扩展公钥
+func TestAccountKeys(t *testing.T) {
+    master := testMaster(t)
+    tests := []struct {
+        path    string
+        version [4]byte
+        want    string
+    }{
+        {"m/84'/0'/0'", [4]byte{0x04, 0xb2, 0x47, 0x46}, "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"},
+        {"m/86'/0'/0'", bip32.VersionXPub, "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"},
+    }
+    for _, tt := range tests {
+        k, err := master.Derive(tt.path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := k.Neuter().Serialize(tt.version); got != tt.want {
+            t.Errorf("%s = %s, want %s", tt.path, got, tt.want)
+        }
+    }
+}
+
+// BIP86 第一个接收地址的内部公钥与输出公钥
+func TestTaprootOutputKey(t *testing.T) {
+    k, err := testMaster(t).Derive("m/86'/0'/0'/0/0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    pub := k.PublicKey()
+    if got := hex.EncodeToString(pub[1:]); got != "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115" {
+        t.Errorf("internal key = %s", got)
+    }
+    out, err := TaprootOutputKey(pub)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := hex.EncodeToString(out); got != "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c" {
+        t.Errorf("output key = %s", got)
+    }
+}
+
+func TestScriptPubKey(t *testing.T) {
+    k, err := testMaster(t).Derive("m/84'/0'/0'/0/0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    script, err := ScriptPubKey(P2WPKH, k.PublicKey())
+    if err != nil {
+        t.Fatal(err)
+    }
+    // bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu 解码得到的程序
+    version, program, err := SegwitDecode("bc", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := append([]byte{version, byte(len(program))}, program...)
+    if !bytes.Equal(script, want) {
+        t.Errorf("ScriptPubKey = %x, want %x", script, want)
+    }
+}
@@ -0,0 +1,166 @@
+//go:build goexperiment.simd && amd64
+
+package fastpbkdf2
+
+import (
+    "crypto/hmac"
+    "crypto/sha512"
+    "encoding/binary"
+    "simd/archsimd"
+)
+
+// 八路并行：每个向量的八个 64 位元素分别属于八个口令。U1 的消息长度
+// 不定，按标量计算；之后每轮的消息都正好是一个块（64 字节 + 填充），
+// 内层、外层各一次压缩，全部在向量寄存器中完成。状态与消息共 24 个
+// 向量，AVX2 的 16 个寄存器放不下，四路版本几乎没有收益，所以只用 AVX-512
+// 的 32 个寄存器和原生循环移位。
+
+type vec = archsimd.Uint64x8
+
+var sha512K = [80]uint64{
+    0x428a2f98d728ae22, 0x7137449123ef65cd, 0xb5c0fbcfec4d3b2f, 0xe9b5dba58189dbbc,
+    0x3956c25bf348b538, 0x59f111f1b605d019, 0x923f82a4af194f9b, 0xab1c5ed5da6d8118,
+    0xd807aa98a3030242, 0x12835b0145706fbe, 0x243185be4ee4b28c, 0x550c7dc3d5ffb4e2,
+    0x72be5d74f27b896f, 0x80deb1fe3b1696b1, 0x9bdc06a725c71235, 0xc19bf174cf692694,
+    0xe49b69c19ef14ad2, 0xefbe4786384f25e3, 0x0fc19dc68b8cd5b5, 0x240ca1cc77ac9c65,
+    0x2de92c6f592b0275, 0x4a7484aa6ea6e483, 0x5cb0a9dcbd41fbd4, 0x76f988da831153b5,
+    0x983e5152ee66dfab, 0xa831c66d2db43210, 0xb00327c898fb213f, 0xbf597fc7beef0ee4,
+    0xc6e00bf33da88fc2, 0xd5a79147930aa725, 0x06ca6351e003826f, 0x142929670a0e6e70,
+    0x27b70a8546d22ffc, 0x2e1b21385c26c926, 0x4d2c6dfc5ac42aed, 0x53380d139d95b3df,
+    0x650a73548baf63de, 0x766a0abb3c77b2a8, 0x81c2c92e47edaee6, 0x92722c851482353b,
+    0xa2bfe8a14cf10364, 0xa81a664bbc423001, 0xc24b8b70d0f89791, 0xc76c51a30654be30,
+    0xd192e819d6ef5218, 0xd69906245565a910, 0xf40e35855771202a, 0x106aa07032bbd1b8,
+    0x19a4c116b8d2d0c8, 0x1e376c085141ab53, 0x2748774cdf8eeb99, 0x34b0bcb5e19b48a8,
+    0x391c0cb3c5c95a63, 0x4ed8aa4ae3418acb, 0x5b9cca4f7763e373, 0x682e6ff3d6b2b8a3,
+    0x748f82ee5defb2fc, 0x78a5636f43172f60, 0x84c87814a1f0ab72, 0x8cc702081a6439ec,
+    0x90befffa23631e28, 0xa4506cebde82bde9, 0xbef9a3f7b2c67915, 0xc67178f2e372532b,
+    0xca273eceea26619c, 0xd186b8c721c0c207, 0xeada7dd6cde0eb1e, 0xf57d4f7fee6ed178,
+    0x06f067aa72176fba, 0x0a637dc5a2c898a6, 0x113f9804bef90dae, 0x1b710b35131c471b,
+    0x28db77f523047d84, 0x32caab7b40c72493, 0x3c9ebe0a15c9bebc, 0x431d67c49c100d4c,
+    0x4cc5d4becb3e42b6, 0x597f299cfc657e2a, 0x5fcb6fab3ad6faec, 0x6c44198c4a475817,
+}
+
+var sha512IV = [8]uint64{
+    0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+    0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+func init() {
+    if archsimd.X86.AVX512() {
+        Lanes = 8
+        keysVector = keysAVX512
+    }
+}
+
+func keysAVX512(passwords, salts [][]byte, iter int, out [][]byte) int {
+    n := len(passwords) / 8 * 8
+    for g := 0; g < n; g += 8 {
+        key8((*[8][]byte)(passwords[g:g+8]), (*[8][]byte)(salts[g:g+8]), iter, (*[8][]byte)(out[g:g+8]))
+    }
+    return n
+}
+
+func key8(passwords, salts *[8][]byte, iter int, out *[8][]byte) {
+    var ipad, opad, u1 [8][]byte
+    for l := range 8 {
+        p := passwords[l]
+        if len(p) > sha512.BlockSize {
+            sum := sha512.Sum512(p)
+            p = sum[:]
+        }
+        ipad[l] = make([]byte, sha512.BlockSize)
+        opad[l] = make([]byte, sha512.BlockSize)
+        copy(ipad[l], p)
+        copy(opad[l], p)
+        for i := range ipad[l] {
+            ipad[l][i] ^= 0x36
+            opad[l][i] ^= 0x5c
+        }
+
+        // U1 = HMAC(P, S || INT(1))
+        mac := hmac.New(sha512.New, passwords[l])
+        mac.Write(salts[l])
+        mac.Write([]byte{0, 0, 0, 1})
+        u1[l] = mac.Sum(nil)
+    }
+
+    var iv [8]vec
+    for i := range iv {
+        iv[i] = archsimd.BroadcastUint64x8(sha512IV[i])
+    }
+    var block [16]vec
+    loadWords(block[:], &ipad)
+    inner := compress(&iv, &block)
+    loadWords(block[:], &opad)
+    outer := compress(&iv, &block)
+
+    // 后续每轮的块：8 个字的 U，0x80 填充，长度 (128+64)×8 位
+    var u, t [8]vec
+    loadWords(u[:], &u1)
+    t = u
+    for i := 8; i < 15; i++ {
+        block[i] = archsimd.BroadcastUint64x8(0)
+    }
+    block[8] = archsimd.BroadcastUint64x8(1 << 63)
+    block[15] = archsimd.BroadcastUint64x8((sha512.BlockSize + sha512.Size) * 8)
+    for n := 1; n < iter; n++ {
+        copy(block[:8], u[:])
+        h := compress(&inner, &block)
+        copy(block[:8], h[:])
+        u = compress(&outer, &block)
+        for i := range t {
+            t[i] = t[i].Xor(u[i])
+        }
+    }
+
+    var lane [8]uint64
+    for l := range 8 {
+        out[l] = make([]byte, Size)
+    }
+    for i := range t {
+        t[i].StoreArray(&lane)
+        for l := range 8 {
+            binary.BigEndian.PutUint64(out[l][i*8:], lane[l])
+        }
+    }
+}
+
+// 八个口令各自的大端字节按字转置为向量
+func loadWords(dst []vec, src *[8][]byte) {
+    var lane [8]uint64
+    for i := range dst {
+        for l := range 8 {
+            lane[l] = binary.BigEndian.Uint64(src[l][i*8:])
+        }
+        dst[i] = archsimd.LoadUint64x8Array(&lane)
+    }
+}
+
+func rotr(x vec, n uint64) vec {
+    return x.RotateAllRight(n)
+}
+
+// SHA-512 压缩函数，八路同时进行
+func compress(state *[8]vec, block *[16]vec) [8]vec {
+    w := *block
+    a, b, c, d, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+    for i := range 80 {
+        if i >= 16 {
+            w15, w2 := w[(i-15)&15], w[(i-2)&15]
+            s0 := rotr(w15, 1).Xor(rotr(w15, 8)).Xor(w15.ShiftAllRight(7))
+            s1 := rotr(w2, 19).Xor(rotr(w2, 61)).Xor(w2.ShiftAllRight(6))
+            w[i&15] = w[i&15].Add(s0).Add(w[(i-7)&15]).Add(s1)
+        }
+        s1 := rotr(e, 14).Xor(rotr(e, 18)).Xor(rotr(e, 41))
+        ch := e.And(f).Xor(g.AndNot(e))
+        t1 := h.Add(s1).Add(ch).Add(archsimd.BroadcastUint64x8(sha512K[i])).Add(w[i&15])
+        s0 := rotr(a, 28).Xor(rotr(a, 34)).Xor(rotr(a, 39))
+        maj := a.And(b).Xor(a.And(c)).Xor(b.And(c))
+        t2 := s0.Add(maj)
+        h, g, f, e, d, c, b, a = g, f, e, d.Add(t1), c, b, a, t1.Add(t2)
+    }
+    return [8]vec{
+        state[0].Add(a), state[1].Add(b), state[2].Add(c), state[3].Add(d),
+        state[4].Add(e), state[5].Add(f), state[6].Add(g), state[7].Add(h),
+    }
+}
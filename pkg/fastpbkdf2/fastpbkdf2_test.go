@@ -0,0 +1,94 @@
+package fastpbkdf2
+
+import (
+    "bytes"
+    "crypto/sha512"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "testing"
+
+    "golang.org/x/crypto/pbkdf2"
+)
+
+// 与 x/crypto 比较：密码短于、等于、长于一个块，盐为空或跨块，轮数 1、2、2048
+func TestKeyMatchesXCrypto(t *testing.T) {
+    var passwords, salts [][]byte
+    for _, pl := range []int{0, 1, 64, 127, 128, 129, 300} {
+        for _, sl := range []int{0, 8, 120, 250} {
+            passwords = append(passwords, bytes.Repeat([]byte{byte(pl + 1)}, pl))
+            salts = append(salts, bytes.Repeat([]byte{byte(sl + 7)}, sl))
+        }
+    }
+    for _, iter := range []int{1, 2, 2048} {
+        batch := Keys(passwords, salts, iter)
+        for i := range passwords {
+            want := pbkdf2.Key(passwords[i], salts[i], iter, Size, sha512.New)
+            if got := Key(passwords[i], salts[i], iter); !bytes.Equal(got, want) {
+                t.Errorf("Key(%d-byte password, %d-byte salt, %d) = %x, want %x", len(passwords[i]), len(salts[i]), iter, got, want)
+            }
+            if !bytes.Equal(batch[i], want) {
+                t.Errorf("Keys[%d] (%d-byte password, %d-byte salt, %d) = %x, want %x", i, len(passwords[i]), len(salts[i]), iter, batch[i], want)
+            }
+        }
+    }
+}
+
+// BIP39 官方向量（口令 TREZOR）；一次传入全部 24 个，覆盖整组并行与余下的单个
+func TestBIP39Vectors(t *testing.T) {
+    data, err := os.ReadFile("../../embed/bip39-vectors.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    var vectors map[string][][]string
+    if err := json.Unmarshal(data, &vectors); err != nil {
+        t.Fatal(err)
+    }
+    var passwords, salts [][]byte
+    var seeds []string
+    for _, v := range vectors["english"] {
+        passwords = append(passwords, []byte(v[1]))
+        salts = append(salts, []byte("mnemonicTREZOR"))
+        seeds = append(seeds, v[2])
+    }
+    if len(seeds) != 24 {
+        t.Fatalf("got %d vectors, want 24", len(seeds))
+    }
+    for i, got := range Keys(passwords, salts, 2048) {
+        if hex.EncodeToString(got) != seeds[i] {
+            t.Errorf("Keys: vector %d: %x, want %s", i, got, seeds[i])
+        }
+        if got := hex.EncodeToString(Key(passwords[i], salts[i], 2048)); got != seeds[i] {
+            t.Errorf("Key: vector %d: %s, want %s", i, got, seeds[i])
+        }
+    }
+}
+
+func TestKeysLengthMismatch(t *testing.T) {
+    defer func() {
+        if recover() == nil {
+            t.Error("Keys did not panic on 2 passwords and 1 salt")
+        }
+    }()
+    Keys(make([][]byte, 2), make([][]byte, 1), 1)
+}
+
+func BenchmarkKey(b *testing.B) {
+    password, salt := []byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"), []byte("mnemonic")
+    for b.Loop() {
+        Key(password, salt, 2048)
+    }
+}
+
+func BenchmarkKeys(b *testing.B) {
+    passwords, salts := make([][]byte, 8), make([][]byte, 8)
+    for i := range passwords {
+        passwords[i] = []byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+        salts[i] = []byte(fmt.Sprint("mnemonic", i))
+    }
+    for b.Loop() {
+        Keys(passwords, salts, 2048)
+    }
+    b.ReportMetric(float64(b.N*len(passwords))/b.Elapsed().Seconds(), "keys/s")
+}
@@ -0,0 +1,90 @@
+// Package framebuffer draws monochrome pictures (QR codes, word tables) on a
+// Linux framebuffer device such as /dev/fb0, bypassing terminal emulators.
+// E-ink panels driven through fbdev (fbtft and similar drivers) appear as
+// /dev/fbN as well.
+//
+// Canvas and the built-in 5×7 font are portable; Open only works on Linux.
+package framebuffer
+
+import "fmt"
+
+// Canvas is a black-on-white bitmap. The zero pixel is white.
+type Canvas struct {
+    Width, Height int
+    pix           []bool
+}
+
+// NewCanvas returns a white canvas of w×h pixels.
+func NewCanvas(w, h int) *Canvas {
+    return &Canvas{Width: w, Height: h, pix: make([]bool, w*h)}
+}
+
+// At reports whether the pixel at (x, y) is black. Pixels outside the canvas
+// are white.
+func (c *Canvas) At(x, y int) bool {
+    if x < 0 || y < 0 || x >= c.Width || y >= c.Height {
+        return false
+    }
+    return c.pix[y*c.Width+x]
+}
+
+// FillRect blackens the w×h rectangle at (x, y), clipped to the canvas.
+func (c *Canvas) FillRect(x, y, w, h int) {
+    for yy := max(y, 0); yy < min(y+h, c.Height); yy++ {
+        for xx := max(x, 0); xx < min(x+w, c.Width); xx++ {
+            c.pix[yy*c.Width+xx] = true
+        }
+    }
+}
+
+// Bitmap draws bm (true = black, indexed [y][x]) at (x, y), each module
+// scale×scale pixels, as returned by a QR encoder.
+func (c *Canvas) Bitmap(x, y, scale int, bm [][]bool) {
+    for row, line := range bm {
+        for col, black := range line {
+            if black {
+                c.FillRect(x+col*scale, y+row*scale, scale, scale)
+            }
+        }
+    }
+}
+
+// Text draws s with its top-left corner at (x, y), each font pixel
+// scale×scale. It fails on characters the built-in font does not have.
+func (c *Canvas) Text(x, y, scale int, s string) error {
+    for _, r := range s {
+        g, ok := glyphs[r]
+        if !ok {
+            return fmt.Errorf("framebuffer: no glyph for %q", r)
+        }
+        for row, bits := range g {
+            for col := 0; col < GlyphWidth; col++ {
+                if bits&(1<<(GlyphWidth-1-col)) != 0 {
+                    c.FillRect(x+col*scale, y+row*scale, scale, scale)
+                }
+            }
+        }
+        x += (GlyphWidth + 1) * scale
+    }
+    return nil
+}
+
+// TextWidth returns the width in pixels of s drawn at scale, including the
+// one-pixel gap after each character.
+func TextWidth(s string, scale int) int {
+    n := 0
+    for range s {
+        n++
+    }
+    return n * (GlyphWidth + 1) * scale
+}
+
+// CanDraw reports whether every character of s is in the built-in font.
+func CanDraw(s string) bool {
+    for _, r := range s {
+        if _, ok := glyphs[r]; !ok {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,145 @@
+//go:build linux
+
+package framebuffer
+
+import (
+    "encoding/binary"
+    "fmt"
+    "os"
+    "unsafe"
+
+    "golang.org/x/sys/unix"
+)
+
+// ioctl requests from <linux/fb.h>.
+const (
+    fbiogetVScreenInfo = 0x4600
+    fbiogetFScreenInfo = 0x4602
+
+    fbVisualMono01 = 0 // 1 bpp, set bit is black
+)
+
+type bitfield struct{ offset, length uint32 }
+
+// Device is an open framebuffer.
+type Device struct {
+    Width, Height int
+
+    f          *os.File
+    bpp        int
+    lineLength int
+    start      int64 // byte offset of the visible area (y panning)
+    mono01     bool
+    red        bitfield
+    green      bitfield
+    blue       bitfield
+}
+
+// Open opens a framebuffer device such as /dev/fb0 and reads its geometry.
+// 1, 8, 16, 24 and 32 bits per pixel are supported.
+func Open(path string) (*Device, error) {
+    f, err := os.OpenFile(path, os.O_RDWR, 0)
+    if err != nil {
+        return nil, err
+    }
+    var vinfo [160]byte
+    var finfo [80]byte
+    if err := ioctl(f, fbiogetVScreenInfo, vinfo[:]); err != nil {
+        f.Close()
+        return nil, fmt.Errorf("framebuffer: %s: %w", path, err)
+    }
+    if err := ioctl(f, fbiogetFScreenInfo, finfo[:]); err != nil {
+        f.Close()
+        return nil, fmt.Errorf("framebuffer: %s: %w", path, err)
+    }
+    u32 := func(b []byte, off int) uint32 { return binary.NativeEndian.Uint32(b[off:]) }
+
+    // fb_fix_screeninfo: char id[16]; unsigned long smem_start; __u32 smem_len,
+    // type, type_aux, visual; __u16 xpanstep, ypanstep, ywrapstep; __u32 line_length
+    ptr := int(unsafe.Sizeof(uintptr(0)))
+    visual := 16 + ptr + 12
+    lineLength := (visual + 4 + 6 + 3) &^ 3
+
+    d := &Device{
+        Width:      int(u32(vinfo[:], 0)),
+        Height:     int(u32(vinfo[:], 4)),
+        f:          f,
+        bpp:        int(u32(vinfo[:], 24)),
+        lineLength: int(u32(finfo[:], lineLength)),
+        mono01:     u32(finfo[:], visual) == fbVisualMono01,
+        red:        bitfield{u32(vinfo[:], 32), u32(vinfo[:], 36)},
+        green:      bitfield{u32(vinfo[:], 44), u32(vinfo[:], 48)},
+        blue:       bitfield{u32(vinfo[:], 56), u32(vinfo[:], 60)},
+    }
+    d.start = int64(u32(vinfo[:], 20)) * int64(d.lineLength)
+    switch d.bpp {
+    case 1, 8, 16, 24, 32:
+    default:
+        f.Close()
+        return nil, fmt.Errorf("framebuffer: %s: %d bits per pixel is not supported", path, d.bpp)
+    }
+    if d.Width == 0 || d.Height == 0 || d.lineLength*8 < d.Width*d.bpp {
+        f.Close()
+        return nil, fmt.Errorf("framebuffer: %s: unusable geometry %dx%d", path, d.Width, d.Height)
+    }
+    return d, nil
+}
+
+func ioctl(f *os.File, req uintptr, buf []byte) error {
+    _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&buf[0])))
+    if errno != 0 {
+        return errno
+    }
+    return nil
+}
+
+// Draw copies c to the visible screen. c is clipped or padded with white to
+// the device size.
+func (d *Device) Draw(c *Canvas) error {
+    buf := make([]byte, d.lineLength*d.Height)
+    white := d.white()
+    bytesPP := d.bpp / 8
+    for y := 0; y < d.Height; y++ {
+        line := buf[y*d.lineLength:]
+        for x := 0; x < d.Width; x++ {
+            black := c.At(x, y)
+            if d.bpp == 1 {
+                if black == d.mono01 {
+                    line[x/8] |= 0x80 >> (x % 8)
+                }
+                continue
+            }
+            v := uint32(0)
+            if !black {
+                v = white
+            }
+            for i := 0; i < bytesPP; i++ {
+                line[x*bytesPP+i] = byte(v >> (8 * i))
+            }
+        }
+    }
+    _, err := d.f.WriteAt(buf, d.start)
+    return err
+}
+
+// Clear paints the visible screen white.
+func (d *Device) Clear() error {
+    return d.Draw(NewCanvas(0, 0))
+}
+
+// Close closes the device. The picture stays on screen until overwritten.
+func (d *Device) Close() error {
+    return d.f.Close()
+}
+
+// White pixel value for 8 to 32 bits per pixel.
+func (d *Device) white() uint32 {
+    if d.bpp == 8 {
+        return 0xff
+    }
+    var v uint32
+    for _, bf := range []bitfield{d.red, d.green, d.blue} {
+        v |= (1<<bf.length - 1) << bf.offset
+    }
+    return v
+}
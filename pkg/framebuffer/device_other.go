@@ -0,0 +1,24 @@
+//go:build !linux
+
+package framebuffer
+
+import "errors"
+
+// Device is an open framebuffer.
+type Device struct {
+    Width, Height int
+}
+
+// Open always fails: framebuffer devices exist only on Linux.
+func Open(path string) (*Device, error) {
+    return nil, errors.New("framebuffer: only supported on Linux")
+}
+
+// Draw copies c to the visible screen.
+func (d *Device) Draw(c *Canvas) error { return errors.New("framebuffer: only supported on Linux") }
+
+// Clear paints the visible screen white.
+func (d *Device) Clear() error { return errors.New("framebuffer: only supported on Linux") }
+
+// Close closes the device.
+func (d *Device) Close() error { return nil }
@@ -0,0 +1,173 @@
+// Package rnghealth runs continuous health tests on a stream of random
+// bytes, for validating an entropy source (a new hardware RNG, say) before
+// trusting it with seed generation.
+//
+// Each byte is one sample. The repetition count and adaptive proportion
+// tests follow NIST SP 800-90B section 4.4, with cutoffs derived from the
+// claimed min-entropy per byte and a false-alarm rate of 2^-40 per test, so
+// a healthy source practically never trips them even in long soak runs. A
+// chi-square test of the byte frequencies over each 1 MiB block catches
+// bias that is too small for the per-sample tests.
+//
+// SampleTest and EntropyTest are one-shot checks for a single generation:
+// the FIPS 140-2 statistical tests on a 20,000-bit sample, and a sanity
+// check of the secret itself.
+package rnghealth
+
+import (
+    "fmt"
+    "math"
+)
+
+// Window of the adaptive proportion test (SP 800-90B, non-binary sources).
+const APTWindow = 512
+
+// BlockSize is the number of bytes in each chi-square block.
+const BlockSize = 1 << 20
+
+// Per-test false-alarm probability, 2^-40.
+var alpha = math.Ldexp(1, -40)
+
+// Failure is one failed test.
+type Failure struct {
+    Test   string // "repetition", "proportion" or "chi-square"
+    Offset int64  // byte offset in the stream where the failure was detected
+    Detail string
+}
+
+func (f Failure) String() string {
+    return fmt.Sprintf("%s test failed at byte %d: %s", f.Test, f.Offset, f.Detail)
+}
+
+// Monitor holds the state of the three tests. The zero value is not usable;
+// create one with New.
+type Monitor struct {
+    MinEntropy float64 // claimed min-entropy per byte, in bits
+    RCTCutoff  int     // a run of this many identical bytes fails
+    APTCutoff  int     // this many copies of the first byte in a window fails
+
+    offset int64
+
+    rctByte byte
+    rctRun  int
+
+    aptByte  byte
+    aptCount int
+    aptPos   int
+
+    counts   [256]int
+    inBlock  int
+    chiLimit float64
+
+    // Failures by test name.
+    Counts map[string]int
+}
+
+// New returns a monitor for a source claimed to give minEntropy bits per
+// byte (8 for a full-entropy source; less for a raw, unconditioned noise
+// source).
+func New(minEntropy float64) (*Monitor, error) {
+    if minEntropy <= 0 || minEntropy > 8 {
+        return nil, fmt.Errorf("rnghealth: min-entropy %.2f is outside (0, 8] bits per byte", minEntropy)
+    }
+    p := math.Exp2(-minEntropy)
+    return &Monitor{
+        MinEntropy: minEntropy,
+        RCTCutoff:  1 + int(math.Ceil(40/minEntropy)),
+        APTCutoff:  binomialCutoff(APTWindow-1, p) + 1,
+        chiLimit:   chiSquareCritical(255, 40),
+        Counts:     map[string]int{},
+    }, nil
+}
+
+// Bytes returns the number of bytes tested so far.
+func (m *Monitor) Bytes() int64 {
+    return m.offset
+}
+
+// Feed tests p and returns the failures it caused.
+func (m *Monitor) Feed(p []byte) []Failure {
+    var fails []Failure
+    fail := func(test, format string, args ...any) {
+        m.Counts[test]++
+        fails = append(fails, Failure{Test: test, Offset: m.offset, Detail: fmt.Sprintf(format, args...)})
+    }
+    for _, b := range p {
+        // Repetition count: the same byte RCTCutoff times in a row.
+        if m.offset > 0 && b == m.rctByte {
+            m.rctRun++
+            if m.rctRun == m.RCTCutoff {
+                fail("repetition", "byte %02x repeated %d times in a row", b, m.rctRun)
+            }
+        } else {
+            m.rctByte, m.rctRun = b, 1
+        }
+
+        // Adaptive proportion: how often the first byte of a window recurs
+        // in the window.
+        if m.aptPos == 0 {
+            m.aptByte, m.aptCount = b, 1
+        } else if b == m.aptByte {
+            m.aptCount++
+            if m.aptCount == m.APTCutoff {
+                fail("proportion", "byte %02x appeared %d times in a %d-byte window", b, m.aptCount, APTWindow)
+            }
+        }
+        m.aptPos = (m.aptPos + 1) % APTWindow
+
+        m.counts[b]++
+        m.inBlock++
+        if m.inBlock == BlockSize {
+            if chi := m.chiSquare(); chi > m.chiLimit {
+                fail("chi-square", "byte frequencies over the last %d bytes give chi-square %.0f (limit %.0f, 255 degrees of freedom)", BlockSize, chi, m.chiLimit)
+            }
+            m.counts, m.inBlock = [256]int{}, 0
+        }
+        m.offset++
+    }
+    return fails
+}
+
+func (m *Monitor) chiSquare() float64 {
+    expected := float64(m.inBlock) / 256
+    chi := 0.0
+    for _, c := range m.counts {
+        d := float64(c) - expected
+        chi += d * d / expected
+    }
+    return chi
+}
+
+// binomialCutoff returns the smallest c with P(Binomial(n, p) ≥ c) ≤ alpha.
+func binomialCutoff(n int, p float64) int {
+    tail := 0.0
+    for k := n; k >= 0; k-- {
+        term := math.Exp(lchoose(n, k) + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+        if tail+term > alpha {
+            return k + 1
+        }
+        tail += term
+    }
+    return 0
+}
+
+func lchoose(n, k int) float64 {
+    a, _ := math.Lgamma(float64(n + 1))
+    b, _ := math.Lgamma(float64(k + 1))
+    c, _ := math.Lgamma(float64(n - k + 1))
+    return a - b - c
+}
+
+// chiSquareCritical returns the value a chi-square variable with df degrees
+// of freedom exceeds with probability 2^-bits (Wilson–Hilferty approximation).
+func chiSquareCritical(df int, bits float64) float64 {
+    z := normalQuantile(math.Ldexp(1, -int(bits)))
+    k := float64(df)
+    t := 1 - 2/(9*k) + z*math.Sqrt(2/(9*k))
+    return k * t * t * t
+}
+
+// normalQuantile returns z with P(N(0, 1) > z) = q.
+func normalQuantile(q float64) float64 {
+    return math.Sqrt2 * math.Erfcinv(2*q)
+}
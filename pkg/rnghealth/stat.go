@@ -0,0 +1,116 @@
+package rnghealth
+
+import (
+    "fmt"
+    "math"
+    "math/bits"
+)
+
+// SampleBytes is the sample size of SampleTest: the 20,000 bits of the
+// FIPS 140-2 statistical tests.
+const SampleBytes = 2500
+
+// FIPS 140-2 (change notice 1) acceptance intervals for the runs test, for
+// runs of length 1 to 5 and 6 or more, of zeros and of ones alike.
+var runBounds = [6][2]int{{2315, 2685}, {1114, 1386}, {527, 723}, {240, 384}, {103, 209}, {103, 209}}
+
+// SampleTest runs the FIPS 140-2 monobit, poker, runs and long-run tests on
+// exactly SampleBytes bytes. A healthy generator fails about once in ten
+// thousand samples, so a single failure should be confirmed on a fresh
+// sample before the generator is declared broken.
+func SampleTest(sample []byte) ([]Failure, error) {
+    if len(sample) != SampleBytes {
+        return nil, fmt.Errorf("rnghealth: sample has %d bytes, need %d", len(sample), SampleBytes)
+    }
+    var fails []Failure
+    fail := func(test, format string, args ...any) {
+        fails = append(fails, Failure{Test: test, Detail: fmt.Sprintf(format, args...)})
+    }
+
+    ones := 0
+    var nibbles [16]int
+    for _, b := range sample {
+        ones += bits.OnesCount8(b)
+        nibbles[b>>4]++
+        nibbles[b&0xf]++
+    }
+    if ones <= 9725 || ones >= 10275 {
+        fail("monobit", "%d one bits in 20000 (allowed 9726–10274)", ones)
+    }
+
+    sum := 0.0
+    for _, f := range nibbles {
+        sum += float64(f) * float64(f)
+    }
+    if x := 16.0/5000*sum - 5000; x <= 2.16 || x >= 46.17 {
+        fail("poker", "X = %.2f (allowed 2.16–46.17)", x)
+    }
+
+    var runs [2][6]int
+    longest := 0
+    forEachRun(sample, func(bit, n int) {
+        runs[bit][min(n, 6)-1]++
+        longest = max(longest, n)
+    })
+    for bit := range runs {
+        for i, n := range runs[bit] {
+            if lo, hi := runBounds[i][0], runBounds[i][1]; n < lo || n > hi {
+                length := fmt.Sprint(i + 1)
+                if i == 5 {
+                    length = "6+"
+                }
+                fail("runs", "%d runs of %s %ds (allowed %d–%d)", n, length, bit, lo, hi)
+            }
+        }
+    }
+    if longest >= 26 {
+        fail("long run", "a run of %d equal bits (allowed up to 25)", longest)
+    }
+    return fails, nil
+}
+
+// EntropyTest checks one secret of 128 to 256 bits for gross anomalies: far
+// too many or too few one bits, or a very long run of equal bits. Its limits
+// have a false-alarm rate of 2^-40, so only a stuck or badly broken source
+// trips them; a passing secret is not thereby shown to be random.
+func EntropyTest(entropy []byte) []Failure {
+    var fails []Failure
+    n := len(entropy) * 8
+    ones := 0
+    for _, b := range entropy {
+        ones += bits.OnesCount8(b)
+    }
+    if c := binomialCutoff(n, 0.5); ones >= c || ones <= n-c {
+        fails = append(fails, Failure{Test: "monobit", Detail: fmt.Sprintf("%d one bits in %d (allowed %d–%d)", ones, n, n-c+1, c-1)})
+    }
+    // Some run of k equal bits somewhere in n bits has probability below n·2^-(k-1).
+    limit := 1 + int(math.Ceil(-math.Log2(alpha)+math.Log2(float64(n))))
+    longest := 0
+    forEachRun(entropy, func(bit, n int) { longest = max(longest, n) })
+    if longest >= limit {
+        fails = append(fails, Failure{Test: "long run", Detail: fmt.Sprintf("a run of %d equal bits (allowed up to %d)", longest, limit-1)})
+    }
+    return fails
+}
+
+// forEachRun calls f with the bit value and length of each maximal run of
+// equal bits in p, most significant bit first.
+func forEachRun(p []byte, f func(bit, n int)) {
+    cur, n := -1, 0
+    for _, b := range p {
+        for i := 7; i >= 0; i-- {
+            bit := int(b>>i) & 1
+            if bit == cur {
+                n++
+                continue
+            }
+            if n > 0 {
+                f(cur, n)
+            }
+            cur, n = bit, 1
+        }
+    }
+    if n > 0 {
+        f(cur, n)
+    }
+}
@@ -0,0 +1,69 @@
+package rnghealth
+
+import (
+    "bytes"
+    "math/rand/v2"
+    "testing"
+)
+
+// 固定种子的 ChaCha8：结果确定，且通过全部检验
+func chachaBytes(n int) []byte {
+    b := make([]byte, n)
+    rand.NewChaCha8([32]byte{1}).Read(b)
+    return b
+}
+
+func TestSampleTest(t *testing.T) {
+    biased := chachaBytes(SampleBytes)
+    for i := range biased[:1000] {
+        biased[i] |= 0x01
+    }
+    for _, tc := range []struct {
+        name   string
+        sample []byte
+        want   []string
+    }{
+        {"chacha8", chachaBytes(SampleBytes), nil},
+        {"zeros", make([]byte, SampleBytes), []string{"monobit", "poker", "runs", "long run"}},
+        {"alternating", bytes.Repeat([]byte{0x55}, SampleBytes), []string{"poker", "runs"}},
+        {"biased", biased, []string{"monobit"}},
+    } {
+        fails, err := SampleTest(tc.sample)
+        if err != nil {
+            t.Fatal(err)
+        }
+        got := map[string]bool{}
+        for _, f := range fails {
+            got[f.Test] = true
+        }
+        for _, w := range tc.want {
+            if !got[w] {
+                t.Errorf("%s: %s test passed, want failure", tc.name, w)
+            }
+        }
+        if tc.want == nil && len(fails) > 0 {
+            t.Errorf("%s: unexpected failures %v", tc.name, fails)
+        }
+    }
+    if _, err := SampleTest(make([]byte, 32)); err == nil {
+        t.Error("SampleTest accepted 32 bytes")
+    }
+}
+
+func TestEntropyTest(t *testing.T) {
+    for _, n := range []int{16, 32} {
+        if fails := EntropyTest(chachaBytes(n)); len(fails) > 0 {
+            t.Errorf("%d random bytes: %v", n, fails)
+        }
+        if fails := EntropyTest(make([]byte, n)); len(fails) != 2 {
+            t.Errorf("%d zero bytes: got %v, want monobit and long run", n, fails)
+        }
+        // 演示熵 7f…：七成以上是 1
+        if fails := EntropyTest(bytes.Repeat([]byte{0x7f}, n)); len(fails) != 1 || fails[0].Test != "monobit" {
+            t.Errorf("%d bytes of 7f: got %v, want monobit", n, fails)
+        }
+        if fails := EntropyTest(bytes.Repeat([]byte{0x5a}, n)); len(fails) != 0 {
+            t.Errorf("%d bytes of 5a: %v (balanced, short runs: not a gross anomaly)", n, fails)
+        }
+    }
+}
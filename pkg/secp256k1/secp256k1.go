@@ -0,0 +1,145 @@
+// Package secp256k1 implements the few secp256k1 curve operations needed for
+// BIP32 key derivation and address encoding, on top of the field and scalar
+// arithmetic of github.com/decred/dcrd/dcrec/secp256k1/v4.
+//
+// Private keys never pass through math/big: they are reduced and added as
+// fixed-width scalars, and k·G adds one precomputed point per byte of k, a
+// fixed sequence of 32 additions rather than a double-and-add loop that
+// branches on every bit. Points are public and are returned with math/big
+// coordinates for the callers' convenience.
+package secp256k1
+
+import (
+    "errors"
+    "math/big"
+
+    decred "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+var (
+    // P is the field prime, N the group order.
+    P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+    N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+    gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+    gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+
+    // G is the generator point.
+    G = Point{X: gx, Y: gy}
+)
+
+// Point is an affine curve point. The point at infinity has nil coordinates.
+type Point struct {
+    X, Y *big.Int
+}
+
+// IsInfinity reports whether p is the point at infinity.
+func (p Point) IsInfinity() bool {
+    return p.X == nil
+}
+
+// 无穷远点在 decred 中是 Z = 0（或 X = Y = 0）
+func toJacobian(p Point) *decred.JacobianPoint {
+    var j decred.JacobianPoint
+    if p.IsInfinity() {
+        return &j
+    }
+    j.X.SetByteSlice(p.X.Bytes())
+    j.Y.SetByteSlice(p.Y.Bytes())
+    j.Z.SetInt(1)
+    return &j
+}
+
+func fromJacobian(j *decred.JacobianPoint) Point {
+    if j.Z.IsZero() || j.X.IsZero() && j.Y.IsZero() {
+        return Point{}
+    }
+    j.ToAffine()
+    return Point{X: new(big.Int).SetBytes(j.X.Bytes()[:]), Y: new(big.Int).SetBytes(j.Y.Bytes()[:])}
+}
+
+// k 是不超过 32 字节的大端整数，取模 N
+func scalar(k []byte) *decred.ModNScalar {
+    var s decred.ModNScalar
+    s.SetByteSlice(k)
+    return &s
+}
+
+// ScalarMult returns k·p.
+func ScalarMult(p Point, k []byte) Point {
+    var r decred.JacobianPoint
+    decred.ScalarMultNonConst(scalar(k), toJacobian(p), &r)
+    return fromJacobian(&r)
+}
+
+// ScalarBaseMult returns k·G.
+func ScalarBaseMult(k []byte) Point {
+    var r decred.JacobianPoint
+    decred.ScalarBaseMultNonConst(scalar(k), &r)
+    return fromJacobian(&r)
+}
+
+// Add returns p + q.
+func Add(p, q Point) Point {
+    var r decred.JacobianPoint
+    decred.AddNonConst(toJacobian(p), toJacobian(q), &r)
+    return fromJacobian(&r)
+}
+
+// ValidScalar reports whether the 32-byte big-endian k is a valid private key
+// or tweak, that is 0 < k < N.
+func ValidScalar(k []byte) bool {
+    if len(k) != 32 {
+        return false
+    }
+    var s decred.ModNScalar
+    overflow := s.SetByteSlice(k)
+    return !overflow && !s.IsZero()
+}
+
+// Overflows reports whether the 32-byte big-endian k is not below N, as BIP32
+// and BIP341 require of their tweaks. Zero does not overflow.
+func Overflows(k []byte) bool {
+    var s decred.ModNScalar
+    return len(k) != 32 || s.SetByteSlice(k)
+}
+
+// AddScalars returns (a + b) mod N as 32 big-endian bytes. The result may be
+// zero; check it with ValidScalar.
+func AddScalars(a, b []byte) []byte {
+    sum := scalar(a).Add(scalar(b)).Bytes()
+    return sum[:]
+}
+
+// Compress returns the 33-byte SEC1 compressed encoding of p.
+func Compress(p Point) []byte {
+    out := make([]byte, 33)
+    out[0] = 0x02 + byte(p.Y.Bit(0))
+    p.X.FillBytes(out[1:])
+    return out
+}
+
+// Decompress parses a 33-byte SEC1 compressed point.
+func Decompress(b []byte) (Point, error) {
+    if len(b) != 33 || (b[0] != 0x02 && b[0] != 0x03) {
+        return Point{}, errors.New("secp256k1: invalid compressed point")
+    }
+    pub, err := decred.ParsePubKey(b)
+    if err != nil {
+        return Point{}, errors.New("secp256k1: point not on curve")
+    }
+    return Point{X: pub.X(), Y: pub.Y()}, nil
+}
+
+// LiftX returns the point with x-coordinate x and even y (BIP340).
+func LiftX(x *big.Int) (Point, error) {
+    if x.Sign() < 0 || x.Cmp(P) >= 0 {
+        return Point{}, errors.New("secp256k1: x out of range")
+    }
+    var fx, fy decred.FieldVal
+    fx.SetByteSlice(x.Bytes())
+    if !decred.DecompressY(&fx, false, &fy) {
+        return Point{}, errors.New("secp256k1: point not on curve")
+    }
+    return Point{X: new(big.Int).Set(x), Y: new(big.Int).SetBytes(fy.Bytes()[:])}, nil
+}
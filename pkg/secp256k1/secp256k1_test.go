@@ -0,0 +1,159 @@
+package secp256k1
+
+import (
+    "bytes"
+    "math/big"
+    "testing"
+)
+
+func hexInt(s string) *big.Int {
+    n, ok := new(big.Int).SetString(s, 16)
+    if !ok {
+        panic("bad hex " + s)
+    }
+    return n
+}
+
+// k·G 的已知结果
+func TestScalarBaseMult(t *testing.T) {
+    tests := []struct {
+        k, x, y string
+    }{
+        {"01", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+        {"02", "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+        {"03", "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9", "388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+        {"aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522", "34f9460f0e4f08393d192b3c5133a6ba099aa0ad9fd54ebccfacdfa239ff49c6", "0b71ea9bd730fd8923f6d25a7a91e7dd7728a960686cb5a901bb419e0f2ca232"},
+        // (N-1)·G = -G
+        {"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777"},
+    }
+    for _, tt := range tests {
+        p := ScalarBaseMult(hexInt(tt.k).Bytes())
+        if p.IsInfinity() || p.X.Cmp(hexInt(tt.x)) != 0 || p.Y.Cmp(hexInt(tt.y)) != 0 {
+            t.Errorf("%s·G = (%x, %x), want (%s, %s)", tt.k, p.X, p.Y, tt.x, tt.y)
+        }
+    }
+    if p := ScalarBaseMult(N.Bytes()); !p.IsInfinity() {
+        t.Errorf("N·G = (%x, %x), want infinity", p.X, p.Y)
+    }
+}
+
+func TestAdd(t *testing.T) {
+    two := ScalarBaseMult([]byte{2})
+    three := ScalarBaseMult([]byte{3})
+    if got := Add(G, two); got.X.Cmp(three.X) != 0 || got.Y.Cmp(three.Y) != 0 {
+        t.Errorf("G + 2G != 3G")
+    }
+    if got := Add(G, G); got.X.Cmp(two.X) != 0 || got.Y.Cmp(two.Y) != 0 {
+        t.Errorf("G + G != 2G")
+    }
+    neg := Point{X: G.X, Y: new(big.Int).Sub(P, G.Y)}
+    if got := Add(G, neg); !got.IsInfinity() {
+        t.Errorf("G + (-G) is not infinity")
+    }
+    if got := Add(G, Point{}); got.X.Cmp(G.X) != 0 || got.Y.Cmp(G.Y) != 0 {
+        t.Errorf("G + infinity != G")
+    }
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+    for _, k := range []string{"01", "02", "03", "aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522"} {
+        p := ScalarBaseMult(hexInt(k).Bytes())
+        b := Compress(p)
+        q, err := Decompress(b)
+        if err != nil {
+            t.Fatalf("Decompress(%x): %v", b, err)
+        }
+        if q.X.Cmp(p.X) != 0 || q.Y.Cmp(p.Y) != 0 {
+            t.Errorf("Decompress(Compress(%s·G)) differs", k)
+        }
+    }
+    want := hexInt("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798").Bytes()
+    if got := Compress(G); !bytes.Equal(got, want) {
+        t.Errorf("Compress(G) = %x", got)
+    }
+
+    // x = 5 时 x³+7 = 132 不是二次剩余
+    bad := make([]byte, 33)
+    bad[0], bad[32] = 0x02, 5
+    if _, err := Decompress(bad); err == nil {
+        t.Error("Decompress accepted a point not on the curve")
+    }
+    if _, err := Decompress(append([]byte{0x04}, G.X.FillBytes(make([]byte, 32))...)); err == nil {
+        t.Error("Decompress accepted an uncompressed prefix")
+    }
+}
+
+func TestLiftX(t *testing.T) {
+    p, err := LiftX(G.X)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p.Y.Bit(0) != 0 || p.Y.Cmp(G.Y) != 0 {
+        t.Errorf("LiftX(Gx).Y = %x, want even %x", p.Y, G.Y)
+    }
+    if _, err := LiftX(P); err == nil {
+        t.Error("LiftX accepted x = P")
+    }
+}
+
+func TestScalars(t *testing.T) {
+    n := N.FillBytes(make([]byte, 32))
+    nMinus1 := new(big.Int).Sub(N, big.NewInt(1)).FillBytes(make([]byte, 32))
+    zero := make([]byte, 32)
+    one := big.NewInt(1).FillBytes(make([]byte, 32))
+    max := bytes.Repeat([]byte{0xff}, 32)
+
+    for _, tt := range []struct {
+        name             string
+        k                []byte
+        valid, overflows bool
+    }{
+        {"zero", zero, false, false},
+        {"one", one, true, false},
+        {"N-1", nMinus1, true, false},
+        {"N", n, false, true},
+        {"2^256-1", max, false, true},
+        {"short", []byte{1}, false, true},
+    } {
+        if got := ValidScalar(tt.k); got != tt.valid {
+            t.Errorf("ValidScalar(%s) = %v", tt.name, got)
+        }
+        if got := Overflows(tt.k); got != tt.overflows {
+            t.Errorf("Overflows(%s) = %v", tt.name, got)
+        }
+    }
+
+    // 与 math/big 的结果比较
+    a := hexInt("aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522").FillBytes(make([]byte, 32))
+    for _, b := range [][]byte{zero, one, nMinus1, a, hexInt("7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0").FillBytes(make([]byte, 32))} {
+        want := new(big.Int).Add(new(big.Int).SetBytes(a), new(big.Int).SetBytes(b))
+        want.Mod(want, N)
+        if got := AddScalars(a, b); !bytes.Equal(got, want.FillBytes(make([]byte, 32))) {
+            t.Errorf("AddScalars(a, %x) = %x, want %x", b, got, want)
+        }
+    }
+    if got := AddScalars(one, nMinus1); !bytes.Equal(got, zero) || ValidScalar(got) {
+        t.Errorf("1 + (N-1) = %x, want 0", got)
+    }
+}
+
+// k·P 与 k·G 一致，(a+b)·G = a·G + b·G
+func TestScalarMult(t *testing.T) {
+    k := hexInt("aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522").Bytes()
+    if p, q := ScalarMult(G, k), ScalarBaseMult(k); p.X.Cmp(q.X) != 0 || p.Y.Cmp(q.Y) != 0 {
+        t.Error("ScalarMult(G, k) != ScalarBaseMult(k)")
+    }
+    two := ScalarBaseMult([]byte{2})
+    six := ScalarBaseMult([]byte{6})
+    if p := ScalarMult(two, []byte{3}); p.X.Cmp(six.X) != 0 || p.Y.Cmp(six.Y) != 0 {
+        t.Error("3·(2G) != 6G")
+    }
+    if p := ScalarMult(Point{}, k); !p.IsInfinity() {
+        t.Error("k·infinity is not infinity")
+    }
+    a, b := k, hexInt("0123456789abcdef").FillBytes(make([]byte, 32))
+    sum := ScalarBaseMult(AddScalars(a, b))
+    if p := Add(ScalarBaseMult(a), ScalarBaseMult(b)); p.X.Cmp(sum.X) != 0 || p.Y.Cmp(sum.Y) != 0 {
+        t.Error("(a+b)·G != a·G + b·G")
+    }
+}
@@ -0,0 +1,66 @@
+// Package secretcmp compares secrets — entropy, seeds, word indices, MACs and
+// checksums derived from them — in time that does not depend on the position
+// of the first difference.
+//
+// Lengths are treated as public: inputs of different length compare unequal
+// at once. Word counts and entropy sizes are visible anyway, and hiding them
+// would need padding that callers cannot do in general.
+//
+// Code that embeds these checks in a server should use these helpers instead
+// of ==, bytes.Equal or slices.Equal on anything derived from a mnemonic.
+package secretcmp
+
+import (
+    "crypto/subtle"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+// Equal reports whether a and b hold the same bytes.
+func Equal(a, b []byte) bool {
+    return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString reports whether a and b are the same string.
+func EqualString(a, b string) bool {
+    return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// EqualBits reports whether a and b hold the same bits.
+func EqualBits(a, b []bool) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    var v uint8
+    for i := range a {
+        v |= bit(a[i]) ^ bit(b[i])
+    }
+    return v == 0
+}
+
+// EqualInts reports whether a and b hold the same integers, for example
+// the word indices of two mnemonics.
+func EqualInts(a, b []int) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    var v uint64
+    for i := range a {
+        v |= uint64(a[i] ^ b[i])
+    }
+    return v == 0
+}
+
+// EqualMnemonic reports whether a and b are the same mnemonic after the
+// normalisation of textnorm.Mnemonic (NFKD, case, whitespace). The
+// normalisation itself is not constant-time; only the comparison is.
+func EqualMnemonic(a, b string) bool {
+    return EqualString(textnorm.Mnemonic(a), textnorm.Mnemonic(b))
+}
+
+func bit(b bool) uint8 {
+    if b {
+        return 1
+    }
+    return 0
+}
@@ -0,0 +1,34 @@
+package secretcmp
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+    tests := []struct {
+        name string
+        got  bool
+        want bool
+    }{
+        {"bytes same", Equal([]byte{1, 2, 3}, []byte{1, 2, 3}), true},
+        {"bytes differ", Equal([]byte{1, 2, 3}, []byte{1, 2, 4}), false},
+        {"bytes length", Equal([]byte{1, 2}, []byte{1, 2, 3}), false},
+        {"bytes empty", Equal(nil, []byte{}), true},
+        {"string same", EqualString("73c5da0a", "73c5da0a"), true},
+        {"string differ", EqualString("73c5da0a", "73c5da0b"), false},
+        {"bits same", EqualBits([]bool{true, false, true}, []bool{true, false, true}), true},
+        {"bits differ", EqualBits([]bool{true, false, true}, []bool{true, true, true}), false},
+        {"bits length", EqualBits([]bool{true}, []bool{true, false}), false},
+        {"ints same", EqualInts([]int{0, 2047, 3}, []int{0, 2047, 3}), true},
+        {"ints differ", EqualInts([]int{0, 2047, 3}, []int{0, 2046, 3}), false},
+        {"ints high bits", EqualInts([]int{1 << 40}, []int{0}), false},
+        {"ints length", EqualInts([]int{1}, []int{1, 1}), false},
+        // 大小写、多余空白和 NFKD 不影响比较
+        {"mnemonic normalised", EqualMnemonic("Abandon  ABOUT\n", "abandon about"), true},
+        {"mnemonic full-width", EqualMnemonic("ａｂａｎｄｏｎ about", "abandon about"), true},
+        {"mnemonic differ", EqualMnemonic("abandon about", "abandon above"), false},
+    }
+    for _, tt := range tests {
+        if tt.got != tt.want {
+            t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+        }
+    }
+}
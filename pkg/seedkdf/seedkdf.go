@@ -0,0 +1,171 @@
+// Package seedkdf turns a mnemonic and passphrase into a seed. BIP39 is the
+// only standard scheme; the others are experimental stretching functions for
+// non-Bitcoin uses and produce seeds that no wallet will recognise.
+//
+// Further schemes can be added with Register, e.g. from an init function in
+// a separate file, without touching the rest of the tool.
+package seedkdf
+
+import (
+    "fmt"
+    "sort"
+
+    "golang.org/x/crypto/argon2"
+    "golang.org/x/crypto/scrypt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/fastpbkdf2"
+)
+
+// KDF derives a 64-byte seed. Inputs are already NFKD-normalised.
+type KDF interface {
+    Seed(mnemonic, passphrase string) ([]byte, error)
+    // Standard reports whether seeds are BIP39 seeds usable by wallets.
+    Standard() bool
+    // Describe is a one-line summary including parameters.
+    Describe() string
+}
+
+// MemoryUser is implemented by memory-hard KDFs. MemoryBytes reports the
+// bytes one Seed call allocates, so callers can limit how many run in
+// parallel.
+type MemoryUser interface {
+    MemoryBytes() uint64
+}
+
+// Memory returns the bytes one Seed call of k allocates, or 0 if k is not
+// memory-hard.
+func Memory(k KDF) uint64 {
+    if m, ok := k.(MemoryUser); ok {
+        return m.MemoryBytes()
+    }
+    return 0
+}
+
+// BatchSeeder is implemented by KDFs that derive several seeds faster
+// together than one by one, e.g. in vector registers.
+type BatchSeeder interface {
+    Seeds(mnemonic string, passphrases []string) ([][]byte, error)
+    // BatchSize is the number of passphrases that fills one batch.
+    BatchSize() int
+}
+
+// Seeds derives the seed of mnemonic for each passphrase, in batches when
+// k is a BatchSeeder.
+func Seeds(k KDF, mnemonic string, passphrases []string) ([][]byte, error) {
+    if b, ok := k.(BatchSeeder); ok {
+        return b.Seeds(mnemonic, passphrases)
+    }
+    seeds := make([][]byte, len(passphrases))
+    for i, p := range passphrases {
+        seed, err := k.Seed(mnemonic, p)
+        if err != nil {
+            return nil, err
+        }
+        seeds[i] = seed
+    }
+    return seeds, nil
+}
+
+// BatchSize returns the number of passphrases that fills one batch of k,
+// or 1 if k is not a BatchSeeder.
+func BatchSize(k KDF) int {
+    if b, ok := k.(BatchSeeder); ok {
+        return b.BatchSize()
+    }
+    return 1
+}
+
+// Default is the name of the BIP39 KDF.
+const Default = "bip39"
+
+var registry = map[string]KDF{}
+
+// Register makes k available under name. It panics if name is taken.
+func Register(name string, k KDF) {
+    if _, ok := registry[name]; ok {
+        panic("seedkdf: duplicate KDF " + name)
+    }
+    registry[name] = k
+}
+
+// Lookup returns the KDF registered under name.
+func Lookup(name string) (KDF, error) {
+    k, ok := registry[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown KDF %q (available: %v)", name, Names())
+    }
+    return k, nil
+}
+
+// Names lists the registered KDFs in sorted order.
+func Names() []string {
+    names := make([]string, 0, len(registry))
+    for n := range registry {
+        names = append(names, n)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func init() {
+    Register(Default, BIP39{})
+    Register("scrypt", Scrypt{N: 1 << 18, R: 8, P: 1})
+    Register("argon2id", Argon2id{Time: 3, Memory: 256 * 1024, Threads: 4})
+}
+
+// BIP39 is PBKDF2-HMAC-SHA512, 2048 rounds, salt "mnemonic"+passphrase.
+type BIP39 struct{}
+
+func (BIP39) Seed(mnemonic, passphrase string) ([]byte, error) {
+    return fastpbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048), nil
+}
+
+func (BIP39) Seeds(mnemonic string, passphrases []string) ([][]byte, error) {
+    passwords, salts := make([][]byte, len(passphrases)), make([][]byte, len(passphrases))
+    for i, p := range passphrases {
+        passwords[i], salts[i] = []byte(mnemonic), []byte("mnemonic"+p)
+    }
+    return fastpbkdf2.Keys(passwords, salts, 2048), nil
+}
+
+func (BIP39) BatchSize() int { return fastpbkdf2.Lanes }
+
+func (BIP39) Standard() bool { return true }
+
+func (BIP39) Describe() string { return "BIP39 PBKDF2-HMAC-SHA512, 2048 rounds" }
+
+// Scrypt stretches with scrypt; the salt is the same as in BIP39.
+type Scrypt struct {
+    N, R, P int
+}
+
+func (s Scrypt) Seed(mnemonic, passphrase string) ([]byte, error) {
+    return scrypt.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), s.N, s.R, s.P, 64)
+}
+
+func (Scrypt) Standard() bool { return false }
+
+func (s Scrypt) MemoryBytes() uint64 { return 128 * uint64(s.R) * uint64(s.N) }
+
+func (s Scrypt) Describe() string {
+    return fmt.Sprintf("NON-STANDARD scrypt N=%d r=%d p=%d", s.N, s.R, s.P)
+}
+
+// Argon2id stretches with Argon2id; Memory is in KiB.
+type Argon2id struct {
+    Time    uint32
+    Memory  uint32
+    Threads uint8
+}
+
+func (a Argon2id) Seed(mnemonic, passphrase string) ([]byte, error) {
+    return argon2.IDKey([]byte(mnemonic), []byte("mnemonic"+passphrase), a.Time, a.Memory, a.Threads, 64), nil
+}
+
+func (Argon2id) Standard() bool { return false }
+
+func (a Argon2id) MemoryBytes() uint64 { return uint64(a.Memory) << 10 }
+
+func (a Argon2id) Describe() string {
+    return fmt.Sprintf("NON-STANDARD Argon2id t=%d m=%d KiB p=%d", a.Time, a.Memory, a.Threads)
+}
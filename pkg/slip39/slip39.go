@@ -0,0 +1,551 @@
+// Package slip39 splits a master secret into SLIP-39 Shamir backup shares
+// and recombines shares (as made by Trezor and other wallets) into the
+// master secret.
+//
+// Shares are mnemonics of 20 or more words from the 1024-word SLIP-39 list.
+// Each carries its set identifier, group and member indices and thresholds,
+// and an RS1024 checksum. Combine interpolates the member shares of each
+// group in GF(256), then the group shares, checks the digest share and
+// decrypts the result with the optional passphrase (four-round Feistel
+// network over PBKDF2-HMAC-SHA256). Split does the reverse. Consistent
+// checks that shares belong together without recombining them.
+package slip39
+
+import (
+    "crypto/hmac"
+    "crypto/pbkdf2"
+    "crypto/rand"
+    "crypto/sha256"
+    _ "embed"
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//go:embed wordlist.txt
+var wordlistText string
+
+// Wordlist is the SLIP-39 wordlist. Every word is unique in its first four
+// letters.
+var Wordlist = strings.Fields(wordlistText)
+
+var wordIndex = func() map[string]int {
+    m := make(map[string]int, len(Wordlist))
+    for i, w := range Wordlist {
+        m[w] = i
+    }
+    return m
+}()
+
+const (
+    radixBits       = 10
+    metadataWords   = 7 // identifier, exponent, share parameters, checksum
+    checksumWords   = 3
+    minSecretBytes  = 16
+    baseIterations  = 10000
+    feistelRounds   = 4
+    secretIndex     = 255
+    digestIndex     = 254
+    digestLength    = 4
+    minMnemonicSize = metadataWords + (minSecretBytes*8+radixBits-1)/radixBits
+    maxShareCount   = 16
+)
+
+// Errors returned by Combine.
+var (
+    ErrDigest       = errors.New("slip39: invalid digest; the shares are wrong or from different sets")
+    ErrInsufficient = errors.New("slip39: not enough shares")
+)
+
+// Share is one decoded share mnemonic.
+type Share struct {
+    Identifier        uint16 // random 15-bit set identifier
+    Extendable        bool
+    IterationExponent int
+    GroupIndex        int
+    GroupThreshold    int
+    GroupCount        int
+    MemberIndex       int
+    MemberThreshold   int
+    Value             []byte
+}
+
+// ParseShare decodes a share mnemonic, accepting words or their first four
+// letters, and verifies its checksum.
+func ParseShare(mnemonic string) (*Share, error) {
+    fields := strings.Fields(strings.ToLower(mnemonic))
+    if len(fields) < minMnemonicSize {
+        return nil, fmt.Errorf("slip39: %d words, a share has at least %d", len(fields), minMnemonicSize)
+    }
+    data := make([]int, len(fields))
+    for i, f := range fields {
+        idx, ok := lookupWord(f)
+        if !ok {
+            return nil, fmt.Errorf("slip39: word %d '%s' is not on the SLIP-39 list", i+1, f)
+        }
+        data[i] = idx
+    }
+
+    idExp := data[0]<<radixBits | data[1]
+    s := &Share{
+        Identifier:        uint16(idExp >> 5),
+        Extendable:        idExp>>4&1 == 1,
+        IterationExponent: idExp & 0xf,
+    }
+    if polymod(customization(s.Extendable), data) != 1 {
+        return nil, errors.New("slip39: checksum mismatch (a word is wrong or missing)")
+    }
+
+    params := data[2]<<radixBits | data[3]
+    s.GroupIndex = params >> 16 & 0xf
+    s.GroupThreshold = params>>12&0xf + 1
+    s.GroupCount = params>>8&0xf + 1
+    s.MemberIndex = params >> 4 & 0xf
+    s.MemberThreshold = params&0xf + 1
+    if s.GroupCount < s.GroupThreshold {
+        return nil, fmt.Errorf("slip39: group threshold %d exceeds group count %d", s.GroupThreshold, s.GroupCount)
+    }
+
+    valueWords := data[4 : len(data)-checksumWords]
+    padding := radixBits * len(valueWords) % 16
+    if padding > 8 {
+        return nil, errors.New("slip39: invalid share length")
+    }
+    s.Value = make([]byte, (radixBits*len(valueWords)-padding)/8)
+    // 10-bit words, most significant first; the padding bits must be zero
+    acc, bits, out := 0, 0, 0
+    for i, w := range valueWords {
+        acc = acc<<radixBits | w
+        bits += radixBits
+        if i == 0 {
+            if acc>>(radixBits-padding) != 0 {
+                return nil, errors.New("slip39: invalid padding")
+            }
+            bits -= padding
+            acc &= 1<<bits - 1
+        }
+        for bits >= 8 {
+            bits -= 8
+            s.Value[out] = byte(acc >> bits)
+            out++
+            acc &= 1<<bits - 1
+        }
+    }
+    return s, nil
+}
+
+func lookupWord(w string) (int, bool) {
+    if idx, ok := wordIndex[w]; ok {
+        return idx, true
+    }
+    if len(w) == 4 {
+        for i, word := range Wordlist {
+            if strings.HasPrefix(word, w) {
+                return i, true
+            }
+        }
+    }
+    return 0, false
+}
+
+func customization(extendable bool) string {
+    if extendable {
+        return "shamir_extendable"
+    }
+    return "shamir"
+}
+
+var rsGen = [10]int{0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009, 0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120}
+
+func polymod(custom string, data []int) int {
+    chk := 1
+    step := func(v int) {
+        b := chk >> 20
+        chk = (chk&0xfffff)<<10 ^ v
+        for i, g := range rsGen {
+            if b>>i&1 == 1 {
+                chk ^= g
+            }
+        }
+    }
+    for _, c := range []byte(custom) {
+        step(int(c))
+    }
+    for _, v := range data {
+        step(v)
+    }
+    return chk
+}
+
+// Mnemonic encodes the share as words, with a fresh checksum.
+func (s *Share) Mnemonic() string {
+    idExp := int(s.Identifier)<<5 | s.IterationExponent
+    if s.Extendable {
+        idExp |= 1 << 4
+    }
+    params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+    data := []int{idExp >> radixBits, idExp & (1<<radixBits - 1), params >> radixBits, params & (1<<radixBits - 1)}
+
+    // the value as a big-endian number, zero-padded at the front to whole words
+    words := (len(s.Value)*8 + radixBits - 1) / radixBits
+    acc, bits := 0, radixBits*words-len(s.Value)*8
+    for _, b := range s.Value {
+        acc = acc<<8 | int(b)
+        bits += 8
+        for bits >= radixBits {
+            bits -= radixBits
+            data = append(data, acc>>bits)
+            acc &= 1<<bits - 1
+        }
+    }
+
+    chk := polymod(customization(s.Extendable), append(data, 0, 0, 0)) ^ 1
+    for i := checksumWords - 1; i >= 0; i-- {
+        data = append(data, chk>>(radixBits*i)&(1<<radixBits-1))
+    }
+    out := make([]string, len(data))
+    for i, v := range data {
+        out[i] = Wordlist[v]
+    }
+    return strings.Join(out, " ")
+}
+
+// Group is the member threshold and the number of shares of one group.
+type Group struct {
+    Threshold int
+    Count     int
+}
+
+// Split encrypts the master secret with the passphrase and splits it into
+// shares: any groupThreshold of the groups, each with its own threshold of
+// member shares, recover it. The shares are extendable (SLIP-39 since 2024)
+// and use the given iteration exponent (Trezor uses 1). The result holds the
+// shares of each group in order.
+func Split(secret []byte, passphrase string, groupThreshold int, groups []Group, exponent int) ([][]*Share, error) {
+    if len(secret) < minSecretBytes || len(secret)%2 != 0 {
+        return nil, fmt.Errorf("slip39: the master secret must be an even number of bytes, at least %d", minSecretBytes)
+    }
+    if err := checkPassphrase(passphrase); err != nil {
+        return nil, err
+    }
+    if exponent < 0 || exponent > 15 {
+        return nil, errors.New("slip39: the iteration exponent must be 0–15")
+    }
+    if len(groups) < 1 || len(groups) > maxShareCount {
+        return nil, fmt.Errorf("slip39: 1–%d groups, got %d", maxShareCount, len(groups))
+    }
+    if groupThreshold < 1 || groupThreshold > len(groups) {
+        return nil, fmt.Errorf("slip39: group threshold %d must be between 1 and the number of groups (%d)", groupThreshold, len(groups))
+    }
+    for i, g := range groups {
+        if g.Threshold < 1 || g.Threshold > g.Count || g.Count > maxShareCount {
+            return nil, fmt.Errorf("slip39: group %d: %d-of-%d must have 1 ≤ threshold ≤ count ≤ %d", i+1, g.Threshold, g.Count, maxShareCount)
+        }
+        if g.Threshold == 1 && g.Count > 1 {
+            return nil, fmt.Errorf("slip39: group %d: a 1-of-%d group would be %d copies of one share; use 1-of-1", i+1, g.Count, g.Count)
+        }
+    }
+
+    var id [2]byte
+    if _, err := rand.Read(id[:]); err != nil {
+        return nil, err
+    }
+    identifier := (uint16(id[0])<<8 | uint16(id[1])) & 0x7fff
+    ems := encrypt(secret, passphrase, exponent, identifier, true)
+
+    groupSecrets, err := splitSecret(groupThreshold, len(groups), ems)
+    if err != nil {
+        return nil, err
+    }
+    out := make([][]*Share, len(groups))
+    for gi, g := range groups {
+        members, err := splitSecret(g.Threshold, g.Count, groupSecrets[gi])
+        if err != nil {
+            return nil, err
+        }
+        for mi, v := range members {
+            out[gi] = append(out[gi], &Share{
+                Identifier:        identifier,
+                Extendable:        true,
+                IterationExponent: exponent,
+                GroupIndex:        gi,
+                GroupThreshold:    groupThreshold,
+                GroupCount:        len(groups),
+                MemberIndex:       mi,
+                MemberThreshold:   g.Threshold,
+                Value:             v,
+            })
+        }
+    }
+    return out, nil
+}
+
+// Consistent checks that the shares can belong to one set: the same
+// identifier, iteration exponent, group parameters and length, one member
+// threshold per group, indices within range and no member given twice with
+// different values. It does not recombine anything, so it cannot tell
+// whether the values themselves are right; only Combine's digest can.
+func Consistent(shares []*Share) error {
+    if len(shares) == 0 {
+        return nil
+    }
+    first := shares[0]
+    threshold := map[int]int{}
+    seen := map[[2]int]*Share{}
+    for _, s := range shares {
+        if s.Identifier != first.Identifier || s.Extendable != first.Extendable || s.IterationExponent != first.IterationExponent ||
+            s.GroupThreshold != first.GroupThreshold || s.GroupCount != first.GroupCount || len(s.Value) != len(first.Value) {
+            return errors.New("slip39: the shares belong to different sets")
+        }
+        if s.GroupIndex >= s.GroupCount {
+            return fmt.Errorf("slip39: group %d does not exist in a set of %d groups", s.GroupIndex+1, s.GroupCount)
+        }
+        if t, ok := threshold[s.GroupIndex]; ok && t != s.MemberThreshold {
+            return fmt.Errorf("slip39: group %d has shares with different thresholds", s.GroupIndex+1)
+        }
+        threshold[s.GroupIndex] = s.MemberThreshold
+        if s.MemberThreshold == 1 && s.MemberIndex != 0 {
+            return fmt.Errorf("slip39: group %d is 1-of-1 but has member %d", s.GroupIndex+1, s.MemberIndex+1)
+        }
+        key := [2]int{s.GroupIndex, s.MemberIndex}
+        if prev, ok := seen[key]; ok && string(prev.Value) != string(s.Value) {
+            return fmt.Errorf("slip39: group %d member %d given twice with different values", s.GroupIndex+1, s.MemberIndex+1)
+        }
+        seen[key] = s
+    }
+    return nil
+}
+
+func checkPassphrase(passphrase string) error {
+    for _, c := range passphrase {
+        if c < 32 || c > 126 {
+            return errors.New("slip39: the passphrase must be printable ASCII")
+        }
+    }
+    return nil
+}
+
+// Combine recovers the master secret from enough shares of one set: the
+// group threshold number of groups, each with its member threshold number of
+// shares. Passphrases are printable ASCII; an empty one is the default.
+func Combine(shares []*Share, passphrase string) ([]byte, error) {
+    if len(shares) == 0 {
+        return nil, ErrInsufficient
+    }
+    if err := checkPassphrase(passphrase); err != nil {
+        return nil, err
+    }
+    if err := Consistent(shares); err != nil {
+        return nil, err
+    }
+    first := shares[0]
+    groups := map[int]map[int]*Share{}
+    for _, s := range shares {
+        g := groups[s.GroupIndex]
+        if g == nil {
+            g = map[int]*Share{}
+            groups[s.GroupIndex] = g
+        }
+        g[s.MemberIndex] = s
+    }
+
+    var groupShares []point
+    for gi, g := range groups {
+        var members []point
+        threshold := 0
+        for mi, s := range g {
+            members = append(members, point{mi, s.Value})
+            threshold = s.MemberThreshold
+        }
+        if len(members) < threshold {
+            continue
+        }
+        v, err := recoverSecret(threshold, members[:threshold])
+        if err != nil {
+            return nil, fmt.Errorf("group %d: %w", gi+1, err)
+        }
+        groupShares = append(groupShares, point{gi, v})
+    }
+    if len(groupShares) < first.GroupThreshold {
+        return nil, ErrInsufficient
+    }
+    ems, err := recoverSecret(first.GroupThreshold, groupShares[:first.GroupThreshold])
+    if err != nil {
+        return nil, err
+    }
+    return decrypt(ems, passphrase, first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// Missing describes what is still needed to combine shares, or returns ""
+// when Combine has enough. It assumes the shares belong to one set.
+func Missing(shares []*Share) string {
+    if len(shares) == 0 {
+        return "no shares yet"
+    }
+    members := map[int]map[int]bool{}
+    threshold := map[int]int{}
+    for _, s := range shares {
+        if members[s.GroupIndex] == nil {
+            members[s.GroupIndex] = map[int]bool{}
+        }
+        members[s.GroupIndex][s.MemberIndex] = true
+        threshold[s.GroupIndex] = s.MemberThreshold
+    }
+    complete := 0
+    var partial []string
+    for g := 0; g < shares[0].GroupCount; g++ {
+        switch n := len(members[g]); {
+        case n == 0:
+        case n >= threshold[g]:
+            complete++
+        default:
+            partial = append(partial, fmt.Sprintf("group %d has %d of %d shares", g+1, n, threshold[g]))
+        }
+    }
+    if complete >= shares[0].GroupThreshold {
+        return ""
+    }
+    msg := fmt.Sprintf("%d of %d groups complete", complete, shares[0].GroupThreshold)
+    if len(partial) > 0 {
+        msg += "; " + strings.Join(partial, ", ")
+    }
+    return msg
+}
+
+type point struct {
+    x int
+    y []byte
+}
+
+func recoverSecret(threshold int, shares []point) ([]byte, error) {
+    if threshold == 1 {
+        return shares[0].y, nil
+    }
+    secret := interpolate(shares, secretIndex)
+    digest := interpolate(shares, digestIndex)
+    mac := hmac.New(sha256.New, digest[digestLength:])
+    mac.Write(secret)
+    if !secretcmp.Equal(mac.Sum(nil)[:digestLength], digest[:digestLength]) {
+        return nil, ErrDigest
+    }
+    return secret, nil
+}
+
+// splitSecret shares the secret among count members, any threshold of which
+// recover it: threshold-2 random shares, the digest share at x = 254 and the
+// secret at x = 255 fix the polynomial, evaluated at x = 0..count-1.
+func splitSecret(threshold, count int, secret []byte) ([][]byte, error) {
+    if threshold == 1 {
+        out := make([][]byte, count)
+        for i := range out {
+            out[i] = append([]byte{}, secret...)
+        }
+        return out, nil
+    }
+    var base []point
+    for i := 0; i < threshold-2; i++ {
+        y := make([]byte, len(secret))
+        if _, err := rand.Read(y); err != nil {
+            return nil, err
+        }
+        base = append(base, point{i, y})
+    }
+    randomPart := make([]byte, len(secret)-digestLength)
+    if _, err := rand.Read(randomPart); err != nil {
+        return nil, err
+    }
+    mac := hmac.New(sha256.New, randomPart)
+    mac.Write(secret)
+    digest := append(mac.Sum(nil)[:digestLength], randomPart...)
+    base = append(base, point{digestIndex, digest}, point{secretIndex, secret})
+
+    out := make([][]byte, count)
+    for i := range out {
+        if i < threshold-2 {
+            out[i] = base[i].y
+        } else {
+            out[i] = interpolate(base, i)
+        }
+    }
+    return out, nil
+}
+
+// GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1, generator 3.
+var gfExp, gfLog = func() (exp [255]int, log [256]int) {
+    p := 1
+    for i := range exp {
+        exp[i] = p
+        log[p] = i
+        p ^= p << 1
+        if p&0x100 != 0 {
+            p ^= 0x11b
+        }
+    }
+    return
+}()
+
+// Lagrange interpolation at x of the shares' byte vectors.
+func interpolate(shares []point, x int) []byte {
+    for _, s := range shares {
+        if s.x == x {
+            return s.y
+        }
+    }
+    logProd := 0
+    for _, s := range shares {
+        logProd += gfLog[s.x^x]
+    }
+    out := make([]byte, len(shares[0].y))
+    for _, s := range shares {
+        logBasis := logProd - gfLog[s.x^x]
+        for _, o := range shares {
+            if o.x != s.x {
+                logBasis -= gfLog[s.x^o.x]
+            }
+        }
+        logBasis = (logBasis%255 + 255) % 255
+        for i, b := range s.y {
+            if b != 0 {
+                out[i] ^= byte(gfExp[(gfLog[b]+logBasis)%255])
+            }
+        }
+    }
+    return out
+}
+
+func decrypt(ems []byte, passphrase string, exponent int, id uint16, extendable bool) []byte {
+    return feistel(ems, passphrase, exponent, id, extendable, true)
+}
+
+func encrypt(secret []byte, passphrase string, exponent int, id uint16, extendable bool) []byte {
+    return feistel(secret, passphrase, exponent, id, extendable, false)
+}
+
+// Decryption runs the rounds in reverse order.
+func feistel(in []byte, passphrase string, exponent int, id uint16, extendable, reverse bool) []byte {
+    half := len(in) / 2
+    l := append([]byte{}, in[:half]...)
+    r := append([]byte{}, in[half:]...)
+    var salt []byte
+    if !extendable {
+        salt = append([]byte("shamir"), byte(id>>8), byte(id))
+    }
+    iterations := (baseIterations << exponent) / feistelRounds
+    for n := 0; n < feistelRounds; n++ {
+        i := n
+        if reverse {
+            i = feistelRounds - 1 - n
+        }
+        f, err := pbkdf2.Key(sha256.New, string(append([]byte{byte(i)}, passphrase...)), append(append([]byte{}, salt...), r...), iterations, len(r))
+        if err != nil {
+            panic(err)
+        }
+        for j := range l {
+            l[j] ^= f[j]
+        }
+        l, r = r, l
+    }
+    return append(r, l...)
+}
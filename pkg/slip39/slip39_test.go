@@ -0,0 +1,124 @@
+package slip39
+
+import (
+    "bytes"
+    "encoding/hex"
+    "testing"
+)
+
+// 官方测试向量 1：单份 1-of-1 分享，口令 TREZOR
+const vector1 = "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"
+
+func TestVectorRoundTrip(t *testing.T) {
+    s, err := ParseShare(vector1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := s.Mnemonic(); got != vector1 {
+        t.Errorf("Mnemonic: got %q", got)
+    }
+    secret, err := Combine([]*Share{s}, "TREZOR")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := hex.EncodeToString(secret); got != "bb54aac4b89dc868ba37d9cc21b2cece" {
+        t.Errorf("Combine: got %s", got)
+    }
+}
+
+func TestSplitCombine(t *testing.T) {
+    secret, _ := hex.DecodeString("0c94fee5ce0bd2dc9c0eaf8be4a7d4e5")
+    tests := []struct {
+        name           string
+        groupThreshold int
+        groups         []Group
+        pick           [][]int // 每组用哪些成员
+    }{
+        {"1-of-1", 1, []Group{{1, 1}}, [][]int{{0}}},
+        {"2-of-3", 1, []Group{{2, 3}}, [][]int{{2, 0}}},
+        {"3-of-5", 1, []Group{{3, 5}}, [][]int{{4, 1, 3}}},
+        {"two groups of three", 2, []Group{{1, 1}, {2, 3}, {3, 5}}, [][]int{nil, {1, 2}, {0, 2, 4}}},
+    }
+    for _, tt := range tests {
+        sets, err := Split(secret, "TREZOR", tt.groupThreshold, tt.groups, 0)
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        var picked []*Share
+        for gi, members := range tt.pick {
+            for _, mi := range members {
+                // 经过助记词编码再解析，检查校验和与各字段
+                s, err := ParseShare(sets[gi][mi].Mnemonic())
+                if err != nil {
+                    t.Fatalf("%s: group %d member %d: %v", tt.name, gi+1, mi+1, err)
+                }
+                picked = append(picked, s)
+            }
+        }
+        if err := Consistent(picked); err != nil {
+            t.Errorf("%s: Consistent: %v", tt.name, err)
+        }
+        if m := Missing(picked); m != "" {
+            t.Errorf("%s: Missing: %s", tt.name, m)
+        }
+        got, err := Combine(picked, "TREZOR")
+        if err != nil || !bytes.Equal(got, secret) {
+            t.Errorf("%s: Combine: got %x, %v", tt.name, got, err)
+        }
+        if len(picked) > 1 {
+            if Missing(picked[:len(picked)-1]) == "" {
+                t.Errorf("%s: one share short is reported complete", tt.name)
+            }
+        }
+    }
+}
+
+func TestSplitRejects(t *testing.T) {
+    secret := make([]byte, 16)
+    tests := []struct {
+        name           string
+        secret         []byte
+        groupThreshold int
+        groups         []Group
+    }{
+        {"short secret", make([]byte, 14), 1, []Group{{1, 1}}},
+        {"odd secret", make([]byte, 17), 1, []Group{{1, 1}}},
+        {"group threshold", secret, 2, []Group{{1, 1}}},
+        {"member threshold", secret, 1, []Group{{4, 3}}},
+        {"1-of-n", secret, 1, []Group{{1, 3}}},
+        {"17 members", secret, 1, []Group{{2, 17}}},
+    }
+    for _, tt := range tests {
+        if _, err := Split(tt.secret, "", tt.groupThreshold, tt.groups, 0); err == nil {
+            t.Errorf("%s: accepted", tt.name)
+        }
+    }
+}
+
+func TestConsistent(t *testing.T) {
+    a, err := Split(make([]byte, 16), "", 1, []Group{{2, 3}}, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    b, err := Split(make([]byte, 16), "", 1, []Group{{2, 3}}, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := Consistent([]*Share{a[0][0], a[0][1], a[0][1]}); err != nil {
+        t.Errorf("same share twice: %v", err)
+    }
+    if Consistent([]*Share{a[0][0], b[0][1]}) == nil && a[0][0].Identifier != b[0][1].Identifier {
+        t.Errorf("shares of two sets accepted")
+    }
+    changed := *a[0][1]
+    changed.Value = append([]byte{}, changed.Value...)
+    changed.Value[0] ^= 1
+    if Consistent([]*Share{a[0][0], a[0][1], &changed}) == nil {
+        t.Errorf("one member with two values accepted")
+    }
+    other := *a[0][1]
+    other.MemberThreshold = 3
+    if Consistent([]*Share{a[0][0], &other}) == nil {
+        t.Errorf("two member thresholds in one group accepted")
+    }
+}
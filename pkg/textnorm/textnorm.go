@@ -0,0 +1,46 @@
+// Package textnorm is the single place where mnemonics, words and
+// passphrases are normalised before they are compared or hashed.
+//
+// Policy:
+//   - Everything is converted to Unicode NFKD, as BIP39 requires. This also
+//     maps the Japanese ideographic space (U+3000), no-break spaces and
+//     full-width Latin letters to their plain forms.
+//   - Mnemonics and words are case-folded (lower case), and any run of
+//     Unicode whitespace between words becomes a single ASCII space.
+//   - Passphrases are only converted to NFKD: case and whitespace are part of
+//     the secret and are left exactly as typed.
+package textnorm
+
+import (
+    "strings"
+
+    "golang.org/x/text/unicode/norm"
+)
+
+// NFKD returns the NFKD form of s.
+func NFKD(s string) string {
+    return norm.NFKD.String(s)
+}
+
+// Word normalises a single word: NFKD, lower case, surrounding whitespace
+// removed.
+func Word(s string) string {
+    return strings.TrimSpace(NFKD(strings.ToLower(s)))
+}
+
+// Words splits a mnemonic on any Unicode whitespace and normalises each word.
+func Words(s string) []string {
+    return strings.Fields(NFKD(strings.ToLower(s)))
+}
+
+// Mnemonic returns the canonical form of a mnemonic: normalised words joined
+// by single ASCII spaces.
+func Mnemonic(s string) string {
+    return strings.Join(Words(s), " ")
+}
+
+// Passphrase returns the NFKD form of a BIP39 passphrase, preserving case and
+// whitespace.
+func Passphrase(s string) string {
+    return NFKD(s)
+}
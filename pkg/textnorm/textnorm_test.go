@@ -0,0 +1,111 @@
+package textnorm
+
+import (
+    "crypto/pbkdf2"
+    "crypto/sha512"
+    "encoding/hex"
+    "slices"
+    "testing"
+)
+
+// 测试语料：日文全角空格、法语/西班牙语重音（预组合与分解两种写法）、
+// 各种空白与大小写
+var mnemonicCorpus = []struct {
+    name string
+    in   string
+    want string
+}{
+    {"plain", "abandon about", "abandon about"},
+    {"upper case", "ABANDON About", "abandon about"},
+    {"extra spaces", "  abandon   about  ", "abandon about"},
+    {"tabs and newlines", "abandon\tabout\r\nzoo\n", "abandon about zoo"},
+    {"no-break space", "abandon\u00a0about", "abandon about"},
+    {"em space", "abandon\u2003about", "abandon about"},
+    {"full-width latin", "\uff21\uff22\uff21\uff2e\uff24\uff2f\uff2e \uff41\uff42\uff4f\uff55\uff54", "abandon about"},
+    // あいこくしん　あおぞら（ぞ 分解为 そ + U+3099）
+    {"ideographic space", "あいこくしん\u3000あおぞら", "あいこくしん あおそ\u3099ら"},
+    {"ideographic spaces doubled", "あいこくしん\u3000\u3000あおぞら\u3000", "あいこくしん あおそ\u3099ら"},
+    {"half-width katakana", "\uff71\uff72\uff7a\uff78\uff7c\uff9d", "アイコクシン"},
+    {"french precomposed", "\u00e9l\u00e8ve fa\u00e7ade", "e\u0301le\u0300ve fac\u0327ade"},
+    {"french decomposed", "e\u0301le\u0300ve fac\u0327ade", "e\u0301le\u0300ve fac\u0327ade"},
+    {"french upper case", "\u00c9L\u00c8VE", "e\u0301le\u0300ve"},
+    {"spanish precomposed", "\u00e1baco \u00f1and\u00fa", "a\u0301baco n\u0303andu\u0301"},
+    {"spanish upper case", "\u00c1BACO \u00d1AND\u00da", "a\u0301baco n\u0303andu\u0301"},
+    {"spanish decomposed", "a\u0301baco n\u0303andu\u0301", "a\u0301baco n\u0303andu\u0301"},
+    {"empty", "", ""},
+    {"only whitespace", " \t\u3000\n", ""},
+}
+
+func TestMnemonic(t *testing.T) {
+    for _, c := range mnemonicCorpus {
+        if got := Mnemonic(c.in); got != c.want {
+            t.Errorf("%s: Mnemonic(%q) = %q, want %q", c.name, c.in, got, c.want)
+        }
+    }
+}
+
+func TestMnemonicIdempotent(t *testing.T) {
+    for _, c := range mnemonicCorpus {
+        once := Mnemonic(c.in)
+        if twice := Mnemonic(once); twice != once {
+            t.Errorf("%s: Mnemonic not idempotent: %q -> %q", c.name, once, twice)
+        }
+    }
+}
+
+func TestWords(t *testing.T) {
+    got := Words("  Élève　ÑANDÚ\n")
+    want := []string{"élève", "ñandú"}
+    if !slices.Equal(got, want) {
+        t.Errorf("Words = %q, want %q", got, want)
+    }
+}
+
+func TestWord(t *testing.T) {
+    cases := []struct{ in, want string }{
+        {" Abandon ", "abandon"},
+        {"\u00c9L\u00c8VE", "e\u0301le\u0300ve"},
+        {"\u00d1and\u00fa", "n\u0303andu\u0301"},
+        {"\u3000あおぞら\u3000", "あおそ\u3099ら"},
+    }
+    for _, c := range cases {
+        if got := Word(c.in); got != c.want {
+            t.Errorf("Word(%q) = %q, want %q", c.in, got, c.want)
+        }
+    }
+}
+
+// 口令只做 NFKD：大小写与空白都是口令的一部分
+func TestPassphrase(t *testing.T) {
+    cases := []struct{ in, want string }{
+        {"TREZOR", "TREZOR"},
+        {"  two  spaces ", "  two  spaces "},
+        {"\u00c9l\u00e8ve", "E\u0301le\u0300ve"},
+        {"\u00f1", "n\u0303"},
+        {"a\u3000b", "a b"},
+        {"\u334d", "メートル"},
+        {"\u30ac", "\u30ab\u3099"},
+    }
+    for _, c := range cases {
+        if got := Passphrase(c.in); got != c.want {
+            t.Errorf("Passphrase(%q) = %q, want %q", c.in, got, c.want)
+        }
+    }
+}
+
+// BIP39 日文测试向量（bip32JP/bip39 test_JP_BIP39.json 第一条）
+func TestJapaneseSeedVector(t *testing.T) {
+    mnemonic := "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　" +
+        "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら"
+    passphrase := "㍍ガバヴァぱばぐゞちぢ十人十色"
+    want := "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c" +
+        "467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55"
+
+    seed, err := pbkdf2.Key(sha512.New, Mnemonic(mnemonic), []byte("mnemonic"+Passphrase(passphrase)), 2048, 64)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := hex.EncodeToString(seed); got != want {
+        t.Errorf("seed = %s, want %s", got, want)
+    }
+}
@@ -0,0 +1,65 @@
+// Package watchonly derives addresses from an account extended public key
+// (xpub, ypub or zpub). It is the public side of the tool: Parse refuses
+// private extended keys, and nothing here reads binary.txt or a seed.
+package watchonly
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+)
+
+// SLIP-132 version bytes that also fix the address type.
+var (
+    VersionYPub = [4]byte{0x04, 0x9d, 0x7c, 0xb2}
+    VersionZPub = [4]byte{0x04, 0xb2, 0x47, 0x46}
+)
+
+// ErrPrivate is returned for xprv and other private extended keys.
+var ErrPrivate = errors.New("watchonly: extended key is private; give the account xpub")
+
+// Account is an account-level extended public key and its address type.
+type Account struct {
+    Key  *bip32.Key
+    Type btcaddr.Type
+}
+
+// Parse decodes an account xpub, ypub or zpub. A ypub always gives
+// P2SH-P2WPKH and a zpub P2WPKH addresses; a plain xpub carries no type and
+// uses def.
+func Parse(s string, def btcaddr.Type) (*Account, error) {
+    key, version, err := bip32.Parse(s)
+    if err != nil {
+        return nil, err
+    }
+    if key.Private {
+        return nil, ErrPrivate
+    }
+    a := &Account{Key: key, Type: def}
+    switch version {
+    case bip32.VersionXPub:
+    case VersionYPub:
+        a.Type = btcaddr.P2SHP2WPKH
+    case VersionZPub:
+        a.Type = btcaddr.P2WPKH
+    default:
+        return nil, fmt.Errorf("watchonly: unsupported version %x (mainnet xpub, ypub or zpub expected)", version)
+    }
+    return a, nil
+}
+
+// Address returns the address at branch/index: branch 0 is receive, 1 is
+// change.
+func (a *Account) Address(branch, index uint32) (string, error) {
+    chain, err := a.Key.Child(branch)
+    if err != nil {
+        return "", err
+    }
+    child, err := chain.Child(index)
+    if err != nil {
+        return "", err
+    }
+    return btcaddr.Encode(a.Type, child.PublicKey())
+}
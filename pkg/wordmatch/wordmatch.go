@@ -0,0 +1,152 @@
+// Package wordmatch maps arbitrary strings (OCR output, typos, partial
+// transcriptions) onto the nearest words of a fixed dictionary such as the
+// BIP39 word list, reporting an edit distance and a confidence score.
+package wordmatch
+
+import (
+    "sort"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+// Candidate is a dictionary word proposed for an input string.
+type Candidate struct {
+    Word       string
+    Index      int
+    Distance   int
+    Confidence float64 // 0 (no resemblance) … 1 (exact match)
+}
+
+// Matcher matches strings against a fixed word list.
+type Matcher struct {
+    words []string
+}
+
+// New returns a Matcher over words. The slice is not copied and must not be
+// modified while the Matcher is in use.
+func New(words []string) *Matcher {
+    return &Matcher{words: words}
+}
+
+// Best returns the closest word to s. Ties are broken by list order.
+func (m *Matcher) Best(s string) Candidate {
+    c := m.Suggest(s, 1)
+    if len(c) == 0 {
+        return Candidate{Index: -1}
+    }
+    return c[0]
+}
+
+// Suggest returns up to n candidates ordered by increasing distance.
+func (m *Matcher) Suggest(s string, n int) []Candidate {
+    s = Normalize(s)
+    all := make([]Candidate, 0, len(m.words))
+    for i, w := range m.words {
+        d := Distance(s, w)
+        all = append(all, Candidate{
+            Word:       w,
+            Index:      i,
+            Distance:   d,
+            Confidence: confidence(s, w, d),
+        })
+        if d == 0 {
+            return []Candidate{all[len(all)-1]}
+        }
+    }
+
+    sort.SliceStable(all, func(i, j int) bool {
+        return all[i].Distance < all[j].Distance
+    })
+
+    if n > len(all) {
+        n = len(all)
+    }
+    out := all[:n]
+
+    // 最优解不唯一时，降低其置信度
+    if len(all) > 1 && all[0].Distance == all[1].Distance {
+        for i := range out {
+            out[i].Confidence /= 2
+        }
+    }
+    return out
+}
+
+// Lookup resolves s to a word by exact match or, failing that, by a prefix
+// that matches exactly one word ("aban" → "abandon").
+func (m *Matcher) Lookup(s string) (Candidate, bool) {
+    s = Normalize(s)
+    if s == "" {
+        return Candidate{Index: -1}, false
+    }
+    found := -1
+    for i, w := range m.words {
+        if w == s {
+            return Candidate{Word: w, Index: i, Confidence: 1}, true
+        }
+        if strings.HasPrefix(w, s) {
+            if found >= 0 {
+                return Candidate{Index: -1}, false
+            }
+            found = i
+        }
+    }
+    if found < 0 {
+        return Candidate{Index: -1}, false
+    }
+    w := m.words[found]
+    return Candidate{Word: w, Index: found, Distance: Distance(s, w), Confidence: 1}, true
+}
+
+// Prefix returns every word starting with s, in list order. An empty s
+// matches nothing.
+func (m *Matcher) Prefix(s string) []Candidate {
+    s = Normalize(s)
+    if s == "" {
+        return nil
+    }
+    var out []Candidate
+    for i, w := range m.words {
+        if strings.HasPrefix(w, s) {
+            out = append(out, Candidate{Word: w, Index: i, Distance: Distance(s, w), Confidence: 1})
+        }
+    }
+    return out
+}
+
+// Normalize applies NFKD, trims and lower-cases s the way the matcher does
+// before comparing it against the word list.
+func Normalize(s string) string {
+    return textnorm.Word(s)
+}
+
+// Distance returns the Levenshtein distance between a and b, counted in runes.
+func Distance(a, b string) int {
+    ra, rb := []rune(a), []rune(b)
+    prev := make([]int, len(rb)+1)
+    cur := make([]int, len(rb)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(ra); i++ {
+        cur[0] = i
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i-1] == rb[j-1] {
+                cost = 0
+            }
+            cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+        }
+        prev, cur = cur, prev
+    }
+    return prev[len(rb)]
+}
+
+func confidence(s, w string, d int) float64 {
+    n := max(len([]rune(s)), len([]rune(w)))
+    if n == 0 {
+        return 0
+    }
+    return 1 - float64(d)/float64(n)
+}
@@ -0,0 +1,195 @@
+package wordmatch_test
+
+import (
+    "math"
+    "slices"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+func matcher(t *testing.T, lang string) *wordmatch.Matcher {
+    t.Helper()
+    words, err := bip39.Wordlist(lang)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return wordmatch.New(words)
+}
+
+// 期望的单词按 NFKD 写，与词表一致
+func nfkd(s string) string {
+    return textnorm.NFKD(s)
+}
+
+// Lookup：完全匹配、唯一前缀、多义前缀与不在词表上的输入
+func TestLookup(t *testing.T) {
+    cases := []struct {
+        lang, in string
+        word     string // "" = 不接受
+        index    int
+    }{
+        {"english", "abandon", "abandon", 0},
+        {"english", "ZOO", "zoo", 2047},
+        {"english", "  about ", "about", 3},
+        {"english", "aban", "abandon", 0},
+        {"english", "lett", "letter", 1028},
+        {"english", "ab", "", -1},
+        {"english", "zo", "", -1},
+        {"english", "a", "", -1},
+        {"english", "", "", -1},
+        {"english", "xyzzy", "", -1},
+        // act 既是单词也是 action、actor 等的前缀：完全匹配优先
+        {"english", "act", "act", 19},
+        {"spanish", "ábaco", "ábaco", 0},
+        {"spanish", "ÁBACO", "ábaco", 0},
+        {"spanish", "ábac", "ábaco", 0},
+        {"spanish", "abaco", "", -1},
+        {"french", "élève", "élève", 642},
+        {"french", "eleve", "", -1},
+        {"japanese", "あいこくし", "あいこくしん", 0},
+        {"japanese", "あい", "", -1},
+        {"czech", "abdik", "abdikace", 0},
+        {"korean", "가격", "가격", 0},
+        {"chinese_simplified", "的", "的", 0},
+    }
+    for _, c := range cases {
+        m := matcher(t, c.lang)
+        got, ok := m.Lookup(c.in)
+        if c.word == "" {
+            if ok || got.Index != -1 {
+                t.Errorf("%s Lookup(%q) = %q, %v; want no match", c.lang, c.in, got.Word, ok)
+            }
+            continue
+        }
+        if !ok || got.Word != nfkd(c.word) || got.Confidence != 1 {
+            t.Errorf("%s Lookup(%q) = %q (%v), %v; want %q", c.lang, c.in, got.Word, got.Confidence, ok, c.word)
+        }
+        if got.Index != c.index {
+            t.Errorf("%s Lookup(%q).Index = %d, want %d", c.lang, c.in, got.Index, c.index)
+        }
+    }
+}
+
+// Best 与 Suggest：最接近的单词、编辑距离与置信度。最优解不唯一时置信度减半
+func TestBest(t *testing.T) {
+    cases := []struct {
+        lang, in   string
+        word       string
+        distance   int
+        confidence float64
+    }{
+        {"english", "zoo", "zoo", 0, 1},
+        {"english", "ZOO", "zoo", 0, 1},
+        {"english", "abandn", "abandon", 1, 1 - 1.0/7},
+        {"english", "abandom", "abandon", 1, 1 - 1.0/7},
+        {"english", "aboot", "about", 1, 0.8},
+        {"english", "hapy", "happy", 1, 0.8},
+        {"english", "lettr", "letter", 1, 1 - 1.0/6},
+        {"english", "tst", "test", 1, 0.75},
+        // wild 与 world 距离都是 1：按词表顺序取 wild，置信度减半
+        {"english", "wrld", "wild", 1, 0.75 / 2},
+        {"english", "xyzq", "buzz", 3, 0.25 / 2},
+        {"spanish", "ábac", "ábaco", 1, 1 - 1.0/6},
+        {"spanish", "ÁBACO", "ábaco", 0, 1},
+        {"french", "élève", "élève", 0, 1},
+        {"french", "ÉLÈVE", "élève", 0, 1},
+        {"japanese", "あいこくし", "あいこくしん", 1, 1 - 1.0/6},
+        {"czech", "abdikovat", "blokovat", 3, (1 - 3.0/9) / 2},
+    }
+    for _, c := range cases {
+        got := matcher(t, c.lang).Best(c.in)
+        if got.Word != nfkd(c.word) || got.Distance != c.distance || math.Abs(got.Confidence-c.confidence) > 1e-9 {
+            t.Errorf("%s Best(%q) = %q d=%d conf=%.4f; want %q d=%d conf=%.4f",
+                c.lang, c.in, got.Word, got.Distance, got.Confidence, c.word, c.distance, c.confidence)
+        }
+    }
+}
+
+// “您是不是要找”：候选按距离排列，同距离保持词表顺序；完全匹配只返回一个
+func TestSuggest(t *testing.T) {
+    en := matcher(t, "english")
+    words := func(cs []wordmatch.Candidate) []string {
+        var out []string
+        for _, c := range cs {
+            out = append(out, c.Word)
+        }
+        return out
+    }
+    if got, want := words(en.Suggest("aboot", 3)), []string{"about", "above", "boat"}; !slices.Equal(got, want) {
+        t.Errorf("Suggest(aboot) = %q, want %q", got, want)
+    }
+    if got, want := words(en.Suggest("abandom", 2)), []string{"abandon", "random"}; !slices.Equal(got, want) {
+        t.Errorf("Suggest(abandom) = %q, want %q", got, want)
+    }
+    if got := en.Suggest("zoo", 5); len(got) != 1 || got[0].Word != "zoo" {
+        t.Errorf("Suggest(zoo) = %q, want only zoo", words(got))
+    }
+    if got := en.Suggest("aboot", 5000); len(got) != bip39.WordCount {
+        t.Errorf("Suggest(aboot, 5000) returned %d candidates", len(got))
+    }
+    for i, c := range en.Suggest("wrld", 2) {
+        if want := []float64{0.75 / 2, 0.8 / 2}[i]; math.Abs(c.Confidence-want) > 1e-9 {
+            t.Errorf("Suggest(wrld)[%d] = %q conf=%.4f, want %.4f", i, c.Word, c.Confidence, want)
+        }
+    }
+    if got := wordmatch.New(nil).Best("abc"); got.Index != -1 {
+        t.Errorf("Best on an empty list = %+v", got)
+    }
+}
+
+func TestPrefix(t *testing.T) {
+    cases := []struct {
+        lang, in string
+        want     []string
+    }{
+        {"english", "ab", []string{"abandon", "ability", "able", "about", "above", "absent", "absorb", "abstract", "absurd", "abuse"}},
+        {"english", "zoo", []string{"zoo"}},
+        {"english", "", nil},
+        {"english", "xyz", nil},
+        {"spanish", "ába", []string{"ábaco"}},
+        {"japanese", "あいこ", []string{"あいこくしん"}},
+    }
+    for _, c := range cases {
+        var got []string
+        for _, p := range matcher(t, c.lang).Prefix(c.in) {
+            got = append(got, p.Word)
+        }
+        want := make([]string, len(c.want))
+        for i, w := range c.want {
+            want[i] = nfkd(w)
+        }
+        if len(want) == 0 {
+            want = nil
+        }
+        if !slices.Equal(got, want) {
+            t.Errorf("%s Prefix(%q) = %q, want %q", c.lang, c.in, got, want)
+        }
+    }
+}
+
+func TestDistance(t *testing.T) {
+    cases := []struct {
+        a, b string
+        want int
+    }{
+        {"", "", 0},
+        {"", "abc", 3},
+        {"kitten", "sitting", 3},
+        {"abandon", "abandon", 0},
+        // 按字符而不是字节计算
+        {"あいこくしん", "あいこくし", 1},
+        {"가격", "가난", 1},
+        {nfkd("ábaco"), "abaco", 1},
+    }
+    for _, c := range cases {
+        if got := wordmatch.Distance(c.a, c.b); got != c.want {
+            t.Errorf("Distance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+        }
+        if got := wordmatch.Distance(c.b, c.a); got != c.want {
+            t.Errorf("Distance(%q, %q) = %d, want %d", c.b, c.a, got, c.want)
+        }
+    }
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   -qr-decode 读取二维码图片
+// -------------------------
+//
+// 用 zbarimg 读取备份二维码的照片或扫描件（PNG、JPEG 等），取出助记词
+// 后按 -v 的方式逐词校验并重算校验位，不需要手机应用。binary.txt 存在时
+// 再比较两者是否为同一助记词。单词本身不打印，只输出校验结果与指纹。
+//
+
+func decodeQRImage(path string, wordList []string) {
+    content, err := scanQR(path)
+    if err != nil {
+        log.Fatalf("Error: -qr-decode: %v", err)
+    }
+    if rest, ok := strings.CutPrefix(content, demoWatermark+": "); ok {
+        fmt.Println("Note: this QR code was made in demo mode; the words are public demo entropy.")
+        content = rest
+    }
+    fmt.Printf("QR code: %d words\n", len(splitWords(content)))
+    validateMnemonic(content, wordList)
+
+    if _, err := os.Stat(binaryPath); err != nil && !demoMode {
+        return
+    }
+    entropy, err := entropyFromPhrase(content, wordList)
+    if err != nil {
+        log.Fatalf("Error: -qr-decode: %v", err)
+    }
+    if secretcmp.EqualBits(bip39.BytesToBits(entropy), loadEntropyBits()) {
+        fmt.Println("QR code matches binary.txt.")
+        transcript.record("qr decode", "match", "", "")
+        return
+    }
+    fmt.Println("QR code does NOT match binary.txt.")
+    transcript.record("qr decode", "mismatch", "", "")
+    transcript.finish()
+    os.Exit(1)
+}
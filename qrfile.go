@@ -0,0 +1,130 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "os"
+    "strings"
+
+    qrcode "github.com/skip2/go-qrcode"
+)
+
+//
+// -------------------------
+//   -qr-out 二维码图片文件
+// -------------------------
+//
+// 把 -q 的二维码写成 PNG 或 SVG（按扩展名），用于打印或在另一台设备上
+// 显示。-qr-scale 为每个模块的像素数，-qr-border 为四周静区的模块数
+// （规范要求至少 4），-qr-ec 为纠错等级 L、M、Q、H（也用于终端二维码）。
+// 文件以 0600 新建，不覆盖已有文件；其中是完整的助记词，用完应删除。
+//
+
+const maxQRScale = 100
+
+var qrLevels = map[string]qrcode.RecoveryLevel{
+    "L": qrcode.Low,
+    "M": qrcode.Medium,
+    "Q": qrcode.High,
+    "H": qrcode.Highest,
+}
+
+func parseQRLevel(s string) (qrcode.RecoveryLevel, error) {
+    level, ok := qrLevels[strings.ToUpper(s)]
+    if !ok {
+        return 0, fmt.Errorf("unknown error-correction level '%s' (L, M, Q or H)", s)
+    }
+    return level, nil
+}
+
+func writeQRFile(path, content string, level qrcode.RecoveryLevel, scale, border int) error {
+    if scale < 1 || scale > maxQRScale {
+        return fmt.Errorf("-qr-scale must be 1–%d pixels per module", maxQRScale)
+    }
+    if border < 0 || border > 40 {
+        return fmt.Errorf("-qr-border must be 0–40 modules")
+    }
+    qr, err := qrcode.New(content, level)
+    if err != nil {
+        return err
+    }
+    qr.DisableBorder = true
+    bitmap := qr.Bitmap()
+
+    var data []byte
+    switch {
+    case strings.HasSuffix(strings.ToLower(path), ".png"):
+        data, err = qrPNG(bitmap, scale, border)
+    case strings.HasSuffix(strings.ToLower(path), ".svg"):
+        data = qrSVG(bitmap, scale, border)
+    default:
+        return fmt.Errorf("%s: the file name must end in .png or .svg", path)
+    }
+    if err != nil {
+        return err
+    }
+
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        return err
+    }
+    if _, err := f.Write(data); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+// 双色调色板 PNG：0 白，1 黑
+func qrPNG(bitmap [][]bool, scale, border int) ([]byte, error) {
+    side := (len(bitmap) + 2*border) * scale
+    img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+    for y, row := range bitmap {
+        for x, dark := range row {
+            if !dark {
+                continue
+            }
+            x0, y0 := (x+border)*scale, (y+border)*scale
+            for dy := 0; dy < scale; dy++ {
+                for dx := 0; dx < scale; dx++ {
+                    img.SetColorIndex(x0+dx, y0+dy, 1)
+                }
+            }
+        }
+    }
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, img); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// 以模块为单位的 viewBox，每行连续的黑色模块合并为一段路径；
+// crispEdges 避免缩放时相邻模块之间出现缝隙
+func qrSVG(bitmap [][]bool, scale, border int) []byte {
+    modules := len(bitmap) + 2*border
+    var b bytes.Buffer
+    fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n",
+        modules*scale, modules*scale, modules, modules)
+    fmt.Fprintf(&b, "<rect width=\"%d\" height=\"%d\" fill=\"#fff\"/>\n", modules, modules)
+    b.WriteString("<path fill=\"#000\" d=\"")
+    for y, row := range bitmap {
+        for x := 0; x < len(row); {
+            if !row[x] {
+                x++
+                continue
+            }
+            run := 1
+            for x+run < len(row) && row[x+run] {
+                run++
+            }
+            fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", x+border, y+border, run, run)
+            x += run
+        }
+    }
+    b.WriteString("\"/>\n</svg>\n")
+    return b.Bytes()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+    "crypto/rand"
+    "flag"
+    "fmt"
+    "log"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    "github.com/skip2/go-qrcode"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+)
+
+//
+// -------------------------
+//   -qr-only 单向二维码模式
+// -------------------------
+//
+// 给气隙机用的“数据二极管”：输入只能是扫描得到的二维码（图片或摄像头），
+// 输出只能是终端上显示的二维码。不读写 binary.txt 或其他任何文件，
+// 不打印明文单词，也没有剪贴板。
+//
+//   -qr-only -b               新生成的助记词只以二维码显示
+//   -qr-only -qr-in IMG|cam   扫描助记词二维码，显示 BIP84 观察钱包描述符的二维码
+//
+
+// 二维码模式允许的选项
+var qrOnlyFlags = map[string]bool{
+    "h":       true,
+    "b":       true,
+    "qr-in":   true,
+    "qr-only": true,
+    "reveal":  true,
+    "tmpdir":  true,
+}
+
+func enforceQROnly() {
+    flag.Visit(func(f *flag.Flag) {
+        if !qrOnlyFlags[f.Name] {
+            log.Fatalf("Error: -%s is not available in QR-only mode.", f.Name)
+        }
+    })
+}
+
+func runQROnly(generate bool, source string, wordList []string) {
+    switch {
+    case generate && source != "":
+        log.Fatalf("Error: use either -b or -qr-in in QR-only mode, not both.")
+    case generate:
+        entropy := make([]byte, 32)
+        if _, err := rand.Read(entropy); err != nil {
+            log.Fatalf("Error generating entropy: %v", err)
+        }
+        fmt.Println("New passphrase QR Code (nothing was written to disk):")
+        printSecretQR(mnemonicFromEntropy(entropy, wordList), qrcode.Low)
+    case source != "":
+        mnemonic, err := scanQR(source)
+        if err != nil {
+            log.Fatalf("Error scanning QR code: %v", err)
+        }
+        if _, err := entropyFromPhrase(mnemonic, wordList); err != nil {
+            log.Fatalf("Error: scanned QR code: %v", err)
+        }
+        desc, err := watchOnlyDescriptor(strings.Join(splitWords(mnemonic), " "))
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        fmt.Println("Checksum: OK")
+        fmt.Println("Watch-only descriptor QR Code (BIP84):")
+        printQR(desc)
+    default:
+        log.Fatalf("Error: QR-only mode needs -b or -qr-in IMG|cam.")
+    }
+}
+
+func printQR(content string) {
+    qr, err := qrcode.New(content, qrcode.Low)
+    if err != nil {
+        log.Fatalf("Error generating QR code: %v", err)
+    }
+    fmt.Println(qr.ToSmallString(false))
+}
+
+// 调用 zbar：cam 用 zbarcam 从摄像头读一个码，否则用 zbarimg 读图片
+func scanQR(source string) (string, error) {
+    var cmd *exec.Cmd
+    var err error
+    if source == "cam" {
+        cmd, err = sandboxCommand("zbarcam", "--raw", "--nodisplay", "-1", "-Sdisable", "-Sqrcode.enable")
+    } else {
+        if source, err = filepath.Abs(source); err != nil {
+            return "", err
+        }
+        cmd, err = sandboxCommand("zbarimg", "--raw", "-q", "-Sdisable", "-Sqrcode.enable", source)
+    }
+    if err != nil {
+        return "", err
+    }
+    out, err := cmd.Output()
+    if err != nil {
+        if _, lookErr := exec.LookPath(cmd.Path); lookErr != nil {
+            return "", fmt.Errorf("%s not found (install zbar-tools)", cmd.Args[0])
+        }
+        return "", fmt.Errorf("no QR code found: %v", err)
+    }
+    codes := strings.Split(strings.TrimSpace(string(out)), "\n")
+    if len(codes) != 1 || codes[0] == "" {
+        return "", fmt.Errorf("expected exactly one QR code, found %d", len(codes))
+    }
+    return strings.TrimSpace(codes[0]), nil
+}
+
+// wpkh([指纹/84h/0h/0h]xpub/0/*)#checksum，Sparrow、Bitcoin Core 等可直接导入
+func watchOnlyDescriptor(mnemonic string) (string, error) {
+    const path = "m/84'/0'/0'"
+    account, err := bip39Master(mnemonic).Derive(path)
+    if err != nil {
+        return "", err
+    }
+    xpub := account.Neuter().Serialize(bip32.VersionXPub)
+    body := fmt.Sprintf("wpkh([%s/84h/0h/0h]%s/0/*)", masterFingerprint(mnemonic), xpub)
+    sum, err := descriptorChecksum(body)
+    if err != nil {
+        return "", err
+    }
+    return body + "#" + sum, nil
+}
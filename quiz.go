@@ -0,0 +1,116 @@
+package main
+
+import (
+    "crypto/rand"
+    "fmt"
+    "log"
+    "math/big"
+    "os"
+    "strings"
+
+    "golang.org/x/term"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -quiz 抄写后的抽查
+// -------------------------
+//
+// 与硬件钱包一样：-no-file 或 -p 显示助记词后，等用户抄好按 Enter，
+// 清屏（含回滚缓冲），再随机抽 quizWords 个位置让用户照纸上的抄写
+// 不回显地输入。每个位置可以重试，输入 show 重新显示全部单词并换一组
+// 位置，直接按 Enter 放弃。全部答对才算通过；放弃时退出码为 1。
+// -no-file 的助记词此后不再存在，所以通过之前不要离开。
+//
+
+const quizWords = 3
+
+// 0..n-1 的随机排列
+func randomPositions(n int) ([]int, error) {
+    positions := make([]int, n)
+    for i := range positions {
+        positions[i] = i
+    }
+    for i := n - 1; i > 0; i-- {
+        j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+        if err != nil {
+            return nil, err
+        }
+        positions[i], positions[j.Int64()] = positions[j.Int64()], positions[i]
+    }
+    return positions, nil
+}
+
+func clearTerminal() {
+    if term.IsTerminal(int(os.Stdout.Fd())) {
+        fmt.Print("\x1b[2J\x1b[H\x1b[3J")
+    }
+}
+
+func runQuiz(mnemonic string, wordList []string) {
+    if !shouldReveal() {
+        log.Fatalf("Error: -quiz: the words were masked; answer y or use -reveal to see them first.")
+    }
+    fp := masterFingerprint(mnemonic)
+    words := strings.Fields(mnemonic)
+    matcher := wordmatch.New(wordList)
+
+    fmt.Println()
+    fmt.Print("Press Enter when the words are on paper; the screen is cleared before the check. ")
+    if _, err := readLine(); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    clearTerminal()
+
+    for {
+        positions, err := randomPositions(len(words))
+        if err != nil {
+            log.Fatalf("Error generating entropy: %v", err)
+        }
+        fmt.Printf("Backup check: type %d words from your paper, not from memory (the first four letters are enough).\n", quizWords)
+        fmt.Println("Type show to see all the words again, or press Enter to give up.")
+        again := false
+        for n, pos := range positions[:quizWords] {
+            for {
+                answer, err := readSecret(fmt.Sprintf("Check %d of %d, word %d: ", n+1, quizWords, pos+1))
+                if err != nil {
+                    log.Fatalf("Error: %v", err)
+                }
+                answer = strings.TrimSpace(answer)
+                if answer == "" {
+                    fmt.Println("Backup check FAIL: given up.")
+                    fmt.Println("Do not rely on this backup until every checked word is correct.")
+                    transcript.record("quiz", "FAIL", fp, "given up")
+                    transcript.finish()
+                    os.Exit(1)
+                }
+                if strings.EqualFold(answer, "show") {
+                    printPhrase(mnemonic)
+                    fmt.Print("Press Enter to clear the screen and start again with other words. ")
+                    if _, err := readLine(); err != nil {
+                        log.Fatalf("Error: %v", err)
+                    }
+                    clearTerminal()
+                    again = true
+                    break
+                }
+                if c, ok := matcher.Lookup(answer); ok && secretcmp.EqualString(c.Word, words[pos]) {
+                    break
+                }
+                fmt.Printf("That is not word %d. Check your paper carefully and try again.\n", pos+1)
+            }
+            if again {
+                break
+            }
+        }
+        if !again {
+            break
+        }
+    }
+
+    fmt.Printf("Backup check PASS: %d of %d words correct.\n", quizWords, quizWords)
+    transcript.record("quiz", "PASS", fp, fmt.Sprintf("%d words", quizWords))
+}
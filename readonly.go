@@ -0,0 +1,73 @@
+package main
+
+import (
+    "flag"
+    "log"
+)
+
+//
+// -------------------------
+//   -read-only 只读模式
+// -------------------------
+//
+// 只读模式下只能检查、校验、查看公开信息，不能生成、导入或显示任何秘密。
+// 用 -tags readonly 构建时 buildReadOnly 为常量 true，所有涉及秘密的分支
+// 都被编译器删掉，二进制中根本没有这些代码。
+//
+
+// 只读模式允许的选项
+var readOnlyFlags = map[string]bool{
+    "h":               true,
+    "version":         true,
+    "schema":          true,
+    "i":               true,
+    "v":               true,
+    "qr-decode":       true,
+    "check-bits":      true,
+    "wordlist":        true,
+    "f":               true, // -qr-decode 比较用
+    "group-bits":      true,
+    "tmpdir":          true, // -qr-decode 调用 zbarimg
+    "groups-per-line": true,
+    "selftest":        true,
+    "canonical":       true,
+    "bench":           true,
+    "jobs":            true,
+    "rngtest":         true,
+    "rng-device":      true,
+    "min-entropy":     true,
+    "wordlist-check":  true,
+    "wordlist-sort":   true,
+    "ledger":          true,
+    "devices":         true,
+    "hints":           true,
+    "hint-check":      true,
+    "bundle-verify":   true,
+    "import":          true, // 仅 -from descriptor
+    "from":            true,
+    "metrics":         true,
+    "uri":             true, // 仅地址，不能用 N
+    "xpub-sheet":      true,
+    "addr-type":       true,
+    "start":           true,
+    "count":           true,
+    "amount":          true,
+    "label":           true,
+    "message":         true,
+    "transcript":      true,
+    "stats":           true,
+    "stats-show":      true,
+    "read-only":       true,
+    "slip39-audit":    true,
+}
+
+func enforceReadOnly(fs *flag.FlagSet, format string) {
+    fs.Visit(func(f *flag.Flag) {
+        if !readOnlyFlags[f.Name] {
+            log.Fatalf("Error: -%s is not available in read-only mode.", f.Name)
+        }
+    })
+    if format != "" && format != "descriptor" {
+        log.Fatalf("Error: only -from descriptor is available in read-only mode.")
+    }
+}
@@ -0,0 +1,5 @@
+//go:build !readonly
+
+package main
+
+const buildReadOnly = false
@@ -0,0 +1,6 @@
+//go:build readonly
+
+package main
+
+// 只读构建：go build -tags readonly
+const buildReadOnly = true
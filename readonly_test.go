@@ -0,0 +1,42 @@
+package main
+
+import (
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// go build -tags readonly 的二进制里不能有显示秘密的代码
+func TestReadOnlyBuildHasNoSecretOutput(t *testing.T) {
+    if testing.Short() {
+        t.Skip("builds the readonly binary")
+    }
+    bin := filepath.Join(t.TempDir(), "passphrase_bitcoin")
+    if out, err := exec.Command("go", "build", "-tags", "readonly", "-o", bin, ".").CombinedOutput(); err != nil {
+        t.Fatalf("go build -tags readonly: %v\n%s", err, out)
+    }
+    out, err := exec.Command("go", "tool", "nm", bin).Output()
+    if err != nil {
+        t.Fatalf("go tool nm: %v", err)
+    }
+    symbols := map[string]bool{}
+    for _, line := range strings.Split(string(out), "\n") {
+        if fields := strings.Fields(line); len(fields) == 3 {
+            symbols[fields[2]] = true
+        }
+    }
+    for _, name := range []string{
+        "printEntropyPhrase", "printPhrase", "printExplanation", "maskPhrase", "printRomanization",
+        "printSecretQR", "printDecoySheet", "printDecoyPhrases", "printBackupSheet", "printDecimalIndices",
+        "printPunchGrid", "printRSBackup", "printOffsetPhrase", "printMemoryOnly", "printSeed",
+        "showDeviceSeed", "generatePassphraseFromBinary", "runQuiz", "clipSecret",
+    } {
+        if symbols["main."+name] {
+            t.Errorf("readonly binary contains main.%s", name)
+        }
+    }
+    if !symbols["main.enforceReadOnly"] {
+        t.Error("main.enforceReadOnly not found; symbol names changed?")
+    }
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/base58"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/seedkdf"
+)
+
+//
+// -------------------------
+//   -recover-passphrase 口令暴力恢复
+// -------------------------
+//
+
+const recoverChunk = 64
+
+// -gap 的上限（-recover-passphrase、-balance-check 与 -rotate-plan 共用）
+const maxGap = 1000
+
+type recoveryTarget struct {
+    fingerprint []byte
+    addrType    btcaddr.Type
+    address     string
+    gap         int
+}
+
+// 目标：8 位十六进制主指纹，或 1…/3…/bc1q…/bc1p… 地址
+func parseRecoveryTarget(s string, gap int) (*recoveryTarget, error) {
+    s = strings.TrimSpace(s)
+    if fp, err := hex.DecodeString(s); err == nil && len(fp) == 4 {
+        return &recoveryTarget{fingerprint: fp}, nil
+    }
+
+    t := &recoveryTarget{address: s, gap: gap}
+    lower := strings.ToLower(s)
+    switch {
+    case strings.HasPrefix(lower, "bc1q"):
+        t.addrType = btcaddr.P2WPKH
+    case strings.HasPrefix(lower, "bc1p"):
+        t.addrType = btcaddr.P2TR
+    case strings.HasPrefix(s, "1"):
+        t.addrType = btcaddr.P2PKH
+    case strings.HasPrefix(s, "3"):
+        t.addrType = btcaddr.P2SHP2WPKH
+    default:
+        return nil, fmt.Errorf("target must be a master fingerprint (8 hex) or a mainnet address")
+    }
+
+    if t.addrType == btcaddr.P2WPKH || t.addrType == btcaddr.P2TR {
+        if _, _, err := btcaddr.SegwitDecode("bc", lower); err != nil {
+            return nil, err
+        }
+        t.address = lower
+    } else if _, err := base58.CheckDecode(s); err != nil {
+        return nil, fmt.Errorf("invalid address: %v", err)
+    }
+    return t, nil
+}
+
+func (t *recoveryTarget) matches(seed []byte) (bool, error) {
+    master, err := bip32.NewMaster(seed)
+    if err != nil {
+        return false, err
+    }
+    if t.fingerprint != nil {
+        fp := master.Fingerprint()
+        return secretcmp.Equal(fp[:], t.fingerprint), nil
+    }
+
+    // m/purpose'/0'/0'/0/i，i < gap
+    chain, err := master.Derive(fmt.Sprintf("m/%d'/0'/0'/0", t.addrType.Purpose()))
+    if err != nil {
+        return false, err
+    }
+    chain = chain.Neuter()
+    for i := 0; i < t.gap; i++ {
+        child, err := chain.Child(uint32(i))
+        if err != nil {
+            continue
+        }
+        addr, err := btcaddr.Encode(t.addrType, child.PublicKey())
+        if err != nil {
+            return false, err
+        }
+        if addr == t.address {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// 断点里只保存助记词的哈希前缀，用于确认属于同一个助记词
+func mnemonicID(mnemonic string) string {
+    sum := sha256.Sum256([]byte("checkpoint:" + mnemonic))
+    return hex.EncodeToString(sum[:8])
+}
+
+// 记录已完成的块，得到“之前全部完成”的断点位置
+type chunkTracker struct {
+    mu      sync.Mutex
+    next    uint64 // 下一个待分配的块
+    done    map[uint64]bool
+    settled uint64 // 此前的块全部完成
+    total   uint64
+}
+
+func (c *chunkTracker) take() (uint64, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if c.next*recoverChunk >= c.total {
+        return 0, false
+    }
+    n := c.next
+    c.next++
+    return n, true
+}
+
+func (c *chunkTracker) finish(n uint64) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.done[n] = true
+    for c.done[c.settled] {
+        delete(c.done, c.settled)
+        c.settled++
+    }
+}
+
+func (c *chunkTracker) resumeIndex() uint64 {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return min(c.settled*recoverChunk, c.total)
+}
+
+func recoverPassphrase(mnemonic, targetSpec, patternSpec, checkpointPath string, resume bool, gap int) {
+    if patternSpec == "" || targetSpec == "" {
+        log.Fatalf("Error: -recover-passphrase needs -target and -pattern")
+    }
+    target, err := parseRecoveryTarget(targetSpec, gap)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    pattern, err := parsePassPattern(patternSpec)
+    if err != nil {
+        log.Fatalf("Error: pattern: %v", err)
+    }
+    count := pattern.count()
+    if !count.IsInt64() {
+        log.Fatalf("Error: pattern has %s candidates, narrow it down", count)
+    }
+    total := count.Uint64()
+
+    var (
+        cp    *checkpointFile
+        start uint64
+    )
+    if checkpointPath != "" {
+        params := map[string]string{
+            "pattern":     patternSpec,
+            "target":      targetSpec,
+            "gap":         fmt.Sprint(gap),
+            "mnemonic_id": mnemonicID(mnemonic),
+        }
+        if seedKDFName != seedkdf.Default {
+            params["kdf"] = seedKDFName
+        }
+        cp, start, err = openCheckpoint(checkpointPath, resume, jobCheckpoint{
+            Kind:   "recover-passphrase",
+            Params: params,
+            Total:  total,
+        })
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if start > 0 {
+            fmt.Printf("Resuming from candidate %d.\n", start)
+        }
+    } else if resume {
+        log.Fatalf("Error: -resume needs -checkpoint FILE")
+    }
+
+    // 以块为单位；断点恢复时从 start 所在块开始
+    firstChunk := start / recoverChunk
+    tracker := &chunkTracker{next: firstChunk, settled: firstChunk, done: map[uint64]bool{}, total: total}
+
+    metrics := startMetrics("recover-passphrase", "candidates")
+    workers := workerCount(seedkdf.Memory(seedKDF))
+    batch := uint64(seedkdf.BatchSize(seedKDF))
+    fmt.Printf("Searching %d candidates with %d workers.\n", total-start, workers)
+
+    var (
+        found     *string
+        foundOnce sync.Once
+        stop      = make(chan struct{})
+        wg        sync.WaitGroup
+    )
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for {
+                select {
+                case <-stop:
+                    return
+                default:
+                }
+                n, ok := tracker.take()
+                if !ok {
+                    return
+                }
+                // 一次交给 KDF 一批候选，向量化的 PBKDF2 可以并行计算
+                end := min((n+1)*recoverChunk, total)
+                for i := n * recoverChunk; i < end; i += batch {
+                    candidates := make([]string, 0, batch)
+                    for j := i; j < min(i+batch, end); j++ {
+                        candidates = append(candidates, pattern.candidate(j))
+                    }
+                    for j, seed := range mnemonicToSeeds(mnemonic, candidates) {
+                        ok, err := target.matches(seed)
+                        metrics.add("candidates", 1)
+                        if err != nil {
+                            metrics.fail("derivation")
+                        }
+                        if err == nil && ok {
+                            foundOnce.Do(func() {
+                                found = &candidates[j]
+                                close(stop)
+                            })
+                            return
+                        }
+                    }
+                }
+                tracker.finish(n)
+            }
+        }()
+    }
+
+    done := make(chan struct{})
+    go func() {
+        wg.Wait()
+        close(done)
+    }()
+
+    began := time.Now()
+    ticker := time.NewTicker(5 * time.Second)
+    defer ticker.Stop()
+    for running := true; running; {
+        select {
+        case <-done:
+            running = false
+        case <-ticker.C:
+            idx := tracker.resumeIndex()
+            rate := float64(idx-start) / time.Since(began).Seconds()
+            fmt.Fprintf(os.Stderr, "Tried %d/%d (%.0f/s)\n", idx, total, rate)
+            if cp != nil {
+                if err := cp.save(idx); err != nil {
+                    log.Printf("Warning: saving checkpoint: %v", err)
+                }
+            }
+        }
+    }
+
+    if cp != nil {
+        cp.remove()
+    }
+    // 空口令也是合法结果
+    if found != nil {
+        metrics.add("found", 1)
+    }
+    metrics.finish()
+    if found != nil {
+        fmt.Println("Passphrase found:")
+        fmt.Printf("%q\n", *found)
+        var paths []string
+        if target.address != "" {
+            paths = append(paths, fmt.Sprintf("m/%d'/0'/0'/0/0..%d", target.addrType.Purpose(), target.gap-1))
+        }
+        newDerivationParams(seedKDF, paths...).print()
+        return
+    }
+    fmt.Println("No candidate matched the target.")
+    os.Exit(1)
+}
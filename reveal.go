@@ -0,0 +1,95 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    qrcode "github.com/skip2/go-qrcode"
+    "golang.org/x/term"
+)
+
+//
+// -------------------------
+//   终端上默认遮住单词
+// -------------------------
+//
+// 助记词打印到终端时默认只显示每个单词的首字母（l*** w*** t*** …），
+// 以免被旁人看到、留在回滚缓冲或 tmux 日志里。第一次要显示时询问，
+// 回答 y 后本次运行中的助记词都照常显示；-reveal 不询问直接显示。
+// 输出不是终端（重定向到文件或管道）、-demo 与 -json 时不遮。
+// 不足 3 个字符的单词（中文、部分日文）整个遮住，否则首字就是全部。
+// 与单词等价的输出（二维码、-d 的序号、-g 的打孔网格、-s 备份纸、-decoy）
+// 经过同一个 shouldReveal：未确认时不显示或同样遮住。
+//
+
+var (
+    revealWords bool // -reveal，或已回答 y
+    revealAsked bool
+
+    // 测试中可替换
+    isTerminal = func(f *os.File) bool { return term.IsTerminal(int(f.Fd())) }
+)
+
+// 代替 fmt.Println(formatPhrase(phrase))
+func printPhrase(phrase string) {
+    if shouldReveal() {
+        fmt.Println(formatPhrase(phrase))
+        if romanizeWords {
+            printRomanization(phrase)
+        }
+        return
+    }
+    fmt.Println(formatPhrase(maskPhrase(phrase)))
+}
+
+func shouldReveal() bool {
+    if revealWords || demoMode || !isTerminal(os.Stdout) {
+        return true
+    }
+    if revealAsked {
+        return false
+    }
+    revealAsked = true
+    if !isTerminal(os.Stdin) {
+        fmt.Fprintln(os.Stderr, "The words are hidden on screen; use -reveal to show them.")
+        return false
+    }
+    fmt.Fprint(os.Stderr, "Show the words on screen? Make sure no one can see it and it is not recorded. [y/N] ")
+    line, err := readLine()
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if strings.EqualFold(strings.TrimSpace(line), "y") {
+        revealWords = true
+        return true
+    }
+    fmt.Fprintln(os.Stderr, "Words hidden; answer y or use -reveal to show them.")
+    return false
+}
+
+func maskPhrase(phrase string) string {
+    words := strings.Fields(phrase)
+    for i, w := range words {
+        if r := []rune(w); len(r) >= 3 {
+            words[i] = string(r[0]) + "***"
+        } else {
+            words[i] = "****"
+        }
+    }
+    return strings.Join(words, " ")
+}
+
+// 代替 qrcode.New + ToSmallString：二维码里就是助记词
+func printSecretQR(content string, level qrcode.RecoveryLevel) {
+    if !shouldReveal() {
+        fmt.Println("(QR code hidden: it holds the words; answer y or use -reveal)")
+        return
+    }
+    qr, err := qrcode.New(content, level)
+    if err != nil {
+        log.Fatalf("Error generating QR code: %v", err)
+    }
+    fmt.Println(qr.ToSmallString(false))
+}
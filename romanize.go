@@ -0,0 +1,112 @@
+package main
+
+import (
+    "embed"
+    "fmt"
+    "strings"
+    "unicode"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+//
+// -------------------------
+//   -romanize 中日韩词表的拉丁转写
+// -------------------------
+//
+// 没有输入法、需要口述或抄写时，在单词旁边显示拉丁字母转写：中文为带声调的
+// 拼音（多音字取最常见的读音），日文为按键盘输入习惯的罗马字（长音 ou），
+// 韩文为按发音的国语罗马字（가격 → gagyeok，국민 → gungmin）。
+// 数据由 go generate 生成（见 internal/mkromanization）：中日文用 ICU，韩文按规则。
+// 拼音不唯一，许多汉字读音相同，最终以汉字为准。
+//
+
+//go:generate go run ./internal/mkromanization
+
+//go:embed embed/romanization/*.txt
+var romanizationFS embed.FS
+
+var romanizeWords bool
+
+func romanizationLanguages() []string {
+    return []string{"chinese_simplified", "chinese_traditional", "japanese", "korean"}
+}
+
+// 当前词表的单词 → 转写；没有转写时为 nil
+func romanizationTable() map[string]string {
+    if customWordlist != "" {
+        return nil
+    }
+    data, err := romanizationFS.ReadFile("embed/romanization/" + phraseLanguage + ".txt")
+    if err != nil {
+        return nil
+    }
+    words, err := bip39.Wordlist(phraseLanguage)
+    if err != nil {
+        return nil
+    }
+    lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+    if len(lines) != len(words) {
+        return nil
+    }
+    table := make(map[string]string, len(words))
+    for i, w := range words {
+        table[w] = lines[i]
+    }
+    return table
+}
+
+// -i 的单词转写
+func printWordRomanization(word string) {
+    if !romanizeWords {
+        return
+    }
+    if r, ok := romanizationTable()[word]; ok {
+        fmt.Println("Romanization:", r)
+    }
+}
+
+// 助记词之后按序号列出单词与转写，每行四个
+func printRomanization(phrase string) {
+    table := romanizationTable()
+    if table == nil {
+        return
+    }
+    words := strings.Fields(phrase)
+    fmt.Println("Romanization:")
+    var line strings.Builder
+    for i, w := range words {
+        fmt.Fprintf(&line, "%4d. %s %-12s", i+1, w, table[textnorm.NFKD(w)])
+        if (i+1)%4 == 0 || i == len(words)-1 {
+            fmt.Println(strings.TrimRight(line.String(), " "))
+            line.Reset()
+        }
+    }
+}
+
+// -i 按转写查找：日文、韩文唯一，拼音可能对应多个汉字
+func wordsForRomanization(input string, wordList []string) []int {
+    table := romanizationTable()
+    if table == nil {
+        return nil
+    }
+    var found []int
+    for i, w := range wordList {
+        // 不带声调的输入匹配所有声调
+        if r := textnorm.NFKD(table[w]); r == input || stripMarks(input) == input && stripMarks(r) == input {
+            found = append(found, i)
+        }
+    }
+    return found
+}
+
+// 去掉声调等组合符号（输入已是 NFKD）：yī → yi
+func stripMarks(s string) string {
+    return strings.Map(func(r rune) rune {
+        if unicode.Is(unicode.Mn, r) {
+            return -1
+        }
+        return r
+    }, s)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+// 抽查嵌入的转写表：拼音、罗马字、韩文按发音的国语罗马字
+func TestRomanizationTable(t *testing.T) {
+    tests := []struct {
+        lang, word, want string
+    }{
+        {"chinese_simplified", "的", "de"},
+        {"chinese_simplified", "一", "yī"},
+        {"chinese_traditional", "是", "shì"},
+        {"japanese", "あいさつ", "aisatsu"},
+        {"japanese", "おしゃれ", "oshare"},
+        {"korean", "가격", "gagyeok"},
+        {"korean", "관리", "gwalli"},
+        {"korean", "국왕", "gugwang"},
+        {"korean", "대한민국", "daehanminguk"},
+    }
+    defer func(lang string) { phraseLanguage = lang }(phraseLanguage)
+    for _, tt := range tests {
+        phraseLanguage = tt.lang
+        got, ok := romanizationTable()[textnorm.NFKD(tt.word)]
+        if !ok {
+            t.Errorf("%s: %s is not in the table", tt.lang, tt.word)
+            continue
+        }
+        if textnorm.NFKD(got) != textnorm.NFKD(tt.want) {
+            t.Errorf("%s: %s = %q, want %q", tt.lang, tt.word, got, tt.want)
+        }
+    }
+}
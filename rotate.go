@@ -0,0 +1,156 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   -rotate-plan 换钥迁移计划
+// -------------------------
+//
+// 旧助记词 → binary.txt 中的新助记词。计划只含公开信息（指纹、账户 xpub、
+// 地址），写成 JSON 供钱包软件使用，同时打印一份人工核对清单。
+// xpub 会暴露全部地址，计划文件仍应妥善保管。
+//
+
+type rotationPlan struct {
+    Version int            `json:"version"`
+    Created string         `json:"created"`
+    Old     rotationWallet `json:"old"`
+    New     rotationWallet `json:"new"`
+    Steps   []string       `json:"steps"`
+    Params  derivationParams `json:"params"`
+}
+
+type rotationWallet struct {
+    Fingerprint string            `json:"fingerprint"`
+    Accounts    []rotationAccount `json:"accounts"`
+}
+
+type rotationAccount struct {
+    Type    string   `json:"type"`
+    Path    string   `json:"path"`
+    XPub    string   `json:"xpub"`
+    Receive []string `json:"receive"`
+    Change  []string `json:"change,omitempty"`
+}
+
+var rotationSteps = []string{
+    "Check every old address for history and balance in a watch-only wallet built from the old xpubs.",
+    "Create the new wallet from the new passphrase and confirm its fingerprint matches this plan.",
+    "Send a small test amount from the old wallet to the first new receive address and confirm it arrives.",
+    "Sweep the remaining funds, account by account, to fresh new receive addresses.",
+    "Re-check the old addresses: all balances must be zero.",
+    "Update anyone who pays you to use the new addresses, then retire the old backup.",
+}
+
+func rotationAccountFor(master *bip32.Key, t btcaddr.Type, n int, withChange bool) (rotationAccount, error) {
+    path := fmt.Sprintf("m/%d'/0'/0'", t.Purpose())
+    account, err := master.Derive(path)
+    if err != nil {
+        return rotationAccount{}, err
+    }
+    account = account.Neuter()
+    a := rotationAccount{Type: t.String(), Path: path, XPub: account.Serialize(bip32.VersionXPub)}
+
+    branches := []*[]string{&a.Receive}
+    if withChange {
+        branches = append(branches, &a.Change)
+    }
+    for branch, out := range branches {
+        chain, err := account.Child(uint32(branch))
+        if err != nil {
+            return a, err
+        }
+        for i := 0; i < n; i++ {
+            child, err := chain.Child(uint32(i))
+            if err != nil {
+                return a, err
+            }
+            addr, err := btcaddr.Encode(t, child.PublicKey())
+            if err != nil {
+                return a, err
+            }
+            *out = append(*out, addr)
+        }
+    }
+    return a, nil
+}
+
+func writeRotationPlan(path, oldMnemonic string, gap int, wordList []string) {
+    if oldMnemonic == "" {
+        s, err := readSecret("Old mnemonic: ")
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        oldMnemonic = s
+    }
+    if _, err := entropyFromPhrase(oldMnemonic, wordList); err != nil {
+        log.Fatalf("Error: old mnemonic: %v", err)
+    }
+    oldMnemonic = strings.Join(splitWords(oldMnemonic), " ")
+    newMnemonic := generatePassphraseFromBinary(wordList)
+    if secretcmp.EqualMnemonic(oldMnemonic, newMnemonic) {
+        log.Fatalf("Error: old mnemonic is the same as binary.txt")
+    }
+
+    plan := rotationPlan{
+        Version: 1,
+        Created: time.Now().UTC().Format(time.RFC3339),
+        Old:     rotationWallet{Fingerprint: masterFingerprint(oldMnemonic)},
+        New:     rotationWallet{Fingerprint: masterFingerprint(newMnemonic)},
+        Steps:   rotationSteps,
+        Params:  newDerivationParams(nil, "m/44'/0'/0'/{0,1}/i", "m/49'/0'/0'/{0,1}/i", "m/84'/0'/0'/{0,1}/i", "m/86'/0'/0'/{0,1}/i"),
+    }
+
+    // 旧钱包：四种地址类型的收款与找零地址都要检查；新钱包：BIP84 收款地址
+    oldMaster := bip39Master(oldMnemonic)
+    for _, t := range []btcaddr.Type{btcaddr.P2PKH, btcaddr.P2SHP2WPKH, btcaddr.P2WPKH, btcaddr.P2TR} {
+        a, err := rotationAccountFor(oldMaster, t, gap, true)
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        plan.Old.Accounts = append(plan.Old.Accounts, a)
+    }
+    a, err := rotationAccountFor(bip39Master(newMnemonic), btcaddr.P2WPKH, gap, false)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    plan.New.Accounts = append(plan.New.Accounts, a)
+
+    data, err := json.MarshalIndent(plan, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error creating %s: %v", path, err)
+    }
+    _, err = f.Write(append(data, '\n'))
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    if err != nil {
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+
+    fmt.Printf("Rotation plan written to %s.\n", path)
+    fmt.Printf("Old wallet %s → new wallet %s\n", plan.Old.Fingerprint, plan.New.Fingerprint)
+    fmt.Println()
+    for i, step := range plan.Steps {
+        fmt.Printf("[ ] %d. %s\n", i+1, step)
+    }
+    fmt.Println()
+    fmt.Printf("First new receive address: %s\n", plan.New.Accounts[0].Receive[0])
+    plan.Params.print()
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   Reed–Solomon 校验词
+// -------------------------
+//
+// 每个单词是 GF(2^11) 上的一个符号。n 个单词决定一个次数 < n 的多项式，
+// 在 α^0 … α^(n-1) 处取值即单词本身，在 α^n … α^(n+k-1) 处取值即 k 个
+// 校验词。任意 n 个已知位置都能插值还原其余位置；可读但写错的单词也能
+// 纠正，只要 2 × 错误数 + 缺失数 ≤ k。
+//
+
+// -rs 最多的校验词数
+const rsMaxParity = 24
+
+const (
+    gf11Size = 2048
+    gf11Poly = 0x805 // x^11 + x^2 + 1
+)
+
+var gf11Exp, gf11Log [gf11Size * 2]int
+
+func init() {
+    x := 1
+    for i := 0; i < gf11Size-1; i++ {
+        gf11Exp[i] = x
+        gf11Log[x] = i
+        x <<= 1
+        if x&gf11Size != 0 {
+            x ^= gf11Poly
+        }
+    }
+    for i := gf11Size - 1; i < len(gf11Exp); i++ {
+        gf11Exp[i] = gf11Exp[i-(gf11Size-1)]
+    }
+}
+
+func gf11Mul(a, b int) int {
+    if a == 0 || b == 0 {
+        return 0
+    }
+    return gf11Exp[gf11Log[a]+gf11Log[b]]
+}
+
+func gf11Div(a, b int) int {
+    if a == 0 {
+        return 0
+    }
+    return gf11Exp[gf11Log[a]+gf11Size-1-gf11Log[b]]
+}
+
+// 拉格朗日插值：由已知点 (xs, ys) 求 x 处的值
+func gf11Interpolate(xs, ys []int, x int) int {
+    result := 0
+    for i := range xs {
+        term := ys[i]
+        for j := range xs {
+            if i == j {
+                continue
+            }
+            term = gf11Mul(term, gf11Div(x^xs[j], xs[i]^xs[j]))
+        }
+        result ^= term
+    }
+    return result
+}
+
+func rsParity(data []int, k int) []int {
+    xs := make([]int, len(data))
+    for i := range data {
+        xs[i] = gf11Exp[i]
+    }
+    parity := make([]int, k)
+    for j := 0; j < k; j++ {
+        parity[j] = gf11Interpolate(xs, data, gf11Exp[len(data)+j])
+    }
+    return parity
+}
+
+// symbols 中 -1 表示缺失（擦除）。Berlekamp–Welch 译码：m 个已知位置、
+// 次数 < n 的多项式 P，可在 2e ≤ m-n 时纠正 e 个错误。求次数 < n+e 的 Q
+// 与首一、次数 e 的 E，使每个已知位置满足 Q(x) = y·E(x)；E 的根就是出错
+// 的位置，P = Q/E。返回前 n 个数据符号与被补全或纠正的位置（从 0 起）
+func rsRecover(symbols []int, n int) ([]int, []int, error) {
+    var xs, ys []int
+    for i, s := range symbols {
+        if s >= 0 {
+            xs = append(xs, gf11Exp[i])
+            ys = append(ys, s)
+        }
+    }
+    m, k := len(xs), len(symbols)-n
+    missing := len(symbols) - m
+    if m < n {
+        return nil, nil, fmt.Errorf("%d words missing, at most %d can be recovered", missing, k)
+    }
+    e := (m - n) / 2
+
+    // 未知数：Q 的 n+e 个系数，E 的前 e 个系数（最高项为 1）；
+    // 特征为 2，y·E(x) 移到左边不变号。n ≥ 1，所以 x^e 在循环中出现
+    rows := make([][]int, m)
+    for r := range rows {
+        row := make([]int, n+2*e+1)
+        p := 1
+        for j := 0; j < n+e; j++ {
+            row[j] = p
+            if j < e {
+                row[n+e+j] = gf11Mul(ys[r], p)
+            }
+            if j == e {
+                row[n+2*e] = gf11Mul(ys[r], p)
+            }
+            p = gf11Mul(p, xs[r])
+        }
+        rows[r] = row
+    }
+    sol, ok := gf11Solve(rows, n+2*e)
+    if !ok {
+        return nil, nil, rsTooDamaged(missing, k)
+    }
+    locator := append(append([]int{}, sol[n+e:]...), 1)
+    poly, rem := gf11PolyDiv(sol[:n+e], locator)
+    for _, c := range rem {
+        if c != 0 {
+            return nil, nil, rsTooDamaged(missing, k)
+        }
+    }
+
+    var fixed []int
+    errors := 0
+    for i, s := range symbols {
+        v := gf11Eval(poly, gf11Exp[i])
+        if v != s {
+            fixed = append(fixed, i)
+            if s >= 0 {
+                errors++
+            }
+        }
+    }
+    if 2*errors+missing > k {
+        return nil, nil, rsTooDamaged(missing, k)
+    }
+
+    data := make([]int, n)
+    for i := range data {
+        data[i] = gf11Eval(poly, gf11Exp[i])
+    }
+    return data, fixed, nil
+}
+
+func rsTooDamaged(missing, k int) error {
+    return fmt.Errorf("too many damaged words: with %d parity words, 2 × wrong words + %d missing must be at most %d", k, missing, k)
+}
+
+// 高斯消元求 rows·x = 最后一列；自由变量取 0，方程矛盾时 ok 为 false
+func gf11Solve(rows [][]int, vars int) ([]int, bool) {
+    pivots := make([]int, 0, vars)
+    r := 0
+    for c := 0; c < vars && r < len(rows); c++ {
+        p := -1
+        for i := r; i < len(rows); i++ {
+            if rows[i][c] != 0 {
+                p = i
+                break
+            }
+        }
+        if p < 0 {
+            continue
+        }
+        rows[r], rows[p] = rows[p], rows[r]
+        inv := gf11Div(1, rows[r][c])
+        for j := c; j <= vars; j++ {
+            rows[r][j] = gf11Mul(rows[r][j], inv)
+        }
+        for i := range rows {
+            if i == r || rows[i][c] == 0 {
+                continue
+            }
+            f := rows[i][c]
+            for j := c; j <= vars; j++ {
+                rows[i][j] ^= gf11Mul(f, rows[r][j])
+            }
+        }
+        pivots = append(pivots, c)
+        r++
+    }
+    for i := r; i < len(rows); i++ {
+        if rows[i][vars] != 0 {
+            return nil, false
+        }
+    }
+    sol := make([]int, vars)
+    for i, c := range pivots {
+        sol[c] = rows[i][vars]
+    }
+    return sol, true
+}
+
+// 多项式系数从低次到高次；除数最高项为 1
+func gf11PolyDiv(num, den []int) (quot, rem []int) {
+    rem = append([]int{}, num...)
+    d := len(den) - 1
+    if len(rem) <= d {
+        return nil, rem
+    }
+    quot = make([]int, len(rem)-d)
+    for i := len(rem) - 1; i >= d; i-- {
+        c := rem[i]
+        quot[i-d] = c
+        if c == 0 {
+            continue
+        }
+        for j := 0; j <= d; j++ {
+            rem[i-d+j] ^= gf11Mul(c, den[j])
+        }
+    }
+    return quot, rem[:d]
+}
+
+func gf11Eval(poly []int, x int) int {
+    v := 0
+    for i := len(poly) - 1; i >= 0; i-- {
+        v = gf11Mul(v, x) ^ poly[i]
+    }
+    return v
+}
+
+func printRSBackup(k int, wordList []string) {
+    if k < 1 || k > rsMaxParity {
+        log.Fatalf("Error: parity word count must be 1–%d", rsMaxParity)
+    }
+    indices := passphraseIndicesFromBinary()
+    parity := rsParity(indices, k)
+
+    fmt.Println("Passphrase:")
+    printPhrase(wordsFromIndices(indices, wordList))
+    fmt.Printf("Reed–Solomon parity words (%d):\n", k)
+    printPhrase(wordsFromIndices(parity, wordList))
+}
+
+// 输入：n 个单词 + k 个校验词，无法辨认的位置写 "?"。n 为 0 时由输入推断：
+// 依次尝试 12、15 … 24 个单词，取第一个能译码且通过 BIP39 校验的。
+// 较短助记词的码字也是较长码的码字，所以必须从小到大试
+func recoverRSBackup(input string, n int, wordList []string) {
+    matcher := wordmatch.New(wordList)
+    fields := splitWords(input)
+    if n != 0 && len(fields) <= n {
+        log.Fatalf("Error: expected %d words followed by parity words, got %d words", n, len(fields))
+    }
+
+    symbols := make([]int, len(fields))
+    for i, f := range fields {
+        c, ok := matcher.Lookup(f)
+        idx := c.Index
+        if !ok {
+            if f != "?" {
+                fmt.Printf("Word %d '%s' is not on the list, treating it as missing.\n", i+1, f)
+            }
+            idx = -1
+        }
+        symbols[i] = idx
+    }
+
+    data, fixed, err := rsDecode(symbols, n)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if n == 0 {
+        fmt.Printf("Read %d words and %d parity words.\n", len(data), len(symbols)-len(data))
+    }
+    if len(fixed) > 0 {
+        fmt.Printf("Repaired: %s\n", rsPositions(fixed, symbols, len(data)))
+    }
+    fmt.Println("Recovered passphrase:")
+    printPhrase(wordsFromIndices(data, wordList))
+}
+
+// 按 n 个单词译码并做 BIP39 校验；n 为 0 时推断单词数
+func rsDecode(symbols []int, n int) ([]int, []int, error) {
+    counts := []int{n}
+    if n == 0 {
+        counts = []int{12, 15, 18, 21, 24}
+    }
+    var failures []string
+    for _, c := range counts {
+        if len(symbols) <= c || len(symbols)-c > rsMaxParity {
+            continue
+        }
+        data, fixed, err := rsRecover(symbols, c)
+        if err == nil {
+            if _, err = bip39.EntropyFromIndices(data); err != nil {
+                err = fmt.Errorf("recovered words fail BIP39 validation: %v", err)
+            }
+        }
+        if err == nil {
+            return data, fixed, nil
+        }
+        if n != 0 {
+            return nil, nil, err
+        }
+        failures = append(failures, fmt.Sprintf("%d words + %d parity words: %v", c, len(symbols)-c, err))
+    }
+    if len(failures) == 0 {
+        return nil, nil, fmt.Errorf("%d words are not 12–24 words followed by 1–%d parity words", len(symbols), rsMaxParity)
+    }
+    return nil, nil, fmt.Errorf("no word count fits:\n  %s", strings.Join(failures, "\n  "))
+}
+
+// 被补全或纠正的位置，如 "word 3 (missing), parity word 2 (corrected)"
+func rsPositions(fixed, symbols []int, n int) string {
+    parts := make([]string, len(fixed))
+    for i, pos := range fixed {
+        name := fmt.Sprintf("word %d", pos+1)
+        if pos >= n {
+            name = fmt.Sprintf("parity word %d", pos-n+1)
+        }
+        how := "corrected"
+        if symbols[pos] < 0 {
+            how = "missing"
+        }
+        parts[i] = name + " (" + how + ")"
+    }
+    return strings.Join(parts, ", ")
+}
+
+func wordsFromIndices(indices []int, wordList []string) string {
+    words := make([]string, len(indices))
+    for i, idx := range indices {
+        words[i] = wordList[idx]
+    }
+    return strings.Join(words, " ")
+}
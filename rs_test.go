@@ -0,0 +1,98 @@
+package main
+
+import (
+    "bytes"
+    "slices"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+// 由固定熵得到的有效 BIP39 单词序号
+func rsTestIndices(words int) []int {
+    entropy := bytes.Repeat([]byte{0x5a, 0xc3, 0x17}, 11)[:words*4/3]
+    bits := bip39.BytesToBits(entropy)
+    bits = append(bits, bip39.ChecksumBits(bits)...)
+    indices := make([]int, words)
+    for i := range indices {
+        indices[i] = bip39.BitsToInt(bits[i*11 : i*11+11])
+    }
+    return indices
+}
+
+func rsTestCodeword(words, k int) []int {
+    data := rsTestIndices(words)
+    return append(slices.Clone(data), rsParity(data, k)...)
+}
+
+func TestRSRecover(t *testing.T) {
+    tests := []struct {
+        name    string
+        k       int
+        erase   []int
+        wrong   []int
+        fixable bool
+    }{
+        {"intact", 4, nil, nil, true},
+        {"4 erasures", 4, []int{0, 5, 11, 14}, nil, true},
+        {"1 error", 4, nil, []int{3}, true},
+        {"2 errors", 4, nil, []int{1, 9}, true},
+        {"error in parity", 4, nil, []int{13}, true},
+        {"1 erasure and 1 error", 4, []int{2}, []int{7}, true},
+        {"2 erasures and 1 error", 4, []int{0, 15}, []int{6}, true},
+        {"5 erasures", 4, []int{0, 1, 2, 3, 4}, nil, false},
+        {"3 errors", 4, nil, []int{0, 4, 8}, false},
+        {"3 erasures and 1 error", 4, []int{1, 2, 3}, []int{10}, false},
+        {"1 erasure and 2 errors", 4, []int{5}, []int{0, 11}, false},
+        {"6 parity, 3 errors", 6, nil, []int{2, 8, 16}, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            code := rsTestCodeword(12, tt.k)
+            symbols := slices.Clone(code)
+            for _, i := range tt.erase {
+                symbols[i] = -1
+            }
+            for _, i := range tt.wrong {
+                symbols[i] = (symbols[i] + 777) % gf11Size
+            }
+
+            data, fixed, err := rsRecover(symbols, 12)
+            if !tt.fixable {
+                if err == nil {
+                    t.Fatalf("recovered %v beyond the limit of %d parity words", data, tt.k)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !slices.Equal(data, code[:12]) {
+                t.Errorf("got %v, want %v", data, code[:12])
+            }
+            want := slices.Sorted(slices.Values(append(slices.Clone(tt.erase), tt.wrong...)))
+            if !slices.Equal(fixed, want) {
+                t.Errorf("repaired positions %v, want %v", fixed, want)
+            }
+        })
+    }
+}
+
+func TestRSDecodeInfersWordCount(t *testing.T) {
+    for _, words := range []int{12, 15, 18, 21, 24} {
+        for _, k := range []int{1, 3, 6, 12} {
+            symbols := rsTestCodeword(words, k)
+            symbols[len(symbols)-1] = -1
+            data, _, err := rsDecode(symbols, 0)
+            if err != nil {
+                t.Fatalf("%d words + %d parity: %v", words, k, err)
+            }
+            if len(data) != words {
+                t.Errorf("%d words + %d parity: read as %d words", words, k, len(data))
+            }
+        }
+    }
+    if _, _, err := rsDecode(make([]int, 10), 0); err == nil {
+        t.Error("10 words accepted")
+    }
+}
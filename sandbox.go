@@ -0,0 +1,142 @@
+package main
+
+import (
+    "fmt"
+    "io/fs"
+    "os"
+    "os/exec"
+    "os/signal"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "syscall"
+)
+
+//
+// -------------------------
+//   临时目录沙箱
+// -------------------------
+//
+// 外部工具（zbarimg、zbarcam、tesseract、age、gpg）可能把中间文件写进
+// $TMPDIR 或当前目录。它们一律在本次会话的沙箱目录中运行：目录建在内存盘
+// （tmpfs：$XDG_RUNTIME_DIR、/dev/shm）上，权限 0700，TMPDIR 也指向它；
+// 正常退出、Ctrl-C 或 SIGTERM 时先用零覆盖其中的文件再删除。
+// 出错退出（log.Fatalf）来不及清理，下次启动时会清掉已结束进程留下的沙箱。
+// 找不到内存盘时退回系统临时目录并给出警告；-tmpdir DIR 可指定位置。
+//
+// binary.txt 等文件的“先写临时文件再改名”必须与目标在同一文件系统，
+// 所以仍然写在目标旁边；那是目标本身的新版本，不是中间产物。
+//
+
+const sandboxPrefix = "passphrase_bitcoin-"
+
+var (
+    sandboxBase string // -tmpdir
+    sandboxPath string // 首次使用时创建
+)
+
+// 内存盘上可写的目录
+func ramTempDir() string {
+    for _, d := range []string{os.Getenv("XDG_RUNTIME_DIR"), "/dev/shm", "/run/shm"} {
+        if d != "" && isRAMBacked(d) {
+            return d
+        }
+    }
+    return ""
+}
+
+func sandboxDir() (string, error) {
+    if sandboxPath != "" {
+        return sandboxPath, nil
+    }
+    base := sandboxBase
+    if base == "" {
+        if base = ramTempDir(); base == "" {
+            base = os.TempDir()
+            fmt.Fprintf(os.Stderr, "Warning: no RAM-backed tmpfs found; temporary files go to %s (choose another with -tmpdir).\n", base)
+        }
+    }
+    dir, err := os.MkdirTemp(base, fmt.Sprintf("%s%d-", sandboxPrefix, os.Getpid()))
+    if err != nil {
+        return "", err
+    }
+    sandboxPath = dir
+
+    c := make(chan os.Signal, 1)
+    signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+    go func() {
+        <-c
+        wipeSandbox()
+        os.Exit(130)
+    }()
+    return dir, nil
+}
+
+// 在沙箱中运行外部工具；相对路径的参数要先转成绝对路径
+func sandboxCommand(name string, args ...string) (*exec.Cmd, error) {
+    dir, err := sandboxDir()
+    if err != nil {
+        return nil, fmt.Errorf("temporary directory: %v", err)
+    }
+    cmd := exec.Command(name, args...)
+    cmd.Dir = dir
+    cmd.Env = append(os.Environ(), "TMPDIR="+dir, "TMP="+dir, "TEMP="+dir)
+    return cmd, nil
+}
+
+func wipeSandbox() {
+    if sandboxPath != "" {
+        wipeDir(sandboxPath)
+        sandboxPath = ""
+    }
+}
+
+// 先用零覆盖每个文件，再删除整个目录
+func wipeDir(dir string) {
+    filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+        if err == nil && d.Type().IsRegular() {
+            zeroFile(path)
+        }
+        return nil
+    })
+    os.RemoveAll(dir)
+}
+
+// 单个文件：清零后删除
+func wipeFile(path string) {
+    zeroFile(path)
+    os.Remove(path)
+}
+
+func zeroFile(path string) {
+    info, err := os.Stat(path)
+    if err != nil {
+        return
+    }
+    if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+        f.Write(make([]byte, info.Size()))
+        f.Sync()
+        f.Close()
+    }
+}
+
+// 清掉已结束的进程留下的沙箱
+func sweepStaleSandboxes() {
+    bases := []string{sandboxBase, ramTempDir(), os.TempDir()}
+    for _, base := range bases {
+        if base == "" {
+            continue
+        }
+        matches, _ := filepath.Glob(filepath.Join(base, sandboxPrefix+"*"))
+        for _, m := range matches {
+            pid, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(m), sandboxPrefix), "-")
+            n, err := strconv.Atoi(pid)
+            if err != nil || n == os.Getpid() || processAlive(n) {
+                continue
+            }
+            if info, err := os.Stat(m); err == nil && info.IsDir() {
+                wipeDir(m)
+            }
+        }
+    }
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package main
+
+import (
+    "os"
+    "strconv"
+
+    "golang.org/x/sys/unix"
+)
+
+// 可写的 tmpfs 或 ramfs
+func isRAMBacked(dir string) bool {
+    var st unix.Statfs_t
+    if unix.Statfs(dir, &st) != nil || unix.Access(dir, unix.W_OK) != nil {
+        return false
+    }
+    return st.Type == unix.TMPFS_MAGIC || st.Type == unix.RAMFS_MAGIC
+}
+
+func processAlive(pid int) bool {
+    _, err := os.Stat("/proc/" + strconv.Itoa(pid))
+    return err == nil
+}
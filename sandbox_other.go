@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package main
+
+// 其他系统无法可靠判断，视为不是内存盘
+func isRAMBacked(dir string) bool {
+    return false
+}
+
+// 无法判断时当作仍在运行，不清理
+func processAlive(pid int) bool {
+    return true
+}
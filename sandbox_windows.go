@@ -0,0 +1,26 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+const stillActive = 259 // STILL_ACTIVE
+
+// Windows 没有通用的内存盘，沙箱建在 %TEMP% 下
+func isRAMBacked(dir string) bool {
+    return false
+}
+
+func processAlive(pid int) bool {
+    h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+    if err != nil {
+        // 没有权限查询的进程仍然存在
+        return err == windows.ERROR_ACCESS_DENIED
+    }
+    defer windows.CloseHandle(h)
+    var code uint32
+    if windows.GetExitCodeProcess(h, &code) != nil {
+        return true
+    }
+    return code == stillActive
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+    "embed"
+    "encoding/json"
+    "fmt"
+    "log"
+    "path"
+    "sort"
+    "strings"
+)
+
+//
+// -------------------------
+//   -schema 机器可读输出的 JSON Schema
+// -------------------------
+//
+// 每种 JSON 输出都有一份 JSON Schema（draft 2020-12），随二进制嵌入，
+// -schema NAME 打印，-schema list 列出全部。集成方可据此校验或生成代码。
+// 同一版本号（$id 末尾的 v1）内只增加可选字段；删改字段要升版本。
+// -selftest 会把每种输出的样例与其 Schema 对照，防止两者脱节。
+//
+
+//go:embed embed/schemas/*.json
+var schemaFS embed.FS
+
+// 每份 Schema 对应的输出样例（数组字段各放一个元素，以便检查元素结构）
+var schemaSamples = map[string]any{
+    "transcript": &sessionTranscript{Version: 1, Options: map[string]string{}, Events: []transcriptEvent{{}}},
+    "metrics":    &jobMetrics{Counts: map[string]int64{}, Failures: map[string]int64{}},
+    "rotate-plan": &rotationPlan{
+        Old:   rotationWallet{Accounts: []rotationAccount{{Receive: []string{""}}}},
+        New:   rotationWallet{Accounts: []rotationAccount{{Receive: []string{""}}}},
+        Steps: rotationSteps,
+    },
+    "descriptors": []coreDescriptor{{}},
+    "export":      &ianColemanState{Rows: []ianColemanRow{{}}, Params: &derivationParams{}},
+    "mnemonic":    &mnemonicReport{Words: []reportWord{{}}, Errors: []string{""}},
+    "bundle":      &verifyBundle{Accounts: []bundleAccount{{Receive: []string{""}}}},
+}
+
+func schemaNames() []string {
+    entries, _ := schemaFS.ReadDir("embed/schemas")
+    names := make([]string, 0, len(entries))
+    for _, e := range entries {
+        names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+    }
+    sort.Strings(names)
+    return names
+}
+
+func printSchema(name string) {
+    if name == "list" {
+        for _, n := range schemaNames() {
+            fmt.Println(n)
+        }
+        return
+    }
+    data, err := schemaFS.ReadFile(path.Join("embed/schemas", name+".json"))
+    if err != nil {
+        log.Fatalf("Error: -schema: unknown output '%s' (%s)", name, strings.Join(schemaNames(), ", "))
+    }
+    fmt.Print(string(data))
+}
+
+// 自检：样例的 JSON 是否满足 Schema 的 required 与 additionalProperties
+func checkSchema(name string) error {
+    data, err := schemaFS.ReadFile(path.Join("embed/schemas", name+".json"))
+    if err != nil {
+        return err
+    }
+    var schema map[string]any
+    if err := json.Unmarshal(data, &schema); err != nil {
+        return err
+    }
+    sample, ok := schemaSamples[name]
+    if !ok {
+        return fmt.Errorf("no sample")
+    }
+    raw, err := json.Marshal(sample)
+    if err != nil {
+        return err
+    }
+    var doc any
+    if err := json.Unmarshal(raw, &doc); err != nil {
+        return err
+    }
+    defs, _ := schema["$defs"].(map[string]any)
+    return checkShape(schema, doc, defs, "$")
+}
+
+// 只检查结构（对象的字段、数组的元素），不检查取值
+func checkShape(schema map[string]any, v any, defs map[string]any, at string) error {
+    if ref, ok := schema["$ref"].(string); ok {
+        def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+        if !ok {
+            return fmt.Errorf("%s: unresolved %s", at, ref)
+        }
+        schema = def
+    }
+    switch schema["type"] {
+    case "object":
+        obj, ok := v.(map[string]any)
+        if !ok {
+            return fmt.Errorf("%s: not an object", at)
+        }
+        props, _ := schema["properties"].(map[string]any)
+        required, _ := schema["required"].([]any)
+        for _, r := range required {
+            if _, ok := obj[r.(string)]; !ok {
+                return fmt.Errorf("%s: missing required %q", at, r)
+            }
+        }
+        for key, val := range obj {
+            sub, ok := props[key].(map[string]any)
+            if !ok {
+                if schema["additionalProperties"] == false {
+                    return fmt.Errorf("%s: %q not in schema", at, key)
+                }
+                continue
+            }
+            if err := checkShape(sub, val, defs, at+"."+key); err != nil {
+                return err
+            }
+        }
+    case "array":
+        arr, ok := v.([]any)
+        if !ok {
+            return fmt.Errorf("%s: not an array", at)
+        }
+        if items, ok := schema["items"].(map[string]any); ok {
+            for i, item := range arr {
+                if err := checkShape(items, item, defs, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+                    return err
+                }
+            }
+        }
+    }
+    return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+    "bytes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/binary"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "time"
+
+    "golang.org/x/crypto/argon2"
+    "golang.org/x/crypto/chacha20poly1305"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   -export-sealed / -import-sealed 云端存储用加密文件
+// -------------------------
+//
+// 密钥只来自本地口令（Argon2id），文件本身可以放在网盘里。
+// 格式：magic "PBX2" | time (4) | memory KiB (4) | threads (1) | salt (16) | language (1) | nonce (24) | 密文+tag
+// 整个文件头作为 AAD，参数或语言被篡改时解密失败。
+// language 是导出时词表的 BIP85 语言编号（自定义 -wordlist 为 0xff）：指纹取决于
+// 单词，导入时要用同一种语言的词表核对，与当时的 -lang 无关。
+// 旧的 "PBX1" 文件没有 language，仍可读取，按当前词表核对。
+//
+
+var (
+    sealedMagic   = []byte("PBX2")
+    sealedMagicV1 = []byte("PBX1")
+)
+
+const (
+    sealedHeaderLen   = 4 + 4 + 4 + 1 + 16 + 1 + chacha20poly1305.NonceSizeX
+    sealedHeaderLenV1 = sealedHeaderLen - 1
+)
+
+// 文件头中自定义词表的语言编号
+const sealedCustomWordlist = 0xff
+
+// 默认参数：约 1 秒、256 MiB
+var sealedParams = argon2Params{time: 4, memory: 256 * 1024, threads: 4}
+
+// 读取时的上限，防止恶意文件耗尽内存
+const (
+    sealedMaxTime   = 64
+    sealedMaxMemory = 4 * 1024 * 1024
+)
+
+type argon2Params struct {
+    time    uint32
+    memory  uint32
+    threads uint8
+}
+
+// 加密内容：熵与不含秘密的元数据
+type sealedPayload struct {
+    Version     int    `json:"version"`
+    Entropy     string `json:"entropy"`
+    Words       int    `json:"words"`
+    Fingerprint string `json:"fingerprint"`
+    Created     string `json:"created"`
+}
+
+func exportSealed(path string, wordList []string) {
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    payload, err := json.Marshal(sealedPayload{
+        Version:     1,
+        Entropy:     hex.EncodeToString(entropy),
+        Words:       len(entropy) * 3 / 4,
+        Fingerprint: masterFingerprint(mnemonic),
+        Created:     time.Now().UTC().Format(time.RFC3339),
+    })
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    passphrase, err := readNewSecret("Encryption passphrase: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    sealed, err := sealArgon2(payload, passphrase, sealedParams, sealedLanguageCode())
+    if err != nil {
+        log.Fatalf("Error encrypting: %v", err)
+    }
+
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error creating %s: %v", path, err)
+    }
+    if _, err := f.Write(sealed); err != nil {
+        f.Close()
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+    if err := f.Close(); err != nil {
+        log.Fatalf("Error writing %s: %v", path, err)
+    }
+    fmt.Printf("%s written (Argon2id t=%d m=%d MiB p=%d, XChaCha20-Poly1305).\n",
+        path, sealedParams.time, sealedParams.memory/1024, sealedParams.threads)
+}
+
+func importSealed(path string, wordList []string) {
+    data, err := readFileLimited(path, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", path, err)
+    }
+    passphrase, err := readSecret("Encryption passphrase: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    plaintext, lang, err := openArgon2(data, passphrase)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    checkList, langName, err := sealedWordList(lang, wordList)
+    if err != nil {
+        log.Fatalf("Error: %s: %v", path, err)
+    }
+
+    var p sealedPayload
+    if err := json.Unmarshal(plaintext, &p); err != nil {
+        log.Fatalf("Error: %s: %v", path, err)
+    }
+    if p.Version != 1 {
+        log.Fatalf("Error: %s: unsupported version %d", path, p.Version)
+    }
+    entropy, err := hex.DecodeString(p.Entropy)
+    if err != nil || !bip39.ValidEntropySize(len(entropy)) {
+        log.Fatalf("Error: %s: invalid entropy", path)
+    }
+    if fp := masterFingerprint(mnemonicFromEntropy(entropy, checkList)); !secretcmp.EqualString(fp, p.Fingerprint) {
+        log.Fatalf("Error: %s: fingerprint %s does not match recorded %s", path, fp, p.Fingerprint)
+    }
+
+    fmt.Printf("Sealed backup: %d words, fingerprint %s, created %s\n", p.Words, p.Fingerprint, p.Created)
+    if lang >= 0 && byte(lang) != sealedLanguageCode() {
+        fmt.Printf("Exported with -lang %s; the fingerprint is that of the %s phrase.\n", langName, langName)
+    }
+    importEntropy(entropy)
+}
+
+func sealArgon2(plaintext []byte, passphrase string, params argon2Params, lang byte) ([]byte, error) {
+    header := append([]byte{}, sealedMagic...)
+    header = binary.BigEndian.AppendUint32(header, params.time)
+    header = binary.BigEndian.AppendUint32(header, params.memory)
+    header = append(header, params.threads)
+    salt := make([]byte, 16)
+    nonce := make([]byte, chacha20poly1305.NonceSizeX)
+    if _, err := rand.Read(salt); err != nil {
+        return nil, err
+    }
+    if _, err := rand.Read(nonce); err != nil {
+        return nil, err
+    }
+    header = append(header, salt...)
+    header = append(header, lang)
+    header = append(header, nonce...)
+
+    aead, err := argon2AEAD(passphrase, header)
+    if err != nil {
+        return nil, err
+    }
+    return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// 返回明文与文件头中的语言编号；PBX1 文件没有语言，返回 -1
+func openArgon2(sealed []byte, passphrase string) ([]byte, int, error) {
+    headerLen, lang := sealedHeaderLen, -1
+    switch {
+    case bytes.HasPrefix(sealed, sealedMagic):
+    case bytes.HasPrefix(sealed, sealedMagicV1):
+        headerLen = sealedHeaderLenV1
+    default:
+        return nil, 0, errors.New("not a sealed backup")
+    }
+    if len(sealed) < headerLen {
+        return nil, 0, errors.New("not a sealed backup")
+    }
+    header := sealed[:headerLen]
+    t := binary.BigEndian.Uint32(header[4:])
+    m := binary.BigEndian.Uint32(header[8:])
+    if t == 0 || t > sealedMaxTime || m < 8 || m > sealedMaxMemory || header[12] == 0 {
+        return nil, 0, fmt.Errorf("unreasonable Argon2id parameters t=%d m=%d KiB p=%d", t, m, header[12])
+    }
+    if headerLen == sealedHeaderLen {
+        lang = int(header[29])
+    }
+
+    aead, err := argon2AEAD(passphrase, header)
+    if err != nil {
+        return nil, 0, err
+    }
+    nonce := header[headerLen-chacha20poly1305.NonceSizeX:]
+    plaintext, err := aead.Open(nil, nonce, sealed[headerLen:], header)
+    if err != nil {
+        return nil, 0, errors.New("wrong passphrase or corrupted data")
+    }
+    return plaintext, lang, nil
+}
+
+// 当前词表的语言编号
+func sealedLanguageCode() byte {
+    if customWordlist != "" {
+        return sealedCustomWordlist
+    }
+    return byte(bip85Languages[phraseLanguage])
+}
+
+// 核对指纹用的词表及其语言：文件头记录的语言；自定义词表与 PBX1 文件用当前词表
+func sealedWordList(lang int, wordList []string) ([]string, string, error) {
+    switch {
+    case lang < 0:
+        return wordList, phraseLanguage, nil
+    case lang == sealedCustomWordlist:
+        if customWordlist == "" {
+            return nil, "", errors.New("exported with a custom -wordlist; give the same -wordlist to import it")
+        }
+        return wordList, customWordlist, nil
+    }
+    for name, code := range bip85Languages {
+        if code == lang {
+            list, err := bip39.Wordlist(name)
+            return list, name, err
+        }
+    }
+    return nil, "", fmt.Errorf("unknown language code %d", lang)
+}
+
+// 参数与盐都从文件头读取
+func argon2AEAD(passphrase string, header []byte) (cipher.AEAD, error) {
+    t := binary.BigEndian.Uint32(header[4:])
+    m := binary.BigEndian.Uint32(header[8:])
+    salt := header[13:29]
+    key := argon2.IDKey([]byte(passphrase), salt, t, m, header[12], chacha20poly1305.KeySize)
+    return chacha20poly1305.NewX(key)
+}
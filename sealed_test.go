@@ -0,0 +1,143 @@
+package main
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/binary"
+    "strings"
+    "testing"
+
+    "golang.org/x/crypto/chacha20poly1305"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+// 测试用的便宜参数
+var sealedTestParams = argon2Params{time: 1, memory: 64, threads: 1}
+
+func TestSealedRoundTrip(t *testing.T) {
+    plaintext := []byte(`{"version":1,"entropy":"00000000000000000000000000000000"}`)
+    sealed, err := sealArgon2(plaintext, "correct horse", sealedTestParams, 3)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.HasPrefix(sealed, sealedMagic) || len(sealed) != sealedHeaderLen+len(plaintext)+chacha20poly1305.Overhead {
+        t.Fatalf("sealed file has %d bytes", len(sealed))
+    }
+    got, lang, err := openArgon2(sealed, "correct horse")
+    if err != nil || !bytes.Equal(got, plaintext) || lang != 3 {
+        t.Fatalf("openArgon2 = %q, %d, %v", got, lang, err)
+    }
+
+    // 同样的输入每次加密结果不同（随机盐与 nonce）
+    again, _ := sealArgon2(plaintext, "correct horse", sealedTestParams, 3)
+    if bytes.Equal(again, sealed) {
+        t.Error("two seals are identical")
+    }
+}
+
+// 错误口令与被改动的任何一个字节都必须解密失败
+func TestSealedTamper(t *testing.T) {
+    plaintext := []byte("entropy and metadata")
+    sealed, err := sealArgon2(plaintext, "correct horse", sealedTestParams, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, pass := range []string{"", "Correct horse", "correct horse ", "wrong"} {
+        if _, _, err := openArgon2(sealed, pass); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+            t.Errorf("passphrase %q: err = %v", pass, err)
+        }
+    }
+
+    regions := map[string]int{
+        "time":       7,
+        "memory":     11,
+        "salt":       13,
+        "language":   29,
+        "nonce":      30,
+        "ciphertext": sealedHeaderLen,
+        "tag":        len(sealed) - 1,
+    }
+    for name, i := range regions {
+        bad := bytes.Clone(sealed)
+        bad[i] ^= 0x01
+        if _, _, err := openArgon2(bad, "correct horse"); err == nil {
+            t.Errorf("%s modified: decrypted", name)
+        }
+    }
+    for _, n := range []int{0, 3, sealedHeaderLen - 1, sealedHeaderLen, len(sealed) - 1} {
+        if _, _, err := openArgon2(sealed[:n], "correct horse"); err == nil {
+            t.Errorf("truncated to %d bytes: decrypted", n)
+        }
+    }
+    if _, _, err := openArgon2(append(bytes.Clone(sealed), 0), "correct horse"); err == nil {
+        t.Error("extra byte: decrypted")
+    }
+
+    // 参数超出上限时不做 Argon2id 就拒绝
+    bad := bytes.Clone(sealed)
+    binary.BigEndian.PutUint32(bad[8:], sealedMaxMemory+1)
+    if _, _, err := openArgon2(bad, "correct horse"); err == nil || !strings.Contains(err.Error(), "unreasonable") {
+        t.Errorf("huge memory: err = %v", err)
+    }
+}
+
+// 旧格式 PBX1（没有语言字节）仍然可以读取
+func TestSealedReadsV1(t *testing.T) {
+    header := append([]byte{}, sealedMagicV1...)
+    header = binary.BigEndian.AppendUint32(header, sealedTestParams.time)
+    header = binary.BigEndian.AppendUint32(header, sealedTestParams.memory)
+    header = append(header, sealedTestParams.threads)
+    random := make([]byte, 16+chacha20poly1305.NonceSizeX)
+    rand.Read(random)
+    header = append(header, random...)
+    aead, err := argon2AEAD("pw", header)
+    if err != nil {
+        t.Fatal(err)
+    }
+    sealed := aead.Seal(header, header[sealedHeaderLenV1-chacha20poly1305.NonceSizeX:], []byte("v1"), header)
+
+    got, lang, err := openArgon2(sealed, "pw")
+    if err != nil || string(got) != "v1" || lang != -1 {
+        t.Errorf("openArgon2(PBX1) = %q, %d, %v", got, lang, err)
+    }
+}
+
+// 指纹按文件头记录的语言核对，与当前 -lang 无关
+func TestSealedWordList(t *testing.T) {
+    defer func(lang, custom string) { phraseLanguage, customWordlist = lang, custom }(phraseLanguage, customWordlist)
+    phraseLanguage, customWordlist = "english", ""
+    english := bip39.English()
+    entropy := bytes.Repeat([]byte{0x7f}, 16)
+
+    spanish, err := bip39.Wordlist("spanish")
+    if err != nil {
+        t.Fatal(err)
+    }
+    recorded := masterFingerprint(mnemonicFromEntropy(entropy, spanish))
+    list, name, err := sealedWordList(bip85Languages["spanish"], english)
+    if err != nil || name != "spanish" {
+        t.Fatalf("sealedWordList(spanish) = %s, %v", name, err)
+    }
+    if fp := masterFingerprint(mnemonicFromEntropy(entropy, list)); fp != recorded {
+        t.Errorf("fingerprint %s, recorded %s", fp, recorded)
+    }
+    if masterFingerprint(mnemonicFromEntropy(entropy, english)) == recorded {
+        t.Error("english and spanish phrases have the same fingerprint")
+    }
+
+    if list, _, err := sealedWordList(-1, english); err != nil || list[0] != "abandon" {
+        t.Errorf("PBX1: %v", err)
+    }
+    if _, _, err := sealedWordList(sealedCustomWordlist, english); err == nil {
+        t.Error("custom wordlist accepted without -wordlist")
+    }
+    if _, _, err := sealedWordList(200, english); err == nil {
+        t.Error("unknown language code accepted")
+    }
+
+    phraseLanguage = "french"
+    if sealedLanguageCode() != 6 {
+        t.Errorf("sealedLanguageCode(french) = %d", sealedLanguageCode())
+    }
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip32"
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -seed / -root 种子与根密钥
+// -------------------------
+//
+// 助记词（binary.txt 或 -mnemonic）加可选的 BIP39 口令，经 PBKDF2-HMAC-SHA512
+// （2048 轮，盐为 "mnemonic"+口令）得到 512 位种子，以十六进制输出。
+// 总是标准 BIP39，不受 -kdf 影响。
+//
+// -root 再由种子算出 BIP32 主密钥（HMAC-SHA512，密钥 "Bitcoin seed"），
+// 输出 xprv、xpub、链码与主指纹，用来在离线机上核对硬件钱包的根密钥。
+//
+
+func printSeed(mnemonicIn string, wordList []string) {
+    seed := promptedSeed(mnemonicIn, wordList)
+    if clipOutput {
+        newDerivationParams(nil).print()
+        clipSecret("BIP39 seed", hex.EncodeToString(seed))
+        return
+    }
+    fmt.Println("BIP39 seed (512 bits):")
+    fmt.Println(hex.EncodeToString(seed))
+    newDerivationParams(nil).print()
+}
+
+func printRootKey(mnemonicIn string, wordList []string) {
+    master, err := bip32.NewMaster(promptedSeed(mnemonicIn, wordList))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fp := master.Fingerprint()
+    fmt.Println("BIP32 root key (m):")
+    fmt.Println("  xprv:               ", master.Serialize(bip32.VersionXPrv))
+    fmt.Println("  xpub:               ", master.Neuter().Serialize(bip32.VersionXPub))
+    fmt.Println("  Chain code:         ", hex.EncodeToString(master.ChainCode))
+    fmt.Println("  Public key:         ", hex.EncodeToString(master.PublicKey()))
+    fmt.Println("  Master fingerprint: ", hex.EncodeToString(fp[:]))
+    newDerivationParams(nil, "m").print()
+}
+
+// 取助记词并询问可选的 BIP39 口令，返回标准 BIP39 种子
+func promptedSeed(mnemonicIn string, wordList []string) []byte {
+    return seedWithPrompt(selectedMnemonic(mnemonicIn, wordList))
+}
+
+func seedWithPrompt(mnemonic string) []byte {
+    passphrase, err := readSecret("BIP39 passphrase (Enter for none): ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if passphrase == "" {
+        fmt.Fprintln(os.Stderr, "(no BIP39 passphrase)")
+    }
+    return bip39.Mnemonic(mnemonic).Seed(passphrase)
+}
+
+// -mnemonic 给出时校验后使用，否则取 binary.txt；返回单空格分隔的小写 NFKD 形式
+func selectedMnemonic(mnemonicIn string, wordList []string) string {
+    if mnemonicIn == "" {
+        return generatePassphraseFromBinary(wordList)
+    }
+    entropy, err := entropyFromPhrase(mnemonicIn, wordList)
+    if err != nil {
+        log.Fatalf("Error: -mnemonic: %v", err)
+    }
+    warnWeakMnemonic(entropy, wordList)
+    return strings.Join(splitWords(mnemonicIn), " ")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+    "crypto/sha256"
+    _ "embed"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   -selftest 自检
+// -------------------------
+//
+// -canonical 输出与平台、构建无关，逐字节一致，可在不同机器间直接比较，
+// 用于发现被篡改的二进制文件。
+//
+
+const englishWordListSHA256 = "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda"
+
+// BIP39 官方英文测试向量（trezor/python-mnemonic vectors.json 的 english 部分：
+// 熵、助记词、以 TREZOR 为口令的种子）。逐项走完 熵 → 助记词 → 熵 与
+// 助记词 → 种子 的完整流程；文件本身的 SHA-256 也一并核对。
+//
+//go:embed embed/bip39-vectors.json
+var bip39VectorsJSON []byte
+
+const (
+    bip39VectorsSHA256     = "a63a37d455dfb2da3ac0a9a88e0d3cf64e3cd6ac21c5a8eb383f7c81a71d5883"
+    bip39VectorsPassphrase = "TREZOR"
+)
+
+type selftestResult struct {
+    name   string
+    detail string
+    ok     bool
+}
+
+func runSelftest(wordList []string) []selftestResult {
+    var results []selftestResult
+
+    sum := sha256.Sum256([]byte(strings.Join(wordList, "\n") + "\n"))
+    listHash := hex.EncodeToString(sum[:])
+    results = append(results, selftestResult{
+        name:   "wordlist",
+        detail: fmt.Sprintf("english words=%d sha256=%s", len(wordList), listHash),
+        ok:     len(wordList) == 2048 && listHash == englishWordListSHA256,
+    })
+
+    var vectors struct {
+        English [][3]string `json:"english"`
+    }
+    vsum := sha256.Sum256(bip39VectorsJSON)
+    err := json.Unmarshal(bip39VectorsJSON, &vectors)
+    results = append(results, selftestResult{
+        name:   "vectors",
+        detail: fmt.Sprintf("trezor english n=%d sha256=%s", len(vectors.English), hex.EncodeToString(vsum[:])),
+        ok:     err == nil && len(vectors.English) > 0 && hex.EncodeToString(vsum[:]) == bip39VectorsSHA256,
+    })
+
+    for i, v := range vectors.English {
+        entropyHex, mnemonic, seedHex := v[0], v[1], v[2]
+        entropy, _ := hex.DecodeString(entropyHex)
+        got := mnemonicFromEntropy(entropy, wordList)
+
+        // 反向：助记词 → 熵
+        back := ""
+        if indices, err := bip39.Indices(strings.Fields(mnemonic), wordList); err == nil {
+            if e, err := bip39.EntropyFromIndices(indices); err == nil {
+                back = hex.EncodeToString(e)
+            }
+        }
+        seed := hex.EncodeToString(bip39.Mnemonic(mnemonic).Seed(bip39VectorsPassphrase))
+
+        var failed []string
+        if got != mnemonic {
+            failed = append(failed, "mnemonic")
+        }
+        if back != entropyHex {
+            failed = append(failed, "entropy")
+        }
+        if seed != seedHex {
+            failed = append(failed, "seed")
+        }
+        detail := fmt.Sprintf("bits=%d mnemonic entropy seed", len(entropy)*8)
+        if len(failed) > 0 {
+            detail = fmt.Sprintf("bits=%d wrong %s", len(entropy)*8, strings.Join(failed, " "))
+        }
+        results = append(results, selftestResult{
+            name:   fmt.Sprintf("vector %02d", i+1),
+            detail: detail,
+            ok:     len(failed) == 0,
+        })
+    }
+
+    // 嵌入的 JSON Schema 与实际输出一致
+    for _, name := range schemaNames() {
+        err := checkSchema(name)
+        detail := "ok"
+        if err != nil {
+            detail = err.Error()
+        }
+        results = append(results, selftestResult{
+            name:   "schema " + name,
+            detail: detail,
+            ok:     err == nil,
+        })
+    }
+
+    return results
+}
+
+func printSelftest(wordList []string, canonical bool) {
+    metrics := startMetrics("selftest", "checks")
+    results := runSelftest(wordList)
+    passed := true
+    for _, r := range results {
+        passed = passed && r.ok
+        metrics.add("checks", 1)
+        if !r.ok {
+            metrics.fail(strings.Fields(r.name)[0])
+        }
+    }
+    metrics.finish()
+
+    if canonical {
+        var b strings.Builder
+        b.WriteString("passphrase_bitcoin selftest canonical v1\n")
+        for _, r := range results {
+            fmt.Fprintf(&b, "%s %s %s\n", r.name, passFail(r.ok), r.detail)
+        }
+        fmt.Fprintf(&b, "result %s\n", passFail(passed))
+        digest := sha256.Sum256([]byte(b.String()))
+        fmt.Fprintf(&b, "digest %s\n", hex.EncodeToString(digest[:]))
+        fmt.Print(b.String())
+    } else {
+        for _, r := range results {
+            fmt.Printf("[%s] %-10s %s\n", passFail(r.ok), r.name, r.detail)
+        }
+        fmt.Println()
+        fmt.Println("Selftest", passFail(passed))
+    }
+
+    transcript.record("selftest", passFail(passed), "", fmt.Sprintf("%d checks", len(results)))
+    if !passed {
+        transcript.finish()
+        os.Exit(1)
+    }
+}
+
+func passFail(ok bool) string {
+    if ok {
+        return "PASS"
+    }
+    return "FAIL"
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    qrcode "github.com/skip2/go-qrcode"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -s 备份纸（逐行校验码）
+// -------------------------
+//
+
+const sheetWordsPerRow = 6
+
+func printBackupSheet(wordList []string) {
+    indices := passphraseIndicesFromBinary()
+    words := make([]string, len(indices))
+    for i, idx := range indices {
+        words[i] = wordList[idx]
+    }
+
+    // 演示模式不写台账
+    serial := "DEMO"
+    if !demoMode {
+        var err error
+        serial, err = newSheetSerial()
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        fp := masterFingerprint(strings.Join(words, " "))
+        if err := recordSheet(serial, fp, len(words)); err != nil {
+            log.Fatalf("Error writing %s: %v", ledgerFile, err)
+        }
+    }
+
+    fmt.Println("Passphrase backup sheet")
+    fmt.Printf("Serial: %s\n", serial)
+    if b := birthdayOf(strings.Join(words, " ")); b != nil {
+        fmt.Println("Birthday:", b)
+    }
+    printSecretQR(qrPayload(strings.Join(words, " ")), qrcode.Low)
+    reveal := shouldReveal()
+    fmt.Println("Row  Words                                                  Check")
+    for row := 0; row*sheetWordsPerRow < len(words); row++ {
+        start := row * sheetWordsPerRow
+        end := min(start+sheetWordsPerRow, len(words))
+        line, check := strings.Join(words[start:end], " "), rowCheck(indices[start:end])
+        if !reveal {
+            line, check = maskPhrase(line), "**"
+        }
+        fmt.Printf("%3d  %-54s %s\n", row+1, line, check)
+    }
+}
+
+// 每行 2 个十六进制字符：该行单词索引的 CRC-8（多项式 0x07）
+func rowCheck(indices []int) string {
+    var crc byte
+    for _, idx := range indices {
+        for _, b := range []byte{byte(idx >> 8), byte(idx)} {
+            crc ^= b
+            for i := 0; i < 8; i++ {
+                if crc&0x80 != 0 {
+                    crc = crc<<1 ^ 0x07
+                } else {
+                    crc <<= 1
+                }
+            }
+        }
+    }
+    return fmt.Sprintf("%02X", crc)
+}
+
+func isRowCheck(s string) bool {
+    if len(s) != 2 {
+        return false
+    }
+    _, err := strconv.ParseUint(s, 16, 8)
+    return err == nil
+}
+
+// 读取手抄回来的备份纸，逐行核对校验码，定位抄错的行
+func importBackupSheet(filename string, wordList []string) {
+    data, err := readFileLimited(filename, maxTextFileSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+
+    matcher := wordmatch.New(wordList)
+
+    var indices []int
+    badRows := 0
+    row := 0
+    for _, line := range strings.Split(string(data), "\n") {
+        fields := strings.Fields(strings.ToLower(line))
+        if len(fields) < 2 || !isRowCheck(fields[len(fields)-1]) {
+            continue
+        }
+        row++
+        check := strings.ToUpper(fields[len(fields)-1])
+        fields = fields[:len(fields)-1]
+        if _, err := strconv.Atoi(strings.TrimRight(fields[0], ".:)")); err == nil {
+            fields = fields[1:]
+        }
+
+        rowIndices := make([]int, 0, len(fields))
+        unknown := false
+        for _, f := range fields {
+            c, ok := matcher.Lookup(f)
+            if !ok {
+                unknown = true
+            }
+            rowIndices = append(rowIndices, c.Index)
+        }
+
+        switch {
+        case unknown:
+            fmt.Printf("Row %d: contains a word not on the list.\n", row)
+            badRows++
+        case rowCheck(rowIndices) != check:
+            fmt.Printf("Row %d: check %s does not match, expected %s.\n", row, check, rowCheck(rowIndices))
+            badRows++
+        }
+        indices = append(indices, rowIndices...)
+    }
+
+    if row == 0 {
+        log.Fatalf("Error: no rows with checkwords found in %s", filename)
+    }
+    if badRows > 0 {
+        log.Fatalf("Error: %d row(s) mis-transcribed, nothing imported.", badRows)
+    }
+    importIndices(indices)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+    "encoding/hex"
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/slip39"
+)
+
+//
+// -------------------------
+//   -slip39-combine 合并 SLIP-39 分享
+// -------------------------
+//
+// 从文件（逗号分隔，每行一份分享）或交互输入（-，不回显，逐份输入直到够数）
+// 读取 SLIP-39 分享，还原主密钥，再按同样长度编码为 BIP39 助记词。
+// 只有当分享是由 BIP39 熵拆分而来时，这个助记词才是“原来的”助记词：
+// Trezor 原生的 SLIP-39 钱包直接用主密钥作 BIP32 种子，换成 BIP39
+// 助记词会得到另一个钱包。
+//
+
+func combineSLIP39(files string, wordList []string) {
+    var shares []*slip39.Share
+    add := func(line, where string) error {
+        if strings.TrimSpace(line) == "" {
+            return nil
+        }
+        s, err := slip39.ParseShare(line)
+        if err != nil {
+            return fmt.Errorf("%s: %v", where, err)
+        }
+        if len(shares) > 0 {
+            first := shares[0]
+            if s.Identifier != first.Identifier || s.Extendable != first.Extendable || s.GroupThreshold != first.GroupThreshold ||
+                s.GroupCount != first.GroupCount || len(s.Value) != len(first.Value) {
+                return fmt.Errorf("%s: belongs to a different share set", where)
+            }
+        }
+        shares = append(shares, s)
+        fmt.Printf("Share %d accepted: group %d, member %d (%d-of-n group; %d of %d groups needed)\n",
+            len(shares), s.GroupIndex+1, s.MemberIndex+1, s.MemberThreshold, s.GroupThreshold, s.GroupCount)
+        return nil
+    }
+
+    if files == "-" {
+        for n := 1; slip39.Missing(shares) != ""; n++ {
+            if len(shares) > 0 {
+                fmt.Println("Still needed:", slip39.Missing(shares))
+            }
+            line, err := readSecret(fmt.Sprintf("Share %d (hidden, words or first 4 letters): ", n))
+            if err != nil {
+                log.Fatalf("Error: %v", err)
+            }
+            if err := add(line, fmt.Sprintf("share %d", n)); err != nil {
+                fmt.Println("Error:", err)
+                n--
+            }
+        }
+    } else {
+        for _, path := range strings.Split(files, ",") {
+            path = strings.TrimSpace(path)
+            data, err := readFileLimited(path, maxTextFileSize)
+            if err != nil {
+                log.Fatalf("Error reading %s: %v", path, err)
+            }
+            for i, line := range strings.Split(string(data), "\n") {
+                if err := add(line, fmt.Sprintf("%s line %d", path, i+1)); err != nil {
+                    log.Fatalf("Error: %v", err)
+                }
+            }
+        }
+        if missing := slip39.Missing(shares); missing != "" {
+            log.Fatalf("Error: not enough shares: %s", missing)
+        }
+    }
+
+    finishSLIP39Combine(shares, wordList)
+}
+
+// 分享已够数：询问口令，合并并显示为 BIP39 助记词
+func finishSLIP39Combine(shares []*slip39.Share, wordList []string) {
+    passphrase, err := readSecret("SLIP-39 passphrase (Enter for none): ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    secret, err := slip39.Combine(shares, passphrase)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    fmt.Println()
+    fmt.Printf("Master secret (%d bits): %s\n", len(secret)*8, hex.EncodeToString(secret))
+    if !bip39.ValidEntropySize(len(secret)) {
+        log.Fatalf("Error: a %d-bit master secret has no BIP39 encoding (128, 160, 192, 224 or 256 bits)", len(secret)*8)
+    }
+    mnemonic := mnemonicFromEntropy(secret, wordList)
+    fmt.Println("BIP39 mnemonic with the same entropy:")
+    printPhrase(mnemonic)
+    fmt.Println("Fingerprint:", masterFingerprint(mnemonic))
+    fmt.Println()
+    fmt.Println("This is the original BIP39 phrase only if the shares were made by splitting its entropy.")
+    fmt.Println("A wallet created as SLIP-39 (e.g. a Trezor Shamir backup) uses the master secret itself")
+    fmt.Println("as its seed; restore such a wallet from the shares, not from this phrase.")
+    warnWeakMnemonic(secret, wordList)
+}
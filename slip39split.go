@@ -0,0 +1,109 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/slip39"
+)
+
+//
+// -------------------------
+//   -slip39-split 生成 SLIP-39 分享
+// -------------------------
+//
+// 把 binary.txt 的熵作为主密钥拆成 SLIP-39 分享，可以导入 Trezor 等钱包，
+// 也可以用 -slip39-combine 还原为同一个 BIP39 助记词。SPEC 为 "2of3"（一组，
+// 任意 2 份），或 "2:2of3,1of1,3of5"（冒号前是需要的组数）。
+// 分享可扩展（extendable），迭代指数为 1，与 Trezor 相同。
+//
+
+const slip39IterationExponent = 1
+
+func parseSLIP39Spec(spec string) (int, []slip39.Group, error) {
+    groupThreshold := 1
+    if t, rest, ok := strings.Cut(spec, ":"); ok {
+        n, err := strconv.Atoi(strings.TrimSpace(t))
+        if err != nil {
+            return 0, nil, fmt.Errorf("bad group threshold %q", t)
+        }
+        groupThreshold, spec = n, rest
+    }
+    var groups []slip39.Group
+    for _, g := range strings.Split(spec, ",") {
+        var k, n int
+        if _, err := fmt.Sscanf(strings.TrimSpace(g), "%dof%d", &k, &n); err != nil {
+            return 0, nil, fmt.Errorf("bad group %q, expected e.g. 2of3", g)
+        }
+        groups = append(groups, slip39.Group{Threshold: k, Count: n})
+    }
+    if len(groups) == 1 && groupThreshold != 1 {
+        return 0, nil, fmt.Errorf("a single group needs group threshold 1")
+    }
+    return groupThreshold, groups, nil
+}
+
+// 拆分 binary.txt 的熵；返回各组分享与 BIP39 助记词的指纹
+func newSLIP39Shares(spec, option string, wordList []string) ([][]*slip39.Share, string) {
+    groupThreshold, groups, err := parseSLIP39Spec(spec)
+    if err != nil {
+        log.Fatalf("Error: %s: %v", option, err)
+    }
+    entropy := bip39.BitsToBytes(loadEntropyBits())
+    if len(entropy)%2 != 0 {
+        log.Fatalf("Error: SLIP-39 needs an even number of bytes; %d bits of entropy cannot be split (use 128, 192 or 256)", len(entropy)*8)
+    }
+    fingerprint := masterFingerprint(mnemonicFromEntropy(entropy, wordList))
+
+    passphrase, err := readSecret("SLIP-39 passphrase (Enter for none): ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if passphrase != "" {
+        again, err := readSecret("Repeat: ")
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if again != passphrase {
+            log.Fatalf("Error: passphrases do not match")
+        }
+    }
+
+    sets, err := slip39.Split(entropy, passphrase, groupThreshold, groups, slip39IterationExponent)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    return sets, fingerprint
+}
+
+func describeSLIP39Set(sets [][]*slip39.Share, fingerprint string) {
+    first := sets[0][0]
+    if first.GroupCount == 1 {
+        fmt.Printf("Any %d of the %d shares recover the secret (set %04x, %d bits).\n", first.MemberThreshold, len(sets[0]), first.Identifier, len(first.Value)*8)
+    } else {
+        fmt.Printf("Any %d of the %d groups recover the secret (set %04x, %d bits).\n", first.GroupThreshold, first.GroupCount, first.Identifier, len(first.Value)*8)
+    }
+    fmt.Printf("-slip39-combine turns them back into the passphrase of binary.txt (fingerprint %s).\n", fingerprint)
+    fmt.Println("If you chose a SLIP-39 passphrase, it is needed too; it is not stored anywhere.")
+}
+
+func splitSLIP39(spec string, wordList []string) {
+    sets, fingerprint := newSLIP39Shares(spec, "-slip39-split", wordList)
+    reveal := shouldReveal()
+    for gi, shares := range sets {
+        fmt.Printf("Group %d of %d: %d of these %d shares needed\n", gi+1, len(sets), shares[0].MemberThreshold, len(shares))
+        for mi, s := range shares {
+            words := s.Mnemonic()
+            if !reveal {
+                words = maskPhrase(words)
+            }
+            fmt.Printf("  Share %d.%d: %s\n", gi+1, mi+1, words)
+        }
+    }
+    describeSLIP39Set(sets, fingerprint)
+    fmt.Println("Check the shares with -slip39-audit before you rely on them.")
+    transcript.record("slip39 split", "ok", fingerprint, fmt.Sprintf("%d of %d groups, set %04x", sets[0][0].GroupThreshold, len(sets), sets[0][0].Identifier))
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/slip39"
+)
+
+//
+// -------------------------
+//   SLIP-39 分享存入 PIV 令牌
+// -------------------------
+//
+// -slip39-token-write SPEC 与 -slip39-split 一样拆分 binary.txt，但不显示
+// 分享，而是逐份写进不同的 YubiKey（PIV 应用的一个数据对象），写后读回
+// 核对；同一个令牌不会收到两份。-slip39-token-read 逐个读取插入的令牌，
+// 够数后与 -slip39-combine 一样合并。读写都经由 ykman，管理密钥与 PIN
+// 由 ykman 在终端上询问；分享只在会话临时目录中短暂落地，随后清零删除。
+//
+// PIV 数据对象不需要 PIN 就能读出，拿到令牌就等于拿到一份分享，
+// 与纸质分享一样保管。FIDO2 大块数据（largeBlob）需要先建常驻凭据，
+// 这里不支持。
+//
+
+// YubiKey 允许的自定义数据对象（0x5f0000–0x5fffff）中的一个，避开标准对象
+const slip39TokenObject = "0x5fff39"
+
+const slip39TokenHeader = "passphrase_bitcoin slip39 share"
+
+func ykman(args ...string) *exec.Cmd {
+    cmd, err := sandboxCommand("ykman", args...)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+    return cmd
+}
+
+// 等用户插入且只插入一个令牌，返回它的序列号
+func waitForToken(prompt string) string {
+    for {
+        fmt.Print(prompt)
+        if _, err := readLine(); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        cmd, err := sandboxCommand("ykman", "list", "--serials")
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        out, err := cmd.Output()
+        if err != nil {
+            log.Fatalf("Error: ykman list: %v", err)
+        }
+        serials := strings.Fields(string(out))
+        switch len(serials) {
+        case 1:
+            return serials[0]
+        case 0:
+            fmt.Println("No YubiKey found.")
+        default:
+            fmt.Printf("%d YubiKeys are connected; leave only one.\n", len(serials))
+        }
+    }
+}
+
+// 分享写到会话临时目录中的文件再交给 ykman，用完清零删除
+func writeShareToToken(serial, share string) error {
+    dir, err := sandboxDir()
+    if err != nil {
+        return err
+    }
+    path := filepath.Join(dir, "share-"+serial)
+    defer wipeFile(path)
+    if err := writeSecretFile(path, []byte(slip39TokenHeader+"\n"+share+"\n")); err != nil {
+        return err
+    }
+    if err := ykman("--device", serial, "piv", "objects", "import", slip39TokenObject, path).Run(); err != nil {
+        return fmt.Errorf("ykman piv objects import: %v", err)
+    }
+    back, err := readShareFromToken(serial)
+    if err != nil {
+        return fmt.Errorf("reading it back: %v", err)
+    }
+    if !secretcmp.EqualString(back, share) {
+        return fmt.Errorf("the share read back differs from the one written")
+    }
+    return nil
+}
+
+func readShareFromToken(serial string) (string, error) {
+    dir, err := sandboxDir()
+    if err != nil {
+        return "", err
+    }
+    path := filepath.Join(dir, "read-"+serial)
+    defer wipeFile(path)
+    if err := ykman("--device", serial, "piv", "objects", "export", slip39TokenObject, path).Run(); err != nil {
+        return "", fmt.Errorf("ykman piv objects export: %v", err)
+    }
+    data, err := readFileLimited(path, maxTextFileSize)
+    if err != nil {
+        return "", err
+    }
+    header, share, _ := strings.Cut(string(data), "\n")
+    if header != slip39TokenHeader {
+        return "", fmt.Errorf("object %s holds no SLIP-39 share written by this tool", slip39TokenObject)
+    }
+    return strings.TrimSpace(share), nil
+}
+
+func writeSLIP39Tokens(spec string, wordList []string) {
+    if _, err := exec.LookPath("ykman"); err != nil {
+        log.Fatalf("Error: ykman not found in PATH (YubiKey Manager, needed for PIV tokens)")
+    }
+    sets, fingerprint := newSLIP39Shares(spec, "-slip39-token-write", wordList)
+    used := map[string]string{}
+    for gi, shares := range sets {
+        for mi, s := range shares {
+            name := fmt.Sprintf("%d.%d", gi+1, mi+1)
+            for {
+                serial := waitForToken(fmt.Sprintf("Insert the token for share %s (group %d of %d) and press Enter: ", name, gi+1, len(sets)))
+                if prev, ok := used[serial]; ok {
+                    fmt.Printf("Token %s already holds share %s; use another one.\n", serial, prev)
+                    continue
+                }
+                if err := writeShareToToken(serial, s.Mnemonic()); err != nil {
+                    fmt.Printf("Share %s on token %s failed: %v\n", name, serial, err)
+                    continue
+                }
+                used[serial] = name
+                fmt.Printf("Share %s written to token %s and read back. Label the token \"share %s\" and remove it.\n", name, serial, name)
+                break
+            }
+        }
+    }
+    describeSLIP39Set(sets, fingerprint)
+    fmt.Println("Anyone holding a token can read its share without a PIN; store the tokens like paper shares.")
+    fmt.Println("Read them back with -slip39-token-read.")
+    transcript.record("slip39 token write", "ok", fingerprint, fmt.Sprintf("%d tokens, set %04x", len(used), sets[0][0].Identifier))
+}
+
+func readSLIP39Tokens(wordList []string) {
+    if _, err := exec.LookPath("ykman"); err != nil {
+        log.Fatalf("Error: ykman not found in PATH (YubiKey Manager, needed for PIV tokens)")
+    }
+    var shares []*slip39.Share
+    read := map[string]bool{}
+    for slip39.Missing(shares) != "" {
+        if len(shares) > 0 {
+            fmt.Println("Still needed:", slip39.Missing(shares))
+        }
+        serial := waitForToken("Insert a share token and press Enter: ")
+        if read[serial] {
+            fmt.Printf("Token %s was already read; insert another one.\n", serial)
+            continue
+        }
+        text, err := readShareFromToken(serial)
+        if err != nil {
+            fmt.Printf("Token %s: %v\n", serial, err)
+            continue
+        }
+        s, err := slip39.ParseShare(text)
+        if err == nil {
+            err = slip39.Consistent(append(append([]*slip39.Share{}, shares...), s))
+        }
+        if err != nil {
+            fmt.Printf("Token %s: %v\n", serial, err)
+            continue
+        }
+        read[serial] = true
+        shares = append(shares, s)
+        fmt.Printf("Token %s: group %d, member %d accepted.\n", serial, s.GroupIndex+1, s.MemberIndex+1)
+    }
+    fmt.Println("Enough shares; the tokens can be removed.")
+    finishSLIP39Combine(shares, wordList)
+}
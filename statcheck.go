@@ -0,0 +1,68 @@
+package main
+
+import (
+    "crypto/rand"
+    "fmt"
+    "log"
+    "os"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/rnghealth"
+)
+
+//
+// -------------------------
+//   -stat-check 生成前的统计检验
+// -------------------------
+//
+// 在 -b、-no-file（含 -n）写出或显示熵之前：
+//   1. 从系统随机数生成器另取 20000 位样本，做 FIPS 140-2 的单比特、扑克、
+//      游程与长游程检验。正常的生成器约万分之一会误报，所以失败时换一份新
+//      样本重测，两次都失败才拒绝；
+//   2. 对熵本身做粗检：1 的个数、最长游程，误报率 2^-40。生成的熵不通过时
+//      拒绝；-cards、-entropy-hex、-pick 等外部输入只警告。
+// 这些检验只能发现坏掉的生成器（例如冷门平台上总返回零），
+// 通过并不能证明输出是真正随机的。结果写到标准错误，不影响 -json。
+//
+
+var statChecks bool
+
+func checkGenerator() {
+    for attempt := 1; ; attempt++ {
+        sample := make([]byte, rnghealth.SampleBytes)
+        if _, err := rand.Read(sample); err != nil {
+            log.Fatalf("Error generating entropy: %v", err)
+        }
+        fails, err := rnghealth.SampleTest(sample)
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        if len(fails) == 0 {
+            fmt.Fprintln(os.Stderr, "Statistical tests: monobit, poker, runs and long run on a 20000-bit sample: PASS")
+            return
+        }
+        for _, f := range fails {
+            fmt.Fprintf(os.Stderr, "Statistical tests: %s FAIL: %s\n", f.Test, f.Detail)
+        }
+        if attempt == 2 {
+            log.Fatalf("Error: the system random generator failed the statistical tests twice; nothing was generated. Check the platform's random source (-rngtest runs a longer test).")
+        }
+        fmt.Fprintln(os.Stderr, "A healthy generator fails about once in 10000 samples; testing a fresh sample.")
+    }
+}
+
+// external：熵来自用户输入，不通过时只警告
+func checkEntropyStats(entropy []byte, external bool) {
+    fails := rnghealth.EntropyTest(entropy)
+    if len(fails) == 0 {
+        fmt.Fprintf(os.Stderr, "Entropy check: %d bits, one-bit count and longest run: PASS\n", len(entropy)*8)
+        return
+    }
+    for _, f := range fails {
+        fmt.Fprintf(os.Stderr, "Entropy check: %s FAIL: %s\n", f.Test, f.Detail)
+    }
+    if external {
+        fmt.Fprintln(os.Stderr, "Warning: the entropy you supplied looks far from random; a passphrase made from it may be guessable.")
+        return
+    }
+    log.Fatalf("Error: the generated entropy looks far from random; nothing was written. Check the platform's random source (-rngtest).")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "sort"
+    "strings"
+    "time"
+)
+
+//
+// -------------------------
+//   -stats 本地使用统计
+// -------------------------
+//
+// 按主指纹统计生成、导入、显示与校验的次数和最近时间，帮助管理多个钱包的
+// 用户看出每份备份上次核对是什么时候。只存在本地文件中，以口令加密
+// （与 -export-sealed 相同的 scrypt + AES-GCM），从不联网。
+// 事件来自 transcript.record 的各个记录点：开了 -stats 就统计，
+// 与是否同时写 -transcript 无关。演示模式不统计。
+//
+
+type walletStats struct {
+    Generated    int    `json:"generated,omitempty"`
+    Imported     int    `json:"imported,omitempty"`
+    Shown        int    `json:"shown,omitempty"`
+    Verified     int    `json:"verified,omitempty"`
+    Failed       int    `json:"failed,omitempty"`
+    FirstSeen    string `json:"first_seen"`
+    LastUsed     string `json:"last_used"`
+    LastVerified string `json:"last_verified,omitempty"`
+}
+
+type usageStats struct {
+    Version int                     `json:"version"`
+    Wallets map[string]*walletStats `json:"wallets"` // 按主指纹
+
+    path       string
+    passphrase string
+}
+
+// 为 nil 时不统计
+var usage *usageStats
+
+// 打开（或新建）统计文件；新建时要求输入两次口令
+func openStats(path string) {
+    s := &usageStats{Version: 1, Wallets: map[string]*walletStats{}, path: path}
+    data, err := readFileLimited(path, maxTextFileSize)
+    switch {
+    case errors.Is(err, os.ErrNotExist):
+        if s.passphrase, err = readNewSecret(fmt.Sprintf("New passphrase for %s: ", path)); err != nil {
+            log.Fatalf("Error: -stats: %v", err)
+        }
+    case err != nil:
+        log.Fatalf("Error reading %s: %v", path, err)
+    default:
+        if s.passphrase, err = readSecret(fmt.Sprintf("Passphrase for %s: ", path)); err != nil {
+            log.Fatalf("Error: -stats: %v", err)
+        }
+        plain, err := openWithPassphrase(data, s.passphrase)
+        if err != nil {
+            log.Fatalf("Error: %s: %v", path, err)
+        }
+        if err := json.Unmarshal(plain, s); err != nil || s.Version != 1 {
+            log.Fatalf("Error: %s: not a stats file", path)
+        }
+        if s.Wallets == nil {
+            s.Wallets = map[string]*walletStats{}
+        }
+    }
+    usage = s
+}
+
+// 把一个记录点归入某类事件；与统计无关的记录点（自检、doctor 等）忽略
+func (s *usageStats) note(step, result, fingerprint string) {
+    if s == nil || demoMode || fingerprint == "" {
+        return
+    }
+    now := time.Now().UTC().Format(time.RFC3339)
+    w := s.Wallets[fingerprint]
+    if w == nil {
+        w = &walletStats{FirstSeen: now}
+        s.Wallets[fingerprint] = w
+    }
+    switch {
+    case step == "generate binary.txt":
+        w.Generated++
+    case step == "import binary.txt":
+        w.Imported++
+    case step == "output from binary.txt":
+        w.Shown++
+    case result == "match" || result == "valid":
+        w.Verified++
+        w.LastVerified = now
+    case result == "mismatch" || result == "invalid":
+        w.Failed++
+    default:
+        return
+    }
+    w.LastUsed = now
+    s.save()
+}
+
+// 先写临时文件再改名，中途出错不会损坏原文件
+func (s *usageStats) save() {
+    data, err := json.Marshal(s)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    sealed, err := sealWithPassphrase(data, s.passphrase)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    tmp := s.path + ".tmp"
+    if err := writeSecretFile(tmp, sealed); err != nil {
+        log.Printf("Warning: writing %s: %v", s.path, err)
+        return
+    }
+    if err := os.Rename(tmp, s.path); err != nil {
+        log.Printf("Warning: writing %s: %v", s.path, err)
+    }
+}
+
+func (s *usageStats) print() {
+    if len(s.Wallets) == 0 {
+        fmt.Printf("%s: no wallets recorded yet.\n", s.path)
+        return
+    }
+    fps := make([]string, 0, len(s.Wallets))
+    for fp := range s.Wallets {
+        fps = append(fps, fp)
+    }
+    // 最久未核对的排在前面
+    sort.Slice(fps, func(i, j int) bool {
+        a, b := s.Wallets[fps[i]], s.Wallets[fps[j]]
+        if a.LastVerified != b.LastVerified {
+            return a.LastVerified < b.LastVerified
+        }
+        return fps[i] < fps[j]
+    })
+    fmt.Println("Fingerprint  Gen  Imp  Shown  Verified  Failed  Last verified         Last used")
+    for _, fp := range fps {
+        w := s.Wallets[fp]
+        last := w.LastVerified
+        if last == "" {
+            last = "never"
+        }
+        fmt.Printf("%-11s  %3d  %3d  %5d  %8d  %6d  %-20s  %s\n", fp, w.Generated, w.Imported, w.Shown, w.Verified, w.Failed,
+            strings.TrimSuffix(last, "Z"), strings.TrimSuffix(w.LastUsed, "Z"))
+    }
+}
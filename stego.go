@@ -0,0 +1,141 @@
+package main
+
+import (
+    "encoding/binary"
+    "fmt"
+    "image"
+    "image/draw"
+    "image/png"
+    "io"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   PNG 隐写备份（LSB）
+// -------------------------
+//
+// 熵必须先用口令加密，再写入每个像素 R/G/B 的最低位。
+// 数据：长度（4 字节，大端）| 加密数据
+//
+
+func stegoEmbed(coverPath string) {
+    cover, err := readPNG(coverPath)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", coverPath, err)
+    }
+
+    passphrase, err := readNewSecret("Encryption passphrase: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    sealed, err := sealWithPassphrase(bip39.BitsToBytes(loadEntropyBits()), passphrase)
+    if err != nil {
+        log.Fatalf("Error encrypting entropy: %v", err)
+    }
+
+    payload := binary.BigEndian.AppendUint32(nil, uint32(len(sealed)))
+    payload = append(payload, sealed...)
+    bits := bip39.BytesToBits(payload)
+
+    b := cover.Bounds()
+    if len(bits) > b.Dx()*b.Dy()*3 {
+        log.Fatalf("Error: image too small, needs at least %d pixels", (len(bits)+2)/3)
+    }
+
+    for i, bit := range bits {
+        px := i / 3
+        off := cover.PixOffset(b.Min.X+px%b.Dx(), b.Min.Y+px/b.Dx()) + i%3
+        cover.Pix[off] = cover.Pix[off]&^1 | boolToByte(bit)
+    }
+
+    outPath := strings.TrimSuffix(coverPath, ".png") + "-stego.png"
+    f, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        log.Fatalf("Error creating %s: %v", outPath, err)
+    }
+    defer f.Close()
+    if err := png.Encode(f, cover); err != nil {
+        log.Fatalf("Error writing %s: %v", outPath, err)
+    }
+    fmt.Printf("%s written.\n", outPath)
+}
+
+func stegoExtract(imagePath string, wordList []string) {
+    img, err := readPNG(imagePath)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", imagePath, err)
+    }
+
+    b := img.Bounds()
+    capacity := b.Dx() * b.Dy() * 3
+    readBits := func(start, n int) []bool {
+        bits := make([]bool, 0, n)
+        for i := start; i < start+n && i < capacity; i++ {
+            px := i / 3
+            off := img.PixOffset(b.Min.X+px%b.Dx(), b.Min.Y+px/b.Dx()) + i%3
+            bits = append(bits, img.Pix[off]&1 == 1)
+        }
+        return bits
+    }
+
+    n := int(binary.BigEndian.Uint32(bip39.BitsToBytes(readBits(0, 32))))
+    if n <= 0 || n > maxStegoPayload || 32+n*8 > capacity {
+        log.Fatalf("Error: no embedded backup found in %s", imagePath)
+    }
+    sealed := bip39.BitsToBytes(readBits(32, n*8))
+
+    passphrase, err := readSecret("Encryption passphrase: ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    entropy, err := openWithPassphrase(sealed, passphrase)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if !bip39.ValidEntropySize(len(entropy)) {
+        log.Fatalf("Error: embedded entropy has invalid length %d", len(entropy))
+    }
+
+    fmt.Println("Passphrase:")
+    printPhrase(mnemonicFromEntropy(entropy, wordList))
+}
+
+// 统一转成 NRGBA，便于直接操作像素字节
+func readPNG(path string) (*image.NRGBA, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    // 先读尺寸，防止“解压炸弹”式的超大图片
+    cfg, err := png.DecodeConfig(f)
+    if err != nil {
+        return nil, err
+    }
+    if int64(cfg.Width)*int64(cfg.Height) > maxImagePixels {
+        return nil, fmt.Errorf("%w: %dx%d pixels", errTooLarge, cfg.Width, cfg.Height)
+    }
+    if _, err := f.Seek(0, io.SeekStart); err != nil {
+        return nil, err
+    }
+    src, err := png.Decode(f)
+    if err != nil {
+        return nil, err
+    }
+    img := image.NewNRGBA(src.Bounds())
+    draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+    return img, nil
+}
+
+func boolToByte(b bool) byte {
+    if b {
+        return 1
+    }
+    return 0
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   子命令
+// -------------------------
+//
+// 常用操作可以写成子命令：passphrase_bitcoin generate -words 12、
+// passphrase_bitcoin derive 84 -count 5 等。每个子命令只接受与它相关的
+// 选项，有自己的帮助（generate -h 或 help generate），并且一次只做一件事；
+// 旧接口里 -p -q 会把助记词从 binary.txt 读出两次。
+//
+// 子命令的选项定义在各自的 FlagSet 上（见 commands.go），与全局选项互不相干。
+// 旧的动作选项（-b、-no-file、-p、-q、-i、-v、-derive、-selftest）只是一层
+// 转换：legacyCommands 把它们和同名选项改写成子命令的参数，再按子命令解析、
+// 执行，所以两种写法的输出相同。旧写法照旧可用，但在标准错误上提示对应的
+// 子命令写法；与子命令无关的选项不能再混在一起。
+//
+
+type subcommand struct {
+    name     string
+    args     string // 位置参数说明；空表示不接受
+    minArgs  int
+    maxArgs  int    // -1 表示不限
+    legacy   string // 对应的旧选项，它的值是位置参数
+    readOnly bool   // 只读模式下可用
+    summary  string
+    command  func() command
+}
+
+var subcommands = []subcommand{
+    {
+        name: "generate", legacy: "b",
+        summary: "Generate new entropy and write binary.txt (or only print it with -no-file).",
+        command: func() command { return &generateCommand{} },
+    },
+    {
+        name: "mnemonic", legacy: "p",
+        summary: "Print the passphrase of binary.txt.",
+        command: func() command { return &mnemonicCommand{} },
+    },
+    {
+        name: "qr", legacy: "q",
+        summary: "Show the passphrase of binary.txt as a QR code, or write it to -qr-out FILE.",
+        command: func() command { return &qrCommand{} },
+    },
+    {
+        name: "inspect", args: "WORD|PREFIX|BITS", minArgs: 1, maxArgs: 1, legacy: "i", readOnly: true,
+        summary: "Show a word's index and 11-bit binary, every word with a prefix, or the word for 11 bits.",
+        command: func() command { return &inspectCommand{} },
+    },
+    {
+        name: "validate", args: "[WORDS...]", maxArgs: -1, legacy: "v", readOnly: true,
+        summary: "Check each word and the checksum of a mnemonic. Without WORDS, ask for it (hidden),\nso it stays out of the shell history.",
+        command: func() command { return &validateCommand{} },
+    },
+    {
+        name: "derive", args: "44|49|84|86", minArgs: 1, maxArgs: 1, legacy: "derive",
+        summary: "Print addresses m/purpose'/0'/account'/0/i of binary.txt or -mnemonic.",
+        command: func() command { return &deriveCommand{} },
+    },
+    {
+        name: "selftest", legacy: "selftest", readOnly: true,
+        summary: "Run the 24 official BIP39 test vectors (entropy, mnemonic and seed) and the other built-in checks;\nexit code 1 on any failure.",
+        command: func() command { return &selftestCommand{} },
+    },
+}
+
+// 所有子命令都接受的选项
+type commonOptions struct {
+    lang, wordlist, separator, transcript, tmpdir, stats string
+    readOnly                                             bool
+}
+
+func (o *commonOptions) define(fs *flag.FlagSet) {
+    fs.StringVar(&o.lang, "lang", "english", "Wordlist: "+strings.Join(bip39.Languages(), ", "))
+    fs.StringVar(&o.wordlist, "wordlist", "", "Use the 2048-word list in FILE instead of the built-in one (non-standard; checked first)")
+    fs.StringVar(&o.separator, "separator", "space", "Word separator in printed passphrases: space, newline or comma")
+    fs.StringVar(&o.transcript, "transcript", "", "Record the command, options, fingerprints and results (no secrets) as JSON in FILE")
+    fs.StringVar(&o.tmpdir, "tmpdir", "", "Directory for the session's temporary files (default: a RAM-backed tmpfs)")
+    fs.StringVar(&o.stats, "stats", "", "Count generation and verification events per wallet in encrypted FILE")
+    fs.BoolVar(&o.readOnly, "read-only", false, "Refuse the command if it generates or shows secrets")
+}
+
+// 一次子命令调用：解析后的选项与位置参数
+type invocation struct {
+    sub    *subcommand
+    legacy string // 旧写法时用到的动作选项
+    fs     *flag.FlagSet
+    cmd    command
+    common commonOptions
+    args   []string
+}
+
+func findSubcommand(name string) *subcommand {
+    for i := range subcommands {
+        if subcommands[i].name == name {
+            return &subcommands[i]
+        }
+    }
+    return nil
+}
+
+func subcommandNames() string {
+    names := make([]string, len(subcommands))
+    for i, c := range subcommands {
+        names[i] = c.name
+    }
+    return strings.Join(names, ", ")
+}
+
+// 新的调用，选项取默认值
+func (c *subcommand) invocation() *invocation {
+    inv := &invocation{sub: c, fs: flag.NewFlagSet(c.name, flag.ExitOnError), cmd: c.command()}
+    inv.cmd.define(inv.fs)
+    inv.common.define(inv.fs)
+    inv.fs.Usage = func() {
+        usage := "passphrase_bitcoin " + c.name + " [options]"
+        if c.args != "" {
+            usage += " " + c.args
+        }
+        fmt.Fprintf(inv.fs.Output(), "Usage: %s\n\n%s\n\nOptions:\n", usage, c.summary)
+        inv.fs.PrintDefaults()
+    }
+    return inv
+}
+
+func (c *subcommand) parse(args []string) *invocation {
+    inv := c.invocation()
+    // flag 在第一个位置参数处停止，这里允许选项写在位置参数之后
+    rest := args
+    for {
+        inv.fs.Parse(rest)
+        if inv.fs.NArg() == 0 {
+            break
+        }
+        inv.args = append(inv.args, inv.fs.Arg(0))
+        rest = inv.fs.Args()[1:]
+    }
+    if len(inv.args) < c.minArgs || (c.maxArgs >= 0 && len(inv.args) > c.maxArgs) {
+        if c.args == "" {
+            log.Fatalf("Error: %s takes no arguments, got '%s'", c.name, inv.args[0])
+        }
+        log.Fatalf("Error: usage: passphrase_bitcoin %s [options] %s", c.name, c.args)
+    }
+    return inv
+}
+
+// 代替 flag.Parse：第一个参数是子命令时按子命令解析并返回它；
+// 否则解析全局选项，返回 nil
+func parseCommandLine() *invocation {
+    args := os.Args[1:]
+    if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+        flag.Parse()
+        if flag.NArg() > 0 {
+            hint := "quote values that contain spaces"
+            if findSubcommand(flag.Arg(0)) != nil {
+                hint = "put the command first: passphrase_bitcoin " + flag.Arg(0) + " [options]"
+            }
+            log.Fatalf("Error: unexpected argument '%s'; %s", flag.Arg(0), hint)
+        }
+        return nil
+    }
+
+    if args[0] == "help" {
+        if len(args) == 1 {
+            flag.Set("h", "true")
+            return nil
+        }
+        c := findSubcommand(args[1])
+        if c == nil {
+            log.Fatalf("Error: unknown command '%s'; commands: %s", args[1], subcommandNames())
+        }
+        inv := c.invocation()
+        inv.fs.SetOutput(os.Stdout)
+        inv.fs.Usage()
+        os.Exit(0)
+    }
+
+    c := findSubcommand(args[0])
+    if c == nil {
+        log.Fatalf("Error: unknown command '%s'; commands: %s (or -h for all options)", args[0], subcommandNames())
+    }
+    return c.parse(args[1:])
+}
+
+// 执行子命令。given 是用户实际写下的选项（子命令的 FlagSet，或旧写法时的
+// 全局选项），会话记录保存它们
+func runCommands(given *flag.FlagSet, invs []*invocation) {
+    common := invs[0].common
+    checkArgLengths(given)
+    for _, inv := range invs {
+        for _, a := range inv.args {
+            if len(a) > maxArgLen {
+                log.Fatalf("Error: %s: argument longer than %d bytes", inv.sub.name, maxArgLen)
+            }
+        }
+        if buildReadOnly || common.readOnly {
+            if !inv.sub.readOnly {
+                log.Fatalf("Error: %s is not available in read-only mode.", inv.sub.name)
+            }
+            enforceReadOnly(inv.fs, "")
+        }
+    }
+    useTmpDir(common.tmpdir)
+    sweepStaleSandboxes()
+    defer wipeSandbox()
+    useSeparator(common.separator)
+    useWordList(given, common.lang, common.wordlist)
+
+    if common.transcript != "" {
+        startTranscript(common.transcript, given)
+        defer transcript.finish()
+    }
+    if common.stats != "" {
+        openStats(common.stats)
+    }
+
+    wordList := loadWordList()
+    if len(wordList) != 2048 {
+        log.Fatalf("Error: word list length %d, expected 2048", len(wordList))
+    }
+    for _, inv := range invs {
+        inv.cmd.run(inv.fs, inv.args, wordList)
+    }
+}
+
+// 旧的动作选项改写成子命令：每个用到的动作一个子命令，其他选项按名字交给
+// 接受它的子命令，旧动作选项的值作为位置参数。没有用到旧动作选项时返回 nil
+func legacyCommands() []*invocation {
+    given := map[string]*flag.Flag{}
+    var names []string
+    flag.Visit(func(f *flag.Flag) {
+        given[f.Name] = f
+        names = append(names, f.Name)
+    })
+    // -qr-only -b 是二维码模式，不是 generate
+    if given["qr-only"] != nil || given["qr-in"] != nil {
+        return nil
+    }
+    noFile := given["no-file"] != nil
+    if noFile && (given["p"] != nil || given["qr-out"] != nil) {
+        log.Fatalf("Error: -no-file writes nothing and reads no binary.txt; it cannot be combined with -encrypt, -qr-out or -p.")
+    }
+
+    var used []*subcommand
+    claimed := map[string]bool{"no-file": noFile}
+    for i := range subcommands {
+        c := &subcommands[i]
+        switch {
+        case c.name == "generate" && noFile:
+        // -no-file -q：二维码由 generate -no-file -qr 显示
+        case c.name == "qr" && noFile:
+            continue
+        case given[c.legacy] == nil:
+            continue
+        }
+        used = append(used, c)
+        claimed[c.legacy] = true
+    }
+    if len(used) == 0 {
+        return nil
+    }
+
+    args := make([][]string, len(used))
+    for i, c := range used {
+        accepted := map[string]bool{}
+        c.invocation().fs.VisitAll(func(f *flag.Flag) { accepted[f.Name] = true })
+        for _, name := range names {
+            if accepted[name] && name != c.legacy {
+                args[i] = append(args[i], "-"+name+"="+given[name].Value.String())
+                claimed[name] = true
+            }
+        }
+        if c.name == "generate" && noFile && given["q"] != nil {
+            args[i] = append(args[i], "-qr")
+            claimed["q"] = true
+        }
+        if v := given[c.legacy]; c.args != "" && v != nil && (c.minArgs > 0 || v.Value.String() != "-") {
+            args[i] = append(args[i], "--", v.Value.String())
+        }
+    }
+    for _, name := range names {
+        if !claimed[name] {
+            log.Fatalf("Error: -%s cannot be combined with -%s; run them one at a time.", name, used[0].legacy)
+        }
+    }
+
+    invs := make([]*invocation, len(used))
+    var actions []string
+    for i, c := range used {
+        invs[i] = c.parse(args[i])
+        invs[i].legacy = c.legacy
+        if given[c.legacy] == nil {
+            invs[i].legacy = "no-file"
+        }
+        actions = append(actions, "-"+invs[i].legacy)
+    }
+    if len(invs) > 1 {
+        fmt.Fprintf(os.Stderr, "Warning: combining %s is deprecated; run one command at a time:\n", strings.Join(actions, " "))
+    }
+    for _, inv := range invs {
+        fmt.Fprintf(os.Stderr, "Warning: -%s is deprecated; use: %s\n", inv.legacy, inv.commandLine())
+    }
+    return invs
+}
+
+// 与这次调用等价的命令行；秘密选项的值不回显
+func (inv *invocation) commandLine() string {
+    c := inv.sub
+    parts := []string{"passphrase_bitcoin", c.name}
+    inv.fs.Visit(func(f *flag.Flag) {
+        switch {
+        case isBoolFlag(f):
+            parts = append(parts, "-"+f.Name)
+        case transcriptSecretFlags[f.Name]:
+            parts = append(parts, "-"+f.Name, "...")
+        default:
+            parts = append(parts, "-"+f.Name, shellQuote(f.Value.String()))
+        }
+    })
+    switch {
+    case len(inv.args) == 0:
+    case transcriptSecretFlags[c.legacy]:
+        if c.minArgs > 0 {
+            parts = append(parts, c.args)
+        }
+    default:
+        for _, a := range inv.args {
+            parts = append(parts, shellQuote(a))
+        }
+    }
+    return strings.Join(parts, " ")
+}
+
+// 命令行上是否给出了选项 name（值与默认值相同也算）
+func flagGiven(fs *flag.FlagSet, name string) bool {
+    given := false
+    fs.Visit(func(f *flag.Flag) {
+        if f.Name == name {
+            given = true
+        }
+    })
+    return given
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+    b, ok := f.Value.(interface{ IsBoolFlag() bool })
+    return ok && b.IsBoolFlag()
+}
+
+func shellQuote(s string) string {
+    if s != "" && !strings.ContainsAny(s, " \t'\"\\$`*?[]{}()<>|&;!#~") {
+        return s
+    }
+    return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
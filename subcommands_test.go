@@ -0,0 +1,203 @@
+package main
+
+import (
+    "bytes"
+    "flag"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// 旧的动作选项只是子命令的另一种写法：同样的输入在标准输出上给出同样的结果，
+// 写出的文件也相同。旧写法另在标准错误上提示子命令写法
+func TestLegacyShim(t *testing.T) {
+    if testing.Short() {
+        t.Skip("builds the binary")
+    }
+    bin := filepath.Join(t.TempDir(), "passphrase_bitcoin")
+    if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+        t.Fatalf("go build: %v\n%s", err, out)
+    }
+    // 每次在新的空目录中运行，binary.txt 与 birthday.txt 互不影响
+    run := func(t *testing.T, stdin string, args ...string) (stdout, stderr string, dir string) {
+        t.Helper()
+        dir = t.TempDir()
+        cmd := exec.Command(bin, args...)
+        cmd.Dir = dir
+        cmd.Stdin = strings.NewReader(stdin)
+        var out, errOut bytes.Buffer
+        cmd.Stdout, cmd.Stderr = &out, &errOut
+        if err := cmd.Run(); err != nil {
+            t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, errOut.String())
+        }
+        return out.String(), errOut.String(), dir
+    }
+
+    const phrase = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+    cases := []struct {
+        name       string
+        legacy     []string
+        command    []string
+        stdin      string
+        files      []string // 两种写法写出的文件须相同
+        equivalent string   // 旧写法提示的子命令写法
+    }{
+        {
+            name:       "generate",
+            legacy:     []string{"-b", "-entropy-hex", "0c1e24e5917779d297e14d45f14e1a1a", "-group-bits", "8", "-f", "w.txt"},
+            command:    []string{"generate", "-entropy-hex", "0c1e24e5917779d297e14d45f14e1a1a", "-group-bits", "8", "-f", "w.txt"},
+            files:      []string{"w.txt"},
+            equivalent: "passphrase_bitcoin generate -entropy-hex ... -f w.txt -group-bits 8",
+        },
+        {
+            name:       "generate -no-file",
+            legacy:     []string{"-no-file", "-q", "-entropy-hex", "00000000000000000000000000000000", "-reveal", "-lang", "spanish"},
+            command:    []string{"generate", "-no-file", "-qr", "-entropy-hex", "00000000000000000000000000000000", "-reveal", "-lang", "spanish"},
+            equivalent: "passphrase_bitcoin generate -entropy-hex ... -lang spanish -no-file -qr -reveal",
+        },
+        {
+            name:       "mnemonic",
+            legacy:     []string{"-p", "-demo", "-reveal", "-separator", "comma"},
+            command:    []string{"mnemonic", "-demo", "-reveal", "-separator", "comma"},
+            equivalent: "passphrase_bitcoin mnemonic -demo -reveal -separator comma",
+        },
+        {
+            name:       "mnemonic -json",
+            legacy:     []string{"-p", "-demo", "-json"},
+            command:    []string{"mnemonic", "-json", "-demo"},
+            equivalent: "passphrase_bitcoin mnemonic -demo -json",
+        },
+        {
+            name:       "qr",
+            legacy:     []string{"-q", "-demo", "-reveal", "-qr-ec", "M"},
+            command:    []string{"qr", "-demo", "-reveal", "-qr-ec", "M"},
+            equivalent: "passphrase_bitcoin qr -demo -qr-ec M -reveal",
+        },
+        {
+            name:       "qr -qr-out",
+            legacy:     []string{"-q", "-demo", "-qr-out", "backup.svg", "-qr-scale", "4"},
+            command:    []string{"qr", "-demo", "-qr-out", "backup.svg", "-qr-scale", "4"},
+            files:      []string{"backup.svg"},
+            equivalent: "passphrase_bitcoin qr -demo -qr-out backup.svg -qr-scale 4",
+        },
+        {
+            name:       "inspect",
+            legacy:     []string{"-i", "aban"},
+            command:    []string{"inspect", "aban"},
+            equivalent: "passphrase_bitcoin inspect WORD|PREFIX|BITS",
+        },
+        {
+            name:       "inspect -romanize",
+            legacy:     []string{"-i", "10001", "-lang", "japanese", "-romanize"},
+            command:    []string{"inspect", "10001", "-lang", "japanese", "-romanize"},
+            equivalent: "passphrase_bitcoin inspect -lang japanese -romanize WORD|PREFIX|BITS",
+        },
+        {
+            name:       "validate",
+            legacy:     []string{"-v", phrase},
+            command:    append([]string{"validate"}, strings.Fields(phrase)...),
+            equivalent: "passphrase_bitcoin validate",
+        },
+        {
+            name:       "validate -json",
+            legacy:     []string{"-v", "-", "-json"},
+            command:    []string{"validate", "-json"},
+            stdin:      phrase + "\n",
+            equivalent: "passphrase_bitcoin validate -json",
+        },
+        {
+            name:       "derive",
+            legacy:     []string{"-derive", "84", "-demo", "-count", "3", "-change"},
+            command:    []string{"derive", "-demo", "-count", "3", "-change", "84"},
+            stdin:      "\n",
+            equivalent: "passphrase_bitcoin derive -change -count 3 -demo 84",
+        },
+        {
+            name:       "derive -out",
+            legacy:     []string{"-derive", "86", "-mnemonic", phrase, "-count", "4", "-out", "a.csv"},
+            command:    []string{"derive", "86", "-mnemonic", phrase, "-count", "4", "-out", "a.csv"},
+            stdin:      "\n",
+            files:      []string{"a.csv"},
+            equivalent: "passphrase_bitcoin derive -count 4 -mnemonic ... -out a.csv 86",
+        },
+        {
+            name:       "selftest",
+            legacy:     []string{"-selftest", "-canonical"},
+            command:    []string{"selftest", "-canonical"},
+            equivalent: "passphrase_bitcoin selftest -canonical",
+        },
+    }
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            legacyOut, legacyErr, legacyDir := run(t, c.stdin, c.legacy...)
+            out, errOut, dir := run(t, c.stdin, c.command...)
+            if legacyOut != out {
+                t.Errorf("stdout differs\n%s:\n%s\n%s:\n%s", strings.Join(c.legacy, " "), legacyOut, strings.Join(c.command, " "), out)
+            }
+            if out == "" {
+                t.Error("no output")
+            }
+            for _, name := range c.files {
+                a, err := os.ReadFile(filepath.Join(legacyDir, name))
+                if err != nil {
+                    t.Fatal(err)
+                }
+                b, err := os.ReadFile(filepath.Join(dir, name))
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if !bytes.Equal(a, b) {
+                    t.Errorf("%s differs", name)
+                }
+            }
+            if want := "use: " + c.equivalent + "\n"; !strings.Contains(legacyErr, want) {
+                t.Errorf("legacy stderr lacks %q:\n%s", want, legacyErr)
+            }
+            if strings.Contains(errOut, "deprecated") {
+                t.Errorf("command warns: %s", errOut)
+            }
+        })
+    }
+}
+
+// 子命令的选项与全局选项分开定义：不能共用同一个值，名字相同的选项含义也相同，
+// 字符串选项同样要归入秘密或公开两类
+func TestSubcommandFlags(t *testing.T) {
+    defineFlags()
+
+    for i := range subcommands {
+        c := &subcommands[i]
+        if flag.Lookup(c.legacy) == nil {
+            t.Errorf("%s: no legacy option -%s", c.name, c.legacy)
+        }
+        a, b := c.invocation(), c.invocation()
+        a.fs.VisitAll(func(f *flag.Flag) {
+            if f.Value == b.fs.Lookup(f.Name).Value {
+                t.Errorf("%s -%s: two invocations share one value", c.name, f.Name)
+            }
+            global := flag.Lookup(f.Name)
+            switch {
+            case global == nil && !(c.name == "generate" && f.Name == "qr"):
+                t.Errorf("%s -%s has no legacy option of the same name", c.name, f.Name)
+            case global != nil && global.Value == f.Value:
+                t.Errorf("%s -%s shares its value with the global option", c.name, f.Name)
+            case global != nil && global.DefValue != f.DefValue:
+                t.Errorf("%s -%s defaults to %q, the legacy option to %q", c.name, f.Name, f.DefValue, global.DefValue)
+            }
+            if isStringValue(f.Value) && !transcriptSecretFlags[f.Name] && !transcriptPublicFlags[f.Name] {
+                t.Errorf("%s -%s is neither secret nor public", c.name, f.Name)
+            }
+        })
+    }
+}
+
+func isStringValue(v flag.Value) bool {
+    g, ok := v.(flag.Getter)
+    if !ok {
+        return false
+    }
+    _, isString := g.Get().(string)
+    return isString
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "crypto/rand"
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "os/exec"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+)
+
+//
+// -------------------------
+//   K-of-N 门限备份（age / GPG 收件人）
+// -------------------------
+//
+// 对助记词的每个单词索引做 Shamir 秘密分享，运算在 GF(2^11) 上（与 Reed–Solomon
+// 校验词相同的域），所以每份分享与助记词的单词数相同。第 i 份在 x = α^i 处取值，
+// 常数项是原单词；少于 K 份不泄露任何信息。
+// 每份分享用对应收件人的 age 或 GPG 公钥加密后写入 share-i.age / share-i.gpg，
+// 任意 K 个收件人各自解密后，用 -threshold-combine 合并。
+//
+
+const shareHeader = "passphrase_bitcoin threshold share"
+
+// 份数上限；x 的取值 α^x 在 x < 2047 之前互不相同，这里取一个远小于它的数
+const maxShares = 255
+
+type thresholdShare struct {
+    set         string
+    k, n, x     int
+    fingerprint string
+    indices     []int
+}
+
+type shareRecipient struct {
+    tool string // age 或 gpg
+    id   string
+}
+
+// "age1…"、"ssh-…" 用 age，其它按 GPG 用户 ID；也可写 "age:" / "gpg:" 前缀
+func parseRecipients(s string) ([]shareRecipient, error) {
+    var rs []shareRecipient
+    for _, f := range strings.Split(s, ",") {
+        f = strings.TrimSpace(f)
+        switch {
+        case f == "":
+            continue
+        case strings.HasPrefix(f, "age:"):
+            rs = append(rs, shareRecipient{"age", f[4:]})
+        case strings.HasPrefix(f, "gpg:"):
+            rs = append(rs, shareRecipient{"gpg", f[4:]})
+        case strings.HasPrefix(f, "age1"), strings.HasPrefix(f, "ssh-"):
+            rs = append(rs, shareRecipient{"age", f})
+        default:
+            rs = append(rs, shareRecipient{"gpg", f})
+        }
+    }
+    if len(rs) == 0 {
+        return nil, fmt.Errorf("no recipients")
+    }
+    return rs, nil
+}
+
+func splitThreshold(k int, recipientSpec string, wordList []string) {
+    recipients, err := parseRecipients(recipientSpec)
+    if err != nil {
+        log.Fatalf("Error: -recipients: %v", err)
+    }
+    n := len(recipients)
+    if n > maxShares {
+        log.Fatalf("Error: at most %d recipients, got %d", maxShares, n)
+    }
+    if k < 2 || k > n {
+        log.Fatalf("Error: threshold %d must be between 2 and the number of recipients (%d)", k, n)
+    }
+    for _, r := range recipients {
+        if _, err := exec.LookPath(r.tool); err != nil {
+            log.Fatalf("Error: %s not found in PATH (needed for recipient %s)", r.tool, r.id)
+        }
+    }
+
+    indices := passphraseIndicesFromBinary()
+    fingerprint := masterFingerprint(wordsFromIndices(indices, wordList))
+    setID := make([]byte, 4)
+    if _, err := rand.Read(setID); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+
+    shares, err := shamirSplit(indices, k, n)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    for i, r := range recipients {
+        text := formatShare(thresholdShare{
+            set:         hex.EncodeToString(setID),
+            k:           k,
+            n:           n,
+            x:           i + 1,
+            fingerprint: fingerprint,
+            indices:     shares[i],
+        }, wordList)
+
+        path := fmt.Sprintf("share-%d.%s", i+1, r.tool)
+        if err := encryptToRecipient(r, []byte(text), path); err != nil {
+            log.Fatalf("Error: share %d for %s: %v", i+1, r.id, err)
+        }
+        fmt.Printf("%s written for %s (%s).\n", path, r.id, r.tool)
+    }
+    fmt.Printf("Any %d of these %d shares recover the passphrase (set %x, fingerprint %s).\n", k, n, setID, fingerprint)
+}
+
+// 每个位置一个 K-1 次随机多项式，常数项为原单词
+func shamirSplit(secret []int, k, n int) ([][]int, error) {
+    shares := make([][]int, n)
+    for i := range shares {
+        shares[i] = make([]int, len(secret))
+    }
+    coeffs := make([]int, k)
+    buf := make([]byte, 2*(k-1))
+    for pos, s := range secret {
+        if _, err := rand.Read(buf); err != nil {
+            return nil, err
+        }
+        coeffs[0] = s
+        for j := 1; j < k; j++ {
+            coeffs[j] = int(binary.BigEndian.Uint16(buf[2*(j-1):])) & (gf11Size - 1)
+        }
+        for i := 0; i < n; i++ {
+            x := gf11Exp[i+1]
+            y := 0
+            for j := k - 1; j >= 0; j-- {
+                y = gf11Mul(y, x) ^ coeffs[j]
+            }
+            shares[i][pos] = y
+        }
+    }
+    return shares, nil
+}
+
+// 合并前检查每一份：属于同一组、编号在 1…N 内且不重复、单词索引在域内。
+// 重复的 x 会让插值除以零，结果悄悄出错，所以必须拒绝
+func shamirCombine(shares []thresholdShare) ([]int, error) {
+    if len(shares) == 0 {
+        return nil, fmt.Errorf("no shares")
+    }
+    first := shares[0]
+    seen := map[int]bool{}
+    for _, s := range shares {
+        if s.set != first.set || s.k != first.k || s.n != first.n || len(s.indices) != len(first.indices) {
+            return nil, fmt.Errorf("share #%d belongs to a different share set", s.x)
+        }
+        if s.x < 1 || s.x > s.n || s.n > maxShares {
+            return nil, fmt.Errorf("bad share number #%d of %d", s.x, s.n)
+        }
+        if seen[s.x] {
+            return nil, fmt.Errorf("share #%d given twice", s.x)
+        }
+        seen[s.x] = true
+        for _, v := range s.indices {
+            if v < 0 || v >= gf11Size {
+                return nil, fmt.Errorf("share #%d: word index %d out of range", s.x, v)
+            }
+        }
+    }
+    if len(shares) < first.k {
+        return nil, fmt.Errorf("got %d shares, need %d", len(shares), first.k)
+    }
+
+    shares = shares[:first.k]
+    xs := make([]int, len(shares))
+    for i, s := range shares {
+        xs[i] = gf11Exp[s.x]
+    }
+    secret := make([]int, len(first.indices))
+    ys := make([]int, len(shares))
+    for pos := range secret {
+        for i, s := range shares {
+            ys[i] = s.indices[pos]
+        }
+        secret[pos] = gf11Interpolate(xs, ys, 0)
+    }
+    return secret, nil
+}
+
+// 明文只经过管道交给 age/gpg，不落盘
+func encryptToRecipient(r shareRecipient, plaintext []byte, path string) error {
+    var cmd *exec.Cmd
+    var err error
+    if r.tool == "age" {
+        cmd, err = sandboxCommand("age", "--armor", "--recipient", r.id)
+    } else {
+        cmd, err = sandboxCommand("gpg", "--batch", "--armor", "--trust-model", "always", "--encrypt", "--recipient", r.id, "--output", "-")
+    }
+    if err != nil {
+        return err
+    }
+    cmd.Stdin = bytes.NewReader(plaintext)
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    out, err := cmd.Output()
+    if err != nil {
+        return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+    }
+
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+    if err != nil {
+        return err
+    }
+    if _, err := f.Write(out); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+func formatShare(s thresholdShare, wordList []string) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "%s %d-of-%d #%d\n", shareHeader, s.k, s.n, s.x)
+    fmt.Fprintf(&b, "set: %s\n", s.set)
+    fmt.Fprintf(&b, "fingerprint: %s\n", s.fingerprint)
+    fmt.Fprintf(&b, "words: %s\n", wordsFromIndices(s.indices, wordList))
+    return b.String()
+}
+
+func parseShare(text string, wordList []string) (thresholdShare, error) {
+    var s thresholdShare
+    scanner := bufio.NewScanner(strings.NewReader(text))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if rest, ok := strings.CutPrefix(line, shareHeader+" "); ok {
+            if _, err := fmt.Sscanf(rest, "%d-of-%d #%d", &s.k, &s.n, &s.x); err != nil {
+                return s, fmt.Errorf("bad header %q", line)
+            }
+            continue
+        }
+        key, value, ok := strings.Cut(line, ":")
+        if !ok {
+            continue
+        }
+        value = strings.TrimSpace(value)
+        switch key {
+        case "set":
+            s.set = value
+        case "fingerprint":
+            s.fingerprint = value
+        case "words":
+            indices, err := bip39.Indices(splitWords(value), wordList)
+            if err != nil {
+                return s, err
+            }
+            s.indices = indices
+        }
+    }
+    if s.k < 2 || s.x < 1 || s.x > s.n || s.set == "" || len(s.indices) == 0 {
+        return s, fmt.Errorf("not a %s", shareHeader)
+    }
+    if s.n > maxShares || s.k > s.n {
+        return s, fmt.Errorf("bad header: %d-of-%d (at most %d shares)", s.k, s.n, maxShares)
+    }
+    return s, nil
+}
+
+// 一个来源里可以有多份分享（如 age -d 的输出接在一起），在标题行处切开
+func splitShareTexts(text string) []string {
+    var texts []string
+    var cur strings.Builder
+    for _, line := range strings.SplitAfter(text, "\n") {
+        if strings.HasPrefix(strings.TrimSpace(line), shareHeader+" ") && cur.Len() > 0 {
+            texts = append(texts, cur.String())
+            cur.Reset()
+        }
+        cur.WriteString(line)
+    }
+    if strings.TrimSpace(cur.String()) != "" {
+        texts = append(texts, cur.String())
+    }
+    return texts
+}
+
+// files 是逗号分隔的文件名；- 表示标准输入，例如 age -d share-1.age | … -threshold-combine -
+func combineThreshold(files string, wordList []string) {
+    var shares []thresholdShare
+    seen := map[int]bool{}
+    stdinUsed := false
+    for _, path := range strings.Split(files, ",") {
+        path = strings.TrimSpace(path)
+        var data []byte
+        var err error
+        if path == "-" {
+            if stdinUsed {
+                log.Fatalf("Error: - (standard input) given twice")
+            }
+            stdinUsed = true
+            path = "standard input"
+            data, err = readAllLimited(stdinReader, maxTextFileSize)
+        } else {
+            data, err = readFileLimited(path, maxTextFileSize)
+        }
+        if err != nil {
+            log.Fatalf("Error reading %s: %v", path, err)
+        }
+        texts := splitShareTexts(string(data))
+        if len(texts) == 0 {
+            log.Fatalf("Error: %s: not a %s", path, shareHeader)
+        }
+        for _, text := range texts {
+            s, err := parseShare(text, wordList)
+            if err != nil {
+                log.Fatalf("Error: %s: %v", path, err)
+            }
+            if len(shares) > 0 {
+                first := shares[0]
+                if s.set != first.set || s.k != first.k || s.n != first.n || len(s.indices) != len(first.indices) {
+                    log.Fatalf("Error: %s: share #%d belongs to a different share set", path, s.x)
+                }
+            }
+            if seen[s.x] {
+                log.Fatalf("Error: %s: share #%d given twice", path, s.x)
+            }
+            seen[s.x] = true
+            shares = append(shares, s)
+        }
+    }
+    indices, err := shamirCombine(shares)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if _, err := bip39.EntropyFromIndices(indices); err != nil {
+        log.Fatalf("Error: combined passphrase is invalid: %v", err)
+    }
+    mnemonic := wordsFromIndices(indices, wordList)
+    if fp := masterFingerprint(mnemonic); !secretcmp.EqualString(fp, shares[0].fingerprint) {
+        log.Fatalf("Error: combined fingerprint %s does not match %s", fp, shares[0].fingerprint)
+    }
+
+    fmt.Println("Passphrase:")
+    printPhrase(mnemonic)
+}
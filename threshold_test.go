@@ -0,0 +1,218 @@
+package main
+
+import (
+    "slices"
+    "strings"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+func thresholdTestShares(t *testing.T, secret []int, k, n int) []thresholdShare {
+    t.Helper()
+    ys, err := shamirSplit(secret, k, n)
+    if err != nil {
+        t.Fatal(err)
+    }
+    shares := make([]thresholdShare, n)
+    for i := range shares {
+        shares[i] = thresholdShare{set: "0123abcd", k: k, n: n, x: i + 1, fingerprint: "00000000", indices: ys[i]}
+    }
+    return shares
+}
+
+// 从 n 份中取 k 份的全部组合
+func subsets(n, k int) [][]int {
+    if k == 0 {
+        return [][]int{nil}
+    }
+    var out [][]int
+    for first := 0; first <= n-k; first++ {
+        for _, rest := range subsets(n-first-1, k-1) {
+            s := []int{first}
+            for _, r := range rest {
+                s = append(s, first+1+r)
+            }
+            out = append(out, s)
+        }
+    }
+    return out
+}
+
+func pick(shares []thresholdShare, idx []int) []thresholdShare {
+    out := make([]thresholdShare, len(idx))
+    for i, j := range idx {
+        out[i] = shares[j]
+    }
+    return out
+}
+
+// 任意 K 份（任意顺序）都还原出原单词
+func TestShamirEveryKSubset(t *testing.T) {
+    secret := rsTestIndices(12)
+    for n := 2; n <= 5; n++ {
+        for k := 2; k <= n; k++ {
+            shares := thresholdTestShares(t, secret, k, n)
+            for _, idx := range subsets(n, k) {
+                got, err := shamirCombine(pick(shares, idx))
+                if err != nil {
+                    t.Fatalf("%d-of-%d %v: %v", k, n, idx, err)
+                }
+                if !slices.Equal(got, secret) {
+                    t.Errorf("%d-of-%d %v: combined %v, want %v", k, n, idx, got, secret)
+                }
+                slices.Reverse(idx)
+                if got, _ := shamirCombine(pick(shares, idx)); !slices.Equal(got, secret) {
+                    t.Errorf("%d-of-%d %v reversed: combined %v", k, n, idx, got)
+                }
+            }
+            // 多于 K 份也可以
+            if got, err := shamirCombine(shares); err != nil || !slices.Equal(got, secret) {
+                t.Errorf("%d-of-%d all shares: %v, %v", k, n, got, err)
+            }
+        }
+    }
+}
+
+// K-1 份被拒绝；即使改写份中的 K 强行插值，得到的也不是原单词
+func TestShamirTooFewShares(t *testing.T) {
+    secret := rsTestIndices(24)
+    for _, kn := range [][2]int{{2, 3}, {3, 5}, {4, 4}} {
+        k, n := kn[0], kn[1]
+        shares := thresholdTestShares(t, secret, k, n)
+        for _, idx := range subsets(n, k-1) {
+            few := pick(shares, idx)
+            if _, err := shamirCombine(few); err == nil || !strings.Contains(err.Error(), "need") {
+                t.Errorf("%d-of-%d with %v: err = %v, want too few shares", k, n, idx, err)
+            }
+            forged := slices.Clone(few)
+            for i := range forged {
+                forged[i].k = k - 1
+            }
+            if got, err := shamirCombine(forged); err == nil && slices.Equal(got, secret) {
+                t.Errorf("%d-of-%d: %d shares recovered the secret", k, n, k-1)
+            }
+        }
+    }
+}
+
+func TestShamirRejectsBadShares(t *testing.T) {
+    secret := rsTestIndices(12)
+    shares := thresholdTestShares(t, secret, 3, 5)
+    tests := []struct {
+        name   string
+        shares func() []thresholdShare
+        want   string
+    }{
+        {"duplicate index", func() []thresholdShare {
+            return []thresholdShare{shares[0], shares[1], shares[0]}
+        }, "given twice"},
+        {"relabelled duplicate", func() []thresholdShare {
+            s := shares[2]
+            s.x = 2
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "given twice"},
+        {"index 0", func() []thresholdShare {
+            s := shares[2]
+            s.x = 0
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "bad share number"},
+        {"index above n", func() []thresholdShare {
+            s := shares[2]
+            s.x = 6
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "bad share number"},
+        {"word index out of range", func() []thresholdShare {
+            s := shares[2]
+            s.indices = slices.Clone(s.indices)
+            s.indices[4] = gf11Size
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "out of range"},
+        {"different set", func() []thresholdShare {
+            s := shares[2]
+            s.set = "ffffffff"
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "different share set"},
+        {"different length", func() []thresholdShare {
+            s := shares[2]
+            s.indices = s.indices[:11]
+            return []thresholdShare{shares[0], shares[1], s}
+        }, "different share set"},
+        {"none", func() []thresholdShare { return nil }, "no shares"},
+    }
+    for _, tt := range tests {
+        if _, err := shamirCombine(tt.shares()); err == nil || !strings.Contains(err.Error(), tt.want) {
+            t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+        }
+    }
+}
+
+// 改了一个单词的分享：合并结果不同，由校验和或指纹发现
+func TestShamirCorruptedWord(t *testing.T) {
+    wordList := bip39.English()
+    secret := rsTestIndices(12)
+    fp := masterFingerprint(wordsFromIndices(secret, wordList))
+    shares := thresholdTestShares(t, secret, 2, 3)
+    for pos := range secret {
+        bad := shares[1]
+        bad.indices = slices.Clone(bad.indices)
+        bad.indices[pos] ^= 1
+        got, err := shamirCombine([]thresholdShare{shares[0], bad})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if slices.Equal(got, secret) {
+            t.Fatalf("position %d: corrupted share still gave the secret", pos)
+        }
+        if _, err := bip39.EntropyFromIndices(got); err == nil && masterFingerprint(wordsFromIndices(got, wordList)) == fp {
+            t.Errorf("position %d: corruption not detected", pos)
+        }
+    }
+}
+
+// 分享的文本格式往返；不在词表上的单词、错误的标题被拒绝
+func TestShareFormatRoundTrip(t *testing.T) {
+    wordList := bip39.English()
+    shares := thresholdTestShares(t, rsTestIndices(12), 2, 3)
+    for _, s := range shares {
+        got, err := parseShare(formatShare(s, wordList), wordList)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got.set != s.set || got.k != s.k || got.n != s.n || got.x != s.x || !slices.Equal(got.indices, s.indices) {
+            t.Errorf("round trip of share #%d: %+v", s.x, got)
+        }
+    }
+    text := formatShare(shares[0], wordList)
+    for name, bad := range map[string]string{
+        "unknown word": strings.Replace(text, "words: ", "words: notaword ", 1),
+        "x above n":    strings.Replace(text, "2-of-3 #1", "2-of-3 #4", 1),
+        "k above n":    strings.Replace(text, "2-of-3 #1", "4-of-3 #1", 1),
+        "k of 1":       strings.Replace(text, "2-of-3 #1", "1-of-3 #1", 1),
+    } {
+        if _, err := parseShare(bad, wordList); err == nil {
+            t.Errorf("%s: parseShare accepted\n%s", name, bad)
+        }
+    }
+}
+
+func TestSplitShareTexts(t *testing.T) {
+    wordList := bip39.English()
+    shares := thresholdTestShares(t, rsTestIndices(12), 2, 3)
+    joined := formatShare(shares[0], wordList) + formatShare(shares[2], wordList)
+    texts := splitShareTexts(joined)
+    if len(texts) != 2 {
+        t.Fatalf("got %d texts, want 2", len(texts))
+    }
+    var parsed []thresholdShare
+    for _, text := range texts {
+        s, err := parseShare(text, wordList)
+        if err != nil {
+            t.Fatal(err)
+        }
+        parsed = append(parsed, s)
+    }
+    if got, err := shamirCombine(parsed); err != nil || !slices.Equal(got, rsTestIndices(12)) {
+        t.Errorf("combined %v, %v", got, err)
+    }
+}
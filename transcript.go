@@ -0,0 +1,127 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "log"
+    "os"
+    "runtime"
+    "time"
+)
+
+//
+// -------------------------
+//   -transcript 会话记录
+// -------------------------
+//
+// 把本次运行的命令、参数、指纹与校验结果写成 JSON，作为“何时、如何生成并
+// 校验备份”的存档证据。不记录助记词、口令或熵；可能含秘密的参数值一律
+// 替换为 [redacted]。每记录一步就重写一次文件，出错退出时 status 停留在
+// running，可以看出会话没有正常结束。
+//
+
+// 值可能是秘密（或秘密的一部分）的选项
+var transcriptSecretFlags = map[string]bool{
+    "mnemonic":      true,
+    "import-dec":    true,
+    "import-offset": true,
+    "cards":         true,
+    "entropy-hex":   true,
+    "dice":          true,
+    "coins":         true,
+    "decode":        true,
+    "check-bits":    true,
+    "recover-rs":    true,
+    "pattern":       true,
+    "last-word":     true,
+    "i":             true,
+    "v":             true,
+}
+
+type sessionTranscript struct {
+    Version  int               `json:"version"`
+    Tool     string            `json:"tool"`
+    Platform string            `json:"platform"`
+    Started  string            `json:"started"`
+    Finished string            `json:"finished,omitempty"`
+    Status   string            `json:"status"`
+    Options  map[string]string `json:"options"`
+    Events   []transcriptEvent `json:"events"`
+
+    path string
+}
+
+type transcriptEvent struct {
+    Time        string `json:"time"`
+    Step        string `json:"step"`
+    Result      string `json:"result"`
+    Fingerprint string `json:"fingerprint,omitempty"`
+    Detail      string `json:"detail,omitempty"`
+}
+
+// 为 nil 时不记录
+var transcript *sessionTranscript
+
+func startTranscript(path string, fs *flag.FlagSet) {
+    t := &sessionTranscript{
+        Version:  1,
+        Tool:     "passphrase_bitcoin",
+        Platform: runtime.GOOS + "/" + runtime.GOARCH + " " + runtime.Version(),
+        Started:  time.Now().UTC().Format(time.RFC3339),
+        Status:   "running",
+        Options:  map[string]string{},
+        Events:   []transcriptEvent{},
+        path:     path,
+    }
+    fs.Visit(func(f *flag.Flag) {
+        value := f.Value.String()
+        if transcriptSecretFlags[f.Name] {
+            value = "[redacted]"
+        }
+        t.Options[f.Name] = value
+    })
+    transcript = t
+    t.write()
+}
+
+func (t *sessionTranscript) record(step, result, fingerprint, detail string) {
+    usage.note(step, result, fingerprint)
+    if t == nil {
+        return
+    }
+    t.Events = append(t.Events, transcriptEvent{
+        Time:        time.Now().UTC().Format(time.RFC3339),
+        Step:        step,
+        Result:      result,
+        Fingerprint: fingerprint,
+        Detail:      detail,
+    })
+    t.write()
+}
+
+// 记录 binary.txt 当前的主指纹
+func (t *sessionTranscript) recordBinary(step, result string, wordList []string) {
+    if t == nil && usage == nil {
+        return
+    }
+    t.record(step, result, masterFingerprint(generatePassphraseFromBinary(wordList)), "")
+}
+
+func (t *sessionTranscript) finish() {
+    if t == nil {
+        return
+    }
+    t.Finished = time.Now().UTC().Format(time.RFC3339)
+    t.Status = "completed"
+    t.write()
+}
+
+func (t *sessionTranscript) write() {
+    data, err := json.MarshalIndent(t, "", "  ")
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if err := os.WriteFile(t.path, append(data, '\n'), 0644); err != nil {
+        log.Printf("Warning: writing %s: %v", t.path, err)
+    }
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "strings"
+    "testing"
+)
+
+// 值不是秘密的字符串选项：文件名、格式、标签、地址等。新增字符串选项时
+// 要么放进 transcriptSecretFlags，要么放在这里，否则测试失败。
+var transcriptPublicFlags = map[string]bool{
+    "f": true, "schema": true, "tmpdir": true, "stats": true, "qr-decode": true, "qr-out": true, "qr-ec": true,
+    "ocr": true, "import-grid": true, "decoy-recover": true, "import-sheet": true, "audio-export": true,
+    "audio-decode": true, "stego-embed": true, "stego-extract": true, "export-sealed": true, "import-sealed": true,
+    "recipients": true, "threshold-combine": true, "slip39-combine": true, "slip39-split": true, "slip39-audit": true, "slip39-token-write": true, "device": true, "import": true,
+    "export": true, "to": true, "from": true, "wordlist-check": true, "wordlist-sort": true, "electrum": true,
+    "proxy": true, "bundle-export": true, "bundle-verify": true, "rotate-plan": true, "hint-add": true,
+    "hint-check": true, "target": true, "checkpoint": true, "kdf": true, "masked": true, "separator": true,
+    "metrics": true, "uri": true, "amount": true, "label": true, "message": true, "xpub-sheet": true,
+    "addr-type": true, "transcript": true, "qr-in": true, "fb": true, "debias": true, "derive": true,
+    "out": true, "set-birthday": true, "rngtest": true, "rng-device": true, "wordlist": true, "lang": true,
+}
+
+// main 在解析参数之前定义全部选项；-version 随后直接返回。只能运行一次
+func defineFlags() {
+    if flag.Lookup("b") != nil {
+        return
+    }
+    args := os.Args
+    defer func() { os.Args = args }()
+    os.Args = []string{"passphrase_bitcoin", "-version"}
+    main()
+}
+
+func TestTranscriptRedactsSecretFlags(t *testing.T) {
+    defineFlags()
+
+    flag.VisitAll(func(f *flag.Flag) {
+        if fmt.Sprintf("%T", f.Value) != "*flag.stringValue" || strings.HasPrefix(f.Name, "test.") {
+            return
+        }
+        secret, public := transcriptSecretFlags[f.Name], transcriptPublicFlags[f.Name]
+        switch {
+        case secret && public:
+            t.Errorf("-%s is listed both as secret and as public", f.Name)
+        case !secret && !public:
+            t.Errorf("-%s is not redacted in transcripts; add it to transcriptSecretFlags (or to transcriptPublicFlags if its value is never secret)", f.Name)
+        }
+    })
+    for name := range transcriptSecretFlags {
+        if flag.Lookup(name) == nil {
+            t.Errorf("transcriptSecretFlags: no option -%s", name)
+        }
+    }
+}
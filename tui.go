@@ -0,0 +1,445 @@
+package main
+
+import (
+    "crypto/rand"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+    "unicode"
+    "unicode/utf8"
+
+    qrcode "github.com/skip2/go-qrcode"
+    "golang.org/x/term"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -tui 全屏引导
+// -------------------------
+//
+// 给不熟悉命令行的用户：全屏界面一步步完成
+//   选择熵来源 → 选择单词数 → 逐个抄写单词 → 抽查几个位置 → 可选显示二维码 → 保存。
+// 方向键（或 j/k）移动，回车确认，q 退出。界面使用终端的备用屏幕，
+// 退出时清屏并清除回滚缓冲，单词不会留在终端的滚动记录里。
+// 保存在界面关闭之后进行（-encrypt 的口令照常询问），-f 同样适用。
+//
+
+var errTUIQuit = errors.New("quit")
+
+// 抽查的单词个数
+const tuiQuizWords = 3
+
+type tui struct {
+    fd      int
+    state   *term.State // 进入原始模式前的终端状态
+    pending []byte      // 已读入、尚未处理的按键（粘贴或快速输入时一次读到多个）
+    summary []string    // 关闭界面后打印
+}
+
+func runTUI(wordList []string) {
+    fd := int(os.Stdin.Fd())
+    if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+        log.Fatalf("Error: -tui needs an interactive terminal")
+    }
+    state, err := term.MakeRaw(fd)
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    // 备用屏幕、隐藏光标
+    fmt.Print("\x1b[?1049h\x1b[?25l")
+    t := &tui{fd: fd, state: state}
+    entropy, save, err := t.run(wordList)
+    // 清屏、回到主屏幕、清除回滚缓冲
+    fmt.Print("\x1b[2J\x1b[H\x1b[?25h\x1b[?1049l\x1b[3J")
+    term.Restore(fd, state)
+    if errors.Is(err, errTUIQuit) {
+        fmt.Println("Cancelled. Nothing was saved.")
+        return
+    }
+    if errors.Is(err, errTUIDone) {
+        for _, l := range t.summary {
+            fmt.Println(l)
+        }
+        return
+    }
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    if !save {
+        fmt.Println("Finished without saving. The words exist only on your paper now.")
+        transcript.record("guided setup", "not saved", masterFingerprint(mnemonicFromEntropy(entropy, wordList)), "")
+        return
+    }
+
+    if _, err := os.Stat(binaryPath); err == nil {
+        log.Fatalf("Error: %s already exists, move it away first.", binaryPath)
+    }
+    if err := writeBinaryFile(binaryPath, entropy); err != nil {
+        log.Fatalf("Error writing %s: %v", binaryPath, err)
+    }
+    fp := masterFingerprint(mnemonicFromEntropy(entropy, wordList))
+    fmt.Printf("%s generated successfully (%d bits, %d words).\n", binaryPath, len(entropy)*8, len(entropy)*3/4)
+    fmt.Println("Fingerprint:", fp)
+    transcript.recordBinary("generate binary.txt", "ok", wordList)
+    if err := recordBirthday(fp, time.Now()); err != nil {
+        log.Fatalf("Error writing %s: %v", birthdayFile, err)
+    }
+}
+
+func (t *tui) run(wordList []string) ([]byte, bool, error) {
+    if _, err := os.Stat(binaryPath); err == nil || demoMode {
+        intro := binaryPath + " already exists."
+        if demoMode {
+            intro = "Demo mode: the stored phrase is the public demo phrase."
+        }
+        choice, err := t.menu("Start", []string{intro}, []string{"Set up a new passphrase (it cannot be saved)", "Compare a written backup with " + binaryPath})
+        if err != nil {
+            return nil, false, err
+        }
+        if choice == 1 {
+            return nil, false, t.compare(wordList)
+        }
+    }
+
+    var entropy []byte
+    if demoMode {
+        entropy = demoEntropy
+    } else {
+        var err error
+        if entropy, err = t.chooseEntropy(); err != nil {
+            return nil, false, err
+        }
+    }
+    mnemonic := mnemonicFromEntropy(entropy, wordList)
+    words := strings.Fields(mnemonic)
+
+    for {
+        if err := t.review(words); err != nil {
+            return nil, false, err
+        }
+        ok, err := t.quiz(words, wordList)
+        if err != nil {
+            return nil, false, err
+        }
+        if ok {
+            break
+        }
+    }
+
+    for {
+        options := []string{"Show the QR code", "Save to " + binaryPath + " and finish", "Finish without saving"}
+        if demoMode {
+            options[1] = "(saving is disabled in demo mode)"
+        } else if _, err := os.Stat(binaryPath); err == nil {
+            options[1] = "(" + binaryPath + " already exists: cannot save)"
+        }
+        choice, err := t.menu("Step 5 of 5: finish", []string{
+            "All checked words were correct.",
+            "Keep the paper somewhere safe; anyone who reads it can take the funds.",
+        }, options)
+        if err != nil {
+            return nil, false, err
+        }
+        switch choice {
+        case 0:
+            if err := t.showQR(mnemonic); err != nil {
+                return nil, false, err
+            }
+        case 1:
+            if strings.HasPrefix(options[1], "(") {
+                continue
+            }
+            return entropy, true, nil
+        case 2:
+            return entropy, false, nil
+        }
+    }
+}
+
+func (t *tui) chooseEntropy() ([]byte, error) {
+    source, err := t.menu("Step 1 of 5: where the randomness comes from", []string{
+        "The passphrase is made from random bits. The system random generator is enough;",
+        "dice rolls or keyboard mashing are mixed in on top for those who want it.",
+    }, []string{"System random generator (recommended)", "System random + dice rolls", "System random + keyboard mashing"})
+    if err != nil {
+        return nil, err
+    }
+    sizes := []int{24, 12, 15, 18, 21}
+    n, err := t.menu("Step 2 of 5: number of words", []string{
+        "24 words is the most common choice; 12 words is also considered secure.",
+    }, []string{"24 words (256 bits)", "12 words (128 bits)", "15 words (160 bits)", "18 words (192 bits)", "21 words (224 bits)"})
+    if err != nil {
+        return nil, err
+    }
+    size := sizes[n] * 4 / 3
+
+    entropy := make([]byte, size)
+    if _, err := rand.Read(entropy); err != nil {
+        return nil, err
+    }
+    switch source {
+    case 1:
+        intro := []string{
+            "Roll a six-sided die many times and type the results (1–6).",
+            fmt.Sprintf("About %d rolls cover all %d bits on their own.", (size*8*100+257)/258, size*8),
+        }
+        for {
+            rolls, err := t.input("Dice rolls", intro, "Rolls: ")
+            if err != nil {
+                return nil, err
+            }
+            s, err := diceSource(rolls)
+            if err == nil {
+                mixSources(entropy, []entropySource{s})
+                break
+            }
+            intro = append(intro[:2], "", "Not accepted: "+err.Error())
+        }
+    case 2:
+        t.clear()
+        data, err := runEntropyGame(size * 8)
+        if errors.Is(err, errGameAborted) {
+            return nil, errTUIQuit
+        }
+        if err != nil {
+            return nil, err
+        }
+        mixSources(entropy, []entropySource{{name: "keyboard game", detail: "estimated", bits: float64(size * 8), data: data}})
+    }
+    return entropy, nil
+}
+
+// 逐个显示单词；看完最后一个才能继续
+func (t *tui) review(words []string) error {
+    for i := 0; i < len(words); {
+        t.draw("Step 3 of 5: write the words down", []string{
+            fmt.Sprintf("Word %d of %d", i+1, len(words)),
+            "",
+            fmt.Sprintf("      \x1b[1m%d. %s\x1b[0m", i+1, words[i]),
+            "",
+            "Write it on paper, in order. Never store it in a file, a password",
+            "manager or a phone, and never take a photo of it.",
+        }, "→/Enter next   ← previous   q quit")
+        key, err := t.key()
+        if err != nil {
+            return err
+        }
+        switch key {
+        case "right", "enter", " ", "l":
+            i++
+        case "left", "h", "backspace":
+            if i > 0 {
+                i--
+            }
+        case "q", "ctrl-c":
+            if t.confirmQuit() {
+                return errTUIQuit
+            }
+        }
+    }
+    return nil
+}
+
+// 随机抽查几个位置；全部答对返回 true
+func (t *tui) quiz(words []string, wordList []string) (bool, error) {
+    matcher := wordmatch.New(wordList)
+    positions, err := randomPositions(len(words))
+    if err != nil {
+        return false, err
+    }
+
+    for n, pos := range positions[:min(tuiQuizWords, len(words))] {
+        for {
+            answer, err := t.wordInput(fmt.Sprintf("Step 4 of 5: check %d of %d", n+1, tuiQuizWords), []string{
+                "Look at your paper, not at the screen you just saw.",
+                "The first four letters are enough.",
+            }, fmt.Sprintf("Word %d: ", pos+1), matcher)
+            if err != nil {
+                return false, err
+            }
+            c, ok := matcher.Lookup(answer)
+            if ok && secretcmp.EqualString(c.Word, words[pos]) {
+                break
+            }
+            choice, err := t.menu("Step 4 of 5: not quite", []string{
+                fmt.Sprintf("That is not word %d. Check your paper carefully.", pos+1),
+            }, []string{"Try again", "Show all the words again"})
+            if err != nil {
+                return false, err
+            }
+            if choice == 1 {
+                return false, nil
+            }
+        }
+    }
+    return true, nil
+}
+
+func (t *tui) showQR(mnemonic string) error {
+    qr, err := qrcode.New(qrPayload(mnemonic), qrcode.Low)
+    if err != nil {
+        return err
+    }
+    lines := strings.Split(strings.TrimRight(qr.ToSmallString(false), "\n"), "\n")
+    t.draw("QR code", append([]string{"Scan it with the wallet you are setting up, then press any key.", ""}, lines...), "any key: back")
+    _, err = t.key()
+    return err
+}
+
+func (t *tui) confirmQuit() bool {
+    choice, err := t.menu("Quit?", []string{"Nothing has been saved yet."}, []string{"Continue", "Quit without saving"})
+    return err != nil || choice == 1
+}
+
+// 方向键选择，回车确认，也可直接按序号
+func (t *tui) menu(title string, intro, options []string) (int, error) {
+    sel := 0
+    for {
+        lines := append([]string{}, intro...)
+        lines = append(lines, "")
+        for i, o := range options {
+            if i == sel {
+                lines = append(lines, fmt.Sprintf("  \x1b[7m> %d. %s\x1b[0m", i+1, o))
+            } else {
+                lines = append(lines, fmt.Sprintf("    %d. %s", i+1, o))
+            }
+        }
+        t.draw(title, lines, "↑/↓ move   Enter choose   q quit")
+        key, err := t.key()
+        if err != nil {
+            return 0, err
+        }
+        switch {
+        case key == "up" || key == "k":
+            sel = (sel + len(options) - 1) % len(options)
+        case key == "down" || key == "j" || key == "tab":
+            sel = (sel + 1) % len(options)
+        case key == "enter":
+            return sel, nil
+        case len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(options):
+            sel = int(key[0] - '1')
+        case key == "q" || key == "ctrl-c":
+            if title == "Quit?" {
+                return 0, nil
+            }
+            if t.confirmQuit() {
+                return 0, errTUIQuit
+            }
+        }
+    }
+}
+
+// 一行文字输入（备用屏幕，退出时清除）
+func (t *tui) input(title string, intro []string, prompt string) (string, error) {
+    var text []rune
+    for {
+        lines := append(append([]string{}, intro...), "", prompt+string(text)+"_")
+        t.draw(title, lines, "Enter confirm   Ctrl-C quit")
+        key, err := t.key()
+        if err != nil {
+            return "", err
+        }
+        switch {
+        case key == "enter":
+            return strings.TrimSpace(string(text)), nil
+        case key == "backspace":
+            if len(text) > 0 {
+                text = text[:len(text)-1]
+            }
+        case key == "ctrl-c":
+            if t.confirmQuit() {
+                return "", errTUIQuit
+            }
+        case utf8.RuneCountInString(key) == 1 && len(text) < maxLineLen:
+            text = append(text, []rune(key)...)
+        }
+    }
+}
+
+// 暂时回到普通终端执行 f（如询问 binary.txt 的解密口令）
+func (t *tui) cooked(f func()) {
+    fmt.Print("\x1b[2J\x1b[H\x1b[?25h\x1b[?1049l")
+    term.Restore(t.fd, t.state)
+    f()
+    if _, err := term.MakeRaw(t.fd); err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    fmt.Print("\x1b[?1049h\x1b[?25l")
+}
+
+func (t *tui) clear() {
+    fmt.Print("\x1b[2J\x1b[H")
+}
+
+func (t *tui) draw(title string, lines []string, footer string) {
+    var b strings.Builder
+    b.WriteString("\x1b[2J\x1b[H")
+    fmt.Fprintf(&b, "\x1b[1m passphrase_bitcoin — guided setup\x1b[0m   %s\r\n", title)
+    if demoMode {
+        b.WriteString(" " + demoWatermark + "\r\n")
+    }
+    b.WriteString("\r\n")
+    for _, l := range lines {
+        b.WriteString("  " + l + "\r\n")
+    }
+    b.WriteString("\r\n \x1b[2m" + footer + "\x1b[0m\r\n")
+    fmt.Print(b.String())
+}
+
+// 读一个按键；方向键是 ESC [ A..D。其他可见字符按 UTF-8 整个返回
+// （重音字母、输入法提交的假名、韩文、汉字）
+func (t *tui) key() (string, error) {
+    for len(t.pending) == 0 || t.pending[0] >= 0x80 && !utf8.FullRune(t.pending) {
+        var b [64]byte
+        n, err := os.Stdin.Read(b[:])
+        if err != nil {
+            return "", err
+        }
+        t.pending = append(t.pending, b[:n]...)
+    }
+    s := t.pending
+    if s[0] >= 0x80 {
+        r, size := utf8.DecodeRune(s)
+        t.pending = s[size:]
+        if r == utf8.RuneError || !unicode.IsPrint(r) {
+            return "", nil
+        }
+        return string(r), nil
+    }
+    if len(s) >= 3 && s[0] == 0x1b && s[1] == '[' {
+        t.pending = s[3:]
+        switch s[2] {
+        case 'A':
+            return "up", nil
+        case 'B':
+            return "down", nil
+        case 'C':
+            return "right", nil
+        case 'D':
+            return "left", nil
+        }
+        return "", nil
+    }
+    t.pending = s[1:]
+    switch c := s[0]; {
+    case c == 3 || c == 4:
+        return "ctrl-c", nil
+    case c == '\r' || c == '\n':
+        return "enter", nil
+    case c == 127 || c == 8:
+        return "backspace", nil
+    case c == '\t':
+        return "tab", nil
+    case c == 0x1b:
+        return "esc", nil
+    case c >= 0x20 && c < 0x7f:
+        return string(c), nil
+    }
+    return "", nil
+}
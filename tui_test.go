@@ -0,0 +1,78 @@
+package main
+
+import (
+    "slices"
+    "testing"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+// 粘贴或快速输入时一次读到的多个按键：多字节字符整个返回，方向键照常识别
+func TestTUIKeyUTF8(t *testing.T) {
+    ui := &tui{pending: []byte("aé가あ的\x1b[Bz\r")}
+    var keys []string
+    for len(ui.pending) > 0 {
+        k, err := ui.key()
+        if err != nil {
+            t.Fatal(err)
+        }
+        keys = append(keys, k)
+    }
+    want := []string{"a", "é", "가", "あ", "的", "down", "z", "enter"}
+    if !slices.Equal(keys, want) {
+        t.Errorf("keys = %q, want %q", keys, want)
+    }
+}
+
+// 候选：↓/↑ 选择，回车采用选中的单词，Tab 补全，继续输入取消选择
+func TestWordEntrySuggestions(t *testing.T) {
+    e := newWordEntry(wordmatch.New(bip39.English()), "")
+    for _, k := range []string{"a", "b"} {
+        e.key(k)
+    }
+    var got []string
+    for _, c := range e.candidates() {
+        got = append(got, c.Word)
+    }
+    if want := []string{"abandon", "ability", "able", "about", "above"}; !slices.Equal(got, want) {
+        t.Fatalf("candidates = %q, want %q", got, want)
+    }
+    if e.word() != "ab" {
+        t.Errorf("word() with nothing selected = %q, want the typed text", e.word())
+    }
+    for _, k := range []string{"down", "down", "down", "up"} {
+        e.key(k)
+    }
+    if e.word() != "ability" {
+        t.Errorf("word() = %q, want ability", e.word())
+    }
+    e.key("tab")
+    if string(e.text) != "ability" || e.sel != -1 {
+        t.Errorf("after Tab: text %q, sel %d", string(e.text), e.sel)
+    }
+    // 选中后继续输入，回到输入的文字
+    e = newWordEntry(wordmatch.New(bip39.English()), "zo")
+    e.key("down")
+    e.key("o")
+    if e.word() != "zoo" || e.sel != -1 {
+        t.Errorf("word() = %q, sel %d", e.word(), e.sel)
+    }
+    if e.key("enter") {
+        t.Error("Enter must be left to the caller")
+    }
+}
+
+// 非英文词表：候选按 NFKD 前缀匹配，合成写法的输入也能找到
+func TestWordEntrySuggestionsNonEnglish(t *testing.T) {
+    wordList, err := bip39.Wordlist("spanish")
+    if err != nil {
+        t.Fatal(err)
+    }
+    e := newWordEntry(wordmatch.New(wordList), "ába")
+    e.key("down")
+    if want := textnorm.NFKD("ábaco"); e.word() != want {
+        t.Errorf("word() = %q, want %q", e.word(), want)
+    }
+}
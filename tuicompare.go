@@ -0,0 +1,191 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/secretcmp"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -tui 对照检查备份
+// -------------------------
+//
+// binary.txt 已存在时，-tui 可以选择把纸上的备份与它逐位对照：左栏是
+// binary.txt 的助记词，默认只显示指纹，按 r 确认后才显示单词；右栏是
+// 用户照纸输入的单词，每个位置标出 ✓（一致）、✗（不同）或 ?（不在词表上）。
+// 结果在关闭界面后打印，只含指纹与不一致的位置，不含单词。
+//
+
+// 对照结束，runTUI 打印 t.summary 后返回
+var errTUIDone = errors.New("done")
+
+func (t *tui) compare(wordList []string) error {
+    var bits []bool
+    t.cooked(func() { bits = loadEntropyBits() })
+    stored := strings.Fields(mnemonicFromEntropy(bip39.BitsToBytes(bits), wordList))
+    storedFP := masterFingerprint(strings.Join(stored, " "))
+    matcher := wordmatch.New(wordList)
+    typed := make([]string, len(stored))
+    known := make([]bool, len(stored))
+    sel, reveal := 0, false
+    editing, entry := true, newWordEntry(matcher, "")
+
+    for {
+        lines := []string{
+            fmt.Sprintf("Left: binary.txt, fingerprint %s. Right: your paper backup.", storedFP),
+            "",
+            fmt.Sprintf("   #  %-14s  %-14s", "binary.txt", "your backup"),
+        }
+        for i := range stored {
+            left := "······"
+            if reveal {
+                left = stored[i]
+            }
+            right, mark := typed[i], " "
+            if editing && i == sel {
+                right = string(entry.text) + "_"
+            } else if right != "" {
+                switch {
+                case !known[i]:
+                    mark = "\x1b[33m?\x1b[0m"
+                case secretcmp.EqualString(right, stored[i]):
+                    mark = "\x1b[32m✓\x1b[0m"
+                default:
+                    mark = "\x1b[31m✗\x1b[0m"
+                }
+            }
+            line := fmt.Sprintf("  %2d  %-14s  %-14s %s", i+1, left, right, mark)
+            if i == sel {
+                line = "\x1b[7m" + line + "\x1b[0m"
+            }
+            lines = append(lines, line)
+        }
+        if editing {
+            lines = append(lines, entry.suggestionLines()...)
+        }
+        lines = append(lines, "", t.compareStatus(stored, typed, known, wordList))
+
+        footer := "↑/↓ move   Enter edit   r show/hide binary.txt   d done   q quit"
+        if editing {
+            footer = "type the word (4 letters are enough)   ↑/↓ pick a suggestion   Tab complete   Enter next   Esc stop editing"
+        }
+        t.draw("Compare a backup with binary.txt", lines, footer)
+
+        key, err := t.key()
+        if err != nil {
+            return err
+        }
+        if editing {
+            switch {
+            case entry.key(key):
+            case key == "enter":
+                word := entry.word()
+                if c, ok := matcher.Lookup(word); ok {
+                    typed[sel], known[sel] = c.Word, true
+                } else {
+                    typed[sel], known[sel] = word, false
+                }
+                entry = newWordEntry(matcher, "")
+                if sel+1 < len(stored) && typed[sel+1] == "" {
+                    sel++
+                } else {
+                    editing = false
+                }
+            case key == "esc":
+                editing, entry = false, newWordEntry(matcher, "")
+            case key == "ctrl-c":
+                if t.confirmQuit() {
+                    return errTUIQuit
+                }
+            }
+            continue
+        }
+
+        switch key {
+        case "up", "k":
+            sel = (sel + len(stored) - 1) % len(stored)
+        case "down", "j":
+            sel = (sel + 1) % len(stored)
+        case "enter":
+            editing = true
+            entry = newWordEntry(matcher, typed[sel])
+        case "r":
+            if reveal {
+                reveal = false
+            } else if revealWords {
+                reveal = true
+            } else {
+                choice, err := t.menu("Show binary.txt?", []string{
+                    "The stored words will be on screen next to yours.",
+                    "Make sure no one can see the screen and it is not recorded.",
+                }, []string{"Keep them hidden", "Show them"})
+                if err != nil {
+                    return err
+                }
+                reveal = choice == 1
+            }
+        case "d":
+            t.summary = t.compareSummary(stored, typed, known, storedFP, wordList)
+            return errTUIDone
+        case "q", "ctrl-c":
+            if t.confirmQuit() {
+                return errTUIQuit
+            }
+        }
+    }
+}
+
+// 不一致的位置（从 1 起）；有空位时 complete 为 false
+func compareBackup(stored, typed []string, known []bool) (differ []int, complete bool) {
+    complete = true
+    for i := range stored {
+        switch {
+        case typed[i] == "":
+            complete = false
+        case !known[i] || !secretcmp.EqualString(typed[i], stored[i]):
+            differ = append(differ, i+1)
+        }
+    }
+    return differ, complete
+}
+
+func (t *tui) compareStatus(stored, typed []string, known []bool, wordList []string) string {
+    differ, complete := compareBackup(stored, typed, known)
+    switch {
+    case !complete:
+        return fmt.Sprintf("%d positions differ so far.", len(differ))
+    case len(differ) > 0:
+        return fmt.Sprintf("\x1b[31m%d of %d positions differ.\x1b[0m Press d when done.", len(differ), len(stored))
+    }
+    if _, err := entropyFromPhrase(strings.Join(typed, " "), wordList); err != nil {
+        return "\x1b[31m" + err.Error() + "\x1b[0m"
+    }
+    return fmt.Sprintf("\x1b[32mAll %d words match binary.txt.\x1b[0m Press d when done.", len(stored))
+}
+
+func (t *tui) compareSummary(stored, typed []string, known []bool, storedFP string, wordList []string) []string {
+    differ, complete := compareBackup(stored, typed, known)
+    if !complete {
+        transcript.record("compare backup", "incomplete", storedFP, "")
+        return []string{"Comparison not finished: not every word was entered.", "binary.txt fingerprint: " + storedFP}
+    }
+    if len(differ) == 0 {
+        transcript.record("compare backup", "match", storedFP, fmt.Sprintf("%d words", len(stored)))
+        return []string{fmt.Sprintf("Backup MATCHES binary.txt: all %d words (fingerprint %s).", len(stored), storedFP)}
+    }
+    positions := strings.Trim(fmt.Sprint(differ), "[]")
+    out := []string{fmt.Sprintf("Backup DIFFERS from binary.txt at position(s) %s.", positions)}
+    if _, err := entropyFromPhrase(strings.Join(typed, " "), wordList); err == nil {
+        out = append(out, fmt.Sprintf("The backup is a valid mnemonic of another wallet (fingerprint %s, binary.txt %s).",
+            masterFingerprint(strings.Join(typed, " ")), storedFP))
+    } else {
+        out = append(out, "The backup as typed is not a valid mnemonic.")
+    }
+    transcript.record("compare backup", "mismatch", storedFP, "positions "+positions)
+    return out
+}
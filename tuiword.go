@@ -0,0 +1,112 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -tui 单词输入与候选
+// -------------------------
+//
+// 抽查与对照时逐个输入单词：输入框下方列出以已输入部分开头的单词，
+// ↓/↑ 选中其中一个，回车采用选中的单词，Tab 把输入补全为它。
+// 没有选中时回车采用输入的文字，唯一前缀照常被接受。候选只来自公开的
+// 词表，不会显示 binary.txt 的单词。-masked 不显示候选：候选会暴露正在输入的单词。
+//
+
+// 最多显示的候选个数
+const tuiSuggestions = 5
+
+type wordEntry struct {
+    matcher *wordmatch.Matcher
+    text    []rune
+    sel     int // 选中的候选，-1 = 没有选中
+}
+
+func newWordEntry(matcher *wordmatch.Matcher, text string) *wordEntry {
+    return &wordEntry{matcher: matcher, text: []rune(text), sel: -1}
+}
+
+func (e *wordEntry) candidates() []wordmatch.Candidate {
+    c := e.matcher.Prefix(string(e.text))
+    return c[:min(len(c), tuiSuggestions)]
+}
+
+// 处理编辑按键；其他按键返回 false，由调用者处理
+func (e *wordEntry) key(key string) bool {
+    switch {
+    case key == "down":
+        if e.sel+1 < len(e.candidates()) {
+            e.sel++
+        }
+    case key == "up":
+        if e.sel >= 0 {
+            e.sel--
+        }
+    case key == "tab":
+        if c := e.candidates(); len(c) > 0 {
+            e.text = []rune(c[max(e.sel, 0)].Word)
+            e.sel = -1
+        }
+    case key == "backspace":
+        if len(e.text) > 0 {
+            e.text = e.text[:len(e.text)-1]
+        }
+        e.sel = -1
+    case utf8.RuneCountInString(key) == 1 && key != " " && len(e.text) < maxLineLen:
+        e.text = append(e.text, []rune(key)...)
+        e.sel = -1
+    default:
+        return false
+    }
+    return true
+}
+
+// 回车时的结果：选中的候选，否则输入的文字
+func (e *wordEntry) word() string {
+    if c := e.candidates(); e.sel >= 0 && e.sel < len(c) {
+        return c[e.sel].Word
+    }
+    return strings.TrimSpace(string(e.text))
+}
+
+// 输入框下方的候选行，选中的反色显示
+func (e *wordEntry) suggestionLines() []string {
+    var lines []string
+    for i, c := range e.candidates() {
+        if i == e.sel {
+            lines = append(lines, fmt.Sprintf("  \x1b[7m> %s\x1b[0m", c.Word))
+        } else {
+            lines = append(lines, "    "+c.Word)
+        }
+    }
+    return lines
+}
+
+// 一个单词的输入（备用屏幕，退出时清除）
+func (t *tui) wordInput(title string, intro []string, prompt string, matcher *wordmatch.Matcher) (string, error) {
+    e := newWordEntry(matcher, "")
+    for {
+        lines := append(append([]string{}, intro...), "", prompt+string(e.text)+"_")
+        lines = append(lines, e.suggestionLines()...)
+        t.draw(title, lines, "↑/↓ pick a suggestion   Tab complete   Enter confirm   Ctrl-C quit")
+        key, err := t.key()
+        if err != nil {
+            return "", err
+        }
+        switch {
+        case e.key(key):
+        case key == "enter":
+            return e.word(), nil
+        case key == "ctrl-c":
+            if t.confirmQuit() {
+                return "", errTUIQuit
+            }
+        }
+    }
+}
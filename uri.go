@@ -0,0 +1,122 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "math/big"
+    "strconv"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+)
+
+//
+// -------------------------
+//   -uri BIP21 收款二维码
+// -------------------------
+//
+// bitcoin:<地址>?amount=0.01&label=...&message=...
+// 地址可以直接给出（只涉及公开信息，只读模式下也可用），也可以给一个
+// 数字 N，取 binary.txt 的第 N 个 BIP84 收款地址 m/84'/0'/0'/0/N。
+//
+
+// 21,000,000 BTC，以聪为单位
+const maxSatoshis = 21_000_000 * 100_000_000
+
+// derive 为 false（只读模式）时不允许从 binary.txt 派生地址
+func printPaymentRequest(target, amount, label, message string, derive bool, wordList []string) {
+    address := target
+    if n, err := strconv.ParseUint(target, 10, 31); err == nil {
+        if buildReadOnly || !derive {
+            log.Fatalf("Error: -uri N derives from binary.txt, which is not available in read-only mode; give an address.")
+        } else {
+            address = derivedReceiveAddress(uint32(n), wordList)
+            fmt.Printf("Receive address %d (m/84'/0'/0'/0/%d): %s\n", n, n, address)
+            newDerivationParams(nil, fmt.Sprintf("m/84'/0'/0'/0/%d", n)).print()
+        }
+    }
+
+    uri, err := bip21URI(address, amount, label, message)
+    if err != nil {
+        log.Fatalf("Error: -uri: %v", err)
+    }
+    fmt.Println("Payment request:")
+    fmt.Println(uri)
+    printQR(qrPayload(uri))
+}
+
+func bip21URI(address, amount, label, message string) (string, error) {
+    t, err := parseRecoveryTarget(address, 0)
+    if err != nil {
+        return "", err
+    }
+    if t.fingerprint != nil {
+        return "", fmt.Errorf("%q is not an address", address)
+    }
+
+    var params []string
+    if amount != "" {
+        btc, err := parseBTCAmount(amount)
+        if err != nil {
+            return "", err
+        }
+        params = append(params, "amount="+btc)
+    }
+    if label != "" {
+        params = append(params, "label="+bip21Escape(label))
+    }
+    if message != "" {
+        params = append(params, "message="+bip21Escape(message))
+    }
+
+    uri := "bitcoin:" + address
+    if len(params) > 0 {
+        uri += "?" + strings.Join(params, "&")
+    }
+    return uri, nil
+}
+
+// 金额为十进制 BTC，最多 8 位小数；返回去掉多余 0 的规范写法
+func parseBTCAmount(s string) (string, error) {
+    r, ok := new(big.Rat).SetString(s)
+    if !ok || strings.ContainsAny(s, "eE/") {
+        return "", fmt.Errorf("invalid amount %q", s)
+    }
+    sats := new(big.Rat).Mul(r, big.NewRat(100_000_000, 1))
+    if !sats.IsInt() {
+        return "", fmt.Errorf("amount %q has more than 8 decimal places", s)
+    }
+    n := sats.Num()
+    if n.Sign() <= 0 || n.Cmp(big.NewInt(maxSatoshis)) > 0 {
+        return "", fmt.Errorf("amount %q must be between 0.00000001 and 21000000", s)
+    }
+    btc := r.FloatString(8)
+    btc = strings.TrimRight(btc, "0")
+    return strings.TrimSuffix(btc, "."), nil
+}
+
+// BIP21 用 RFC 3986 百分号编码：空格是 %20 而不是 +
+func bip21Escape(s string) string {
+    var b strings.Builder
+    for _, c := range []byte(s) {
+        switch {
+        case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.IndexByte("-._~", c) >= 0:
+            b.WriteByte(c)
+        default:
+            fmt.Fprintf(&b, "%%%02X", c)
+        }
+    }
+    return b.String()
+}
+
+func derivedReceiveAddress(n uint32, wordList []string) string {
+    child, err := bip39Master(generatePassphraseFromBinary(wordList)).Derive(fmt.Sprintf("m/84'/0'/0'/0/%d", n))
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    address, err := btcaddr.Encode(btcaddr.P2WPKH, child.PublicKey())
+    if err != nil {
+        log.Fatalf("Error: %v", err)
+    }
+    return address
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+    "github.com/li-han-zhang/Go_passphrase/pkg/wordmatch"
+)
+
+//
+// -------------------------
+//   -v 校验已有助记词
+// -------------------------
+//
+// 逐词查表（可给出最接近的单词），再重新计算 SHA-256 校验位，
+// 报告助记词是否有效以及出错的位置。无效时退出码为 1。
+// 参数为 - 时不回显地询问，助记词不会留在 shell 历史里。
+//
+
+func validateMnemonic(phrase string, wordList []string) {
+    if phrase == "-" {
+        var err error
+        if phrase, err = readSecret("Mnemonic (hidden): "); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+    }
+    if jsonOutput {
+        validateMnemonicJSON(phrase, wordList)
+        return
+    }
+    words := splitWords(phrase)
+    valid := true
+    fp := ""
+
+    switch len(words) {
+    case 12, 15, 18, 21, 24:
+        ent := len(words) * 11 * 32 / 33
+        fmt.Printf("Words:    %d (%d-bit entropy, %d-bit checksum)\n", len(words), ent, ent/32)
+    default:
+        fmt.Printf("Words:    %d, expected 12, 15, 18, 21 or 24\n", len(words))
+        valid = false
+    }
+
+    matcher := wordmatch.New(wordList)
+    indices := make([]int, len(words))
+    for i, w := range words {
+        c, ok := matcher.Lookup(w)
+        switch {
+        case !ok:
+            fmt.Printf("Word %2d:  '%s' is not on the list%s\n", i+1, w, didYouMean(matcher, w))
+            valid = false
+        case c.Word != wordmatch.Normalize(w):
+            fmt.Printf("Word %2d:  '%s' read as '%s' (abbreviation; write the full word)\n", i+1, w, c.Word)
+        }
+        indices[i] = c.Index
+    }
+
+    if valid {
+        if e, err := bip39.EntropyFromIndices(indices); err != nil {
+            bits := make([]bool, 0, len(indices)*11)
+            for _, idx := range indices {
+                for b := 10; b >= 0; b-- {
+                    bits = append(bits, (idx>>b)&1 == 1)
+                }
+            }
+            entLen := len(bits) * 32 / 33
+            fmt.Printf("Checksum: FAIL (last %d bits are %s, expected %s)\n",
+                len(bits)-entLen, bitString(bits[entLen:]), bitString(bip39.ChecksumBits(bits[:entLen])))
+            fmt.Println("          The checksum covers the whole phrase: any word may be wrong or out of order.")
+            valid = false
+        } else {
+            fp = masterFingerprint(wordsFromIndices(indices, wordList))
+            fmt.Println("Checksum: OK")
+            fmt.Println("Fingerprint:", fp)
+            warnWeakMnemonic(e, wordList)
+        }
+    }
+
+    if !valid {
+        fmt.Println("Result:   INVALID")
+        transcript.record("validate mnemonic", "invalid", "", "")
+        transcript.finish()
+        os.Exit(1)
+    }
+    fmt.Println("Result:   VALID")
+    transcript.record("validate mnemonic", "valid", fp, "")
+}
+
+// 拼错的单词：按编辑距离给出最接近的几个候选（与最近的相差不超过 1，最多 3 个）；
+// 差得太远（超过单词长度的一半）时不猜
+func didYouMean(matcher *wordmatch.Matcher, s string) string {
+    cands := matcher.Suggest(s, 3)
+    if len(cands) == 0 || cands[0].Distance > max(1, len([]rune(s))/2) {
+        return ""
+    }
+    var quoted []string
+    for _, c := range cands {
+        if c.Distance <= cands[0].Distance+1 {
+            quoted = append(quoted, "'"+c.Word+"'")
+        }
+    }
+    if len(quoted) == 1 {
+        return fmt.Sprintf(" (did you mean %s?)", quoted[0])
+    }
+    return fmt.Sprintf(" (did you mean %s or %s?)", strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+//
+// -------------------------
+//   -check-bits 校验任意位串
+// -------------------------
+//
+// 对“熵 + 校验位”的 0/1 串（例如从其他工具的调试输出抄来的）重算校验位，
+// 报告是否有效、应有的校验位，并给出修正后的位串。只有校验位能被修正：
+// 熵里错了一位会得到另一个同样“有效”的位串，无法察觉。无效时退出码为 1。
+//
+
+func checkBitString(input string) {
+    if input == "-" {
+        var err error
+        if input, err = readSecret("Bits (hidden): "); err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+    }
+    bits, err := bip39.ParseBits(input)
+    if err != nil {
+        log.Fatalf("Error: -check-bits: %v", err)
+    }
+    r, err := bip39.CheckBits(bits)
+    if err != nil {
+        log.Fatalf("Error: -check-bits: %v", err)
+    }
+    if jsonOutput {
+        printJSON(checkBitsReport(r))
+        if !r.Valid {
+            transcript.record("check bits", "invalid", "", "")
+            transcript.finish()
+            os.Exit(1)
+        }
+        transcript.record("check bits", "valid", "", "")
+        return
+    }
+
+    fmt.Printf("Bits:      %d (%d-bit entropy, %d-bit checksum)\n", len(bits), len(r.Entropy), len(r.Expected))
+    switch {
+    case r.Valid:
+        fmt.Printf("Checksum:  OK (%s)\n", bitString(r.Checksum))
+    case len(r.Checksum) == 0:
+        fmt.Printf("Checksum:  MISSING (expected %s)\n", bitString(r.Expected))
+    default:
+        fmt.Printf("Checksum:  FAIL (given %s, expected %s)\n", bitString(r.Checksum), bitString(r.Expected))
+        fmt.Println("           A wrong entropy bit also changes the checksum; compare the entropy with the source.")
+    }
+    if !r.Valid {
+        fmt.Println("Corrected:")
+        for _, line := range strings.Split(strings.TrimSuffix(layoutBits(r.Corrected, bitLayout), "\n"), "\n") {
+            fmt.Println("  " + line)
+        }
+        fmt.Println("Result:    INVALID")
+        transcript.record("check bits", "invalid", "", "")
+        transcript.finish()
+        os.Exit(1)
+    }
+    fmt.Println("Result:    VALID")
+    transcript.record("check bits", "valid", "", "")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+    "fmt"
+    "runtime"
+    "runtime/debug"
+)
+
+//
+// -------------------------
+//   -version 版本信息
+// -------------------------
+//
+// version 由发布工具（go run ./internal/release build）以
+// -ldflags "-X main.version=..." 写入；自行 go build 时为 "dev"。
+// 同时输出 Go 版本、目标平台与构建时记录的 VCS 修订，便于离线用户
+// 核对手中的二进制与发布说明中的哈希是否出自同一次构建。
+//
+
+var version = "dev"
+
+func printVersion() {
+    fmt.Printf("passphrase_bitcoin %s (%s, %s/%s", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+    if buildReadOnly {
+        fmt.Print(", read-only")
+    }
+    fmt.Println(")")
+    info, ok := debug.ReadBuildInfo()
+    if !ok {
+        return
+    }
+    for _, s := range info.Settings {
+        switch s.Key {
+        case "vcs.revision", "vcs.time", "vcs.modified", "CGO_ENABLED", "-trimpath":
+            fmt.Printf("  %-13s %s\n", s.Key, s.Value)
+        }
+    }
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "os"
+    "sort"
+    "strings"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/bip39"
+)
+
+//
+// -------------------------
+//   人为挑选的助记词检测
+// -------------------------
+//
+// 导入、-mnemonic 和 -v 时检查助记词是否像人挑出来的而不是随机生成的：
+// 重复的词、词表上相邻或等间隔的词、按字母顺序排列、全部集中在词表一小段、
+// 大部分是日常英语单词（像在造句）。各条规则的阈值使随机助记词触发的
+// 概率低于 weakPhraseFalseAlarm。最后一个词含校验和，不参与检查。
+//
+
+const weakPhraseFalseAlarm = 1e-4
+
+// 日常英语单词中在 BIP39 英文词表上的部分（436 个）
+var everydayWords = strings.Fields(`
+    about above across act add again age air all alone also always among
+    animal answer any apart area arm army around art ask away baby bag ball
+    base because become before begin behind believe below best better between
+    bird black blue boat body book box boy brain bread bring brother build
+    busy cake call can car card carry case cat catch cause chair change check
+    child city clean clock close cloud coffee come cook cool copy cost country
+    course cover cry cup dad dance day deal dinner doctor dog door dream dress
+    drink drive drop early earth easy egg eight either else empty end enjoy
+    enough enter exit eye face fall family famous farm father feel few field
+    find fine finger fire first fish floor flower fly follow food foot forest
+    forget friend front fruit fun funny future game garden gift girl give glad
+    glass goat gold good great green group grow guess gun hair half hand happy
+    hard hat have head heart heavy hello help high hill hold hole home hope
+    horse hour hundred hungry hurry idea into iron island job join joke joy
+    jump just keep key kid kind kiss kitchen know lady lake lamp large later
+    laugh lazy learn leave left leg letter life light like lion list little
+    live long love lucky lunch machine mad magic make man market master matter
+    meat middle milk mind minute miss mom monkey month moon more morning
+    mother mountain mouse move movie much music must name near need never news
+    next nice night north nose nothing now number ocean off offer office often
+    oil okay old once one only open orange order other over own page pair
+    paper parent party peace pen people person phone picture piece pig pink
+    pizza place play please pretty price problem pull push put quick rain
+    ready real rich ride right ring river road room round run sad safe salt
+    same sand say school sea second secret sell seven share shell ship shoe
+    shop short sick side sing sister six size sleep slow small smile snow soft
+    song soon sorry sound south speak spend spring stand start stay stick
+    still stone story street strong sugar summer sun sure sweet swim table
+    talk teach team tell ten test thank that then there they thing this three
+    time today tomorrow tonight tooth top town toy train tree trip true try
+    turn twelve twenty two uncle under until use very visit wait walk wall
+    want warm wash water way wear welcome what when where wife wild will win
+    window wine winter wish woman wood word work world worry write wrong year
+    yellow you young
+`)
+
+// 命中时打印警告；已知助记词只报一次
+func warnWeakMnemonic(entropy []byte, wordList []string) {
+    if warnKnownMnemonic(entropy) {
+        return
+    }
+    mnemonic, err := bip39.NewMnemonic(entropy, wordList)
+    if err != nil {
+        return
+    }
+    indices, err := bip39.Indices(mnemonic.Words(), wordList)
+    if err != nil {
+        return
+    }
+    findings := weakPhraseFindings(indices, wordList)
+    if len(findings) == 0 {
+        return
+    }
+    fmt.Fprintln(os.Stderr, "Warning: this phrase looks chosen by a person, not generated at random:")
+    for _, f := range findings {
+        fmt.Fprintln(os.Stderr, "  -", f)
+    }
+    fmt.Fprintln(os.Stderr, "Warning: such phrases can be guessed. Move the funds to a passphrase made with -b.")
+    transcript.record("human pattern check", "match", "", strings.Join(findings, "; "))
+}
+
+func weakPhraseFindings(indices []int, wordList []string) []string {
+    var findings []string
+    body := indices[:len(indices)-1]
+    n := len(body)
+    word := func(i int) string { return wordList[i] }
+
+    // 重复：n 个词有 n(n-1)/2 对，每对相同的概率 1/2048
+    count := map[int]int{}
+    for _, idx := range body {
+        count[idx]++
+    }
+    if dup := n - len(count); dup >= binomialThreshold(n*(n-1)/2, 1.0/bip39.WordCount, weakPhraseFalseAlarm) {
+        var repeated []string
+        for idx, c := range count {
+            if c > 1 {
+                repeated = append(repeated, fmt.Sprintf("'%s' ×%d", word(idx), c))
+            }
+        }
+        sort.Strings(repeated)
+        findings = append(findings, fmt.Sprintf("%d repeated words (%s)", dup, strings.Join(repeated, ", ")))
+    }
+
+    // 词表上相邻的词，以及 4 个以上等间隔的词
+    adjacent, example := 0, ""
+    for i := 1; i < n; i++ {
+        if d := body[i] - body[i-1]; d == 1 || d == -1 {
+            adjacent++
+            example = fmt.Sprintf("'%s' '%s'", word(body[i-1]), word(body[i]))
+        }
+    }
+    if adjacent >= binomialThreshold(n-1, 2.0/bip39.WordCount, weakPhraseFalseAlarm) {
+        findings = append(findings, fmt.Sprintf("%d pairs of neighbouring words are also neighbours on the list (e.g. %s)", adjacent, example))
+    }
+    for start := 0; start+3 < n; {
+        d := body[start+1] - body[start]
+        end := start + 1
+        for end+1 < n && body[end+1]-body[end] == d {
+            end++
+        }
+        if d != 0 && end-start >= 3 {
+            findings = append(findings, fmt.Sprintf("words %d–%d step through the list evenly ('%s' … '%s')", start+1, end+1, word(body[start]), word(body[end])))
+        }
+        start = end
+    }
+
+    // 字母顺序（词表本身按字母排序）
+    if n >= 5 && (sort.IntsAreSorted(body) || sort.SliceIsSorted(body, func(i, j int) bool { return body[i] > body[j] })) {
+        findings = append(findings, "the words are in alphabetical order")
+    }
+
+    // 集中在词表的四分之一以内
+    lo, hi := body[0], body[0]
+    for _, idx := range body {
+        lo, hi = min(lo, idx), max(hi, idx)
+    }
+    if n >= 5 && hi-lo < bip39.WordCount/4 {
+        findings = append(findings, fmt.Sprintf("all words come from one quarter of the list ('%s' to '%s')", word(lo), word(hi)))
+    }
+
+    // 日常英语单词（仅当词表包含它们，即英文词表）
+    everyday := map[string]bool{}
+    for _, w := range everydayWords {
+        everyday[w] = true
+    }
+    onList := 0
+    for _, w := range wordList {
+        if everyday[w] {
+            onList++
+        }
+    }
+    if onList == len(everydayWords) {
+        hits := 0
+        for _, idx := range body {
+            if everyday[word(idx)] {
+                hits++
+            }
+        }
+        if hits >= binomialThreshold(n, float64(onList)/float64(len(wordList)), weakPhraseFalseAlarm) {
+            findings = append(findings, fmt.Sprintf("%d of %d words are everyday English words, as in a made-up sentence", hits, n))
+        }
+    }
+    return findings
+}
+
+// 最小的 k，使 n 次、每次概率 p 的试验中至少 k 次成功的概率低于 alpha
+func binomialThreshold(n int, p, alpha float64) int {
+    tail := 0.0
+    for k := n; k >= 0; k-- {
+        term := math.Exp(lgammaInt(n+1) - lgammaInt(k+1) - lgammaInt(n-k+1) + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+        if tail+term >= alpha {
+            return k + 1
+        }
+        tail += term
+    }
+    return 0
+}
+
+func lgammaInt(n int) float64 {
+    v, _ := math.Lgamma(float64(n))
+    return v
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "os"
+    "sort"
+    "strings"
+    "unicode/utf8"
+
+    "golang.org/x/text/unicode/norm"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/textnorm"
+)
+
+//
+// -------------------------
+//   第三方词表维护
+// -------------------------
+//
+// -wordlist-check 检查词表，-wordlist-sort 输出规范形式，
+// -wordlist FILE 在本次运行中用它代替内置词表：必须正好 2048 个互不相同的
+// NFKD 小写单词，否则拒绝。用它生成的助记词不是标准 BIP39，一般钱包无法
+// 恢复，种子也与同样熵的标准助记词不同，所以每次都在标准错误给出警告
+// 和词表的 SHA-256 前缀，便于确认两边用的是同一份词表。
+//
+
+var (
+    customWordlist string   // -wordlist
+    customWords    []string // 首次使用时读取并检查
+)
+
+type wordlistReport struct {
+    words    []string
+    errors   []string
+    warnings []string
+}
+
+func readWordlistFile(filename string) ([]string, []string) {
+    data, err := readFileLimited(filename, maxWordlistSize)
+    if err != nil {
+        log.Fatalf("Error reading %s: %v", filename, err)
+    }
+    var words, problems []string
+    text := strings.TrimPrefix(string(data), "\ufeff")
+    for n, line := range strings.Split(strings.TrimRight(text, "\r\n"), "\n") {
+        line = strings.TrimRight(line, "\r")
+        w := strings.TrimSpace(line)
+        if w == "" {
+            problems = append(problems, fmt.Sprintf("line %d is empty", n+1))
+            continue
+        }
+        if w != line {
+            problems = append(problems, fmt.Sprintf("line %d has surrounding whitespace", n+1))
+        }
+        words = append(words, w)
+    }
+    return words, problems
+}
+
+func checkWordlist(filename string) wordlistReport {
+    words, problems := readWordlistFile(filename)
+    r := wordlistReport{words: words, warnings: problems}
+
+    if len(words) != 2048 {
+        r.errors = append(r.errors, fmt.Sprintf("%d words, expected 2048", len(words)))
+    }
+
+    seen := make(map[string]int)
+    prefixes := make(map[string]string)
+    for i, w := range words {
+        if !norm.NFKD.IsNormalString(w) {
+            r.errors = append(r.errors, fmt.Sprintf("word %d '%s' is not NFKD-normalized", i+1, w))
+        }
+        if strings.ContainsAny(w, " \t") {
+            r.errors = append(r.errors, fmt.Sprintf("word %d '%s' contains whitespace", i+1, w))
+        }
+        nw := norm.NFKD.String(w)
+        if textnorm.Word(w) != nw {
+            r.errors = append(r.errors, fmt.Sprintf("word %d '%s' is not lower case", i+1, w))
+        }
+        if j, ok := seen[nw]; ok {
+            r.errors = append(r.errors, fmt.Sprintf("word %d '%s' duplicates word %d", i+1, w, j+1))
+        }
+        seen[nw] = i
+
+        // BIP39 建议：前 4 个字符即可唯一确定单词
+        p := nw
+        if utf8.RuneCountInString(p) > 4 {
+            p = string([]rune(p)[:4])
+        }
+        if other, ok := prefixes[p]; ok && other != nw {
+            r.warnings = append(r.warnings, fmt.Sprintf("'%s' and '%s' share the prefix '%s'", other, w, p))
+        }
+        prefixes[p] = nw
+    }
+
+    if !sort.StringsAreSorted(words) {
+        r.warnings = append(r.warnings, "words are not sorted")
+    }
+    return r
+}
+
+func printWordlistCheck(filename string) {
+    r := checkWordlist(filename)
+    fmt.Println("Wordlist:", filename)
+    fmt.Println("Words:", len(r.words))
+    for _, e := range r.errors {
+        fmt.Println("Error:", e)
+    }
+    for _, w := range r.warnings {
+        fmt.Println("Warning:", w)
+    }
+    if len(r.errors) > 0 {
+        fmt.Printf("Result: NOT usable (%d errors, %d warnings)\n", len(r.errors), len(r.warnings))
+        os.Exit(1)
+    }
+    fmt.Printf("Result: usable (%d warnings)\n", len(r.warnings))
+}
+
+// 规范化：NFKD、去空白、排序，输出到标准输出
+func printSortedWordlist(filename string) {
+    words, _ := readWordlistFile(filename)
+    for i, w := range words {
+        words[i] = norm.NFKD.String(w)
+    }
+    sort.Strings(words)
+    for _, w := range words {
+        fmt.Println(w)
+    }
+}
+
+// -wordlist：读取、检查并给出非标准警告
+func loadCustomWordlist() []string {
+    if customWords != nil {
+        return customWords
+    }
+    r := checkWordlist(customWordlist)
+    if len(r.errors) > 0 {
+        for _, e := range r.errors[:min(len(r.errors), 10)] {
+            fmt.Fprintln(os.Stderr, "Error:", e)
+        }
+        log.Fatalf("Error: -wordlist %s is not usable (%d errors); see -wordlist-check.", customWordlist, len(r.errors))
+    }
+    customWords = make([]string, len(r.words))
+    for i, w := range r.words {
+        customWords[i] = norm.NFKD.String(w)
+    }
+    sum := sha256.Sum256([]byte(strings.Join(customWords, "\n") + "\n"))
+    fmt.Fprintf(os.Stderr, "Warning: using the NON-STANDARD wordlist %s (sha256 %s, %d warnings).\n", customWordlist, hex.EncodeToString(sum[:4]), len(r.warnings))
+    fmt.Fprintln(os.Stderr, "Warning: standard wallets cannot restore these mnemonics, and their seeds differ from the standard words for the same entropy.")
+    return customWords
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/li-han-zhang/Go_passphrase/pkg/btcaddr"
+    "github.com/li-han-zhang/Go_passphrase/pkg/watchonly"
+)
+
+//
+// -------------------------
+//   -xpub-sheet 收款地址二维码
+// -------------------------
+//
+// 只用账户 xpub（公开信息）打印一张带序号的收款地址二维码，给收银台等场合
+// 使用。这条路径只经过 pkg/watchonly，不读 binary.txt，不接触任何秘密，
+// 只读模式下也可用。
+//
+
+// 一张纸上最多打印的地址数
+const maxSheetAddresses = 1000
+
+func printAddressSheet(xpub, addrType string, start, count int) {
+    def := btcaddr.P2WPKH
+    if addrType != "" {
+        t, err := btcaddr.ParseType(addrType)
+        if err != nil {
+            log.Fatalf("Error: -addr-type: %v", err)
+        }
+        def = t
+    }
+    account, err := watchonly.Parse(xpub, def)
+    if err != nil {
+        log.Fatalf("Error: -xpub-sheet: %v", err)
+    }
+    if addrType != "" && account.Type != def {
+        log.Fatalf("Error: -addr-type %s conflicts with the key prefix, which means %s", addrType, account.Type)
+    }
+    if count < 1 || count > maxSheetAddresses || start < 0 || start > 1<<31-1-count {
+        log.Fatalf("Error: -count must be 1–%d and -start a non-negative index", maxSheetAddresses)
+    }
+
+    fmt.Println("Receive address sheet (watch-only)")
+    fmt.Printf("Key:  %s\n", xpub)
+    fmt.Printf("Type: %s, addresses .../0/%d to .../0/%d\n", account.Type, start, start+count-1)
+    for i := start; i < start+count; i++ {
+        address, err := account.Address(0, uint32(i))
+        if err != nil {
+            log.Fatalf("Error: %v", err)
+        }
+        fmt.Println()
+        fmt.Printf("#%d  %s\n", i, address)
+        printQR("bitcoin:" + address)
+    }
+}